@@ -25,9 +25,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
-func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool) ([]schema.DeployResult, error) {
+func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool, failFast bool) ([]schema.DeployResult, error) {
 	params := map[string]string{
 		"force":          s.Bool(force),
+		"failFast":       s.Bool(failFast),
 		"configFileName": filepath.Base(configPath),
 	}
 	uploadInput := &HTTPUploadInput{
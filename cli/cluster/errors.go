@@ -41,6 +41,8 @@ const (
 	ErrResponseUnknown               = "cli.response_unknown"
 	ErrOperatorResponseUnknown       = "cli.operator_response_unknown"
 	ErrOperatorStreamResponseUnknown = "cli.operator_stream_response_unknown"
+	ErrExecConnectionClosed          = "cli.exec_connection_closed"
+	ErrPortForwardListen             = "cli.port_forward_listen"
 )
 
 func ErrorFailedToConnectOperator(originalError error, envName string, operatorURL string) error {
@@ -104,3 +106,17 @@ func ErrorOperatorStreamResponseUnknown(body string, statusCode int) error {
 		Message: fmt.Sprintf("unexpected response from operator (status code %d): %s", statusCode, body),
 	})
 }
+
+func ErrorExecConnectionClosed(err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrExecConnectionClosed,
+		Message: fmt.Sprintf("the connection to the replica was closed before the command exited (%s)", errors.Message(err)),
+	})
+}
+
+func ErrorPortForwardListen(address string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPortForwardListen,
+		Message: fmt.Sprintf("unable to listen on %s: %s", address, errors.Message(err)),
+	})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+)
+
+// StreamEvents streams api lifecycle events from the operator; if apiName is empty, events for all apis are streamed
+func StreamEvents(operatorConfig OperatorConfig, apiName string, onEvent func(events.Event)) error {
+	path := "/events"
+	if apiName != "" {
+		path += "/" + apiName
+	}
+
+	return streamFromOperator(operatorConfig, path, func(message []byte) {
+		var event events.Event
+		if err := json.Unmarshal(message, &event); err != nil {
+			// forward compatibility: print messages which can't be parsed rather than dropping them
+			fmt.Println(string(message))
+			return
+		}
+		onEvent(event)
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/wsconn"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/docker/docker/pkg/term"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	_execStdinBufferSize         = 8191 // the operator accepts messages of up to 8192 bytes, including the channel byte
+	_execTerminalSizePollPeriod  = 250 * time.Millisecond
+	_execInterruptedExitCode     = 130
+	_portForwardListenerHostname = "localhost"
+)
+
+// Exec runs command in a replica of the api (replica is either an index or a replica name), and returns the command's exit code
+func Exec(operatorConfig OperatorConfig, apiName string, replica string, command []string, tty bool) (int, error) {
+	path := "/exec/" + apiName + "?" + url.Values{"command": command}.Encode()
+	connection, err := dialOperator(operatorConfig, path, map[string]string{
+		"replica": replica,
+		"tty":     strconv.FormatBool(tty),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer connection.Close()
+
+	var writeMutex sync.Mutex
+	writeMessage := func(channel byte, data []byte) error {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		return connection.WriteMessage(websocket.BinaryMessage, append([]byte{channel}, data...))
+	}
+
+	if tty {
+		stdinFd := os.Stdin.Fd()
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		defer term.RestoreTerminal(stdinFd, state)
+
+		go pollTerminalSize(writeMessage)
+	}
+
+	// in a tty, interrupts are forwarded to the command as input
+	interrupted := make(chan struct{})
+	if !tty {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			close(interrupted)
+			writeMutex.Lock()
+			connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			writeMutex.Unlock()
+			connection.Close()
+		}()
+	}
+
+	go forwardStdin(writeMessage)
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			select {
+			case <-interrupted:
+				return _execInterruptedExitCode, nil
+			default:
+				return 0, ErrorExecConnectionClosed(err)
+			}
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case schema.ExecStdoutChannel:
+			os.Stdout.Write(message[1:])
+		case schema.ExecStderrChannel:
+			os.Stderr.Write(message[1:])
+		case schema.ExecStatusChannel:
+			var execStatus schema.ExecStatus
+			if err := json.Unmarshal(message[1:], &execStatus); err != nil {
+				return 0, err
+			}
+			if execStatus.Error != "" {
+				fmt.Fprintln(os.Stderr, execStatus.Error)
+			}
+			return execStatus.ExitCode, nil
+		}
+	}
+}
+
+func forwardStdin(writeMessage func(byte, []byte) error) {
+	buf := make([]byte, _execStdinBufferSize)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if writeErr := writeMessage(schema.ExecStdinChannel, buf[:n]); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			writeMessage(schema.ExecStdinChannel, nil) // close the command's stdin
+			return
+		}
+	}
+}
+
+// the terminal size is polled rather than watched (via SIGWINCH) so that it works on all platforms
+func pollTerminalSize(writeMessage func(byte, []byte) error) {
+	var prevSize schema.TerminalSize
+	for {
+		winsize, err := term.GetWinsize(os.Stdout.Fd())
+		if err == nil {
+			size := schema.TerminalSize{Width: winsize.Width, Height: winsize.Height}
+			if size != prevSize {
+				sizeBytes, _ := json.Marshal(size)
+				if err := writeMessage(schema.ExecResizeChannel, sizeBytes); err != nil {
+					return
+				}
+				prevSize = size
+			}
+		}
+		time.Sleep(_execTerminalSizePollPeriod)
+	}
+}
+
+// PortForward listens on localPort and tunnels each connection to the serving port of a replica of the api, until interrupted
+func PortForward(operatorConfig OperatorConfig, apiName string, replica string, localPort int) error {
+	address := fmt.Sprintf("%s:%d", _portForwardListenerHostname, localPort)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return ErrorPortForwardListen(address, err)
+	}
+	defer listener.Close()
+
+	fmt.Printf("forwarding %s -> %s (replica %s)\n", address, apiName, replica)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil // the listener was closed because of an interrupt
+		}
+		go tunnelConnection(operatorConfig, apiName, replica, conn)
+	}
+}
+
+func tunnelConnection(operatorConfig OperatorConfig, apiName string, replica string, conn net.Conn) {
+	defer conn.Close()
+
+	connection, err := dialOperator(operatorConfig, "/port-forward/"+apiName, map[string]string{"replica": replica})
+	if err != nil {
+		errors.PrintError(err)
+		return
+	}
+	tunnel := wsconn.New(connection)
+	defer tunnel.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(tunnel, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, tunnel)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -18,7 +18,6 @@ package cluster
 
 import (
 	"crypto/tls"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -33,36 +32,84 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-func StreamLogs(operatorConfig OperatorConfig, apiName string) error {
-	return streamLogs(operatorConfig, "/logs/"+apiName)
+// source is either "" (the cluster's log pipeline) or "pods" (read directly from the replicas); onMessage is called for each log message
+func StreamLogs(operatorConfig OperatorConfig, apiName string, source string, onMessage func(string)) error {
+	return streamFromOperator(operatorConfig, "/logs/"+apiName, logMessageHandler(onMessage), logSourceQParams(source))
 }
 
-func StreamJobLogs(operatorConfig OperatorConfig, apiName string, jobID string) error {
-	return streamLogs(operatorConfig, "/logs/"+apiName, map[string]string{"jobID": jobID})
+func StreamJobLogs(operatorConfig OperatorConfig, apiName string, jobID string, source string, onMessage func(string)) error {
+	qParams := logSourceQParams(source)
+	qParams["jobID"] = jobID
+	return streamFromOperator(operatorConfig, "/logs/"+apiName, logMessageHandler(onMessage), qParams)
 }
 
-func GetGCPLogsURL(operatorConfig OperatorConfig, apiName string) (schema.GCPLogsResponse, error) {
-	httpRes, err := HTTPGet(operatorConfig, "/logs/"+apiName)
+func logMessageHandler(onMessage func(string)) func([]byte) {
+	return func(message []byte) {
+		onMessage(string(message))
+	}
+}
+
+func logSourceQParams(source string) map[string]string {
+	qParams := map[string]string{}
+	if source != "" {
+		qParams["source"] = source
+	}
+	return qParams
+}
+
+func SearchLogs(operatorConfig OperatorConfig, apiName string, qParams map[string]string) (schema.LogSearchResponse, error) {
+	endpoint := "/logs/" + apiName + "/search"
+	httpRes, err := HTTPGet(operatorConfig, endpoint, qParams)
+	if err != nil {
+		return schema.LogSearchResponse{}, err
+	}
+
+	var logSearchResponse schema.LogSearchResponse
+	if err = json.Unmarshal(httpRes, &logSearchResponse); err != nil {
+		return schema.LogSearchResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return logSearchResponse, nil
+}
+
+func GetReplicaLogs(operatorConfig OperatorConfig, apiName string) (schema.ReplicaLogsResponse, error) {
+	endpoint := "/logs/" + apiName + "/replicas"
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
 	if err != nil {
-		return schema.GCPLogsResponse{}, err
+		return schema.ReplicaLogsResponse{}, err
 	}
 
-	var gcpLogsResponse schema.GCPLogsResponse
-	if err = json.Unmarshal(httpRes, &gcpLogsResponse); err != nil {
-		return schema.GCPLogsResponse{}, errors.Wrap(err, "/logs/"+apiName, string(httpRes))
+	var replicaLogsResponse schema.ReplicaLogsResponse
+	if err = json.Unmarshal(httpRes, &replicaLogsResponse); err != nil {
+		return schema.ReplicaLogsResponse{}, errors.Wrap(err, endpoint, string(httpRes))
 	}
 
-	return gcpLogsResponse, nil
+	return replicaLogsResponse, nil
 }
 
-func streamLogs(operatorConfig OperatorConfig, path string, qParams ...map[string]string) error {
+// streamFromOperator opens a websocket connection to the operator and calls onMessage for each message received, until interrupted
+func streamFromOperator(operatorConfig OperatorConfig, path string, onMessage func([]byte), qParams ...map[string]string) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
 
-	req, err := operatorRequest(operatorConfig, "GET", path, nil, qParams...)
+	connection, err := dialOperator(operatorConfig, path, qParams...)
 	if err != nil {
 		return err
 	}
+	defer connection.Close()
+
+	done := make(chan struct{})
+	handleConnection(connection, done, onMessage)
+	closeConnection(connection, done, interrupt)
+	return nil
+}
+
+// dialOperator opens a websocket connection to the operator
+func dialOperator(operatorConfig OperatorConfig, path string, qParams ...map[string]string) (*websocket.Conn, error) {
+	req, err := operatorRequest(operatorConfig, "GET", path, nil, qParams...)
+	if err != nil {
+		return nil, err
+	}
 
 	values := req.URL.Query()
 	if operatorConfig.Telemetry {
@@ -83,35 +130,31 @@ func streamLogs(operatorConfig OperatorConfig, path string, qParams ...map[strin
 
 	connection, response, err := dialer.Dial(wsURL, header)
 	if err != nil && response == nil {
-		return ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
+		return nil, ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
 	}
 	defer response.Body.Close()
 
 	if err != nil {
 		bodyBytes, err := ioutil.ReadAll(response.Body)
 		if err != nil || bodyBytes == nil || string(bodyBytes) == "" {
-			return ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
+			return nil, ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
 		}
 		var output schema.ErrorResponse
 		err = json.Unmarshal(bodyBytes, &output)
 		if err != nil || output.Message == "" {
-			return ErrorOperatorStreamResponseUnknown(string(bodyBytes), response.StatusCode)
+			return nil, ErrorOperatorStreamResponseUnknown(string(bodyBytes), response.StatusCode)
 		}
-		return errors.WithStack(&errors.Error{
+		return nil, errors.WithStack(&errors.Error{
 			Kind:        output.Kind,
 			Message:     output.Message,
 			NoTelemetry: true,
 		})
 	}
-	defer connection.Close()
 
-	done := make(chan struct{})
-	handleConnection(connection, done)
-	closeConnection(connection, done, interrupt)
-	return nil
+	return connection, nil
 }
 
-func handleConnection(connection *websocket.Conn, done chan struct{}) {
+func handleConnection(connection *websocket.Conn, done chan struct{}, onMessage func([]byte)) {
 	go func() {
 		defer close(done)
 		for {
@@ -119,7 +162,7 @@ func handleConnection(connection *websocket.Conn, done chan struct{}) {
 			if err != nil {
 				exit.Error(ErrorOperatorSocketRead(err))
 			}
-			fmt.Println(string(message))
+			onMessage(message)
 		}
 	}()
 }
@@ -20,23 +20,18 @@ import (
 	"path/filepath"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
-	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
-func Patch(operatorConfig OperatorConfig, configPath string, force bool) ([]schema.DeployResult, error) {
+func Patch(operatorConfig OperatorConfig, configPath string, configBytes []byte, force bool, failFast bool) ([]schema.DeployResult, error) {
 	params := map[string]string{
 		"force":          s.Bool(force),
+		"failFast":       s.Bool(failFast),
 		"configFileName": filepath.Base(configPath),
 	}
 
-	configBytes, err := files.ReadFileBytes(configPath)
-	if err != nil {
-		return nil, err
-	}
-
 	response, err := HTTPPostJSON(operatorConfig, "/patch", configBytes, params)
 	if err != nil {
 		return nil, err
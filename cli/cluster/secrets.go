@@ -0,0 +1,73 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func SetSecret(operatorConfig OperatorConfig, secretName string, data map[string]string) (schema.SecretResponse, error) {
+	httpRes, err := HTTPPostObjAsJSON(operatorConfig, "/secrets/"+secretName, data)
+	if err != nil {
+		return schema.SecretResponse{}, err
+	}
+
+	var secretRes schema.SecretResponse
+	if err = json.Unmarshal(httpRes, &secretRes); err != nil {
+		return schema.SecretResponse{}, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+
+	return secretRes, nil
+}
+
+func ListSecrets(operatorConfig OperatorConfig) ([]schema.SecretSummary, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/secrets")
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []schema.SecretSummary
+	if err = json.Unmarshal(httpRes, &secrets); err != nil {
+		return nil, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+
+	return secrets, nil
+}
+
+func DeleteSecret(operatorConfig OperatorConfig, secretName string, key string, force bool) (schema.SecretResponse, error) {
+	params := map[string]string{
+		"force": s.Bool(force),
+	}
+	if key != "" {
+		params["key"] = key
+	}
+
+	httpRes, err := HTTPDelete(operatorConfig, "/secrets/"+secretName, params)
+	if err != nil {
+		return schema.SecretResponse{}, err
+	}
+
+	var secretRes schema.SecretResponse
+	if err = json.Unmarshal(httpRes, &secretRes); err != nil {
+		return schema.SecretResponse{}, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+
+	return secretRes, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/gorilla/websocket"
+)
+
+// Stream is a websocket stream from the operator which runs in the background until it's closed (unlike the streams used by
+// `cortex logs` and `cortex events`, which run until they are interrupted)
+type Stream struct {
+	connection *websocket.Conn
+	done       chan struct{}
+	closeOnce  sync.Once
+	closed     bool
+	err        error
+	mutex      sync.Mutex
+}
+
+// OpenLogStream streams the api's logs from the cluster's log pipeline, and calls onMessage for each log message
+func OpenLogStream(operatorConfig OperatorConfig, apiName string, onMessage func(string)) (*Stream, error) {
+	return openStream(operatorConfig, "/logs/"+apiName, logMessageHandler(onMessage))
+}
+
+// OpenEventStream streams the api's recent and new events, and calls onEvent for each event
+func OpenEventStream(operatorConfig OperatorConfig, apiName string, onEvent func(events.Event)) (*Stream, error) {
+	return openStream(operatorConfig, "/events/"+apiName, func(message []byte) {
+		var event events.Event
+		if err := json.Unmarshal(message, &event); err != nil {
+			return
+		}
+		onEvent(event)
+	})
+}
+
+func openStream(operatorConfig OperatorConfig, path string, onMessage func([]byte), qParams ...map[string]string) (*Stream, error) {
+	connection, err := dialOperator(operatorConfig, path, qParams...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &Stream{
+		connection: connection,
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(stream.done)
+		for {
+			_, message, err := connection.ReadMessage()
+			if err != nil {
+				stream.mutex.Lock()
+				if !stream.closed {
+					stream.err = ErrorOperatorSocketRead(err)
+				}
+				stream.mutex.Unlock()
+				return
+			}
+			onMessage(message)
+		}
+	}()
+
+	return stream, nil
+}
+
+// Err returns the error which ended the stream, or nil if the stream is still running or was closed by the caller
+func (stream *Stream) Err() error {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	return stream.err
+}
+
+// Close closes the stream and waits for its background goroutine to exit
+func (stream *Stream) Close() {
+	stream.closeOnce.Do(func() {
+		stream.mutex.Lock()
+		stream.closed = true
+		stream.mutex.Unlock()
+
+		stream.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		stream.connection.Close()
+		<-stream.done
+	})
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// apiName is optional (if empty, the usage of all apis is returned)
+func Top(operatorConfig OperatorConfig, apiName string) (schema.TopResponse, error) {
+	endpoint := "/top"
+	if apiName != "" {
+		endpoint += "/" + apiName
+	}
+
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.TopResponse{}, err
+	}
+
+	var topResponse schema.TopResponse
+	if err = json.Unmarshal(httpRes, &topResponse); err != nil {
+		return schema.TopResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return topResponse, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/archive"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
@@ -68,6 +69,7 @@ var (
 func clusterInit() {
 	_clusterUpCmd.Flags().SortFlags = false
 	addClusterConfigFlag(_clusterUpCmd)
+	addFailFastFlag(_clusterUpCmd)
 	addAWSCredentialsFlags(_clusterUpCmd)
 	addClusterAWSCredentialsFlags(_clusterUpCmd)
 	defaultEnv := getDefaultEnv(_clusterCommandType)
@@ -82,11 +84,13 @@ func clusterInit() {
 	addAWSCredentialsFlags(_clusterInfoCmd)
 	_clusterInfoCmd.Flags().StringVarP(&_flagClusterInfoEnv, "configure-env", "e", "", "name of environment to configure")
 	_clusterInfoCmd.Flags().BoolVarP(&_flagClusterInfoDebug, "debug", "d", false, "save the current cluster state to a file")
+	_clusterInfoCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 	_clusterInfoCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
 	_clusterCmd.AddCommand(_clusterInfoCmd)
 
 	_clusterConfigureCmd.Flags().SortFlags = false
 	addClusterConfigFlag(_clusterConfigureCmd)
+	addFailFastFlag(_clusterConfigureCmd)
 	addAWSCredentialsFlags(_clusterConfigureCmd)
 	addClusterAWSCredentialsFlags(_clusterConfigureCmd)
 	_clusterConfigureCmd.Flags().StringVarP(&_flagClusterConfigureEnv, "configure-env", "e", "", "name of environment to configure")
@@ -427,6 +431,10 @@ var _clusterInfoCmd = &cobra.Command{
 			exit.Error(ErrorLocalEnvironmentCantUseClusterProvider(types.AWSProviderType))
 		}
 
+		if _flagClusterInfoEnv != "" && _flagOutput.IsMachineReadable() {
+			exit.Error(ErrorConflictingFlags("--configure-env", "--output "+_flagOutput.String()))
+		}
+
 		if _, err := docker.GetDockerClient(); err != nil {
 			exit.Error(err)
 		}
@@ -739,7 +747,9 @@ func cmdInfo(awsCreds AWSCredentials, accessConfig *clusterconfig.AccessConfig,
 		exit.Error(ErrorClusterInfo(out))
 	}
 
-	fmt.Println()
+	if !_flagOutput.IsMachineReadable() {
+		fmt.Println()
+	}
 
 	var operatorEndpoint string
 	for _, line := range strings.Split(out, "\n") {
@@ -767,6 +777,10 @@ func printInfoClusterState(awsClient *aws.Client, accessConfig *clusterconfig.Ac
 		return err
 	}
 
+	if _flagOutput.IsMachineReadable() {
+		return clusterstate.AssertClusterStatus(*accessConfig.ClusterName, *accessConfig.Region, clusterState.Status, clusterstate.StatusCreateComplete)
+	}
+
 	fmt.Println(clusterState.TableString())
 	if clusterState.Status == clusterstate.StatusCreateFailed || clusterState.Status == clusterstate.StatusDeleteFailed {
 		fmt.Println(fmt.Sprintf("more information can be found in your AWS console: %s", clusterstate.CloudFormationURL(*accessConfig.ClusterName, *accessConfig.Region)))
@@ -782,7 +796,9 @@ func printInfoClusterState(awsClient *aws.Client, accessConfig *clusterconfig.Ac
 }
 
 func printInfoOperatorResponse(clusterConfig clusterconfig.Config, operatorEndpoint string, awsCreds AWSCredentials) error {
-	fmt.Print("fetching cluster status ...\n\n")
+	if !_flagOutput.IsMachineReadable() {
+		fmt.Print("fetching cluster status ...\n\n")
+	}
 
 	operatorConfig := cluster.OperatorConfig{
 		Telemetry:          isTelemetryEnabled(),
@@ -794,11 +810,17 @@ func printInfoOperatorResponse(clusterConfig clusterconfig.Config, operatorEndpo
 
 	infoResponse, err := cluster.Info(operatorConfig)
 	if err != nil {
-		fmt.Println(clusterConfig.UserStr())
+		if !_flagOutput.IsMachineReadable() {
+			fmt.Println(clusterConfig.UserStr())
+		}
 		return err
 	}
 	infoResponse.ClusterConfig.Config = clusterConfig
 
+	if _flagOutput.IsMachineReadable() {
+		return printOutput(infoResponse)
+	}
+
 	printInfoClusterConfig(infoResponse)
 	printInfoPricing(infoResponse, clusterConfig)
 	printInfoNodes(infoResponse)
@@ -814,11 +836,15 @@ func printInfoClusterConfig(infoResponse *schema.InfoResponse) {
 }
 
 func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterconfig.Config) {
-	numAPIInstances := len(infoResponse.NodeInfos)
-
-	var totalAPIInstancePrice float64
+	numAPIInstances := make(map[string]int)           // node group name -> number of instances
+	totalAPIInstancePrice := make(map[string]float64) // node group name -> total instance price
 	for _, nodeInfo := range infoResponse.NodeInfos {
-		totalAPIInstancePrice += nodeInfo.Price
+		nodeGroupName := nodeInfo.NodeGroup
+		if nodeGroupName == "" {
+			nodeGroupName = clusterconfig.PrimaryNodeGroupName
+		}
+		numAPIInstances[nodeGroupName]++
+		totalAPIInstancePrice[nodeGroupName] += nodeInfo.Price
 	}
 
 	eksPrice := aws.EKSPrices[*clusterConfig.Region]
@@ -826,10 +852,6 @@ func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterco
 	operatorEBSPrice := aws.EBSMetadatas[*clusterConfig.Region]["gp2"].PriceGB * 20 / 30 / 24
 	nlbPrice := aws.NLBMetadatas[*clusterConfig.Region].Price
 	natUnitPrice := aws.NATMetadatas[*clusterConfig.Region].Price
-	apiEBSPrice := aws.EBSMetadatas[*clusterConfig.Region][clusterConfig.InstanceVolumeType.String()].PriceGB * float64(clusterConfig.InstanceVolumeSize) / 30 / 24
-	if clusterConfig.InstanceVolumeType.String() == "io1" && clusterConfig.InstanceVolumeIOPS != nil {
-		apiEBSPrice += aws.EBSMetadatas[*clusterConfig.Region][clusterConfig.InstanceVolumeType.String()].PriceIOPS * float64(*clusterConfig.InstanceVolumeIOPS) / 30 / 24
-	}
 
 	var natTotalPrice float64
 	if clusterConfig.NATGateway == clusterconfig.SingleNATGateway {
@@ -838,7 +860,10 @@ func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterco
 		natTotalPrice = natUnitPrice * float64(len(clusterConfig.AvailabilityZones))
 	}
 
-	totalPrice := eksPrice + totalAPIInstancePrice + apiEBSPrice*float64(numAPIInstances) + operatorInstancePrice + operatorEBSPrice + nlbPrice*2 + natTotalPrice
+	totalPrice := eksPrice + operatorInstancePrice + operatorEBSPrice + nlbPrice*2 + natTotalPrice
+	for _, nodeGroup := range clusterConfig.AllNodeGroups() {
+		totalPrice += totalAPIInstancePrice[nodeGroup.Name] + nodeGroup.EBSPrice(*clusterConfig.Region)*float64(numAPIInstances[nodeGroup.Name])
+	}
 	fmt.Printf(console.Bold("\nyour cluster currently costs %s per hour\n\n"), s.DollarsAndCents(totalPrice))
 
 	headers := []table.Header{
@@ -848,8 +873,18 @@ func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterco
 
 	var rows [][]interface{}
 	rows = append(rows, []interface{}{"1 eks cluster", s.DollarsMaxPrecision(eksPrice)})
-	rows = append(rows, []interface{}{fmt.Sprintf("%d %s for your apis", numAPIInstances, s.PluralS("instance", numAPIInstances)), s.DollarsAndTenthsOfCents(totalAPIInstancePrice) + " total"})
-	rows = append(rows, []interface{}{fmt.Sprintf("%d %dgb ebs %s for your apis", numAPIInstances, clusterConfig.InstanceVolumeSize, s.PluralS("volume", numAPIInstances)), s.DollarsAndTenthsOfCents(apiEBSPrice*float64(numAPIInstances)) + " total"})
+	for _, nodeGroup := range clusterConfig.AllNodeGroups() {
+		numInstances := numAPIInstances[nodeGroup.Name]
+		apiEBSPrice := nodeGroup.EBSPrice(*clusterConfig.Region)
+
+		forStr := "for your apis"
+		if len(clusterConfig.NodeGroups) > 0 {
+			forStr = fmt.Sprintf("for your apis (%s node group)", nodeGroup.Name)
+		}
+
+		rows = append(rows, []interface{}{fmt.Sprintf("%d %s %s", numInstances, s.PluralS("instance", numInstances), forStr), s.DollarsAndTenthsOfCents(totalAPIInstancePrice[nodeGroup.Name]) + " total"})
+		rows = append(rows, []interface{}{fmt.Sprintf("%d %dgb ebs %s %s", numInstances, nodeGroup.InstanceVolumeSize, s.PluralS("volume", numInstances), forStr), s.DollarsAndTenthsOfCents(apiEBSPrice*float64(numInstances)) + " total"})
+	}
 	rows = append(rows, []interface{}{"1 t3.medium instance for the operator", s.DollarsMaxPrecision(operatorInstancePrice)})
 	rows = append(rows, []interface{}{"1 20gb ebs volume for the operator", s.DollarsAndTenthsOfCents(operatorEBSPrice)})
 	rows = append(rows, []interface{}{"2 network load balancers", s.DollarsMaxPrecision(nlbPrice*2) + " total"})
@@ -872,9 +907,12 @@ func printInfoNodes(infoResponse *schema.InfoResponse) {
 	numAPIInstances := len(infoResponse.NodeInfos)
 
 	var totalReplicas int
-	var doesClusterHaveGPUs, doesClusterHaveInfs bool
+	var doesClusterHaveGPUs, doesClusterHaveInfs, doesClusterHaveNodeGroups bool
 	for _, nodeInfo := range infoResponse.NodeInfos {
 		totalReplicas += nodeInfo.NumReplicas
+		if nodeInfo.NodeGroup != "" && nodeInfo.NodeGroup != clusterconfig.PrimaryNodeGroupName {
+			doesClusterHaveNodeGroups = true
+		}
 		if nodeInfo.ComputeUserCapacity.GPU > 0 {
 			doesClusterHaveGPUs = true
 		}
@@ -895,6 +933,7 @@ func printInfoNodes(infoResponse *schema.InfoResponse) {
 	}
 
 	headers := []table.Header{
+		{Title: "node group", Hidden: !doesClusterHaveNodeGroups},
 		{Title: "instance type"},
 		{Title: "lifecycle"},
 		{Title: "replicas"},
@@ -915,7 +954,7 @@ func printInfoNodes(infoResponse *schema.InfoResponse) {
 		memStr := nodeInfo.ComputeUserRequested.Mem.String() + " / " + nodeInfo.ComputeUserCapacity.Mem.String()
 		gpuStr := s.Int64(nodeInfo.ComputeUserRequested.GPU) + " / " + s.Int64(nodeInfo.ComputeUserCapacity.GPU)
 		infStr := s.Int64(nodeInfo.ComputeUserRequested.Inf) + " / " + s.Int64(nodeInfo.ComputeUserCapacity.Inf)
-		rows = append(rows, []interface{}{nodeInfo.InstanceType, lifecycle, nodeInfo.NumReplicas, cpuStr, memStr, gpuStr, infStr})
+		rows = append(rows, []interface{}{nodeInfo.NodeGroup, nodeInfo.InstanceType, lifecycle, nodeInfo.NumReplicas, cpuStr, memStr, gpuStr, infStr})
 	}
 
 	t := table.Table{
@@ -24,6 +24,7 @@ import (
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -54,6 +55,7 @@ var (
 func clusterGCPInit() {
 	_clusterGCPUpCmd.Flags().SortFlags = false
 	addClusterGCPConfigFlag(_clusterGCPUpCmd)
+	addFailFastFlag(_clusterGCPUpCmd)
 	defaultEnv := getDefaultEnv(_clusterGCPCommandType)
 	_clusterGCPUpCmd.Flags().StringVarP(&_flagClusterGCPUpEnv, "configure-env", "e", defaultEnv, "name of environment to configure")
 	addClusterGCPDisallowPromptFlag(_clusterGCPUpCmd)
@@ -66,6 +68,7 @@ func clusterGCPInit() {
 	addClusterGCPZoneFlag(_clusterGCPInfoCmd)
 	_clusterGCPInfoCmd.Flags().StringVarP(&_flagClusterGCPInfoEnv, "configure-env", "e", "", "name of environment to configure")
 	_clusterGCPInfoCmd.Flags().BoolVarP(&_flagClusterGCPInfoDebug, "debug", "d", false, "save the current cluster state to a file")
+	_clusterGCPInfoCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 	_clusterGCPInfoCmd.Flags().BoolVarP(&_flagClusterGCPDisallowPrompt, "yes", "y", false, "skip prompts")
 	_clusterGCPCmd.AddCommand(_clusterGCPInfoCmd)
 
@@ -204,6 +207,10 @@ var _clusterGCPInfoCmd = &cobra.Command{
 			exit.Error(ErrorLocalEnvironmentCantUseClusterProvider(types.GCPProviderType))
 		}
 
+		if _flagClusterGCPInfoEnv != "" && _flagOutput.IsMachineReadable() {
+			exit.Error(ErrorConflictingFlags("--configure-env", "--output "+_flagOutput.String()))
+		}
+
 		if _, err := docker.GetDockerClient(); err != nil {
 			exit.Error(err)
 		}
@@ -298,7 +305,9 @@ var _clusterGCPDownCmd = &cobra.Command{
 }
 
 func cmdInfoGCP(accessConfig *clusterconfig.GCPAccessConfig, disallowPrompt bool) {
-	fmt.Print("fetching cluster endpoints ...\n\n")
+	if !_flagOutput.IsMachineReadable() {
+		fmt.Print("fetching cluster endpoints ...\n\n")
+	}
 	out, exitCode, err := runGCPManagerAccessCommand("/root/info_gcp.sh", *accessConfig, nil, nil)
 	if err != nil {
 		exit.Error(err)
@@ -307,7 +316,9 @@ func cmdInfoGCP(accessConfig *clusterconfig.GCPAccessConfig, disallowPrompt bool
 		exit.Error(ErrorClusterInfo(out))
 	}
 
-	fmt.Println()
+	if !_flagOutput.IsMachineReadable() {
+		fmt.Println()
+	}
 
 	var operatorEndpoint string
 	for _, line := range strings.Split(out, "\n") {
@@ -330,7 +341,9 @@ func cmdInfoGCP(accessConfig *clusterconfig.GCPAccessConfig, disallowPrompt bool
 }
 
 func printInfoOperatorResponseGCP(accessConfig *clusterconfig.GCPAccessConfig, operatorEndpoint string) error {
-	fmt.Print("fetching cluster status ...\n\n")
+	if !_flagOutput.IsMachineReadable() {
+		fmt.Print("fetching cluster status ...\n\n")
+	}
 
 	operatorConfig := cluster.OperatorConfig{
 		Telemetry:        isTelemetryEnabled(),
@@ -343,6 +356,10 @@ func printInfoOperatorResponseGCP(accessConfig *clusterconfig.GCPAccessConfig, o
 		return err
 	}
 
+	if _flagOutput.IsMachineReadable() {
+		return printOutput(infoResponse)
+	}
+
 	infoResponse.ClusterConfig.UserTable().Print()
 
 	return nil
@@ -0,0 +1,414 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/docker/docker/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+const (
+	_dashboardRefreshPeriod   = 2 * time.Second
+	_dashboardRenderPeriod    = 250 * time.Millisecond
+	_dashboardRequestRateSpan = time.Minute // the request rate is averaged over this period
+	_dashboardMaxLogLines     = 1000
+	_dashboardMaxEvents       = 5
+)
+
+var _flagDashboardEnv string
+
+func dashboardInit() {
+	_dashboardCmd.Flags().SortFlags = false
+	_dashboardCmd.Flags().StringVarP(&_flagDashboardEnv, "env", "e", "", "only show the apis in this environment (by default, the apis in all environments are shown)")
+}
+
+var _dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "interactively monitor your apis",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.dashboard")
+
+		if !term.IsTerminal(os.Stdin.Fd()) || !term.IsTerminal(os.Stdout.Fd()) {
+			exit.Error(ErrorDashboardRequiresTerminal())
+		}
+
+		var envs []cliconfig.Environment
+		if _flagDashboardEnv != "" {
+			env, err := ReadOrConfigureEnv(_flagDashboardEnv)
+			if err != nil {
+				exit.Error(err)
+			}
+			envs = append(envs, env)
+		} else {
+			cliConfig, err := readCLIConfig()
+			if err != nil {
+				exit.Error(err)
+			}
+			for _, env := range cliConfig.Environments {
+				envs = append(envs, *env)
+			}
+		}
+
+		if err := newDashboard(envs).run(); err != nil {
+			exit.Error(err)
+		}
+	},
+}
+
+type dashboard struct {
+	sync.Mutex
+	envs            []cliconfig.Environment
+	operatorConfigs map[string]cluster.OperatorConfig // env name -> operator config (not set for the local environment)
+	apis            []dashboardAPI                    // sorted by environment, then by name
+	envErrors       map[string]error
+	requestSamples  map[string][]requestSample // "env/api" -> recent request counts
+	lastRefresh     time.Time
+	selected        int
+	detail          *dashboardDetail // nil when the list of apis is shown
+}
+
+type dashboardAPI struct {
+	env         cliconfig.Environment
+	api         schema.APIResponse
+	requestRate *float64 // requests per second; nil until there are enough samples
+}
+
+type requestSample struct {
+	time  time.Time
+	total int
+}
+
+// dashboardDetail is the state of the view of a single api
+type dashboardDetail struct {
+	env         cliconfig.Environment
+	apiName     string
+	logLines    []string
+	events      []events.Event // most recent last
+	logStream   *cluster.Stream
+	eventStream *cluster.Stream
+	streamErr   error
+}
+
+func newDashboard(envs []cliconfig.Environment) *dashboard {
+	operatorConfigs := map[string]cluster.OperatorConfig{}
+	for _, env := range envs {
+		if env.Provider != types.LocalProviderType {
+			// this may exit, so it's done before the terminal is put in raw mode
+			operatorConfigs[env.Name] = MustGetOperatorConfig(env.Name)
+		}
+	}
+
+	return &dashboard{
+		envs:            envs,
+		operatorConfigs: operatorConfigs,
+		envErrors:       map[string]error{},
+		requestSamples:  map[string][]requestSample{},
+	}
+}
+
+func (d *dashboard) run() error {
+	stdinFd := os.Stdin.Fd()
+	state, err := term.MakeRaw(stdinFd)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer term.RestoreTerminal(stdinFd, state)
+
+	os.Stdout.WriteString(_ansiEnterAltScreen + _ansiHideCursor)
+	defer os.Stdout.WriteString(_ansiShowCursor + _ansiExitAltScreen)
+
+	defer d.closeDetail()
+
+	go func() {
+		for {
+			d.refresh()
+			time.Sleep(_dashboardRefreshPeriod)
+		}
+	}()
+
+	keys := readDashboardKeys()
+	ticker := time.NewTicker(_dashboardRenderPeriod)
+	defer ticker.Stop()
+
+	for {
+		d.draw()
+
+		select {
+		case key, ok := <-keys:
+			if !ok || d.handleKey(key) {
+				return nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the apis in all environments
+func (d *dashboard) refresh() {
+	var apis []dashboardAPI
+	envErrors := map[string]error{}
+
+	for _, env := range d.envs {
+		var apisRes []schema.APIResponse
+		var err error
+		if env.Provider == types.LocalProviderType {
+			apisRes, err = local.GetAPIs()
+		} else {
+			apisRes, err = cluster.GetAPIs(d.operatorConfigs[env.Name])
+		}
+		if err != nil {
+			envErrors[env.Name] = err
+			continue
+		}
+
+		var envAPIs []dashboardAPI
+		for _, api := range apisRes {
+			if api.Spec.Kind == userconfig.RealtimeAPIKind && api.Status != nil {
+				envAPIs = append(envAPIs, dashboardAPI{env: env, api: api})
+			}
+		}
+		sort.Slice(envAPIs, func(i, j int) bool {
+			return envAPIs[i].api.Spec.Name < envAPIs[j].api.Spec.Name
+		})
+		apis = append(apis, envAPIs...)
+	}
+
+	now := time.Now()
+
+	d.Lock()
+	defer d.Unlock()
+
+	// keep the same api selected if its position changed
+	var selectedKey string
+	if d.selected < len(d.apis) {
+		selectedKey = d.apis[d.selected].key()
+	}
+
+	for i := range apis {
+		apis[i].requestRate = d.recordRequests(apis[i], now)
+		if apis[i].key() == selectedKey {
+			d.selected = i
+		}
+	}
+
+	d.apis = apis
+	d.envErrors = envErrors
+	d.lastRefresh = now
+	if d.selected >= len(d.apis) {
+		d.selected = len(d.apis) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+}
+
+func (dAPI dashboardAPI) key() string {
+	return dAPI.env.Name + "/" + dAPI.api.Spec.Name
+}
+
+// recordRequests records the api's total request count, and returns its average request rate over the recent samples
+func (d *dashboard) recordRequests(dAPI dashboardAPI, now time.Time) *float64 {
+	if dAPI.api.Metrics == nil || dAPI.api.Metrics.NetworkStats == nil {
+		return nil
+	}
+
+	key := dAPI.key()
+	samples := append(d.requestSamples[key], requestSample{time: now, total: dAPI.api.Metrics.NetworkStats.Total})
+	for len(samples) > 2 && now.Sub(samples[0].time) > _dashboardRequestRateSpan {
+		samples = samples[1:]
+	}
+	d.requestSamples[key] = samples
+
+	oldest := samples[0]
+	latest := samples[len(samples)-1]
+	elapsed := latest.time.Sub(oldest.time).Seconds()
+	if elapsed <= 0 || latest.total < oldest.total {
+		return nil
+	}
+
+	rate := float64(latest.total-oldest.total) / elapsed
+	return &rate
+}
+
+// handleKey returns true if the dashboard should exit
+func (d *dashboard) handleKey(key string) bool {
+	if key == _keyCtrlC || key == "q" {
+		return true
+	}
+
+	d.Lock()
+	inDetail := d.detail != nil
+	d.Unlock()
+
+	if inDetail {
+		if key == _keyEscape || key == _keyBackspace || key == _keyLeft {
+			d.closeDetail()
+		}
+		return false
+	}
+
+	switch key {
+	case _keyUp, "k":
+		d.Lock()
+		if d.selected > 0 {
+			d.selected--
+		}
+		d.Unlock()
+	case _keyDown, "j":
+		d.Lock()
+		if d.selected < len(d.apis)-1 {
+			d.selected++
+		}
+		d.Unlock()
+	case _keyEnter, _keyRight:
+		d.openDetail()
+	}
+
+	return false
+}
+
+// openDetail shows the selected api, and starts streaming its logs and events
+func (d *dashboard) openDetail() {
+	d.Lock()
+	if d.selected >= len(d.apis) {
+		d.Unlock()
+		return
+	}
+	dAPI := d.apis[d.selected]
+	detail := &dashboardDetail{
+		env:     dAPI.env,
+		apiName: dAPI.api.Spec.Name,
+	}
+	d.detail = detail
+	d.Unlock()
+
+	if dAPI.env.Provider == types.LocalProviderType {
+		return
+	}
+
+	// the streams are opened in the background so that the dashboard stays responsive
+	go func() {
+		operatorConfig := d.operatorConfigs[dAPI.env.Name]
+
+		logStream, err := cluster.OpenLogStream(operatorConfig, detail.apiName, func(message string) {
+			d.addLogLine(detail, message)
+		})
+		if err != nil {
+			d.setStreamErr(detail, err)
+			return
+		}
+
+		eventStream, err := cluster.OpenEventStream(operatorConfig, detail.apiName, func(event events.Event) {
+			if event.Type == events.Autoscaling {
+				d.addEvent(detail, event)
+			}
+		})
+		if err != nil {
+			logStream.Close()
+			d.setStreamErr(detail, err)
+			return
+		}
+
+		d.Lock()
+		defer d.Unlock()
+		if d.detail != detail {
+			// the view was closed while the streams were being opened
+			go logStream.Close()
+			go eventStream.Close()
+			return
+		}
+		detail.logStream = logStream
+		detail.eventStream = eventStream
+	}()
+}
+
+func (d *dashboard) closeDetail() {
+	d.Lock()
+	detail := d.detail
+	d.detail = nil
+	d.Unlock()
+
+	if detail == nil {
+		return
+	}
+	if detail.logStream != nil {
+		detail.logStream.Close()
+	}
+	if detail.eventStream != nil {
+		detail.eventStream.Close()
+	}
+}
+
+func (d *dashboard) addLogLine(detail *dashboardDetail, message string) {
+	d.Lock()
+	defer d.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+		detail.logLines = append(detail.logLines, line)
+	}
+	if len(detail.logLines) > _dashboardMaxLogLines {
+		detail.logLines = detail.logLines[len(detail.logLines)-_dashboardMaxLogLines:]
+	}
+}
+
+func (d *dashboard) addEvent(detail *dashboardDetail, event events.Event) {
+	d.Lock()
+	defer d.Unlock()
+	detail.events = append(detail.events, event)
+	if len(detail.events) > _dashboardMaxEvents {
+		detail.events = detail.events[len(detail.events)-_dashboardMaxEvents:]
+	}
+}
+
+func (d *dashboard) setStreamErr(detail *dashboardDetail, err error) {
+	d.Lock()
+	defer d.Unlock()
+	detail.streamErr = err
+}
+
+// readDashboardKeys reads key presses from stdin (which must be in raw mode); the channel is closed if stdin is closed
+func readDashboardKeys() <-chan string {
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			keys <- string(buf[:n])
+		}
+	}()
+	return keys
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
@@ -80,22 +79,23 @@ var _deleteCmd = &cobra.Command{
 			}
 		} else {
 			if len(args) == 2 {
-				exit.Error(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot delete job %s for api %s", args[1], args[0]))
-			}
-
-			// local only supports deploying 1 replica at a time, so _flagDeleteForce is only useful when attempting to delete an API that has been deployed with different CLI version
-			deleteResponse, err = local.Delete(args[0], _flagDeleteKeepCache, _flagDeleteForce)
-			if err != nil {
-				exit.Error(err)
+				deleteResponse, err = local.StopJob(args[0], args[1])
+				if err != nil {
+					exit.Error(err)
+				}
+			} else {
+				// local only supports deploying 1 replica at a time, so _flagDeleteForce is only useful when attempting to delete an API that has been deployed with different CLI version
+				deleteResponse, err = local.Delete(args[0], _flagDeleteKeepCache, _flagDeleteForce)
+				if err != nil {
+					exit.Error(err)
+				}
 			}
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(deleteResponse)
-			if err != nil {
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(deleteResponse); err != nil {
 				exit.Error(err)
 			}
-			fmt.Print(string(bytes))
 			return
 		}
 
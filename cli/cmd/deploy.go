@@ -18,17 +18,20 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/archive"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
@@ -37,6 +40,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/spf13/cobra"
 )
@@ -52,6 +56,9 @@ var (
 	_flagDeployEnv            string
 	_flagDeployForce          bool
 	_flagDeployDisallowPrompt bool
+	_flagDeployWait           bool
+	_flagDeployWaitTimeout    time.Duration
+	_flagDeployValues         string
 )
 
 func deployInit() {
@@ -59,6 +66,10 @@ func deployInit() {
 	_deployCmd.Flags().StringVarP(&_flagDeployEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
 	_deployCmd.Flags().BoolVarP(&_flagDeployForce, "force", "f", false, "override the in-progress api update")
 	_deployCmd.Flags().BoolVarP(&_flagDeployDisallowPrompt, "yes", "y", false, "skip prompts")
+	_deployCmd.Flags().BoolVarP(&_flagDeployWait, "wait", "w", false, "wait for the apis to finish rolling out, and exit with an error if the rollout fails")
+	_deployCmd.Flags().DurationVar(&_flagDeployWaitTimeout, "wait-timeout", 15*time.Minute, "maximum amount of time to wait for the rollout (used with --wait)")
+	_deployCmd.Flags().StringVar(&_flagDeployValues, "values", "", "path to a yaml file of variables used to resolve ${VAR} references in the configuration file (takes precedence over environment variables)")
+	addFailFastFlag(_deployCmd)
 	_deployCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 }
 
@@ -79,6 +90,10 @@ var _deployCmd = &cobra.Command{
 			exit.Error(err)
 		}
 
+		if _flagDeployWait && env.Provider == types.LocalProviderType {
+			exit.Error(ErrorFlagNotSupportedInLocalEnvironment("--wait"))
+		}
+
 		configPath := getConfigPath(args)
 
 		projectRoot := files.Dir(configPath)
@@ -89,37 +104,40 @@ var _deployCmd = &cobra.Command{
 			exit.Error(ErrorDeployFromTopLevelDir("root", env.Provider))
 		}
 
+		configBytes, err := readConfigBytes(configPath, env.Name, _flagDeployValues)
+		if err != nil {
+			exit.Error(err)
+		}
+
 		var deployResults []schema.DeployResult
 		if env.Provider == types.LocalProviderType {
-			projectFiles, err := findProjectFiles(env.Provider, configPath)
+			projectFiles, err := findProjectFiles(env.Provider, configPath, env.Name, _flagDeployValues)
 			if err != nil {
 				exit.Error(err)
 			}
 
 			local.OutputType = _flagOutput // Set output type for the Local package
-			deployResults, err = local.Deploy(env, configPath, projectFiles, _flagDeployDisallowPrompt)
+			deployResults, err = local.Deploy(env, configPath, configBytes, projectFiles, _flagDeployDisallowPrompt)
 			if err != nil {
-				exit.Error(err)
+				exit.Error(applyFailFast(err))
 			}
 		} else {
-			deploymentBytes, err := getDeploymentBytes(env.Provider, configPath)
+			deploymentBytes, err := getDeploymentBytes(env.Provider, configPath, env.Name, configBytes)
 			if err != nil {
 				exit.Error(err)
 			}
 
-			deployResults, err = cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce)
+			deployResults, err = cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce, _flagFailFast)
 			if err != nil {
 				exit.Error(err)
 			}
 		}
 
 		switch _flagOutput {
-		case flags.JSONOutputType:
-			bytes, err := libjson.Marshal(deployResults)
-			if err != nil {
+		case flags.JSONOutputType, flags.YAMLOutputType:
+			if err := printOutput(deployResults); err != nil {
 				exit.Error(err)
 			}
-			fmt.Print(string(bytes))
 		case flags.MixedOutputType:
 			err := mixedPrint(deployResults)
 			if err != nil {
@@ -137,6 +155,13 @@ var _deployCmd = &cobra.Command{
 		if didAnyResultsError(deployResults) {
 			exit.Error(nil)
 		}
+
+		if _flagDeployWait {
+			err := waitForRollouts(MustGetOperatorConfig(env.Name), deployResults, _flagDeployWaitTimeout)
+			if err != nil {
+				exit.Error(err)
+			}
+		}
 	},
 }
 
@@ -159,11 +184,69 @@ func getConfigPath(args []string) string {
 	return files.RelToAbsPath(configPath, _cwd)
 }
 
-func findProjectFiles(provider types.ProviderType, configPath string) ([]string, error) {
+// Reads the configuration file, resolves variable references using the values file (if provided) and the environment variables,
+// and merges the environment's overlay file (e.g. cortex.<env_name>.yaml) on top of it if it exists (variables are resolved in
+// each file before merging, since whether a reference is quoted determines its type)
+func readConfigBytes(configPath string, envName string, valuesPath string) ([]byte, error) {
+	lookup := cr.VariableLookupFn(os.LookupEnv)
+	if valuesPath != "" {
+		values, err := cr.ReadVariablesFile(files.RelToAbsPath(valuesPath, _cwd))
+		if err != nil {
+			return nil, err
+		}
+		lookup = cr.LookupFnFromMaps(os.LookupEnv, values)
+	}
+
+	configBytes, err := files.ReadFileBytes(configPath)
+	if err != nil {
+		return nil, err
+	}
+	configBytes, err = spec.InterpolateAPIConfigs(configBytes, filepath.Base(configPath), lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayPath := getConfigOverlayPath(configPath, envName)
+	if !files.IsFile(overlayPath) {
+		return configBytes, nil
+	}
+
+	overlayBytes, err := files.ReadFileBytes(overlayPath)
+	if err != nil {
+		return nil, err
+	}
+	overlayBytes, err = spec.InterpolateAPIConfigs(overlayBytes, filepath.Base(overlayPath), lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.MergeAPIConfigOverlay(configBytes, filepath.Base(configPath), overlayBytes, filepath.Base(overlayPath))
+}
+
+// e.g. cortex.yaml -> cortex.<env_name>.yaml
+func getConfigOverlayPath(configPath string, envName string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + envName + ext
+}
+
+// getConfigFilePaths returns the paths of the files which readConfigBytes() reads (the configuration file, the environment's overlay file if it exists, and the values file if provided)
+func getConfigFilePaths(configPath string, envName string, valuesPath string) []string {
+	configFilePaths := []string{configPath}
+	if overlayPath := getConfigOverlayPath(configPath, envName); files.IsFile(overlayPath) {
+		configFilePaths = append(configFilePaths, overlayPath)
+	}
+	if valuesPath != "" {
+		configFilePaths = append(configFilePaths, files.RelToAbsPath(valuesPath, _cwd))
+	}
+	return configFilePaths
+}
+
+// the configuration files which are read for the environment are not included in the project files (the merged configuration is sent separately)
+func findProjectFiles(provider types.ProviderType, configPath string, envName string, valuesPath string) ([]string, error) {
 	projectRoot := files.Dir(configPath)
 
 	ignoreFns := []files.IgnoreFn{
-		files.IgnoreSpecificFiles(configPath),
+		files.IgnoreSpecificFiles(getConfigFilePaths(configPath, envName, valuesPath)...),
 		files.IgnoreCortexDebug,
 		files.IgnoreHiddenFiles,
 		files.IgnoreHiddenFolders,
@@ -204,19 +287,14 @@ func findProjectFiles(provider types.ProviderType, configPath string) ([]string,
 	return projectPaths, nil
 }
 
-func getDeploymentBytes(provider types.ProviderType, configPath string) (map[string][]byte, error) {
-	configBytes, err := files.ReadFileBytes(configPath)
-	if err != nil {
-		return nil, err
-	}
-
+func getDeploymentBytes(provider types.ProviderType, configPath string, envName string, configBytes []byte) (map[string][]byte, error) {
 	uploadBytes := map[string][]byte{
 		"config": configBytes,
 	}
 
 	projectRoot := files.Dir(configPath)
 
-	projectPaths, err := findProjectFiles(provider, configPath)
+	projectPaths, err := findProjectFiles(provider, configPath, envName, _flagDeployValues)
 	if err != nil {
 		return nil, err
 	}
@@ -339,6 +417,16 @@ func getAPICommandsMessage(results []schema.DeployResult, envName string) string
 		}
 	}
 
+	for _, result := range results {
+		if len(result.Error) > 0 {
+			continue
+		}
+		if result.API != nil && result.API.Spec.Kind == userconfig.BatchAPIKind {
+			items.Add(fmt.Sprintf("cortex job submit %s SUBMISSION_FILE%s", apiName, envArg), "(submit a job)")
+			break
+		}
+	}
+
 	return strings.TrimSpace(items.String(&table.KeyValuePairOpts{
 		Delimiter: pointer.String(""),
 		NumSpaces: pointer.Int(2),
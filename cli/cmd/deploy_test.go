@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectFilesExcludesConfigFiles(t *testing.T) {
+	projectRoot, err := ioutil.TempDir("", "cortex-deploy-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(projectRoot) })
+
+	for _, fileName := range []string{"cortex.yaml", "cortex.prod.yaml", "cortex.dev.yaml", "values.yaml", "predictor.py", "requirements.txt"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectRoot, fileName), []byte(""), 0644))
+	}
+	configPath := filepath.Join(projectRoot, "cortex.yaml")
+
+	projectFiles, err := findProjectFiles(types.LocalProviderType, configPath, "prod", filepath.Join(projectRoot, "values.yaml"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(projectRoot, "cortex.dev.yaml"),
+		filepath.Join(projectRoot, "predictor.py"),
+		filepath.Join(projectRoot, "requirements.txt"),
+	}, projectFiles)
+
+	// overlay files are only excluded for the environment which is being deployed to
+	projectFiles, err = findProjectFiles(types.LocalProviderType, configPath, "staging", "")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(projectRoot, "cortex.prod.yaml"),
+		filepath.Join(projectRoot, "cortex.dev.yaml"),
+		filepath.Join(projectRoot, "values.yaml"),
+		filepath.Join(projectRoot, "predictor.py"),
+		filepath.Join(projectRoot, "requirements.txt"),
+	}, projectFiles)
+}
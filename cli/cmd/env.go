@@ -23,7 +23,6 @@ import (
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/types"
@@ -129,12 +128,10 @@ var _envListCmd = &cobra.Command{
 			exit.Error(err)
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(cliConfig.Environments)
-			if err != nil {
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(cliConfig.Environments); err != nil {
 				exit.Error(err)
 			}
-			fmt.Print(string(bytes))
 			return
 		}
 
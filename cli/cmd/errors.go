@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -76,6 +77,24 @@ const (
 	ErrShellCompletionNotSupported             = "cli.shell_completion_not_supported"
 	ErrNoTerminalWidth                         = "cli.no_terminal_width"
 	ErrDeployFromTopLevelDir                   = "cli.deploy_from_top_level_dir"
+	ErrFlagNotSupportedInLocalEnvironment      = "cli.flag_not_supported_in_local_environment"
+	ErrAPIRolloutFailed                        = "cli.api_rollout_failed"
+	ErrAPIRolloutTimeout                       = "cli.api_rollout_timeout"
+	ErrInvalidLogTime                          = "cli.invalid_log_time"
+	ErrLogSearchTimeRange                      = "cli.log_search_time_range"
+	ErrLogSearchNotSupportedForJobs            = "cli.log_search_not_supported_for_jobs"
+	ErrInvalidLogSource                        = "cli.invalid_log_source"
+	ErrLogSourceNotSupportedForSearch          = "cli.log_source_not_supported_for_search"
+	ErrInvalidPort                             = "cli.invalid_port"
+	ErrLoadTestNoPayloads                      = "cli.load_test_no_payloads"
+	ErrInvalidFlagValue                        = "cli.invalid_flag_value"
+	ErrNoPredictInputs                         = "cli.no_predict_inputs"
+	ErrPredictionsFailed                       = "cli.predictions_failed"
+	ErrConflictingFlags                        = "cli.conflicting_flags"
+	ErrDashboardRequiresTerminal               = "cli.dashboard_requires_terminal"
+	ErrInitTemplateEmpty                       = "cli.init_template_empty"
+	ErrInvalidSchemaType                       = "cli.invalid_schema_type"
+	ErrInvalidAPIConfigFile                    = "cli.invalid_api_config_file"
 )
 
 func ErrorInvalidProvider(providerStr string) error {
@@ -346,3 +365,129 @@ func ErrorDeployFromTopLevelDir(genericDirName string, providerType types.Provid
 		Message: fmt.Sprintf("cannot deploy from your %s directory - when deploying your API, cortex sends all files in your project directory (i.e. the directory which contains cortex.yaml) to your %s (see https://docs.cortex.dev/v/%s/deployments/realtime-api/predictors#project-files for Realtime API and https://docs.cortex.dev/v/%s/deployments/batch-api/predictors#project-files for Batch API); therefore it is recommended to create a subdirectory for your project files", genericDirName, targetStr, consts.CortexVersionMinor, consts.CortexVersionMinor),
 	})
 }
+
+func ErrorFlagNotSupportedInLocalEnvironment(flag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFlagNotSupportedInLocalEnvironment,
+		Message: fmt.Sprintf("the %s flag is not supported in local environment", flag),
+	})
+}
+
+func ErrorAPIRolloutFailed(apiName string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIRolloutFailed,
+		Message: fmt.Sprintf("%s failed to roll out: %s", apiName, reason),
+	})
+}
+
+func ErrorAPIRolloutTimeout(apiNames []string, timeout time.Duration) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIRolloutTimeout,
+		Message: fmt.Sprintf("timed out after %s waiting for %s to roll out (run `cortex get` to check the status of your apis, or use the --wait-timeout flag to wait longer)", timeout.String(), s.StrsAnd(apiNames)),
+	})
+}
+
+func ErrorInvalidLogTime(flag string, value string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidLogTime,
+		Message: fmt.Sprintf("invalid value for %s: %s; specify a duration before now (e.g. 30m or 2h) or a timestamp (e.g. 2020-12-01T15:04:05Z)", flag, s.UserStr(value)),
+	})
+}
+
+func ErrorLogSearchTimeRange(since time.Time, until time.Time) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLogSearchTimeRange,
+		Message: fmt.Sprintf("--since (%s) must be before --until (%s)", since.Local().Format(time.RFC3339), until.Local().Format(time.RFC3339)),
+	})
+}
+
+func ErrorLogSearchNotSupportedForJobs() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLogSearchNotSupportedForJobs,
+		Message: "--since, --until, --grep, and --replica are not supported for job logs",
+	})
+}
+
+func ErrorInvalidLogSource(source string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidLogSource,
+		Message: fmt.Sprintf("invalid value for --source: %s (the only supported value is \"pods\")", s.UserStr(source)),
+	})
+}
+
+func ErrorLogSourceNotSupportedForSearch() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLogSourceNotSupportedForSearch,
+		Message: "--source cannot be combined with --since, --until, --grep, or --replica, since replicas only retain their recent logs",
+	})
+}
+
+func ErrorInvalidPort(port string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidPort,
+		Message: fmt.Sprintf("invalid port: %s (must be an integer between 1 and 65535)", s.UserStr(port)),
+	})
+}
+
+func ErrorLoadTestNoPayloads(dirPath string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLoadTestNoPayloads,
+		Message: fmt.Sprintf("%s does not contain any payload files", dirPath),
+	})
+}
+
+func ErrorInvalidFlagValue(flag string, requirement string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidFlagValue,
+		Message: fmt.Sprintf("%s must be %s", flag, requirement),
+	})
+}
+
+func ErrorNoPredictInputs(path string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoPredictInputs,
+		Message: fmt.Sprintf("%s does not contain any inputs", path),
+	})
+}
+
+func ErrorPredictionsFailed(numFailed int, numTotal int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPredictionsFailed,
+		Message: fmt.Sprintf("%d of %d %s failed (see the output for details)", numFailed, numTotal, s.PluralS("prediction", numTotal)),
+	})
+}
+
+func ErrorConflictingFlags(flag string, conflictingFlag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrConflictingFlags,
+		Message: fmt.Sprintf("%s cannot be combined with %s", flag, conflictingFlag),
+	})
+}
+
+func ErrorDashboardRequiresTerminal() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDashboardRequiresTerminal,
+		Message: "the dashboard can only be shown in an interactive terminal; use `cortex get --watch` instead",
+	})
+}
+
+func ErrorInitTemplateEmpty(templateDir string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInitTemplateEmpty,
+		Message: fmt.Sprintf("%s: template directory does not contain any files", templateDir),
+	})
+}
+
+func ErrorInvalidSchemaType(schemaType string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSchemaType,
+		Message: fmt.Sprintf("%s is not a valid schema type (api and cluster are supported)", s.UserStr(schemaType)),
+	})
+}
+
+func ErrorInvalidAPIConfigFile(configFileName string, numErrors int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidAPIConfigFile,
+		Message: fmt.Sprintf("%s is invalid (%d %s)", configFileName, numErrors, s.PluralS("error", numErrors)),
+	})
+}
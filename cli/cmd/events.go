@@ -0,0 +1,88 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/spf13/cobra"
+)
+
+var _flagEventsEnv string
+
+func eventsInit() {
+	_eventsCmd.Flags().SortFlags = false
+	_eventsCmd.Flags().StringVarP(&_flagEventsEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_eventsCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+}
+
+var _eventsCmd = &cobra.Command{
+	Use:   "events [API_NAME]",
+	Short: "stream deployment, scaling, and failure events for all apis or a specific api",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagEventsEnv)
+		if err != nil {
+			telemetry.Event("cli.events")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.events", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		if !_flagOutput.IsMachineReadable() {
+			err = printEnvIfNotSpecified(_flagEventsEnv, cmd)
+			if err != nil {
+				exit.Error(err)
+			}
+		}
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Append(ErrorNotSupportedInLocalEnvironment(), "; use `cortex logs` to view the logs of apis running locally"))
+		}
+
+		apiName := ""
+		if len(args) == 1 {
+			apiName = args[0]
+		}
+
+		err = cluster.StreamEvents(MustGetOperatorConfig(env.Name), apiName, printEvent)
+		if err != nil {
+			exit.Error(err)
+		}
+	},
+}
+
+func printEvent(event events.Event) {
+	if _flagOutput.IsMachineReadable() {
+		if err := printOutputItem(event); err != nil {
+			exit.Error(err)
+		}
+		return
+	}
+
+	timestamp := event.Timestamp.Local().Format(time.RFC3339)
+	fmt.Printf("%s  %s  %s  %s\n", console.Bold(timestamp), event.APIName, event.Type.String(), event.Message)
+}
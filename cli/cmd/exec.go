@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/docker/docker/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagExecEnv     string
+	_flagExecReplica string
+	_flagExecTTY     bool
+)
+
+func execInit() {
+	_execCmd.Flags().SortFlags = false
+	_execCmd.Flags().StringVarP(&_flagExecEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_execCmd.Flags().StringVar(&_flagExecReplica, "replica", "0", "the replica to run the command in (an index into the api's running replicas, or a replica name)")
+	_execCmd.Flags().BoolVarP(&_flagExecTTY, "tty", "t", true, "allocate a tty for the command (only applies when stdin is a terminal)")
+}
+
+var _execCmd = &cobra.Command{
+	Use:   "exec API_NAME -- COMMAND [ARGS...]",
+	Short: "run a command in a replica of an api",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagExecEnv)
+		if err != nil {
+			telemetry.Event("cli.exec")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.exec", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Append(ErrorNotSupportedInLocalEnvironment(), "; use `docker exec` to run commands in the containers of apis running locally"))
+		}
+
+		tty := _flagExecTTY && term.IsTerminal(os.Stdin.Fd())
+
+		exitCode, err := cluster.Exec(MustGetOperatorConfig(env.Name), args[0], _flagExecReplica, args[1:], tty)
+		if err != nil {
+			exit.Error(err)
+		}
+		exit.Code(exitCode)
+	},
+}
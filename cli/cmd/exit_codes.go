@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+)
+
+// exit codes by category of error (see docs/miscellaneous/cli.md); errors which don't fall into any of these categories exit with code 1
+const (
+	_exitCodeUsage         = 2 // invalid arguments or flags
+	_exitCodeInvalidConfig = 3 // invalid api or cluster configuration
+	_exitCodeNotFound      = 4 // the api, job, secret, or environment does not exist
+	_exitCodeConnection    = 5 // unable to connect or authenticate to the cluster (or to docker)
+	_exitCodeAPIFailure    = 6 // the api is not ready, its rollout failed, or predictions failed
+)
+
+// error kinds returned by the operator (the operator packages aren't imported by the cli, so exit_codes_test.go checks that these match)
+const (
+	_errOperationIsOnlySupportedForKind  = "resources.operation_is_only_supported_for_kind"
+	_errJobIDRequired                    = "resources.job_id_required"
+	_errCannotChangeTypeOfDeployedAPI    = "resources.cannot_change_kind_of_deployed_api"
+	_errRealtimeAPIUsedByTrafficSplitter = "resources.realtime_api_used_by_traffic_splitter"
+	_errNoAvailableNodeComputeLimit      = "resources.no_available_node_compute_limit"
+	_errAPINotDeployed                   = "resources.api_not_deployed"
+	_errAPIsNotDeployed                  = "resources.apis_not_deployed"
+	_errAPIIDNotFound                    = "resources.api_id_not_found"
+	_errSecretNotFound                   = "resources.secret_not_found"
+	_errSecretKeyNotFound                = "resources.secret_key_not_found"
+	_errBatchJobNotFound                 = "batchapi.job_not_found"
+	_errReplicaNotFound                  = "realtimeapi.replica_not_found"
+	_errAuthAPIError                     = "endpoints.auth_api_error"
+	_errAuthInvalid                      = "endpoints.auth_invalid"
+	_errAuthOtherAccount                 = "endpoints.auth_other_account"
+	_errAPIVersionMismatch               = "endpoints.api_version_mismatch"
+	_errLoadBalancerInitializing         = "operator.load_balancer_initializing"
+)
+
+func setErrorExitCodes() {
+	exit.SetErrorExitCode(_exitCodeUsage,
+		ErrInvalidProvider,
+		ErrNotSupportedInLocalEnvironment,
+		ErrLocalEnvironmentCantUseClusterProvider,
+		ErrCommandNotSupportedForKind,
+		ErrOneAWSFlagSet,
+		ErrOnlyAWSClusterFlagSet,
+		ErrClusterConfigOrPromptsRequired,
+		ErrClusterAccessConfigOrPromptsRequired,
+		ErrGCPClusterAccessConfigOrPromptsRequired,
+		ErrShellCompletionNotSupported,
+		ErrInvalidSchemaType,
+		ErrFlagNotSupportedInLocalEnvironment,
+		ErrInvalidLogTime,
+		ErrLogSearchTimeRange,
+		ErrLogSearchNotSupportedForJobs,
+		ErrInvalidLogSource,
+		ErrLogSourceNotSupportedForSearch,
+		ErrInvalidPort,
+		ErrLoadTestNoPayloads,
+		ErrInvalidFlagValue,
+		ErrNoPredictInputs,
+		ErrConflictingFlags,
+		ErrDashboardRequiresTerminal,
+		ErrInitTemplateEmpty,
+		local.ErrJobsNotSupportedForKind,
+		local.ErrJobIDRequired,
+		_errOperationIsOnlySupportedForKind,
+		_errJobIDRequired,
+	)
+
+	exit.SetErrorExitCode(_exitCodeInvalidConfig,
+		ErrCortexYAMLNotFound,
+		ErrCredentialsInClusterConfig,
+		ErrDeployFromTopLevelDir,
+		ErrInvalidAPIConfigFile,
+		local.ErrJobSubmissionRequiresItemList,
+		local.ErrAPIUsedByTrafficSplitter,
+		"spec",
+		"configreader",
+		"clusterconfig",
+		"userconfig",
+		_errCannotChangeTypeOfDeployedAPI,
+		_errRealtimeAPIUsedByTrafficSplitter,
+		_errNoAvailableNodeComputeLimit,
+	)
+
+	exit.SetErrorExitCode(_exitCodeNotFound,
+		ErrEnvironmentNotFound,
+		local.ErrAPINotDeployed,
+		local.ErrAPISpecNotFound,
+		local.ErrJobNotFound,
+		local.ErrTrafficSplitterAPIsNotDeployed,
+		_errAPINotDeployed,
+		_errAPIsNotDeployed,
+		_errAPIIDNotFound,
+		_errSecretNotFound,
+		_errSecretKeyNotFound,
+		_errBatchJobNotFound,
+		_errReplicaNotFound,
+	)
+
+	exit.SetErrorExitCode(_exitCodeConnection,
+		cluster.ErrFailedToConnectOperator,
+		cluster.ErrOperatorSocketRead,
+		ErrInvalidOperatorEndpoint,
+		ErrNoOperatorLoadBalancer,
+		ErrConnectToDockerDaemon,
+		ErrDockerPermissions,
+		docker.ErrConnectToDockerDaemon,
+		docker.ErrDockerPermissions,
+		ErrOneAWSEnvVarSet,
+		ErrOnlyAWSClusterEnvVarSet,
+		ErrMissingAWSCredentials,
+		_errAuthAPIError,
+		_errAuthInvalid,
+		_errAuthOtherAccount,
+		_errAPIVersionMismatch,
+		_errLoadBalancerInitializing,
+	)
+
+	exit.SetErrorExitCode(_exitCodeAPIFailure,
+		ErrAPINotReady,
+		ErrAPIRolloutFailed,
+		ErrAPIRolloutTimeout,
+		ErrPredictionsFailed,
+	)
+}
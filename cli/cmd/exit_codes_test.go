@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/operator/endpoints"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorErrorKinds(t *testing.T) {
+	require.Equal(t, resources.ErrOperationIsOnlySupportedForKind, _errOperationIsOnlySupportedForKind)
+	require.Equal(t, resources.ErrJobIDRequired, _errJobIDRequired)
+	require.Equal(t, resources.ErrCannotChangeTypeOfDeployedAPI, _errCannotChangeTypeOfDeployedAPI)
+	require.Equal(t, resources.ErrRealtimeAPIUsedByTrafficSplitter, _errRealtimeAPIUsedByTrafficSplitter)
+	require.Equal(t, resources.ErrNoAvailableNodeComputeLimit, _errNoAvailableNodeComputeLimit)
+	require.Equal(t, resources.ErrAPINotDeployed, _errAPINotDeployed)
+	require.Equal(t, resources.ErrAPIsNotDeployed, _errAPIsNotDeployed)
+	require.Equal(t, resources.ErrAPIIDNotFound, _errAPIIDNotFound)
+	require.Equal(t, resources.ErrSecretNotFound, _errSecretNotFound)
+	require.Equal(t, resources.ErrSecretKeyNotFound, _errSecretKeyNotFound)
+	require.Equal(t, batchapi.ErrJobNotFound, _errBatchJobNotFound)
+	require.Equal(t, realtimeapi.ErrReplicaNotFound, _errReplicaNotFound)
+	require.Equal(t, endpoints.ErrAuthAPIError, _errAuthAPIError)
+	require.Equal(t, endpoints.ErrAuthInvalid, _errAuthInvalid)
+	require.Equal(t, endpoints.ErrAuthOtherAccount, _errAuthOtherAccount)
+	require.Equal(t, endpoints.ErrAPIVersionMismatch, _errAPIVersionMismatch)
+	require.Equal(t, operator.ErrLoadBalancerInitializing, _errLoadBalancerInitializing)
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
@@ -102,7 +101,7 @@ var _getCmd = &cobra.Command{
 					return "", err
 				}
 
-				if _flagOutput == flags.JSONOutputType {
+				if _flagOutput.IsMachineReadable() {
 					return apiTable, nil
 				}
 
@@ -118,15 +117,11 @@ var _getCmd = &cobra.Command{
 					return "", err
 				}
 
-				if env.Provider == types.LocalProviderType {
-					return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get status of job %s for api %s", args[1], args[0]))
-				}
-
 				jobTable, err := getJob(env, args[0], args[1])
 				if err != nil {
 					return "", err
 				}
-				if _flagOutput == flags.JSONOutputType {
+				if _flagOutput.IsMachineReadable() {
 					return jobTable, nil
 				}
 
@@ -148,7 +143,7 @@ var _getCmd = &cobra.Command{
 						return "", err
 					}
 
-					if _flagOutput == flags.JSONOutputType {
+					if _flagOutput.IsMachineReadable() {
 						return apiTable, nil
 					}
 
@@ -225,13 +220,8 @@ func getAPIsInAllEnvironments() (string, error) {
 		allAPIsOutput = append(allAPIsOutput, apisOutput)
 	}
 
-	if _flagOutput == flags.JSONOutputType {
-		bytes, err := libjson.Marshal(allAPIsOutput)
-		if err != nil {
-			return "", err
-		}
-
-		return string(bytes), nil
+	if _flagOutput.IsMachineReadable() {
+		return formatOutput(allAPIsOutput)
 	}
 
 	out := ""
@@ -327,12 +317,8 @@ func getAPIsByEnv(env cliconfig.Environment, printEnv bool) (string, error) {
 			return "", err
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(apisRes)
-			if err != nil {
-				return "", err
-			}
-			return string(bytes), nil
+		if _flagOutput.IsMachineReadable() {
+			return formatOutput(apisRes)
 		}
 	} else {
 		apisRes, err = cluster.GetAPIs(MustGetOperatorConfig(env.Name))
@@ -340,12 +326,8 @@ func getAPIsByEnv(env cliconfig.Environment, printEnv bool) (string, error) {
 			return "", err
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(apisRes)
-			if err != nil {
-				return "", err
-			}
-			return string(bytes), nil
+		if _flagOutput.IsMachineReadable() {
+			return formatOutput(apisRes)
 		}
 	}
 
@@ -454,12 +436,8 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 			return "", err
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(apisRes)
-			if err != nil {
-				return "", err
-			}
-			return string(bytes), nil
+		if _flagOutput.IsMachineReadable() {
+			return formatOutput(apisRes)
 		}
 
 		if len(apisRes) == 0 {
@@ -474,7 +452,7 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 		case userconfig.TrafficSplitterKind:
 			return trafficSplitterTable(apiRes, env)
 		case userconfig.BatchAPIKind:
-			return batchAPITable(apiRes), nil
+			return batchAPITable(apiRes, env), nil
 		default:
 			return "", errors.ErrorUnexpected(fmt.Sprintf("encountered unexpected kind %s for api %s", apiRes.Spec.Kind, apiRes.Spec.Name))
 		}
@@ -485,12 +463,8 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 		return "", err
 	}
 
-	if _flagOutput == flags.JSONOutputType {
-		bytes, err := libjson.Marshal(apisRes)
-		if err != nil {
-			return "", err
-		}
-		return string(bytes), nil
+	if _flagOutput.IsMachineReadable() {
+		return formatOutput(apisRes)
 	}
 
 	if len(apisRes) == 0 {
@@ -499,7 +473,14 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 
 	apiRes := apisRes[0]
 
-	return realtimeAPITable(apiRes, env)
+	switch apiRes.Spec.Kind {
+	case userconfig.BatchAPIKind:
+		return batchAPITable(apiRes, env), nil
+	case userconfig.TrafficSplitterKind:
+		return trafficSplitterTable(apiRes, env)
+	default:
+		return realtimeAPITable(apiRes, env)
+	}
 }
 
 func apiHistoryTable(apiVersions []schema.APIVersion) string {
@@ -510,15 +491,38 @@ func apiHistoryTable(apiVersions []schema.APIVersion) string {
 		},
 	}
 
+	anyRolloutFailures := false
+	for _, apiVersion := range apiVersions {
+		if apiVersion.RolloutFailure != nil {
+			anyRolloutFailures = true
+		}
+	}
+	if anyRolloutFailures {
+		t.Headers = append(t.Headers, table.Header{Title: "rollout"})
+	}
+
 	t.Rows = make([][]interface{}, len(apiVersions))
 	for i, apiVersion := range apiVersions {
 		lastUpdated := time.Unix(apiVersion.LastUpdated, 0)
 		t.Rows[i] = []interface{}{apiVersion.APIID, libtime.SinceStr(&lastUpdated)}
+		if anyRolloutFailures {
+			t.Rows[i] = append(t.Rows[i], rolloutFailureStr(apiVersion.RolloutFailure))
+		}
 	}
 
 	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
 }
 
+func rolloutFailureStr(rolloutFailure *schema.RolloutFailure) string {
+	if rolloutFailure == nil {
+		return "-"
+	}
+	if rolloutFailure.RolledBackTo != "" {
+		return fmt.Sprintf("failed, rolled back to %s (%s)", rolloutFailure.RolledBackTo, rolloutFailure.Reason)
+	}
+	return fmt.Sprintf("failed (%s)", rolloutFailure.Reason)
+}
+
 func titleStr(title string) string {
 	return "\n" + console.Bold(title) + "\n"
 }
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+var _initKinds = []string{"realtime", "batch", "traffic-splitter"}
+var _initPredictorTypes = []string{userconfig.PythonPredictorType.String(), userconfig.TensorFlowPredictorType.String(), userconfig.ONNXPredictorType.String()}
+
+var (
+	_flagInitKind        string
+	_flagInitPredictor   string
+	_flagInitName        string
+	_flagInitTemplateDir string
+)
+
+type initTemplateData struct {
+	APIName string
+}
+
+func initInit() {
+	_initCmd.Flags().SortFlags = false
+	_initCmd.Flags().StringVarP(&_flagInitKind, "kind", "k", "realtime", fmt.Sprintf("kind of api to create: one of %s", strings.Join(_initKinds, "|")))
+	_initCmd.Flags().StringVarP(&_flagInitPredictor, "predictor", "p", userconfig.PythonPredictorType.String(), fmt.Sprintf("type of predictor: one of %s", strings.Join(_initPredictorTypes, "|")))
+	_initCmd.Flags().StringVarP(&_flagInitName, "name", "n", "", "name of the api (defaults to the name of the directory)")
+	_initCmd.Flags().StringVar(&_flagInitTemplateDir, "template-dir", "", "directory of custom templates (defaults to the templates directory in the cli config directory, e.g. ~/.cortex/templates)")
+}
+
+var _initCmd = &cobra.Command{
+	Use:   "init [DIRECTORY]",
+	Short: "create a new api project from a template",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.init", map[string]interface{}{"kind": _flagInitKind, "predictor": _flagInitPredictor})
+
+		if !slices.HasString(_initKinds, _flagInitKind) {
+			exit.Error(ErrorInvalidFlagValue("--kind", "one of "+s.StrsOr(_initKinds)))
+		}
+		if !slices.HasString(_initPredictorTypes, _flagInitPredictor) {
+			exit.Error(ErrorInvalidFlagValue("--predictor", "one of "+s.StrsOr(_initPredictorTypes)))
+		}
+
+		dir := filepath.Clean(_cwd)
+		if len(args) == 1 {
+			dir = files.RelToAbsPath(args[0], _cwd)
+		}
+
+		apiName := _flagInitName
+		if apiName == "" {
+			apiName = defaultInitAPIName(dir)
+		} else if err := urls.CheckDNS1035(apiName); err != nil {
+			exit.Error(errors.Wrap(err, "--name"))
+		}
+
+		templateDir := _flagInitTemplateDir
+		if templateDir == "" {
+			templateDir = filepath.Join(_localDir, "templates")
+		} else {
+			templateDir = files.RelToAbsPath(templateDir, _cwd)
+		}
+
+		templateName := initTemplateName(_flagInitKind, _flagInitPredictor)
+		templateFiles, err := getInitTemplate(templateName, templateDir)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		paths, err := writeInitTemplate(templateFiles, dir, initTemplateData{APIName: apiName})
+		if err != nil {
+			exit.Error(err)
+		}
+
+		fmt.Printf("created %s in %s\n\n", s.StrsAnd(paths), dir)
+		fmt.Println(console.Bold("next steps:"))
+		if relDir, err := filepath.Rel(_cwd, dir); err == nil && relDir != "." {
+			fmt.Println("  cd " + relDir)
+		}
+		fmt.Println("  cortex deploy")
+		switch _flagInitKind {
+		case "realtime":
+			fmt.Printf("  cortex predict %s sample.json\n", apiName)
+		case "batch":
+			fmt.Printf("  cortex job submit %s sample.json\n", apiName)
+		case "traffic-splitter":
+			fmt.Printf("  cortex get %s\n\nrequests to the traffic splitter's endpoint are split between %s-a and %s-b\n", apiName, apiName, apiName)
+		}
+	},
+}
+
+// e.g. "realtime-python" or "traffic-splitter-onnx"
+func initTemplateName(kind string, predictorType string) string {
+	return kind + "-" + predictorType
+}
+
+// getInitTemplate returns the contents of the template's files by path; a template in templateDir (i.e. a subdirectory named after
+// the template) replaces the embedded template with the same name
+func getInitTemplate(templateName string, templateDir string) (map[string]string, error) {
+	customTemplateDir := filepath.Join(templateDir, templateName)
+	if !files.IsDir(customTemplateDir) {
+		return _initTemplates[templateName], nil
+	}
+
+	paths, err := files.ListDirRecursive(customTemplateDir, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, ErrorInitTemplateEmpty(customTemplateDir)
+	}
+
+	templateFiles := map[string]string{}
+	for _, path := range paths {
+		content, err := files.ReadFile(filepath.Join(customTemplateDir, path))
+		if err != nil {
+			return nil, err
+		}
+		templateFiles[path] = content
+	}
+
+	return templateFiles, nil
+}
+
+// writeInitTemplate renders the template's files into dir, and returns the paths of the files which were created (relative to dir);
+// no files are written if any of them already exist
+func writeInitTemplate(templateFiles map[string]string, dir string, data initTemplateData) ([]string, error) {
+	paths := make([]string, 0, len(templateFiles))
+	for path := range templateFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rendered := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		if files.IsFileOrDir(filepath.Join(dir, path)) {
+			return nil, files.ErrorFileAlreadyExists(filepath.Join(dir, path))
+		}
+
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(templateFiles[path])
+		if err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		rendered[path] = buf.Bytes()
+	}
+
+	for _, path := range paths {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := files.WriteFile(rendered[path], fullPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+var _invalidAPINameCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// defaultInitAPIName derives a valid api name from the name of the directory
+func defaultInitAPIName(dir string) string {
+	name := strings.ToLower(filepath.Base(dir))
+	name = _invalidAPINameCharsRegex.ReplaceAllString(name, "-")
+	name = strings.TrimLeft(name, "-0123456789")
+	name = strings.TrimRight(name, "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	if urls.CheckDNS1035(name) != nil {
+		return "my-api"
+	}
+	return name
+}
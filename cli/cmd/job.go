@@ -0,0 +1,113 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagJobEnv string
+)
+
+func jobInit() {
+	_jobSubmitCmd.Flags().SortFlags = false
+	_jobSubmitCmd.Flags().StringVarP(&_flagJobEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobSubmitCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+	_jobCmd.AddCommand(_jobSubmitCmd)
+}
+
+var _jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "manage batch api jobs (contains subcommands)",
+}
+
+var _jobSubmitCmd = &cobra.Command{
+	Use:   "submit API_NAME SUBMISSION_FILE",
+	Short: "submit a job to a batch api",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagJobEnv)
+		if err != nil {
+			telemetry.Event("cli.job.submit")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.job.submit", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		err = printEnvIfNotSpecified(_flagJobEnv, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		submission, err := files.ReadFileBytes(files.UserRelToAbsPath(args[1]))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var jobSpec spec.Job
+		if env.Provider == types.LocalProviderType {
+			localJobSpec, err := local.SubmitJob(env, apiName, submission)
+			if err != nil {
+				exit.Error(err)
+			}
+			jobSpec = *localJobSpec
+		} else {
+			jobSpec, err = cluster.SubmitJob(MustGetOperatorConfig(env.Name), apiName, submission)
+			if err != nil {
+				exit.Error(err)
+			}
+		}
+
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(jobSpec); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		var envArg string
+		if env.Name != getDefaultEnv(_generalCommandType) {
+			envArg = " --env " + env.Name
+		}
+
+		fmt.Println(console.Bold(fmt.Sprintf("submitted job %s", jobSpec.ID)) + "\n")
+
+		var items table.KeyValuePairs
+		items.Add(fmt.Sprintf("cortex get %s %s%s", apiName, jobSpec.ID, envArg), "(show job status)")
+		items.Add(fmt.Sprintf("cortex logs %s %s%s", apiName, jobSpec.ID, envArg), "(stream job logs)")
+		items.Add(fmt.Sprintf("cortex delete %s %s%s", apiName, jobSpec.ID, envArg), "(stop the job)")
+		fmt.Println(strings.TrimSpace(items.String(&table.KeyValuePairOpts{
+			Delimiter: pointer.String(""),
+			NumSpaces: pointer.Int(2),
+		})))
+	},
+}
@@ -21,8 +21,8 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
-	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
@@ -81,7 +81,7 @@ func batchAPIsTable(batchAPIs []schema.APIResponse, envNames []string) table.Tab
 	}
 }
 
-func batchAPITable(batchAPI schema.APIResponse) string {
+func batchAPITable(batchAPI schema.APIResponse, env cliconfig.Environment) string {
 	jobRows := make([][]interface{}, 0, len(batchAPI.JobStatuses))
 
 	out := ""
@@ -131,31 +131,35 @@ func batchAPITable(batchAPI schema.APIResponse) string {
 		out += t.MustFormat()
 	}
 
-	out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint + "\n"
+	if env.Provider != types.LocalProviderType {
+		out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint + "\n"
 
-	out += "\n" + apiHistoryTable(batchAPI.APIVersions)
+		out += "\n" + apiHistoryTable(batchAPI.APIVersions)
+	}
 
 	if !_flagVerbose {
 		return out
 	}
 
-	out += titleStr("batch api configuration") + batchAPI.Spec.UserStr(types.AWSProviderType)
+	out += titleStr("batch api configuration") + batchAPI.Spec.UserStr(env.Provider)
 
 	return out
 }
 
 func getJob(env cliconfig.Environment, apiName string, jobID string) (string, error) {
-	resp, err := cluster.GetJob(MustGetOperatorConfig(env.Name), apiName, jobID)
+	var resp schema.JobResponse
+	var err error
+	if env.Provider == types.LocalProviderType {
+		resp, err = local.GetJob(apiName, jobID)
+	} else {
+		resp, err = cluster.GetJob(MustGetOperatorConfig(env.Name), apiName, jobID)
+	}
 	if err != nil {
 		return "", err
 	}
 
-	if _flagOutput == flags.JSONOutputType {
-		bytes, err := libjson.Marshal(resp)
-		if err != nil {
-			return "", err
-		}
-		return string(bytes), nil
+	if _flagOutput.IsMachineReadable() {
+		return formatOutput(resp)
 	}
 
 	job := resp.JobStatus
@@ -251,7 +255,9 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 		}
 	}
 
-	out += "\n" + console.Bold("job endpoint: ") + resp.Endpoint + "\n"
+	if env.Provider != types.LocalProviderType {
+		out += "\n" + console.Bold("job endpoint: ") + resp.Endpoint + "\n"
+	}
 
 	jobSpecStr, err := libjson.Pretty(job.Job)
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
@@ -61,7 +62,7 @@ func existingCachedClusterConfigPaths() []string {
 func readCachedClusterConfigFile(clusterConfig *clusterconfig.Config, filePath string) error {
 	errs := cr.ParseYAMLFile(clusterConfig, clusterconfig.Validation, filePath)
 	if errors.HasError(errs) {
-		return errors.FirstError(errs...)
+		return errors.Join(errs...)
 	}
 
 	return nil
@@ -70,7 +71,7 @@ func readCachedClusterConfigFile(clusterConfig *clusterconfig.Config, filePath s
 func readUserClusterConfigFile(clusterConfig *clusterconfig.Config) error {
 	errs := cr.ParseYAMLFile(clusterConfig, clusterconfig.UserValidation, _flagClusterConfig)
 	if errors.HasError(errs) {
-		return errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
+		return errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
 	}
 
 	return nil
@@ -85,7 +86,7 @@ func getNewClusterAccessConfig(disallowPrompt bool) (*clusterconfig.AccessConfig
 	if _flagClusterConfig != "" {
 		errs := cr.ParseYAMLFile(accessConfig, clusterconfig.AccessValidation, _flagClusterConfig)
 		if errors.HasError(errs) {
-			return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
+			return nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
 		}
 	}
 
@@ -121,7 +122,7 @@ func getClusterAccessConfigWithCache(disallowPrompt bool) (*clusterconfig.Access
 	if _flagClusterConfig != "" {
 		errs := cr.ParseYAMLFile(accessConfig, clusterconfig.AccessValidation, _flagClusterConfig)
 		if errors.HasError(errs) {
-			return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
+			return nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
 		}
 	}
 
@@ -441,6 +442,74 @@ func setConfigFieldsFromCached(userClusterConfig *clusterconfig.Config, cachedCl
 	}
 	userClusterConfig.SpotConfig = cachedClusterConfig.SpotConfig
 
+	if err := setNodeGroupFieldsFromCached(userClusterConfig, cachedClusterConfig, awsClient); err != nil {
+		return errors.Wrap(err, clusterconfig.NodeGroupsKey)
+	}
+
+	return nil
+}
+
+// Only the min and max instances of each node group can be modified on a running cluster
+func setNodeGroupFieldsFromCached(userClusterConfig *clusterconfig.Config, cachedClusterConfig *clusterconfig.Config, awsClient *aws.Client) error {
+	var cachedNames []string
+	for _, nodeGroup := range cachedClusterConfig.NodeGroups {
+		cachedNames = append(cachedNames, nodeGroup.Name)
+	}
+	var userNames []string
+	for _, nodeGroup := range userClusterConfig.NodeGroups {
+		userNames = append(userNames, nodeGroup.Name)
+	}
+	if !strset.New(userNames...).IsEqual(strset.New(cachedNames...)) {
+		return clusterconfig.ErrorNodeGroupsCannotBeChangedOnUpdate(cachedNames)
+	}
+
+	for _, userNodeGroup := range userClusterConfig.NodeGroups {
+		var cachedNodeGroup *clusterconfig.NodeGroup
+		for _, nodeGroup := range cachedClusterConfig.NodeGroups {
+			if nodeGroup.Name == userNodeGroup.Name {
+				cachedNodeGroup = nodeGroup
+			}
+		}
+
+		if userNodeGroup.InstanceType != cachedNodeGroup.InstanceType {
+			return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.InstanceTypeKey, cachedNodeGroup.InstanceType), userNodeGroup.Name)
+		}
+
+		if userNodeGroup.InstanceVolumeSize != cachedNodeGroup.InstanceVolumeSize {
+			return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.InstanceVolumeSizeKey, cachedNodeGroup.InstanceVolumeSize), userNodeGroup.Name)
+		}
+
+		if userNodeGroup.InstanceVolumeType != cachedNodeGroup.InstanceVolumeType {
+			return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.InstanceVolumeTypeKey, cachedNodeGroup.InstanceVolumeType), userNodeGroup.Name)
+		}
+
+		if userNodeGroup.InstanceVolumeIOPS != nil && s.Obj(userNodeGroup.InstanceVolumeIOPS) != s.Obj(cachedNodeGroup.InstanceVolumeIOPS) {
+			return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.InstanceVolumeIOPSKey, cachedNodeGroup.InstanceVolumeIOPS), userNodeGroup.Name)
+		}
+
+		if userNodeGroup.Spot != cachedNodeGroup.Spot {
+			return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.SpotKey, cachedNodeGroup.Spot), userNodeGroup.Name)
+		}
+
+		if userNodeGroup.SpotConfig != nil {
+			if cachedNodeGroup.SpotConfig == nil {
+				return errors.Wrap(clusterconfig.ErrorConfiguredWhenSpotIsNotEnabled(clusterconfig.SpotConfigKey), userNodeGroup.Name)
+			}
+
+			err := clusterconfig.AutoGenerateSpotConfig(awsClient, userNodeGroup.SpotConfig, *cachedClusterConfig.Region, userNodeGroup.InstanceType)
+			if err != nil {
+				return err
+			}
+
+			if s.Obj(userNodeGroup.SpotConfig) != s.Obj(cachedNodeGroup.SpotConfig) {
+				return errors.Wrap(clusterconfig.ErrorConfigCannotBeChangedOnUpdate(clusterconfig.SpotConfigKey, cachedNodeGroup.SpotConfig), userNodeGroup.Name)
+			}
+		}
+
+		userNodeGroup.InstanceVolumeIOPS = cachedNodeGroup.InstanceVolumeIOPS
+		userNodeGroup.SpotConfig = cachedNodeGroup.SpotConfig
+	}
+
 	return nil
 }
 
@@ -450,11 +519,6 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsCreds A
 	operatorEBSPrice := aws.EBSMetadatas[*clusterConfig.Region]["gp2"].PriceGB * 20 / 30 / 24
 	nlbPrice := aws.NLBMetadatas[*clusterConfig.Region].Price
 	natUnitPrice := aws.NATMetadatas[*clusterConfig.Region].Price
-	apiInstancePrice := aws.InstanceMetadatas[*clusterConfig.Region][*clusterConfig.InstanceType].Price
-	apiEBSPrice := aws.EBSMetadatas[*clusterConfig.Region][clusterConfig.InstanceVolumeType.String()].PriceGB * float64(clusterConfig.InstanceVolumeSize) / 30 / 24
-	if clusterConfig.InstanceVolumeType.String() == "io1" && clusterConfig.InstanceVolumeIOPS != nil {
-		apiEBSPrice += aws.EBSMetadatas[*clusterConfig.Region][clusterConfig.InstanceVolumeType.String()].PriceIOPS * float64(*clusterConfig.InstanceVolumeIOPS) / 30 / 24
-	}
 
 	var natTotalPrice float64
 	if clusterConfig.NATGateway == clusterconfig.SingleNATGateway {
@@ -464,8 +528,8 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsCreds A
 	}
 
 	fixedPrice := eksPrice + operatorInstancePrice + operatorEBSPrice + 2*nlbPrice + natTotalPrice
-	totalMinPrice := fixedPrice + float64(*clusterConfig.MinInstances)*(apiInstancePrice+apiEBSPrice)
-	totalMaxPrice := fixedPrice + float64(*clusterConfig.MaxInstances)*(apiInstancePrice+apiEBSPrice)
+	totalMinPrice := fixedPrice
+	totalMaxPrice := fixedPrice
 
 	fmt.Printf("aws access key id %s will be used to provision a cluster named \"%s\" in %s:\n\n", s.MaskString(awsCreds.AWSAccessKeyID, 4), clusterConfig.ClusterName, *clusterConfig.Region)
 
@@ -477,32 +541,51 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsCreds A
 	rows := [][]interface{}{}
 	rows = append(rows, []interface{}{"1 eks cluster", s.DollarsMaxPrecision(eksPrice)})
 
-	instanceStr := "instances"
-	volumeStr := "volumes"
-	if *clusterConfig.MinInstances == 1 && *clusterConfig.MaxInstances == 1 {
-		instanceStr = "instance"
-		volumeStr = "volume"
-	}
-	workerInstanceStr := fmt.Sprintf("%d - %d %s %s for your apis", *clusterConfig.MinInstances, *clusterConfig.MaxInstances, *clusterConfig.InstanceType, instanceStr)
-	ebsInstanceStr := fmt.Sprintf("%d - %d %dgb ebs %s for your apis", *clusterConfig.MinInstances, *clusterConfig.MaxInstances, clusterConfig.InstanceVolumeSize, volumeStr)
-	if *clusterConfig.MinInstances == *clusterConfig.MaxInstances {
-		workerInstanceStr = fmt.Sprintf("%d %s %s for your apis", *clusterConfig.MinInstances, *clusterConfig.InstanceType, instanceStr)
-		ebsInstanceStr = fmt.Sprintf("%d %dgb ebs %s for your apis", *clusterConfig.MinInstances, clusterConfig.InstanceVolumeSize, volumeStr)
-	}
+	isSpot := false
+	isScalable := false
+	for _, nodeGroup := range clusterConfig.AllNodeGroups() {
+		apiInstancePrice := aws.InstanceMetadatas[*clusterConfig.Region][nodeGroup.InstanceType].Price
+		apiEBSPrice := nodeGroup.EBSPrice(*clusterConfig.Region)
+
+		forStr := "for your apis"
+		if len(clusterConfig.NodeGroups) > 0 {
+			forStr = fmt.Sprintf("for your apis (%s node group)", nodeGroup.Name)
+		}
 
-	workerPriceStr := s.DollarsMaxPrecision(apiInstancePrice) + " each"
-	isSpot := clusterConfig.Spot != nil && *clusterConfig.Spot
-	if isSpot {
-		spotPrice, err := awsClient.SpotInstancePrice(*clusterConfig.Region, *clusterConfig.InstanceType)
-		workerPriceStr += " (spot pricing unavailable)"
-		if err == nil && spotPrice != 0 {
-			workerPriceStr = fmt.Sprintf("%s - %s each (varies based on spot price)", s.DollarsMaxPrecision(spotPrice), s.DollarsMaxPrecision(apiInstancePrice))
-			totalMinPrice = fixedPrice + float64(*clusterConfig.MinInstances)*(spotPrice+apiEBSPrice)
+		instanceStr := "instances"
+		volumeStr := "volumes"
+		if nodeGroup.MinInstances == 1 && nodeGroup.MaxInstances == 1 {
+			instanceStr = "instance"
+			volumeStr = "volume"
+		}
+		workerInstanceStr := fmt.Sprintf("%d - %d %s %s %s", nodeGroup.MinInstances, nodeGroup.MaxInstances, nodeGroup.InstanceType, instanceStr, forStr)
+		ebsInstanceStr := fmt.Sprintf("%d - %d %dgb ebs %s %s", nodeGroup.MinInstances, nodeGroup.MaxInstances, nodeGroup.InstanceVolumeSize, volumeStr, forStr)
+		if nodeGroup.MinInstances == nodeGroup.MaxInstances {
+			workerInstanceStr = fmt.Sprintf("%d %s %s %s", nodeGroup.MinInstances, nodeGroup.InstanceType, instanceStr, forStr)
+			ebsInstanceStr = fmt.Sprintf("%d %dgb ebs %s %s", nodeGroup.MinInstances, nodeGroup.InstanceVolumeSize, volumeStr, forStr)
+		} else {
+			isScalable = true
 		}
+
+		minInstancePrice := apiInstancePrice
+		workerPriceStr := s.DollarsMaxPrecision(apiInstancePrice) + " each"
+		if nodeGroup.Spot {
+			isSpot = true
+			spotPrice, err := awsClient.SpotInstancePrice(*clusterConfig.Region, nodeGroup.InstanceType)
+			workerPriceStr += " (spot pricing unavailable)"
+			if err == nil && spotPrice != 0 {
+				workerPriceStr = fmt.Sprintf("%s - %s each (varies based on spot price)", s.DollarsMaxPrecision(spotPrice), s.DollarsMaxPrecision(apiInstancePrice))
+				minInstancePrice = spotPrice
+			}
+		}
+
+		totalMinPrice += float64(nodeGroup.MinInstances) * (minInstancePrice + apiEBSPrice)
+		totalMaxPrice += float64(nodeGroup.MaxInstances) * (apiInstancePrice + apiEBSPrice)
+
+		rows = append(rows, []interface{}{workerInstanceStr, workerPriceStr})
+		rows = append(rows, []interface{}{ebsInstanceStr, s.DollarsAndTenthsOfCents(apiEBSPrice) + " each"})
 	}
 
-	rows = append(rows, []interface{}{workerInstanceStr, workerPriceStr})
-	rows = append(rows, []interface{}{ebsInstanceStr, s.DollarsAndTenthsOfCents(apiEBSPrice) + " each"})
 	rows = append(rows, []interface{}{"1 t3.medium instance for the operator", s.DollarsMaxPrecision(operatorInstancePrice)})
 	rows = append(rows, []interface{}{"1 20gb ebs volume for the operator", s.DollarsAndTenthsOfCents(operatorEBSPrice)})
 	rows = append(rows, []interface{}{"2 network load balancers", s.DollarsMaxPrecision(nlbPrice) + " each"})
@@ -524,11 +607,11 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsCreds A
 
 	if totalMinPrice != totalMaxPrice {
 		priceStr = fmt.Sprintf("%s - %s", s.DollarsAndCents(totalMinPrice), s.DollarsAndCents(totalMaxPrice))
-		if isSpot && *clusterConfig.MinInstances != *clusterConfig.MaxInstances {
+		if isSpot && isScalable {
 			suffix = " based on cluster size and spot instance pricing/availability"
-		} else if isSpot && *clusterConfig.MinInstances == *clusterConfig.MaxInstances {
+		} else if isSpot && !isScalable {
 			suffix = " based on spot instance pricing/availability"
-		} else if !isSpot && *clusterConfig.MinInstances != *clusterConfig.MaxInstances {
+		} else if !isSpot && isScalable {
 			suffix = " based on cluster size"
 		}
 	}
@@ -549,8 +632,18 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsCreds A
 		fmt.Print(fmt.Sprintf("warning: you've configured the operator load balancer to be internal; you must configure VPC Peering to connect your CLI to your cluster operator (see https://docs.cortex.dev/v/%s/aws/vpc-peering)\n\n", consts.CortexVersionMinor))
 	}
 
-	if isSpot && clusterConfig.SpotConfig.OnDemandBackup != nil && !*clusterConfig.SpotConfig.OnDemandBackup {
-		if *clusterConfig.SpotConfig.OnDemandBaseCapacity == 0 && *clusterConfig.SpotConfig.OnDemandPercentageAboveBaseCapacity == 0 {
+	var spotWithoutOnDemandBackup, onDemandDisabled bool
+	for _, nodeGroup := range clusterConfig.AllNodeGroups() {
+		if nodeGroup.Spot && nodeGroup.SpotConfig != nil && nodeGroup.SpotConfig.OnDemandBackup != nil && !*nodeGroup.SpotConfig.OnDemandBackup {
+			spotWithoutOnDemandBackup = true
+			if *nodeGroup.SpotConfig.OnDemandBaseCapacity == 0 && *nodeGroup.SpotConfig.OnDemandPercentageAboveBaseCapacity == 0 {
+				onDemandDisabled = true
+			}
+		}
+	}
+
+	if spotWithoutOnDemandBackup {
+		if onDemandDisabled {
 			fmt.Printf("warning: you've disabled on-demand instances (%s=0 and %s=0); spot instances are not guaranteed to be available so please take that into account for production clusters; see https://docs.cortex.dev/v/%s/aws/spot for more information\n\n", clusterconfig.OnDemandBaseCapacityKey, clusterconfig.OnDemandPercentageAboveBaseCapacityKey, consts.CortexVersionMinor)
 		} else {
 			fmt.Printf("warning: you've enabled spot instances; spot instances are not guaranteed to be available so please take that into account for production clusters; see https://docs.cortex.dev/v/%s/aws/spot for more information\n\n", consts.CortexVersionMinor)
@@ -655,6 +748,14 @@ func clusterConfigConfirmationStr(clusterConfig clusterconfig.Config, awsCreds A
 		}
 	}
 
+	if len(clusterConfig.NodeGroups) > 0 {
+		var nodeGroupStrs []string
+		for _, nodeGroup := range clusterConfig.NodeGroups {
+			nodeGroupStrs = append(nodeGroupStrs, nodeGroup.SummaryStr())
+		}
+		items.Add(clusterconfig.NodeGroupsUserKey, strings.Join(nodeGroupStrs, "; "))
+	}
+
 	if clusterConfig.VPCCIDR != nil {
 		items.Add(clusterconfig.VPCCIDRUserKey, clusterConfig.VPCCIDR)
 	}
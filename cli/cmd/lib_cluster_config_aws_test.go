@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetNodeGroupFieldsFromCached(t *testing.T) {
+	cachedNodeGroups := func() []*clusterconfig.NodeGroup {
+		return []*clusterconfig.NodeGroup{
+			{
+				Name:               "cpu",
+				InstanceType:       "m5.large",
+				MinInstances:       1,
+				MaxInstances:       5,
+				InstanceVolumeSize: 50,
+				InstanceVolumeType: clusterconfig.GP2VolumeType,
+			},
+			{
+				Name:               "gpu",
+				InstanceType:       "g4dn.xlarge",
+				MinInstances:       0,
+				MaxInstances:       2,
+				InstanceVolumeSize: 100,
+				InstanceVolumeType: clusterconfig.IO1VolumeType,
+				InstanceVolumeIOPS: pointer.Int64(3000),
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		update  func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup
+		errKind string // empty if no error is expected
+	}{
+		{
+			name: "instance counts changed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].MinInstances = 0
+				nodeGroups[1].MaxInstances = 10
+				nodeGroups[1].InstanceVolumeIOPS = nil
+				return nodeGroups
+			},
+		},
+		{
+			name: "reordered",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				return []*clusterconfig.NodeGroup{nodeGroups[1], nodeGroups[0]}
+			},
+		},
+		{
+			name: "node group added",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				return append(nodeGroups, &clusterconfig.NodeGroup{Name: "inf", InstanceType: "inf1.xlarge"})
+			},
+			errKind: clusterconfig.ErrNodeGroupsCannotBeChangedOnUpdate,
+		},
+		{
+			name: "node group removed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				return nodeGroups[:1]
+			},
+			errKind: clusterconfig.ErrNodeGroupsCannotBeChangedOnUpdate,
+		},
+		{
+			name: "node group renamed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[1].Name = "gpus"
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrNodeGroupsCannotBeChangedOnUpdate,
+		},
+		{
+			name: "instance type changed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].InstanceType = "m5.xlarge"
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfigCannotBeChangedOnUpdate,
+		},
+		{
+			name: "volume size changed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].InstanceVolumeSize = 100
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfigCannotBeChangedOnUpdate,
+		},
+		{
+			name: "volume type changed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].InstanceVolumeType = clusterconfig.ST1VolumeType
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfigCannotBeChangedOnUpdate,
+		},
+		{
+			name: "iops changed",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[1].InstanceVolumeIOPS = pointer.Int64(2000)
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfigCannotBeChangedOnUpdate,
+		},
+		{
+			name: "spot enabled",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].Spot = true
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfigCannotBeChangedOnUpdate,
+		},
+		{
+			name: "spot config set when spot is not enabled",
+			update: func(nodeGroups []*clusterconfig.NodeGroup) []*clusterconfig.NodeGroup {
+				nodeGroups[0].SpotConfig = &clusterconfig.SpotConfig{}
+				return nodeGroups
+			},
+			errKind: clusterconfig.ErrConfiguredWhenSpotIsNotEnabled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cachedClusterConfig := &clusterconfig.Config{Region: pointer.String("us-west-2"), NodeGroups: cachedNodeGroups()}
+			userClusterConfig := &clusterconfig.Config{Region: pointer.String("us-west-2"), NodeGroups: tc.update(cachedNodeGroups())}
+
+			err := setNodeGroupFieldsFromCached(userClusterConfig, cachedClusterConfig, nil)
+			if tc.errKind != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.errKind, errors.GetKind(err))
+				return
+			}
+
+			require.NoError(t, err)
+			for _, nodeGroup := range userClusterConfig.NodeGroups {
+				cachedNodeGroup, ok := cachedClusterConfig.GetNodeGroup(nodeGroup.Name)
+				require.True(t, ok)
+				require.Equal(t, cachedNodeGroup.InstanceVolumeIOPS, nodeGroup.InstanceVolumeIOPS)
+				require.Equal(t, cachedNodeGroup.SpotConfig, nodeGroup.SpotConfig)
+			}
+		})
+	}
+}
@@ -57,7 +57,7 @@ func existingCachedGCPClusterConfigPaths() []string {
 func readCachedGCPClusterConfigFile(clusterConfig *clusterconfig.GCPConfig, filePath string) error {
 	errs := cr.ParseYAMLFile(clusterConfig, clusterconfig.GCPValidation, filePath)
 	if errors.HasError(errs) {
-		return errors.FirstError(errs...)
+		return errors.Join(errs...)
 	}
 
 	return nil
@@ -66,7 +66,7 @@ func readCachedGCPClusterConfigFile(clusterConfig *clusterconfig.GCPConfig, file
 func readUserGCPClusterConfigFile(clusterConfig *clusterconfig.GCPConfig) error {
 	errs := cr.ParseYAMLFile(clusterConfig, clusterconfig.UserGCPValidation, _flagClusterGCPConfig)
 	if errors.HasError(errs) {
-		return errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
+		return errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
 	}
 
 	return nil
@@ -81,7 +81,7 @@ func getNewGCPClusterAccessConfig(disallowPrompt bool) (*clusterconfig.GCPAccess
 	if _flagClusterGCPConfig != "" {
 		errs := cr.ParseYAMLFile(accessConfig, clusterconfig.GCPAccessValidation, _flagClusterGCPConfig)
 		if errors.HasError(errs) {
-			return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
+			return nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
 		}
 	}
 
@@ -120,7 +120,7 @@ func getGCPClusterAccessConfigWithCache(disallowPrompt bool) (*clusterconfig.GCP
 	if _flagClusterGCPConfig != "" {
 		errs := cr.ParseYAMLFile(accessConfig, clusterconfig.GCPAccessValidation, _flagClusterGCPConfig)
 		if errors.HasError(errs) {
-			return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
+			return nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
 		}
 	}
 
@@ -0,0 +1,337 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/docker/docker/pkg/term"
+)
+
+const (
+	_ansiEnterAltScreen = "\033[?1049h"
+	_ansiExitAltScreen  = "\033[?1049l"
+	_ansiHideCursor     = "\033[?25l"
+	_ansiShowCursor     = "\033[?25h"
+	_ansiCursorHome     = "\033[H"
+	_ansiClearLine      = "\033[K" // clears from the cursor to the end of the line
+	_ansiClearBelow     = "\033[J" // clears from the cursor to the end of the screen
+	_ansiReverse        = "\033[7m"
+	_ansiReset          = "\033[0m"
+)
+
+// keys as they are read from a terminal in raw mode
+const (
+	_keyCtrlC     = "\x03"
+	_keyEscape    = "\x1b"
+	_keyEnter     = "\r"
+	_keyBackspace = "\x7f"
+	_keyUp        = "\x1b[A"
+	_keyDown      = "\x1b[B"
+	_keyRight     = "\x1b[C"
+	_keyLeft      = "\x1b[D"
+)
+
+const (
+	_titleRequestRate = "requests/s"
+)
+
+// draw redraws the whole screen
+func (d *dashboard) draw() {
+	width, height := 80, 24
+	if winsize, err := term.GetWinsize(os.Stdout.Fd()); err == nil && winsize.Width > 0 && winsize.Height > 0 {
+		width, height = int(winsize.Width), int(winsize.Height)
+	}
+
+	d.Lock()
+	lines := []string{d.headerLine(width), ""}
+	bodyHeight := libmath.MaxInt(height-len(lines)-1, 0)
+	var body []string
+	var footer string
+	if d.detail != nil {
+		body = d.detailLines(bodyHeight)
+		footer = "esc back   q quit"
+	} else {
+		body = d.listLines(bodyHeight)
+		footer = "↑/↓ select   enter show api   q quit"
+	}
+	d.Unlock()
+
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+	lines = append(lines, body[:bodyHeight]...)
+	lines = append(lines, footer)
+
+	var screen strings.Builder
+	screen.WriteString(_ansiCursorHome)
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		screen.WriteString(truncateTerminalLine(line, width) + _ansiReset + _ansiClearLine)
+	}
+	screen.WriteString(_ansiClearBelow)
+	os.Stdout.WriteString(screen.String())
+}
+
+func (d *dashboard) headerLine(width int) string {
+	title := "cortex dashboard"
+	timeStr := ""
+	if !d.lastRefresh.IsZero() {
+		timeStr = "updated " + d.lastRefresh.Format("15:04:05")
+	}
+	padding := strings.Repeat(" ", libmath.MaxInt(width-len(title)-len(timeStr), 1))
+	return console.Bold(title) + padding + timeStr
+}
+
+func (d *dashboard) listLines(height int) []string {
+	if d.lastRefresh.IsZero() {
+		return []string{"fetching apis ..."}
+	}
+
+	var lines []string
+
+	if len(d.apis) == 0 {
+		if len(d.envErrors) < len(d.envs) {
+			lines = append(lines, "no realtime apis are deployed")
+		}
+	} else {
+		t := d.apisTable()
+		tableLines := strings.Split(strings.TrimRight(t.MustFormat(&table.Opts{Sort: pointer.Bool(false)}), "\n"), "\n")
+		header, rows := tableLines[0], tableLines[1:]
+
+		// scroll so that the selected api is visible
+		numVisibleRows := libmath.MaxInt(height-len(d.envErrors)-2, 1)
+		start := libmath.MaxInt(d.selected-numVisibleRows+1, 0)
+		end := libmath.MinInt(start+numVisibleRows, len(rows))
+
+		lines = append(lines, header)
+		for i := start; i < end; i++ {
+			if i == d.selected {
+				lines = append(lines, _ansiReverse+rows[i]+_ansiReset)
+			} else {
+				lines = append(lines, rows[i])
+			}
+		}
+	}
+
+	if len(d.envErrors) > 0 {
+		lines = append(lines, "")
+		for _, env := range d.envs {
+			if err, ok := d.envErrors[env.Name]; ok {
+				lines = append(lines, fmt.Sprintf("unable to get apis from the %s environment: %s", env.Name, errors.MessageFirstLine(err)))
+			}
+		}
+	}
+
+	return lines
+}
+
+func (d *dashboard) apisTable() table.Table {
+	var totalFailed int32
+	rows := make([][]interface{}, 0, len(d.apis))
+
+	for _, dAPI := range d.apis {
+		api := dAPI.api
+		rows = append(rows, []interface{}{
+			dAPI.env.Name,
+			api.Spec.Name,
+			api.Status.Message(),
+			api.Status.Updated.Ready,
+			api.Status.Requested,
+			api.Status.Updated.TotalFailed(),
+			requestRateStr(dAPI.requestRate),
+			latencyStr(api.Metrics),
+			code5XXStr(api.Metrics),
+		})
+		totalFailed += api.Status.Updated.TotalFailed()
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: _titleEnvironment},
+			{Title: _titleRealtimeAPI},
+			{Title: _titleStatus},
+			{Title: _titleUpToDate},
+			{Title: _titleRequested},
+			{Title: _titleFailed, Hidden: totalFailed == 0},
+			{Title: _titleRequestRate},
+			{Title: _titleAvgRequest},
+			{Title: _title5XX},
+		},
+		Rows: rows,
+	}
+}
+
+func requestRateStr(requestRate *float64) string {
+	if requestRate == nil {
+		return "-"
+	}
+	return s.Round(*requestRate, 1, 0)
+}
+
+// detailLines shows the selected api's status, recent autoscaling events, and history above its logs
+func (d *dashboard) detailLines(height int) []string {
+	detail := d.detail
+	isLocal := detail.env.Provider == types.LocalProviderType
+
+	top := []string{console.Bold(detail.env.Name + " / " + detail.apiName), ""}
+
+	var dAPI *dashboardAPI
+	for i := range d.apis {
+		if d.apis[i].env.Name == detail.env.Name && d.apis[i].api.Spec.Name == detail.apiName {
+			dAPI = &d.apis[i]
+			break
+		}
+	}
+
+	if dAPI == nil {
+		top = append(top, fmt.Sprintf("%s is not deployed", detail.apiName))
+	} else {
+		t := realtimeAPIsTable([]schema.APIResponse{dAPI.api}, []string{detail.env.Name})
+		t.FindHeaderByTitle(_titleEnvironment).Hidden = true
+		t.FindHeaderByTitle(_titleRealtimeAPI).Hidden = true
+		if isLocal {
+			hideReplicaCountColumns(&t)
+		}
+		t.Headers = append(t.Headers, table.Header{Title: _titleRequestRate})
+		t.Rows[0] = append(t.Rows[0], requestRateStr(dAPI.requestRate))
+
+		out := t.MustFormat()
+		if len(dAPI.api.ColorStatuses) > 0 {
+			out += "\n" + colorStatusesTable(dAPI.api.ColorStatuses)
+		}
+		out += "\n" + console.Bold("endpoint: ") + dAPI.api.Endpoint + "\n"
+		top = append(top, strings.Split(strings.TrimRight(out, "\n"), "\n")...)
+	}
+
+	if !isLocal {
+		top = append(top, "", console.Bold("recent autoscaling events"))
+		if len(detail.events) == 0 {
+			top = append(top, "none")
+		}
+		for _, event := range detail.events {
+			top = append(top, fmt.Sprintf("%s  %s", event.Timestamp.Local().Format(time.RFC3339), event.Message))
+		}
+
+		if dAPI != nil {
+			top = append(top, "", console.Bold("history"))
+			top = append(top, strings.Split(strings.TrimRight(apiHistoryTable(dAPI.api.APIVersions), "\n"), "\n")...)
+		}
+	}
+
+	// the logs get at least half of the screen
+	topHeight := libmath.MinInt(len(top), height-height/2-1)
+	if topHeight < len(top) {
+		top = top[:libmath.MaxInt(topHeight, 0)]
+	}
+	logsHeight := libmath.MaxInt(height-len(top)-2, 0)
+
+	lines := append(top, "", console.Bold("logs"))
+
+	switch {
+	case isLocal:
+		lines = append(lines, fmt.Sprintf("logs can't be streamed from the local environment in the dashboard; run `cortex logs %s --env %s` instead", detail.apiName, detail.env.Name))
+	case detail.streamErr != nil:
+		lines = append(lines, errors.ErrorStr(detail.streamErr))
+	case detail.logStream != nil && detail.logStream.Err() != nil:
+		lines = append(lines, errors.ErrorStr(detail.logStream.Err()))
+	case len(detail.logLines) == 0:
+		lines = append(lines, "waiting for logs ...")
+	default:
+		logLines := detail.logLines
+		if len(logLines) > logsHeight {
+			logLines = logLines[len(logLines)-logsHeight:]
+		}
+		for _, logLine := range logLines {
+			lines = append(lines, sanitizeTerminalLine(logLine))
+		}
+	}
+
+	return lines
+}
+
+// truncateTerminalLine truncates line to width visible characters (ansi escape sequences are kept, but not counted)
+func truncateTerminalLine(line string, width int) string {
+	var out strings.Builder
+	runes := []rune(line)
+	numVisible := 0
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\x1b' {
+			// copy the escape sequence up to (and including) its final character
+			out.WriteRune(runes[i])
+			if i+1 < len(runes) && runes[i+1] == '[' {
+				for i++; i < len(runes); i++ {
+					out.WriteRune(runes[i])
+					if runes[i] >= '@' && runes[i] <= '~' && runes[i] != '[' {
+						break
+					}
+				}
+			}
+			continue
+		}
+
+		if numVisible >= width {
+			continue
+		}
+		out.WriteRune(runes[i])
+		numVisible++
+	}
+
+	return out.String()
+}
+
+// matches ansi escape sequences: control sequences (e.g. "\x1b[2J"), operating system commands (e.g. "\x1b]0;title\x07"), and other escape sequences (e.g. "\x1b7" or "\x1b(B")
+var _ansiEscapeSequenceRegex = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)?|[ -/]*[0-~]?)`)
+
+// matches select graphic rendition sequences (e.g. "\x1b[1;31m"), which only change colors and styles
+var _ansiSGRSequenceRegex = regexp.MustCompile(`^\x1b\[[0-9;]*m$`)
+
+// sanitizeTerminalLine removes control characters and ansi escape sequences (other than color and style sequences) which would break the layout
+func sanitizeTerminalLine(line string) string {
+	line = _ansiEscapeSequenceRegex.ReplaceAllStringFunc(line, func(sequence string) string {
+		if _ansiSGRSequenceRegex.MatchString(sequence) {
+			return sequence
+		}
+		return ""
+	})
+	line = strings.ReplaceAll(line, "\t", "    ")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\x1b' {
+			return -1
+		}
+		return r
+	}, line)
+}
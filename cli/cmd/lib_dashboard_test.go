@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeTerminalLine(t *testing.T) {
+	require.Equal(t, "plain text", sanitizeTerminalLine("plain text"))
+	require.Equal(t, "a    b", sanitizeTerminalLine("a\tb"))
+	require.Equal(t, "progress", sanitizeTerminalLine("\rprogress\n"))
+	require.Equal(t, "ab", sanitizeTerminalLine("a\x00\x07b"))
+
+	// color and style sequences are kept
+	require.Equal(t, "\x1b[1;31merror\x1b[0m", sanitizeTerminalLine("\x1b[1;31merror\x1b[0m"))
+	require.Equal(t, "\x1b[mreset", sanitizeTerminalLine("\x1b[mreset"))
+
+	// other control sequences are removed
+	require.Equal(t, "cleared", sanitizeTerminalLine("\x1b[2Jcleared"))
+	require.Equal(t, "moved", sanitizeTerminalLine("\x1b[10;20Hmoved"))
+	require.Equal(t, "hidden", sanitizeTerminalLine("\x1b[?25lhidden"))
+	require.Equal(t, "alt", sanitizeTerminalLine("\x1b[?1049halt"))
+
+	// operating system commands are removed
+	require.Equal(t, "title", sanitizeTerminalLine("\x1b]0;window title\x07title"))
+	require.Equal(t, "link", sanitizeTerminalLine("\x1b]8;;https://cortex.dev\x1b\\link"))
+
+	// two-character and incomplete sequences are removed
+	require.Equal(t, "saved", sanitizeTerminalLine("\x1b7saved"))
+	require.Equal(t, "reset", sanitizeTerminalLine("\x1bcreset"))
+	require.Equal(t, "text", sanitizeTerminalLine("text\x1b"))
+	require.Equal(t, "charset", sanitizeTerminalLine("\x1b(Bcharset"))
+	require.Equal(t, "31", sanitizeTerminalLine("\x1b[31"))
+}
+
+func TestTruncateTerminalLine(t *testing.T) {
+	require.Equal(t, "", truncateTerminalLine("", 5))
+	require.Equal(t, "short", truncateTerminalLine("short", 10))
+	require.Equal(t, "trunc", truncateTerminalLine("truncated", 5))
+	require.Equal(t, "", truncateTerminalLine("text", 0))
+
+	// multi-byte characters count as one visible character
+	require.Equal(t, "héllo", truncateTerminalLine("héllo wörld", 5))
+
+	// escape sequences are kept, but not counted
+	require.Equal(t, "\x1b[31mred\x1b[0m", truncateTerminalLine("\x1b[31mred\x1b[0m", 3))
+	require.Equal(t, "\x1b[31mre\x1b[0m", truncateTerminalLine("\x1b[31mred\x1b[0m", 2))
+	require.Equal(t, "\x1b[1;31mab\x1b[0m", truncateTerminalLine("\x1b[1;31mabc\x1b[0mdef", 2))
+}
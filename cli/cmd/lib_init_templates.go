@@ -0,0 +1,364 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+// the templates used by `cortex init`, keyed by template name (see initTemplateName()) and then by file path;
+// files are rendered with text/template, with initTemplateData as the data
+var _initTemplates = map[string]map[string]string{
+	"realtime-python": {
+		"cortex.yaml":      _initRealtimePythonConfig,
+		"predictor.py":     _initRealtimePythonPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initSamplePayload,
+	},
+	"realtime-tensorflow": {
+		"cortex.yaml":      _initRealtimeTensorFlowConfig,
+		"predictor.py":     _initRealtimeTensorFlowPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initIrisSamplePayload,
+	},
+	"realtime-onnx": {
+		"cortex.yaml":      _initRealtimeONNXConfig,
+		"predictor.py":     _initRealtimeONNXPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initIrisSamplePayload,
+	},
+	"batch-python": {
+		"cortex.yaml":      _initBatchPythonConfig,
+		"predictor.py":     _initBatchPythonPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initBatchSampleJobSubmission,
+	},
+	"batch-tensorflow": {
+		"cortex.yaml":      _initBatchTensorFlowConfig,
+		"predictor.py":     _initBatchTensorFlowPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initBatchIrisSampleJobSubmission,
+	},
+	"batch-onnx": {
+		"cortex.yaml":      _initBatchONNXConfig,
+		"predictor.py":     _initBatchONNXPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initBatchIrisSampleJobSubmission,
+	},
+	"traffic-splitter-python": {
+		"cortex.yaml":      _initTrafficSplitterPythonConfig,
+		"predictor.py":     _initRealtimePythonPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initSamplePayload,
+	},
+	"traffic-splitter-tensorflow": {
+		"cortex.yaml":      _initTrafficSplitterTensorFlowConfig,
+		"predictor.py":     _initRealtimeTensorFlowPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initIrisSamplePayload,
+	},
+	"traffic-splitter-onnx": {
+		"cortex.yaml":      _initTrafficSplitterONNXConfig,
+		"predictor.py":     _initRealtimeONNXPredictor,
+		"requirements.txt": _initRequirements,
+		".cortexignore":    _initCortexIgnore,
+		"sample.json":      _initIrisSamplePayload,
+	},
+}
+
+const _initRequirements = `# python packages which your predictor depends on (one per line, e.g. numpy==1.19.4)
+`
+
+const _initCortexIgnore = `# files and directories which should not be uploaded with your api (uses the same syntax as .gitignore)
+.git/
+__pycache__/
+*.ipynb
+`
+
+const _initSamplePayload = `{
+  "text": "hello world"
+}
+`
+
+const _initIrisSamplePayload = `{
+  "sepal_length": 5.2,
+  "sepal_width": 3.6,
+  "petal_length": 1.4,
+  "petal_width": 0.3
+}
+`
+
+const _initBatchSampleJobSubmission = `{
+  "item_list": {
+    "items": [
+      {"text": "hello world"},
+      {"text": "hello again"}
+    ],
+    "batch_size": 1
+  }
+}
+`
+
+const _initBatchIrisSampleJobSubmission = `{
+  "item_list": {
+    "items": [
+      {"sepal_length": 5.2, "sepal_width": 3.6, "petal_length": 1.4, "petal_width": 0.3},
+      {"sepal_length": 6.4, "sepal_width": 3.2, "petal_length": 4.5, "petal_width": 1.5}
+    ],
+    "batch_size": 1
+  }
+}
+`
+
+const _initRealtimePythonConfig = `- name: {{.APIName}}
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+  compute:
+    cpu: 1
+`
+
+const _initRealtimePythonPredictor = `class PythonPredictor:
+    def __init__(self, config):
+        # load your model here (e.g. download it from S3)
+        # config contains the predictor's "config" field from cortex.yaml
+        pass
+
+    def predict(self, payload):
+        # payload is the parsed JSON body of the request
+        return {"received": payload}
+`
+
+// the tensorflow and onnx templates serve the iris classifier from the examples, so that they can be deployed as-is
+const _initRealtimeTensorFlowConfig = `- name: {{.APIName}}
+  kind: RealtimeAPI
+  predictor:
+    type: tensorflow
+    path: predictor.py
+    model_path: s3://cortex-examples/tensorflow/iris-classifier/nn/  # replace with the S3 path of your SavedModel
+  compute:
+    cpu: 1
+`
+
+const _initRealtimeTensorFlowPredictor = `labels = ["setosa", "versicolor", "virginica"]
+
+
+class TensorFlowPredictor:
+    def __init__(self, tensorflow_client, config):
+        self.client = tensorflow_client
+
+    def predict(self, payload):
+        # payload is the parsed JSON body of the request
+        prediction = self.client.predict(payload)
+        predicted_class_id = int(prediction["class_ids"][0])
+        return labels[predicted_class_id]
+`
+
+const _initRealtimeONNXConfig = `- name: {{.APIName}}
+  kind: RealtimeAPI
+  predictor:
+    type: onnx
+    path: predictor.py
+    model_path: s3://cortex-examples/onnx/iris-classifier/  # replace with the S3 path of your ONNX model
+  compute:
+    cpu: 1
+`
+
+const _initRealtimeONNXPredictor = `labels = ["setosa", "versicolor", "virginica"]
+
+
+class ONNXPredictor:
+    def __init__(self, onnx_client, config):
+        self.client = onnx_client
+
+    def predict(self, payload):
+        # payload is the parsed JSON body of the request
+        model_input = [
+            payload["sepal_length"],
+            payload["sepal_width"],
+            payload["petal_length"],
+            payload["petal_width"],
+        ]
+
+        prediction = self.client.predict(model_input)
+        predicted_class_id = prediction[0][0]
+        return labels[predicted_class_id]
+`
+
+const _initBatchPythonConfig = `- name: {{.APIName}}
+  kind: BatchAPI
+  predictor:
+    type: python
+    path: predictor.py
+  compute:
+    cpu: 1
+`
+
+const _initBatchPythonPredictor = `class PythonPredictor:
+    def __init__(self, config, job_spec):
+        # load your model here (e.g. download it from S3)
+        # config contains the predictor's "config" field from cortex.yaml, merged with the job submission's "config" field
+        pass
+
+    def predict(self, payload, batch_id):
+        # payload is a batch of items from the job submission
+        for item in payload:
+            print(item)
+
+    def on_job_complete(self):
+        # optional: called once after all of the job's batches have been processed
+        pass
+`
+
+const _initBatchTensorFlowConfig = `- name: {{.APIName}}
+  kind: BatchAPI
+  predictor:
+    type: tensorflow
+    path: predictor.py
+    model_path: s3://cortex-examples/tensorflow/iris-classifier/nn/  # replace with the S3 path of your SavedModel
+  compute:
+    cpu: 1
+`
+
+const _initBatchTensorFlowPredictor = `labels = ["setosa", "versicolor", "virginica"]
+
+
+class TensorFlowPredictor:
+    def __init__(self, tensorflow_client, config, job_spec):
+        self.client = tensorflow_client
+
+    def predict(self, payload, batch_id):
+        # payload is a batch of items from the job submission
+        for item in payload:
+            prediction = self.client.predict(item)
+            print(labels[int(prediction["class_ids"][0])])
+`
+
+const _initBatchONNXConfig = `- name: {{.APIName}}
+  kind: BatchAPI
+  predictor:
+    type: onnx
+    path: predictor.py
+    model_path: s3://cortex-examples/onnx/iris-classifier/  # replace with the S3 path of your ONNX model
+  compute:
+    cpu: 1
+`
+
+const _initBatchONNXPredictor = `labels = ["setosa", "versicolor", "virginica"]
+
+
+class ONNXPredictor:
+    def __init__(self, onnx_client, config, job_spec):
+        self.client = onnx_client
+
+    def predict(self, payload, batch_id):
+        # payload is a batch of items from the job submission
+        for item in payload:
+            model_input = [
+                item["sepal_length"],
+                item["sepal_width"],
+                item["petal_length"],
+                item["petal_width"],
+            ]
+            prediction = self.client.predict(model_input)
+            print(labels[prediction[0][0]])
+`
+
+// the traffic splitter templates split traffic between two versions of the same api, which can then be changed independently
+const _initTrafficSplitterPythonConfig = `- name: {{.APIName}}-a
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}-b
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}
+  kind: TrafficSplitter
+  apis:
+    - name: {{.APIName}}-a
+      weight: 50
+    - name: {{.APIName}}-b
+      weight: 50
+`
+
+const _initTrafficSplitterTensorFlowConfig = `- name: {{.APIName}}-a
+  kind: RealtimeAPI
+  predictor:
+    type: tensorflow
+    path: predictor.py
+    model_path: s3://cortex-examples/tensorflow/iris-classifier/nn/  # replace with the S3 path of your SavedModel
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}-b
+  kind: RealtimeAPI
+  predictor:
+    type: tensorflow
+    path: predictor.py
+    model_path: s3://cortex-examples/tensorflow/iris-classifier/nn/  # replace with the S3 path of your other SavedModel
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}
+  kind: TrafficSplitter
+  apis:
+    - name: {{.APIName}}-a
+      weight: 50
+    - name: {{.APIName}}-b
+      weight: 50
+`
+
+const _initTrafficSplitterONNXConfig = `- name: {{.APIName}}-a
+  kind: RealtimeAPI
+  predictor:
+    type: onnx
+    path: predictor.py
+    model_path: s3://cortex-examples/onnx/iris-classifier/  # replace with the S3 path of your ONNX model
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}-b
+  kind: RealtimeAPI
+  predictor:
+    type: onnx
+    path: predictor.py
+    model_path: s3://cortex-examples/onnx/iris-classifier/  # replace with the S3 path of your other ONNX model
+  compute:
+    cpu: 1
+
+- name: {{.APIName}}
+  kind: TrafficSplitter
+  apis:
+    - name: {{.APIName}}-a
+      weight: 50
+    - name: {{.APIName}}-b
+      weight: 50
+`
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/stretchr/testify/require"
+)
+
+// the providers which each kind of template can be deployed to
+var _initTemplateProviders = map[string][]types.ProviderType{
+	"realtime":         {types.LocalProviderType, types.AWSProviderType, types.GCPProviderType},
+	"batch":            {types.LocalProviderType, types.AWSProviderType},
+	"traffic-splitter": {types.LocalProviderType, types.AWSProviderType, types.GCPProviderType},
+}
+
+func TestInitTemplatesAreValidAPIConfigs(t *testing.T) {
+	require.Len(t, _initTemplates, len(_initKinds)*len(_initPredictorTypes))
+
+	for _, kind := range _initKinds {
+		for _, predictorType := range _initPredictorTypes {
+			templateName := initTemplateName(kind, predictorType)
+			templateFiles, ok := _initTemplates[templateName]
+			require.True(t, ok, templateName)
+
+			dir, err := ioutil.TempDir("", "cortex-init-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			paths, err := writeInitTemplate(templateFiles, dir, initTemplateData{APIName: "my-api"})
+			require.NoError(t, err, templateName)
+			require.Contains(t, paths, "cortex.yaml", templateName)
+
+			configBytes, err := ioutil.ReadFile(filepath.Join(dir, "cortex.yaml"))
+			require.NoError(t, err)
+
+			require.NotEmpty(t, _initTemplateProviders[kind], kind)
+			for _, provider := range _initTemplateProviders[kind] {
+				_, err := spec.ExtractAPIConfigs(configBytes, provider, "cortex.yaml", nil, nil)
+				require.NoError(t, err, "%s (%s)", templateName, provider)
+			}
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+)
+
+const (
+	_loadTestRequestTimeout    = 60 * time.Second
+	_loadTestErrorStatus       = "error" // the status of requests which did not receive a response (e.g. timeouts)
+	_loadTestReplicaPollPeriod = 5 * time.Second
+	_loadTestMaxRPS            = 1000000 // the request ticker fires every time.Second / rps, so rps must be bounded
+)
+
+type loadTestConfig struct {
+	Endpoint    string
+	Payloads    [][]byte
+	RPS         int // if 0, each worker sends its next request as soon as it receives a response
+	Duration    time.Duration
+	Concurrency int
+}
+
+type loadTestReport struct {
+	NumRequests     int                    `json:"num_requests"`
+	NumSkipped      int                    `json:"num_skipped"` // requests which weren't sent because all workers were busy
+	DurationSeconds float64                `json:"duration_seconds"`
+	Throughput      float64                `json:"throughput"` // responses per second
+	StatusCodes     map[string]int         `json:"status_codes"`
+	LatencyMillis   loadTestLatencies      `json:"latency_millis"`
+	ReplicaTimeline []loadTestReplicaCount `json:"replica_timeline,omitempty"`
+}
+
+type loadTestLatencies struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+type loadTestReplicaCount struct {
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	Requested      int32   `json:"requested"`
+	Ready          int32   `json:"ready"`
+}
+
+// readLoadTestPayloads reads the payload file, or all files in the payload directory (in lexicographical order)
+func readLoadTestPayloads(payloadPath string) ([][]byte, error) {
+	payloadPath = files.UserRelToAbsPath(payloadPath)
+
+	paths := []string{payloadPath}
+	if files.IsDir(payloadPath) {
+		var err error
+		paths, err = files.ListDirRecursive(payloadPath, false, files.IgnoreHiddenFiles)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, ErrorLoadTestNoPayloads(payloadPath)
+		}
+		sort.Strings(paths)
+	}
+
+	payloads := make([][]byte, len(paths))
+	for i, path := range paths {
+		payload, err := files.ReadFileBytes(path)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = payload
+	}
+
+	return payloads, nil
+}
+
+// runLoadTest sends requests until the configured duration has elapsed or the command is interrupted; onTick is called every second with the report so far
+func runLoadTest(config loadTestConfig, onTick func(loadTestReport)) loadTestReport {
+	client := &http.Client{
+		Timeout: _loadTestRequestTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: config.Concurrency,
+		},
+	}
+
+	var mutex sync.Mutex
+	statusCodes := map[string]int{}
+	var latencies []time.Duration
+	numSkipped := 0
+
+	record := func(status string, latency time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		statusCodes[status]++
+		if status != _loadTestErrorStatus {
+			latencies = append(latencies, latency)
+		}
+	}
+
+	report := func(elapsed time.Duration) loadTestReport {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return buildLoadTestReport(statusCodes, latencies, numSkipped, elapsed)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stop := make(chan struct{})
+	requests := make(chan []byte)
+	var wg sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for payload := range requests {
+				status, latency := sendLoadTestRequest(client, config.Endpoint, payload)
+				record(status, latency)
+			}
+		}()
+	}
+
+	startTime := time.Now()
+	deadline := time.After(config.Duration)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	go func() {
+		defer close(requests)
+
+		var rateTicker *time.Ticker
+		if config.RPS > 0 {
+			rateTicker = time.NewTicker(time.Second / time.Duration(config.RPS))
+			defer rateTicker.Stop()
+		}
+
+		for i := 0; ; i++ {
+			payload := config.Payloads[i%len(config.Payloads)]
+
+			if rateTicker == nil {
+				select {
+				case requests <- payload:
+				case <-stop:
+					return
+				}
+				continue
+			}
+
+			select {
+			case <-rateTicker.C:
+			case <-stop:
+				return
+			}
+			select {
+			case requests <- payload:
+			case <-stop:
+				return
+			default:
+				mutex.Lock()
+				numSkipped++
+				mutex.Unlock()
+			}
+		}
+	}()
+
+	for done := false; !done; {
+		select {
+		case <-ticker.C:
+			if onTick != nil {
+				onTick(report(time.Since(startTime)))
+			}
+		case <-deadline:
+			done = true
+		case <-interrupt:
+			done = true
+		}
+	}
+
+	close(stop)
+	wg.Wait() // in-flight requests are allowed to finish
+	return report(time.Since(startTime))
+}
+
+func sendLoadTestRequest(client *http.Client, endpoint string, payload []byte) (string, time.Duration) {
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return _loadTestErrorStatus, 0
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	response, err := client.Do(req)
+	if err != nil {
+		return _loadTestErrorStatus, 0
+	}
+	io.Copy(ioutil.Discard, response.Body) // the connection can only be reused once the body has been read
+	response.Body.Close()
+
+	return strconv.Itoa(response.StatusCode), time.Since(start)
+}
+
+func buildLoadTestReport(statusCodes map[string]int, latencies []time.Duration, numSkipped int, elapsed time.Duration) loadTestReport {
+	report := loadTestReport{
+		NumSkipped:      numSkipped,
+		DurationSeconds: elapsed.Seconds(),
+		StatusCodes:     make(map[string]int, len(statusCodes)),
+	}
+
+	for status, count := range statusCodes {
+		report.StatusCodes[status] = count
+		report.NumRequests += count
+	}
+
+	if elapsed > 0 {
+		report.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+
+	if len(latencies) == 0 {
+		return report
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+
+	report.LatencyMillis = loadTestLatencies{
+		Mean: millis(total / time.Duration(len(sorted))),
+		P50:  millis(percentile(sorted, 50)),
+		P90:  millis(percentile(sorted, 90)),
+		P95:  millis(percentile(sorted, 95)),
+		P99:  millis(percentile(sorted, 99)),
+		Max:  millis(sorted[len(sorted)-1]),
+	}
+
+	return report
+}
+
+// percentile uses the nearest-rank method; sorted must not be empty
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// recordReplicaTimeline polls getReplicaCounts until stop is closed, and returns the replica counts which were observed
+func recordReplicaTimeline(getReplicaCounts func() (requested int32, ready int32, err error), stop <-chan struct{}) <-chan []loadTestReplicaCount {
+	result := make(chan []loadTestReplicaCount, 1)
+
+	go func() {
+		startTime := time.Now()
+		timeline := []loadTestReplicaCount{}
+		ticker := time.NewTicker(_loadTestReplicaPollPeriod)
+		defer ticker.Stop()
+
+		for {
+			requested, ready, err := getReplicaCounts()
+			if err == nil {
+				timeline = append(timeline, loadTestReplicaCount{
+					ElapsedSeconds: time.Since(startTime).Seconds(),
+					Requested:      requested,
+					Ready:          ready,
+				})
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				result <- timeline
+				return
+			}
+		}
+	}()
+
+	return result
+}
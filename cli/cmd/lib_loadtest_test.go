@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	single := []time.Duration{7 * time.Millisecond}
+	require.Equal(t, 7*time.Millisecond, percentile(single, 0))
+	require.Equal(t, 7*time.Millisecond, percentile(single, 50))
+	require.Equal(t, 7*time.Millisecond, percentile(single, 100))
+
+	sorted := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 1*time.Millisecond, percentile(sorted, 0))
+	require.Equal(t, 1*time.Millisecond, percentile(sorted, 10))
+	require.Equal(t, 2*time.Millisecond, percentile(sorted, 11))
+	require.Equal(t, 5*time.Millisecond, percentile(sorted, 50))
+	require.Equal(t, 9*time.Millisecond, percentile(sorted, 90))
+	require.Equal(t, 10*time.Millisecond, percentile(sorted, 95))
+	require.Equal(t, 10*time.Millisecond, percentile(sorted, 100))
+}
+
+func TestBuildLoadTestReport(t *testing.T) {
+	statusCodes := map[string]int{"200": 3, "500": 1, _loadTestErrorStatus: 1}
+	latencies := []time.Duration{40 * time.Millisecond, 10 * time.Millisecond, 30 * time.Millisecond, 20 * time.Millisecond}
+
+	report := buildLoadTestReport(statusCodes, latencies, 2, 2*time.Second)
+
+	require.Equal(t, 5, report.NumRequests)
+	require.Equal(t, 2, report.NumSkipped)
+	require.Equal(t, 2.0, report.DurationSeconds)
+	require.Equal(t, 2.0, report.Throughput)
+	require.Equal(t, statusCodes, report.StatusCodes)
+	require.Equal(t, loadTestLatencies{
+		Mean: 25,
+		P50:  20,
+		P90:  40,
+		P95:  40,
+		P99:  40,
+		Max:  40,
+	}, report.LatencyMillis)
+
+	// the input latencies are not reordered
+	require.Equal(t, 40*time.Millisecond, latencies[0])
+
+	// the report's status codes are a copy
+	report.StatusCodes["200"]++
+	require.Equal(t, 3, statusCodes["200"])
+}
+
+func TestBuildLoadTestReportNoResponses(t *testing.T) {
+	report := buildLoadTestReport(map[string]int{_loadTestErrorStatus: 2}, nil, 0, 0)
+
+	require.Equal(t, 2, report.NumRequests)
+	require.Equal(t, 0.0, report.Throughput)
+	require.Equal(t, loadTestLatencies{}, report.LatencyMillis)
+}
@@ -61,12 +61,20 @@ func runManager(containerConfig *container.Config, addNewLineAfterPull bool, cop
 		return "", nil, err
 	}
 
-	pulledImage, err := docker.PullImage(containerConfig.Image, docker.NoAuth, docker.PrintDots)
+	// machine-readable output is printed to stdout, so the manager's output is redirected to stderr
+	pullVerbosity := docker.PrintDots
+	var managerOut io.Writer = os.Stdout
+	if _flagOutput.IsMachineReadable() {
+		pullVerbosity = docker.NoPrint
+		managerOut = os.Stderr
+	}
+
+	pulledImage, err := docker.PullImage(containerConfig.Image, docker.NoAuth, pullVerbosity)
 	if err != nil {
 		return "", nil, err
 	}
 
-	if pulledImage && addNewLineAfterPull {
+	if pulledImage && addNewLineAfterPull && !_flagOutput.IsMachineReadable() {
 		fmt.Println()
 	}
 
@@ -121,7 +129,7 @@ func runManager(containerConfig *container.Config, addNewLineAfterPull bool, cop
 	var outputBuffer bytes.Buffer
 	tee := io.TeeReader(logsOutput.Reader, &outputBuffer)
 
-	_, err = io.Copy(os.Stdout, tee)
+	_, err = io.Copy(managerOut, tee)
 	if err != nil && err != io.EOF {
 		return "", nil, errors.WithStack(err)
 	}
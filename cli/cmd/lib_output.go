@@ -0,0 +1,73 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/yaml"
+)
+
+// formatOutput formats obj as json or yaml (depending on the -o/--output flag); yaml output has the same field names as json output
+func formatOutput(obj interface{}) (string, error) {
+	jsonBytes, err := libjson.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	if _flagOutput != flags.YAMLOutputType {
+		return string(jsonBytes), nil
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(jsonBytes, &generic); err != nil {
+		return "", errors.WithStack(err)
+	}
+	yamlBytes, err := yaml.Marshal(generic)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(yamlBytes), nil
+}
+
+func printOutput(obj interface{}) error {
+	out, err := formatOutput(obj)
+	if err != nil {
+		return err
+	}
+	fmt.Print(s.EnsureSingleTrailingNewLine(out))
+	return nil
+}
+
+// printOutputItem prints one item of a stream (e.g. an event) as a line of json, or as a yaml document
+func printOutputItem(obj interface{}) error {
+	out, err := formatOutput(obj)
+	if err != nil {
+		return err
+	}
+	if _flagOutput == flags.YAMLOutputType {
+		fmt.Print("---\n" + s.EnsureSingleTrailingNewLine(out))
+		return nil
+	}
+	fmt.Println(out)
+	return nil
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/msgpack"
+)
+
+const (
+	_contentTypeJSON        = "application/json"
+	_contentTypeMsgpack     = "application/msgpack"
+	_contentTypeOctetStream = "application/octet-stream"
+	_predictRequestTimeout  = 600 * time.Second
+	_predictRetryBaseDelay  = time.Second
+	_maxJSONLLineBytes      = 64 * 1024 * 1024
+
+	// the maximum number of inputs which can be dispatched ahead of the earliest unwritten output, as a multiple of parallelism
+	_predictReorderWindowFactor = 4
+)
+
+type predictInput struct {
+	Name        string // the input's file path (relative to the input directory), or "path:line" for jsonl inputs
+	Payload     []byte
+	ContentType string
+}
+
+// predictOutput is written as one line of the output jsonl file for each input (in the same order as the inputs)
+type predictOutput struct {
+	Input          string      `json:"input"`
+	StatusCode     int         `json:"status_code,omitempty"`
+	Response       interface{} `json:"response,omitempty"`
+	ResponseBase64 string      `json:"response_base64,omitempty"` // set instead of response if the response is binary
+	Error          string      `json:"error,omitempty"`           // set if no response was received
+}
+
+func (output *predictOutput) succeeded() bool {
+	return output.Error == "" && output.StatusCode >= 200 && output.StatusCode < 300
+}
+
+func isJSONLPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".jsonl")
+}
+
+// readPredictInputs reads a single file, each line of a jsonl file, or each file in a directory (in lexicographical order)
+func readPredictInputs(inputPath string, contentType string, useMsgpack bool) ([]predictInput, error) {
+	inputPath = files.UserRelToAbsPath(inputPath)
+
+	if files.IsDir(inputPath) {
+		paths, err := files.ListDirRecursive(inputPath, false, files.IgnoreHiddenFiles)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, ErrorNoPredictInputs(inputPath)
+		}
+		sort.Strings(paths)
+
+		inputs := make([]predictInput, len(paths))
+		for i, path := range paths {
+			payload, err := files.ReadFileBytes(path)
+			if err != nil {
+				return nil, err
+			}
+			inputs[i], err = newPredictInput(files.TrimDirPrefix(path, inputPath), payload, predictContentType(path, contentType), useMsgpack)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return inputs, nil
+	}
+
+	if isJSONLPath(inputPath) {
+		return readJSONLInputs(inputPath, useMsgpack)
+	}
+
+	payload, err := files.ReadFileBytes(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	input, err := newPredictInput(inputPath, payload, predictContentType(inputPath, contentType), useMsgpack)
+	if err != nil {
+		return nil, err
+	}
+	return []predictInput{input}, nil
+}
+
+func readJSONLInputs(path string, useMsgpack bool) ([]predictInput, error) {
+	file, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var inputs []predictInput
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), _maxJSONLLineBytes)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		payload := make([]byte, len(line))
+		copy(payload, line)
+
+		input, err := newPredictInput(path+":"+strconv.Itoa(lineNum), payload, _contentTypeJSON, useMsgpack)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+
+	if len(inputs) == 0 {
+		return nil, ErrorNoPredictInputs(path)
+	}
+	return inputs, nil
+}
+
+// json payloads are converted to msgpack if useMsgpack is set
+func newPredictInput(name string, payload []byte, contentType string, useMsgpack bool) (predictInput, error) {
+	if useMsgpack && contentType == _contentTypeJSON {
+		var obj interface{}
+		if err := json.DecodeWithNumber(payload, &obj); err != nil {
+			return predictInput{}, errors.Wrap(err, name)
+		}
+		msgpackBytes, err := msgpack.Marshal(jsonNumbersToNative(obj))
+		if err != nil {
+			return predictInput{}, errors.Wrap(err, name)
+		}
+		return predictInput{Name: name, Payload: msgpackBytes, ContentType: _contentTypeMsgpack}, nil
+	}
+
+	return predictInput{Name: name, Payload: payload, ContentType: contentType}, nil
+}
+
+// the content type is inferred from the file's extension unless it is overridden
+func predictContentType(path string, contentTypeOverride string) string {
+	if contentTypeOverride != "" {
+		return contentTypeOverride
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".jsonl":
+		return _contentTypeJSON
+	case ".msgpack", ".mpk":
+		return _contentTypeMsgpack
+	default:
+		if contentType := mime.TypeByExtension(ext); contentType != "" {
+			return contentType
+		}
+		return _contentTypeOctetStream
+	}
+}
+
+// runPredictions sends the inputs with up to parallelism requests in flight, and calls onOutput with each output in the same order as the inputs
+func runPredictions(apiEndpoint string, inputs []predictInput, parallelism int, retries int, onOutput func(predictOutput) error) error {
+	client := &http.Client{
+		Timeout: _predictRequestTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: parallelism,
+		},
+	}
+
+	type indexedOutput struct {
+		index  int
+		output predictOutput
+	}
+
+	indexes := make(chan int)
+	outputs := make(chan indexedOutput)
+
+	// a slot is taken for each dispatched input and released once its output has been written, which bounds the number of outputs held in pending
+	window := make(chan struct{}, _predictReorderWindowFactor*parallelism)
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				outputs <- indexedOutput{index, sendPredictRequest(client, apiEndpoint, inputs[index], retries)}
+			}
+		}()
+	}
+
+	go func() {
+		for i := range inputs {
+			window <- struct{}{}
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(outputs)
+	}()
+
+	// outputs which arrive out of order are held until all of the preceding outputs have been written
+	pending := map[int]predictOutput{}
+	nextIndex := 0
+	var onOutputErr error
+	for indexedOutput := range outputs {
+		pending[indexedOutput.index] = indexedOutput.output
+		for {
+			output, ok := pending[nextIndex]
+			if !ok {
+				break
+			}
+			delete(pending, nextIndex)
+			nextIndex++
+			<-window
+			if onOutputErr == nil {
+				onOutputErr = onOutput(output)
+			}
+		}
+	}
+
+	return onOutputErr
+}
+
+// requests which fail to connect, or which receive a 429 or 5XX response, are retried with exponential backoff
+func sendPredictRequest(client *http.Client, apiEndpoint string, input predictInput, retries int) predictOutput {
+	var output predictOutput
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(_predictRetryBaseDelay * time.Duration(1<<uint(attempt-1)))
+		}
+
+		output = sendPredictRequestOnce(client, apiEndpoint, input)
+		if output.Error == "" && output.StatusCode != http.StatusTooManyRequests && output.StatusCode < 500 {
+			break
+		}
+	}
+	return output
+}
+
+func sendPredictRequestOnce(client *http.Client, apiEndpoint string, input predictInput) predictOutput {
+	output := predictOutput{Input: input.Name}
+
+	req, err := http.NewRequest("POST", apiEndpoint, bytes.NewReader(input.Payload))
+	if err != nil {
+		output.Error = errors.Message(err)
+		return output
+	}
+	req.Header.Set("Content-Type", input.ContentType)
+
+	response, err := client.Do(req)
+	if err != nil {
+		output.Error = errors.Message(err)
+		return output
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		output.Error = errors.Message(err)
+		return output
+	}
+
+	output.StatusCode = response.StatusCode
+	output.Response, output.ResponseBase64 = decodePredictResponse(response.Header.Get("Content-Type"), body)
+	return output
+}
+
+// json and msgpack responses are decoded, text responses are returned as strings, and other responses are base64-encoded
+func decodePredictResponse(contentType string, body []byte) (interface{}, string) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == _contentTypeJSON:
+		var obj interface{}
+		if err := json.DecodeWithNumber(body, &obj); err == nil {
+			return obj, ""
+		}
+	case mediaType == _contentTypeMsgpack:
+		if obj, err := msgpack.UnmarshalToInterface(body); err == nil {
+			return msgpackToJSONCompatible(obj), ""
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(body), ""
+	}
+
+	return nil, base64.StdEncoding.EncodeToString(body)
+}
+
+// json.Number would be encoded as a string by msgpack
+func jsonNumbersToNative(obj interface{}) interface{} {
+	switch typed := obj.(type) {
+	case gojson.Number:
+		if intVal, err := typed.Int64(); err == nil {
+			return intVal
+		}
+		floatVal, _ := typed.Float64()
+		return floatVal
+	case map[string]interface{}:
+		for key, val := range typed {
+			typed[key] = jsonNumbersToNative(val)
+		}
+	case []interface{}:
+		for i, val := range typed {
+			typed[i] = jsonNumbersToNative(val)
+		}
+	}
+	return obj
+}
+
+// msgpack maps are decoded with interface{} keys, which can't be encoded as json
+func msgpackToJSONCompatible(obj interface{}) interface{} {
+	switch typed := obj.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for key, val := range typed {
+			converted[fmt.Sprint(key)] = msgpackToJSONCompatible(val)
+		}
+		return converted
+	case []interface{}:
+		for i, val := range typed {
+			typed[i] = msgpackToJSONCompatible(val)
+		}
+	case []byte:
+		return base64.StdEncoding.EncodeToString(typed)
+	}
+	return obj
+}
+
+// writePredictOutput writes the output as a single line of jsonl
+func writePredictOutput(writer io.Writer, output predictOutput) error {
+	outputBytes, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(append(outputBytes, '\n'))
+	return errors.WithStack(err)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPredictionsBoundsPendingOutputs(t *testing.T) {
+	parallelism := 2
+	numInputs := 100
+
+	var numReceived int32
+	unblockFirst := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		atomic.AddInt32(&numReceived, 1)
+		if string(body) == "0" {
+			<-unblockFirst
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	inputs := make([]predictInput, numInputs)
+	for i := range inputs {
+		inputs[i] = predictInput{Name: strconv.Itoa(i), Payload: []byte(strconv.Itoa(i)), ContentType: "text/plain"}
+	}
+
+	var numReceivedWhileBlocked int32
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		atomic.StoreInt32(&numReceivedWhileBlocked, atomic.LoadInt32(&numReceived))
+		close(unblockFirst)
+	}()
+
+	var outputs []predictOutput
+	err := runPredictions(server.URL, inputs, parallelism, 0, func(output predictOutput) error {
+		outputs = append(outputs, output)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// while the first input was blocked, no more than the reorder window could be dispatched
+	require.LessOrEqual(t, int(atomic.LoadInt32(&numReceivedWhileBlocked)), _predictReorderWindowFactor*parallelism)
+
+	require.Len(t, outputs, numInputs)
+	for i, output := range outputs {
+		require.Equal(t, strconv.Itoa(i), output.Input)
+		require.Equal(t, strconv.Itoa(i), output.Response)
+	}
+}
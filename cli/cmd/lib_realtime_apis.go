@@ -31,6 +31,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
@@ -53,6 +54,10 @@ func realtimeAPITable(realtimeAPI schema.APIResponse, env cliconfig.Environment)
 
 	out += t.MustFormat()
 
+	if len(realtimeAPI.ColorStatuses) > 0 {
+		out += "\n" + colorStatusesTable(realtimeAPI.ColorStatuses)
+	}
+
 	if env.Provider != types.LocalProviderType && realtimeAPI.Spec.Monitoring != nil {
 		switch realtimeAPI.Spec.Monitoring.ModelType {
 		case userconfig.ClassificationModelType:
@@ -85,6 +90,33 @@ func realtimeAPITable(realtimeAPI schema.APIResponse, env cliconfig.Environment)
 	return out, nil
 }
 
+// colorStatusesTable shows the deployments of an api which is undergoing a blue/green update
+func colorStatusesTable(colorStatuses []schema.ColorStatus) string {
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "color"},
+			{Title: "role"},
+			{Title: _titleStatus},
+			{Title: _titleUpToDate},
+			{Title: _titleRequested},
+			{Title: _titleFailed},
+		},
+	}
+
+	for _, colorStatus := range colorStatuses {
+		t.Rows = append(t.Rows, []interface{}{
+			colorStatus.Color,
+			string(colorStatus.Role),
+			colorStatus.Status.Message(),
+			colorStatus.Status.Updated.Ready,
+			colorStatus.Status.Requested,
+			colorStatus.Status.Updated.TotalFailed(),
+		})
+	}
+
+	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+}
+
 func realtimeAPIsTable(realtimeAPIs []schema.APIResponse, envNames []string) table.Table {
 	rows := make([][]interface{}, 0, len(realtimeAPIs))
 
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const _rolloutPollPeriod = 2 * time.Second
+
+// waitForRollouts blocks until all successfully deployed RealtimeAPIs have finished rolling out, one of them fails, or the timeout is reached
+func waitForRollouts(operatorConfig cluster.OperatorConfig, deployResults []schema.DeployResult, timeout time.Duration) error {
+	// api name -> api id which is being rolled out
+	pendingAPIs := map[string]string{}
+	for _, result := range deployResults {
+		if result.Error != "" || result.API == nil || result.API.Spec.Kind != userconfig.RealtimeAPIKind {
+			continue
+		}
+		pendingAPIs[result.API.Spec.Name] = result.API.Spec.ID
+	}
+
+	if len(pendingAPIs) == 0 {
+		return nil
+	}
+
+	printProgress := _flagOutput == flags.PrettyOutputType
+	if printProgress {
+		fmt.Println()
+	}
+
+	lastProgress := map[string]string{}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		for _, apiName := range pendingAPINames(pendingAPIs) {
+			apiRes, err := cluster.GetAPI(operatorConfig, apiName)
+			if err != nil {
+				return err
+			}
+			if len(apiRes) == 0 || apiRes[0].Status == nil {
+				return errors.ErrorUnexpected("unable to get the status of api", apiName)
+			}
+			apiStatus := apiRes[0].Status
+
+			// the operator records failures which it detects (e.g. a failed blue/green smoke test)
+			if rolloutFailure := findRolloutFailure(apiRes[0].APIVersions, pendingAPIs[apiName]); rolloutFailure != nil {
+				return ErrorAPIRolloutFailed(apiName, rolloutFailureStr(rolloutFailure))
+			}
+
+			// during a blue/green update, the api's status refers to the previous version until traffic is switched
+			if candidateStatus := findCandidateStatus(apiRes[0].ColorStatuses, pendingAPIs[apiName]); candidateStatus != nil {
+				apiStatus = candidateStatus
+			}
+
+			// the operator hasn't applied the new deployment yet
+			if apiStatus.APIID != pendingAPIs[apiName] {
+				continue
+			}
+
+			if isRolloutFailure(apiStatus.Code) {
+				if printProgress {
+					printFailedReplicaLogs(operatorConfig, apiName)
+				}
+				return ErrorAPIRolloutFailed(apiName, fmt.Sprintf("%s (%s)", apiStatus.Code.Message(), apiStatus.Code.String()))
+			}
+
+			// a blue/green candidate is complete once the operator switches traffic to it
+			if isRolloutComplete(&apiStatus.ReplicaCounts) && apiStatus == apiRes[0].Status {
+				delete(pendingAPIs, apiName)
+				if printProgress {
+					fmt.Println(console.Bold(fmt.Sprintf("%s is live", apiName)))
+				}
+				continue
+			}
+
+			progress := rolloutProgress(&apiStatus.ReplicaCounts)
+			if printProgress && progress != lastProgress[apiName] {
+				fmt.Printf("%s: %s\n", apiName, progress)
+				lastProgress[apiName] = progress
+			}
+		}
+
+		if len(pendingAPIs) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return ErrorAPIRolloutTimeout(pendingAPINames(pendingAPIs), timeout)
+		}
+
+		time.Sleep(_rolloutPollPeriod)
+	}
+}
+
+func pendingAPINames(pendingAPIs map[string]string) []string {
+	apiNames := make([]string, 0, len(pendingAPIs))
+	for apiName := range pendingAPIs {
+		apiNames = append(apiNames, apiName)
+	}
+	sort.Strings(apiNames)
+	return apiNames
+}
+
+func findRolloutFailure(apiVersions []schema.APIVersion, apiID string) *schema.RolloutFailure {
+	for _, apiVersion := range apiVersions {
+		// ignore failures from previous attempts to deploy the same api id
+		if apiVersion.APIID == apiID && apiVersion.RolloutFailure != nil && apiVersion.RolloutFailure.FailedAt >= apiVersion.LastUpdated {
+			return apiVersion.RolloutFailure
+		}
+	}
+	return nil
+}
+
+func findCandidateStatus(colorStatuses []schema.ColorStatus, apiID string) *status.Status {
+	for i := range colorStatuses {
+		if colorStatuses[i].Role == schema.CandidateColorRole && colorStatuses[i].Status.APIID == apiID {
+			return &colorStatuses[i].Status
+		}
+	}
+	return nil
+}
+
+func isRolloutFailure(code status.Code) bool {
+	switch code {
+	case status.Error, status.ErrorImagePull, status.OOM, status.Stalled:
+		return true
+	}
+	return false
+}
+
+// a rollout is complete once all requested replicas are up-to-date and ready, and no stale replicas are still serving
+func isRolloutComplete(counts *status.ReplicaCounts) bool {
+	return counts.Updated.Ready >= counts.Requested && counts.Stale.Ready == 0 && counts.Stale.Initializing == 0 && counts.Stale.Pending == 0
+}
+
+func rolloutProgress(counts *status.ReplicaCounts) string {
+	progress := fmt.Sprintf("%d/%d updated %s ready", counts.Updated.Ready, counts.Requested, s.PluralS("replica", counts.Requested))
+
+	if counts.Updated.Initializing > 0 || counts.Updated.Pending > 0 {
+		progress += fmt.Sprintf(" (%d initializing, %d pending)", counts.Updated.Initializing, counts.Updated.Pending)
+	}
+
+	if counts.Stale.Ready > 0 {
+		progress += fmt.Sprintf(", %d stale %s ready", counts.Stale.Ready, s.PluralS("replica", counts.Stale.Ready))
+	}
+
+	return progress
+}
+
+func printFailedReplicaLogs(operatorConfig cluster.OperatorConfig, apiName string) {
+	replicaLogsRes, err := cluster.GetReplicaLogs(operatorConfig, apiName)
+	if err != nil {
+		// the rollout failure is more important to surface than the failure to retrieve logs
+		return
+	}
+
+	for _, replica := range replicaLogsRes.Replicas {
+		fmt.Println()
+		fmt.Println(console.Bold(fmt.Sprintf("%s (%s):", replica.Name, strings.ToLower(replica.Status))))
+		if strings.TrimSpace(replica.Logs) == "" {
+			fmt.Println("no logs available")
+		} else {
+			fmt.Print(s.EnsureSingleTrailingNewLine(replica.Logs))
+		}
+	}
+	fmt.Println()
+}
@@ -21,12 +21,14 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
 )
 
 const (
@@ -45,13 +47,18 @@ func trafficSplitterTable(trafficSplitter schema.APIResponse, env cliconfig.Envi
 		return "", err
 	}
 	t.FindHeaderByTitle(_titleEnvironment).Hidden = true
+	if env.Provider == types.LocalProviderType {
+		t.FindHeaderByTitle(_titleRequested).Hidden = true
+	}
 
 	out += t.MustFormat()
 
 	out += "\n" + console.Bold("last updated: ") + libtime.SinceStr(&lastUpdated)
 	out += "\n" + console.Bold("endpoint: ") + trafficSplitter.Endpoint
 
-	out += "\n" + apiHistoryTable(trafficSplitter.APIVersions)
+	if env.Provider != types.LocalProviderType {
+		out += "\n" + apiHistoryTable(trafficSplitter.APIVersions)
+	}
 
 	if !_flagVerbose {
 		return out, nil
@@ -66,7 +73,13 @@ func trafficSplitTable(trafficSplitter schema.APIResponse, env cliconfig.Environ
 	rows := make([][]interface{}, 0, len(trafficSplitter.Spec.APIs))
 
 	for _, api := range trafficSplitter.Spec.APIs {
-		apisRes, err := cluster.GetAPI(MustGetOperatorConfig(env.Name), api.Name)
+		var apisRes []schema.APIResponse
+		var err error
+		if env.Provider == types.LocalProviderType {
+			apisRes, err = local.GetAPI(api.Name)
+		} else {
+			apisRes, err = cluster.GetAPI(MustGetOperatorConfig(env.Name), api.Name)
+		}
 		if err != nil {
 			return table.Table{}, err
 		}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagLoadTestEnv            string
+	_flagLoadTestRPS            int
+	_flagLoadTestDuration       time.Duration
+	_flagLoadTestConcurrency    int
+	_flagLoadTestRecordReplicas bool
+)
+
+func loadTestInit() {
+	_loadTestCmd.Flags().SortFlags = false
+	_loadTestCmd.Flags().StringVarP(&_flagLoadTestEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_loadTestCmd.Flags().IntVar(&_flagLoadTestRPS, "rps", 10, "target number of requests per second (0 to send requests as fast as the workers allow)")
+	_loadTestCmd.Flags().DurationVar(&_flagLoadTestDuration, "duration", 30*time.Second, "how long to send requests for (e.g. 30s, 5m)")
+	_loadTestCmd.Flags().IntVar(&_flagLoadTestConcurrency, "concurrency", 10, "maximum number of requests in flight at once")
+	_loadTestCmd.Flags().BoolVar(&_flagLoadTestRecordReplicas, "record-replicas", false, "record the api's replica counts during the test (to validate autoscaling)")
+	_loadTestCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+}
+
+var _loadTestCmd = &cobra.Command{
+	Use:   "loadtest API_NAME PAYLOAD",
+	Short: "send prediction requests to an api at a target rate and report latency and throughput",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagLoadTestEnv)
+		if err != nil {
+			telemetry.Event("cli.loadtest")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.loadtest", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		if !_flagOutput.IsMachineReadable() {
+			err = printEnvIfNotSpecified(_flagLoadTestEnv, cmd)
+			if err != nil {
+				exit.Error(err)
+			}
+		}
+
+		if _flagLoadTestRPS < 0 || _flagLoadTestRPS > _loadTestMaxRPS {
+			exit.Error(ErrorInvalidFlagValue("--rps", fmt.Sprintf("between 0 and %d (inclusive)", _loadTestMaxRPS)))
+		}
+		if _flagLoadTestConcurrency < 1 {
+			exit.Error(ErrorInvalidFlagValue("--concurrency", "greater than or equal to 1"))
+		}
+		if _flagLoadTestDuration <= 0 {
+			exit.Error(ErrorInvalidFlagValue("--duration", "greater than 0"))
+		}
+		if _flagLoadTestRecordReplicas && env.Provider == types.LocalProviderType {
+			exit.Error(ErrorFlagNotSupportedInLocalEnvironment("--record-replicas"))
+		}
+
+		apiName := args[0]
+
+		apiRes, err := getReadyRealtimeAPI(env, apiName)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		payloads, err := readLoadTestPayloads(args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var replicaTimeline <-chan []loadTestReplicaCount
+		stopRecording := make(chan struct{})
+		if _flagLoadTestRecordReplicas {
+			operatorConfig := MustGetOperatorConfig(env.Name)
+			replicaTimeline = recordReplicaTimeline(func() (int32, int32, error) {
+				apisRes, err := cluster.GetAPI(operatorConfig, apiName)
+				if err != nil {
+					return 0, 0, err
+				}
+				if len(apisRes) == 0 {
+					return 0, 0, errors.ErrorUnexpected(fmt.Sprintf("unable to find API %s", apiName))
+				}
+				replicaCounts := apisRes[0].Status.ReplicaCounts
+				return replicaCounts.Requested, replicaCounts.Updated.Ready + replicaCounts.Stale.Ready, nil
+			}, stopRecording)
+		}
+
+		var onTick func(loadTestReport)
+		if !_flagOutput.IsMachineReadable() {
+			fmt.Printf("sending requests to %s for %s (ctrl+c to stop early)\n", apiRes.Endpoint, _flagLoadTestDuration)
+			onTick = func(report loadTestReport) {
+				fmt.Printf("\r%.0fs elapsed: %d %s, p50 %sms", report.DurationSeconds, report.NumRequests, s.PluralS("response", report.NumRequests), s.Round(report.LatencyMillis.P50, 1, 1))
+			}
+		}
+
+		report := runLoadTest(loadTestConfig{
+			Endpoint:    apiRes.Endpoint,
+			Payloads:    payloads,
+			RPS:         _flagLoadTestRPS,
+			Duration:    _flagLoadTestDuration,
+			Concurrency: _flagLoadTestConcurrency,
+		}, onTick)
+
+		if replicaTimeline != nil {
+			close(stopRecording)
+			report.ReplicaTimeline = <-replicaTimeline
+		}
+
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(report); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		fmt.Print("\n\n" + loadTestReportStr(report))
+	},
+}
+
+func loadTestReportStr(report loadTestReport) string {
+	out := console.Bold(fmt.Sprintf("received %d %s in %ss (%s responses/s)", report.NumRequests, s.PluralS("response", report.NumRequests), s.Round(report.DurationSeconds, 1, 1), s.Round(report.Throughput, 1, 1))) + "\n"
+
+	if report.NumSkipped > 0 {
+		out += fmt.Sprintf("\n%d %s not sent because all %d workers were busy; increase --concurrency to reach the target rate\n", report.NumSkipped, s.PluralCustom("request was", "requests were", report.NumSkipped), _flagLoadTestConcurrency)
+	}
+
+	if report.NumRequests == 0 {
+		return out
+	}
+
+	statuses := make([]string, 0, len(report.StatusCodes))
+	for status := range report.StatusCodes {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	statusRows := make([][]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		count := report.StatusCodes[status]
+		statusRows = append(statusRows, []interface{}{status, count, s.Round(float64(count)/float64(report.NumRequests)*100, 1, 1) + "%"})
+	}
+	statusTable := table.Table{
+		Headers: []table.Header{{Title: "status"}, {Title: "count"}, {Title: "percent"}},
+		Rows:    statusRows,
+	}
+	out += "\n" + statusTable.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+
+	latencies := report.LatencyMillis
+	latencyTable := table.Table{
+		Headers: []table.Header{{Title: "latency"}, {Title: "mean"}, {Title: "p50"}, {Title: "p90"}, {Title: "p95"}, {Title: "p99"}, {Title: "max"}},
+		Rows: [][]interface{}{{
+			"ms",
+			s.Round(latencies.Mean, 1, 1),
+			s.Round(latencies.P50, 1, 1),
+			s.Round(latencies.P90, 1, 1),
+			s.Round(latencies.P95, 1, 1),
+			s.Round(latencies.P99, 1, 1),
+			s.Round(latencies.Max, 1, 1),
+		}},
+	}
+	out += "\n" + latencyTable.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+
+	if len(report.ReplicaTimeline) > 0 {
+		replicaRows := make([][]interface{}, 0, len(report.ReplicaTimeline))
+		for _, replicaCount := range report.ReplicaTimeline {
+			replicaRows = append(replicaRows, []interface{}{s.Round(replicaCount.ElapsedSeconds, 0, 0) + "s", replicaCount.Requested, replicaCount.Ready})
+		}
+		replicaTable := table.Table{
+			Headers: []table.Header{{Title: "elapsed"}, {Title: "requested replicas"}, {Title: "ready replicas"}},
+			Rows:    replicaRows,
+		}
+		out += "\n" + replicaTable.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+	}
+
+	return out
+}
@@ -18,28 +18,50 @@ package cmd
 
 import (
 	"fmt"
-	"net/http"
-	"net/url"
+	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/spf13/cobra"
 )
 
-var _flagLogsEnv string
+const (
+	_defaultLogSearchSince = "1h"
+	_logSourcePods         = "pods"
+)
+
+var (
+	_flagLogsEnv     string
+	_flagLogsSince   string
+	_flagLogsUntil   string
+	_flagLogsGrep    string
+	_flagLogsReplica string
+	_flagLogsSource  string
+)
 
 func logsInit() {
 	_logsCmd.Flags().SortFlags = false
 	_logsCmd.Flags().StringVarP(&_flagLogsEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_logsCmd.Flags().StringVar(&_flagLogsSince, "since", "", fmt.Sprintf("search logs starting from a duration before now (e.g. 2h) or a timestamp (e.g. 2020-12-01T15:04:05Z) instead of streaming (default %s if another search flag is set)", _defaultLogSearchSince))
+	_logsCmd.Flags().StringVar(&_flagLogsUntil, "until", "", "search logs up to a duration before now (e.g. 30m) or a timestamp (default now)")
+	_logsCmd.Flags().StringVar(&_flagLogsGrep, "grep", "", "only show log lines which contain this string")
+	_logsCmd.Flags().StringVar(&_flagLogsReplica, "replica", "", "only show log lines from this replica")
+	_logsCmd.Flags().StringVar(&_flagLogsSource, "source", "", fmt.Sprintf("set to %s to stream logs directly from each replica instead of from the cluster's log pipeline", _logSourcePods))
+	_logsCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 }
 
 var _logsCmd = &cobra.Command{
 	Use:   "logs API_NAME [JOB_ID]",
-	Short: "stream logs from an api",
+	Short: "stream logs from an api, or search its historical logs",
 	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		env, err := ReadOrConfigureEnv(_flagLogsEnv)
@@ -47,55 +69,69 @@ var _logsCmd = &cobra.Command{
 			telemetry.Event("cli.logs")
 			exit.Error(err)
 		}
-		telemetry.Event("cli.logs", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+		isSearch := _flagLogsSince != "" || _flagLogsUntil != "" || _flagLogsGrep != "" || _flagLogsReplica != ""
+		telemetry.Event("cli.logs", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name, "search": isSearch})
 
-		err = printEnvIfNotSpecified(_flagLogsEnv, cmd)
-		if err != nil {
-			exit.Error(err)
+		if !_flagOutput.IsMachineReadable() {
+			err = printEnvIfNotSpecified(_flagLogsEnv, cmd)
+			if err != nil {
+				exit.Error(err)
+			}
 		}
 
 		apiName := args[0]
-		if env.Provider == types.AWSProviderType {
-			if len(args) == 1 {
-				err := cluster.StreamLogs(MustGetOperatorConfig(env.Name), apiName)
-				if err != nil {
-					exit.Error(err)
-				}
+
+		if _flagLogsSource != "" {
+			if _flagLogsSource != _logSourcePods {
+				exit.Error(ErrorInvalidLogSource(_flagLogsSource))
 			}
-			if len(args) == 2 {
-				err := cluster.StreamJobLogs(MustGetOperatorConfig(env.Name), apiName, args[1])
-				if err != nil {
-					exit.Error(err)
-				}
+			if env.Provider == types.LocalProviderType {
+				exit.Error(ErrorFlagNotSupportedInLocalEnvironment("--source"))
+			}
+			if isSearch {
+				exit.Error(ErrorLogSourceNotSupportedForSearch())
 			}
 		}
 
-		if env.Provider == types.GCPProviderType {
-			gcpLogsResponse, err := cluster.GetGCPLogsURL(MustGetOperatorConfig(env.Name), apiName)
+		if isSearch {
+			if env.Provider == types.LocalProviderType {
+				exit.Error(ErrorNotSupportedInLocalEnvironment(), "cannot search historical logs")
+			}
+			if len(args) == 2 {
+				exit.Error(ErrorLogSearchNotSupportedForJobs())
+			}
+			err := searchLogs(MustGetOperatorConfig(env.Name), apiName)
 			if err != nil {
 				exit.Error(err)
 			}
+			return
+		}
 
-			gcpReq, err := http.NewRequest("GET", "https://console.cloud.google.com/logs/query", nil)
-			if err != nil {
-				exit.Error(err)
+		if env.Provider != types.LocalProviderType {
+			if len(args) == 1 {
+				err := cluster.StreamLogs(MustGetOperatorConfig(env.Name), apiName, _flagLogsSource, printStreamedLogMessage)
+				if err != nil {
+					exit.Error(err)
+				}
 			}
-			query := ""
-			for q, v := range gcpLogsResponse.QueryParams {
-				query += fmt.Sprintf("%s=\"%s\"\n", q, v)
+			if len(args) == 2 {
+				err := cluster.StreamJobLogs(MustGetOperatorConfig(env.Name), apiName, args[1], _flagLogsSource, printStreamedLogMessage)
+				if err != nil {
+					exit.Error(err)
+				}
 			}
-			queryValues := make(url.Values)
-			queryValues.Add("query", query)
-			gcpReq.URL.RawQuery = queryValues.Encode()
-
-			gcpLogsURL := gcpReq.URL.String()
-			consoleOutput := console.Bold(fmt.Sprintf("visit the following link to view logs for api %s: ", apiName)) + gcpLogsURL
-			fmt.Println(consoleOutput)
 		}
 
 		if env.Provider == types.LocalProviderType {
+			if _flagOutput.IsMachineReadable() {
+				exit.Error(ErrorFlagNotSupportedInLocalEnvironment("--output"))
+			}
 			if len(args) == 2 {
-				exit.Error(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot stream logs for job %s for api %s", args[1], args[0]))
+				err := local.StreamJobLogs(apiName, args[1])
+				if err != nil {
+					exit.Error(err)
+				}
+				return
 			}
 			err := local.StreamLogs(apiName)
 			if err != nil {
@@ -104,3 +140,95 @@ var _logsCmd = &cobra.Command{
 		}
 	},
 }
+
+// searchLogs prints all of the api's log lines which match the search flags, in order
+func searchLogs(operatorConfig cluster.OperatorConfig, apiName string) error {
+	now := time.Now()
+
+	sinceStr := _flagLogsSince
+	if sinceStr == "" {
+		sinceStr = _defaultLogSearchSince
+	}
+	since, err := parseLogTime("--since", sinceStr, now)
+	if err != nil {
+		return err
+	}
+
+	until := now
+	if _flagLogsUntil != "" {
+		until, err = parseLogTime("--until", _flagLogsUntil, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !since.Before(until) {
+		return ErrorLogSearchTimeRange(since, until)
+	}
+
+	qParams := map[string]string{
+		"start": s.Int64(libtime.ToMillis(since)),
+		"end":   s.Int64(libtime.ToMillis(until)),
+	}
+	if _flagLogsGrep != "" {
+		qParams["grep"] = _flagLogsGrep
+	}
+	if _flagLogsReplica != "" {
+		qParams["replica"] = _flagLogsReplica
+	}
+
+	for {
+		logSearchResponse, err := cluster.SearchLogs(operatorConfig, apiName, qParams)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range logSearchResponse.Lines {
+			if err := printLogLine(line); err != nil {
+				return err
+			}
+		}
+
+		if logSearchResponse.NextToken == "" {
+			return nil
+		}
+		qParams["nextToken"] = logSearchResponse.NextToken
+	}
+}
+
+// parseLogTime accepts either a duration before now or an RFC3339 timestamp
+func parseLogTime(flag string, value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, ErrorInvalidLogTime(flag, value)
+}
+
+func printLogLine(line schema.LogLine) error {
+	if _flagOutput.IsMachineReadable() {
+		return printOutputItem(line)
+	}
+
+	timestamp := libtime.MillisToTime(line.Timestamp).Local().Format(time.RFC3339)
+	fmt.Printf("%s  %s  %s\n", console.Bold(timestamp), line.Replica, line.Message)
+	return nil
+}
+
+// streamedLogMessage is the machine-readable form of a streamed log message (streamed messages don't have a timestamp)
+type streamedLogMessage struct {
+	Message string `json:"message"`
+}
+
+func printStreamedLogMessage(message string) {
+	if _flagOutput.IsMachineReadable() {
+		if err := printOutputItem(streamedLogMessage{Message: message}); err != nil {
+			exit.Error(err)
+		}
+		return
+	}
+
+	fmt.Println(message)
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
@@ -33,14 +32,17 @@ import (
 )
 
 var (
-	_flagPatchEnv   string
-	_flagPatchForce bool
+	_flagPatchEnv    string
+	_flagPatchForce  bool
+	_flagPatchValues string
 )
 
 func patchInit() {
 	_patchCmd.Flags().SortFlags = false
 	_patchCmd.Flags().StringVarP(&_flagPatchEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
 	_patchCmd.Flags().BoolVarP(&_flagPatchForce, "force", "f", false, "override the in-progress api update")
+	_patchCmd.Flags().StringVar(&_flagPatchValues, "values", "", "path to a yaml file of variables used to resolve ${VAR} references in the configuration file (takes precedence over environment variables)")
+	addFailFastFlag(_patchCmd)
 	_patchCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 }
 
@@ -63,26 +65,29 @@ var _patchCmd = &cobra.Command{
 
 		configPath := getConfigPath(args)
 
+		configBytes, err := readConfigBytes(configPath, env.Name, _flagPatchValues)
+		if err != nil {
+			exit.Error(err)
+		}
+
 		var deployResults []schema.DeployResult
 		if env.Provider == types.LocalProviderType {
-			deployResults, err = local.Patch(env, configPath)
+			deployResults, err = local.Patch(env, configPath, configBytes)
 			if err != nil {
-				exit.Error(err)
+				exit.Error(applyFailFast(err))
 			}
 		} else {
-			deployResults, err = cluster.Patch(MustGetOperatorConfig(env.Name), configPath, _flagPatchForce)
+			deployResults, err = cluster.Patch(MustGetOperatorConfig(env.Name), configPath, configBytes, _flagPatchForce, _flagFailFast)
 			if err != nil {
 				exit.Error(err)
 			}
 		}
 
 		switch _flagOutput {
-		case flags.JSONOutputType:
-			bytes, err := libjson.Marshal(deployResults)
-			if err != nil {
+		case flags.JSONOutputType, flags.YAMLOutputType:
+			if err := printOutput(deployResults); err != nil {
 				exit.Error(err)
 			}
-			fmt.Print(string(bytes))
 		case flags.PrettyOutputType:
 			message := deployMessage(deployResults, env.Name)
 			if didAnyResultsError(deployResults) {
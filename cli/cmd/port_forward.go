@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagPortForwardEnv     string
+	_flagPortForwardReplica string
+)
+
+func portForwardInit() {
+	_portForwardCmd.Flags().SortFlags = false
+	_portForwardCmd.Flags().StringVarP(&_flagPortForwardEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_portForwardCmd.Flags().StringVar(&_flagPortForwardReplica, "replica", "0", "the replica to forward to (an index into the api's running replicas, or a replica name)")
+}
+
+var _portForwardCmd = &cobra.Command{
+	Use:   "port-forward API_NAME LOCAL_PORT",
+	Short: "forward a local port to a replica of an api",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagPortForwardEnv)
+		if err != nil {
+			telemetry.Event("cli.port-forward")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.port-forward", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		err = printEnvIfNotSpecified(_flagPortForwardEnv, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Append(ErrorNotSupportedInLocalEnvironment(), "; apis running locally are already reachable on localhost (run `cortex get API_NAME` to view the api's endpoint)"))
+		}
+
+		apiName := args[0]
+		localPort, ok := s.ParseInt(args[1])
+		if !ok || localPort < 1 || localPort > 65535 {
+			exit.Error(ErrorInvalidPort(args[1]))
+		}
+
+		operatorConfig := MustGetOperatorConfig(env.Name)
+
+		// check the api before listening, since errors are otherwise only reported once a connection is made
+		apisRes, err := cluster.GetAPI(operatorConfig, apiName)
+		if err != nil {
+			exit.Error(err)
+		}
+		if len(apisRes) > 0 && apisRes[0].Spec.Kind != userconfig.RealtimeAPIKind {
+			exit.Error(ErrorCommandNotSupportedForKind(apisRes[0].Spec.Kind, "port-forward"))
+		}
+
+		err = cluster.PortForward(operatorConfig, apiName, _flagPortForwardReplica, localPort)
+		if err != nil {
+			exit.Error(err)
+		}
+	},
+}
@@ -19,14 +19,18 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
@@ -35,17 +39,27 @@ import (
 )
 
 var (
-	_flagPredictEnv string
+	_flagPredictEnv         string
+	_flagPredictContentType string
+	_flagPredictMsgpack     bool
+	_flagPredictParallelism int
+	_flagPredictRetries     int
+	_flagPredictOutputFile  string
 )
 
 func predictInit() {
 	_predictCmd.Flags().SortFlags = false
 	_predictCmd.Flags().StringVarP(&_flagPredictEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_predictCmd.Flags().StringVar(&_flagPredictContentType, "content-type", "", "content type of the input files (inferred from their extensions by default, e.g. image/jpeg for .jpg files)")
+	_predictCmd.Flags().BoolVar(&_flagPredictMsgpack, "msgpack", false, "send json inputs encoded as msgpack")
+	_predictCmd.Flags().IntVar(&_flagPredictParallelism, "parallelism", 4, "maximum number of requests in flight at once (for jsonl files and directories)")
+	_predictCmd.Flags().IntVar(&_flagPredictRetries, "retries", 3, "number of times to retry requests which fail to connect or receive a 429 or 5XX response (for jsonl files and directories)")
+	_predictCmd.Flags().StringVar(&_flagPredictOutputFile, "output-file", "", "path of the jsonl file to write the responses to, one line per input (responses are written to stdout by default)")
 }
 
 var _predictCmd = &cobra.Command{
-	Use:   "predict API_NAME JSON_FILE",
-	Short: "make a prediction request using a json file",
+	Use:   "predict API_NAME INPUT",
+	Short: "make prediction requests using a file, a jsonl file (one request per line), or a directory of files",
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		env, err := ReadOrConfigureEnv(_flagPredictEnv)
@@ -55,81 +69,145 @@ var _predictCmd = &cobra.Command{
 		}
 		telemetry.Event("cli.predict", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
 
-		err = printEnvIfNotSpecified(_flagPredictEnv, cmd)
-		if err != nil {
-			exit.Error(err)
+		apiName := args[0]
+		inputPath := args[1]
+
+		if _flagPredictParallelism < 1 {
+			exit.Error(ErrorInvalidFlagValue("--parallelism", "greater than or equal to 1"))
+		}
+		if _flagPredictRetries < 0 {
+			exit.Error(ErrorInvalidFlagValue("--retries", "greater than or equal to 0"))
 		}
 
-		apiName := args[0]
-		jsonPath := args[1]
+		isBatch := files.IsDir(files.UserRelToAbsPath(inputPath)) || isJSONLPath(inputPath) || _flagPredictOutputFile != ""
 
-		var apisRes []schema.APIResponse
-		if env.Provider == types.LocalProviderType {
-			apisRes, err = local.GetAPI(apiName)
-			if err != nil {
-				exit.Error(err)
-			}
-		} else {
-			apisRes, err = cluster.GetAPI(MustGetOperatorConfig(env.Name), apiName)
+		// when writing jsonl to stdout, nothing else can be printed to stdout
+		if !isBatch || _flagPredictOutputFile != "" {
+			err = printEnvIfNotSpecified(_flagPredictEnv, cmd)
 			if err != nil {
 				exit.Error(err)
 			}
 		}
 
-		if len(apisRes) == 0 {
-			exit.Error(errors.ErrorUnexpected(fmt.Sprintf("unable to find API %s", apiName)))
+		apiRes, err := getReadyRealtimeAPI(env, apiName)
+		if err != nil {
+			exit.Error(err)
 		}
 
-		apiRes := apisRes[0]
-
-		if apiRes.Spec.Kind != userconfig.RealtimeAPIKind {
-			exit.Error(errors.ErrorUnexpected("unable to get api", apiName)) // unexpected
+		inputs, err := readPredictInputs(inputPath, _flagPredictContentType, _flagPredictMsgpack)
+		if err != nil {
+			exit.Error(err)
 		}
 
-		totalReady := apiRes.Status.Updated.Ready + apiRes.Status.Stale.Ready
-		if totalReady == 0 {
-			exit.Error(ErrorAPINotReady(apiName, apiRes.Status.Message()))
+		if !isBatch {
+			err = makePredictRequest(apiRes.Endpoint, inputs[0])
+			if err != nil {
+				exit.Error(err)
+			}
+			return
 		}
 
-		predictResponse, err := makePredictRequest(apiRes.Endpoint, jsonPath)
+		err = predictBatch(apiRes.Endpoint, inputs)
 		if err != nil {
 			exit.Error(err)
 		}
+	},
+}
 
-		prettyResp, err := json.Pretty(predictResponse)
+func predictBatch(apiEndpoint string, inputs []predictInput) error {
+	writer := io.Writer(os.Stdout)
+	if _flagPredictOutputFile != "" {
+		file, err := files.Create(_flagPredictOutputFile)
 		if err != nil {
-			exit.Error(err)
+			return err
 		}
-		fmt.Println(prettyResp)
-	},
+		defer file.Close()
+		writer = file
+	}
+
+	numFailed := 0
+	err := runPredictions(apiEndpoint, inputs, _flagPredictParallelism, _flagPredictRetries, func(output predictOutput) error {
+		if !output.succeeded() {
+			numFailed++
+		}
+		return writePredictOutput(writer, output)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _flagPredictOutputFile != "" {
+		fmt.Printf("wrote %d %s to %s\n", len(inputs), s.PluralS("response", len(inputs)), _flagPredictOutputFile)
+	}
+
+	if numFailed > 0 {
+		return ErrorPredictionsFailed(numFailed, len(inputs))
+	}
+	return nil
 }
 
-func makePredictRequest(apiEndpoint string, jsonPath string) (interface{}, error) {
-	jsonBytes, err := files.ReadFileBytes(jsonPath)
+// getReadyRealtimeAPI returns the api, ensuring that it is a realtime api which has at least one ready replica
+func getReadyRealtimeAPI(env cliconfig.Environment, apiName string) (*schema.APIResponse, error) {
+	var apisRes []schema.APIResponse
+	var err error
+	if env.Provider == types.LocalProviderType {
+		apisRes, err = local.GetAPI(apiName)
+	} else {
+		apisRes, err = cluster.GetAPI(MustGetOperatorConfig(env.Name), apiName)
+	}
 	if err != nil {
-		exit.Error(err)
+		return nil, err
 	}
 
-	payload := bytes.NewBuffer(jsonBytes)
-	req, err := http.NewRequest("POST", apiEndpoint, payload)
+	if len(apisRes) == 0 {
+		return nil, errors.ErrorUnexpected(fmt.Sprintf("unable to find API %s", apiName))
+	}
+
+	apiRes := apisRes[0]
+
+	if apiRes.Spec.Kind != userconfig.RealtimeAPIKind {
+		return nil, errors.ErrorUnexpected("unable to get api", apiName) // unexpected
+	}
+
+	totalReady := apiRes.Status.Updated.Ready + apiRes.Status.Stale.Ready
+	if totalReady == 0 {
+		return nil, ErrorAPINotReady(apiName, apiRes.Status.Message())
+	}
+
+	return &apiRes, nil
+}
+
+func makePredictRequest(apiEndpoint string, input predictInput) error {
+	req, err := http.NewRequest("POST", apiEndpoint, bytes.NewReader(input.Payload))
 	if err != nil {
-		return nil, errors.Wrap(err, _errStrCantMakeRequest)
+		return errors.Wrap(err, _errStrCantMakeRequest)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", input.ContentType)
 	header, httpResponseBody, err := makeRequest(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if header.Get("Content-Type") == "application/json" {
-		var predictResponse interface{}
-		err = json.DecodeWithNumber(httpResponseBody, &predictResponse)
-		if err != nil {
-			return nil, errors.Wrap(err, "prediction response")
-		}
-		return predictResponse, nil
+	if len(httpResponseBody) == 0 {
+		return nil
 	}
 
-	return string(httpResponseBody), nil
+	predictResponse, responseBase64 := decodePredictResponse(header.Get("Content-Type"), httpResponseBody)
+	if responseBase64 != "" {
+		os.Stdout.Write(httpResponseBody) // binary responses are written as-is, e.g. to be redirected to a file
+		return nil
+	}
+
+	if str, ok := predictResponse.(string); ok {
+		fmt.Println(str)
+		return nil
+	}
+
+	prettyResp, err := json.Pretty(predictResponse)
+	if err != nil {
+		return err
+	}
+	fmt.Println(prettyResp)
+	return nil
 }
@@ -24,7 +24,6 @@ import (
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
-	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/types"
@@ -68,12 +67,10 @@ var _refreshCmd = &cobra.Command{
 			exit.Error(err)
 		}
 
-		if _flagOutput == flags.JSONOutputType {
-			bytes, err := libjson.Marshal(refreshResponse)
-			if err != nil {
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(refreshResponse); err != nil {
 				exit.Error(err)
 			}
-			fmt.Print(string(bytes))
 			return
 		}
 
@@ -41,6 +41,7 @@ var (
 	_configFileExts = []string{"yaml", "yml"}
 	_flagVerbose    bool
 	_flagOutput     = flags.PrettyOutputType
+	_flagFailFast   bool
 
 	_credentialsCacheDir string
 	_localDir            string
@@ -61,6 +62,8 @@ const (
 )
 
 func init() {
+	setErrorExitCodes()
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		err := errors.Wrap(err, "unable to determine current working directory")
@@ -122,14 +125,25 @@ func init() {
 	clusterInit()
 	clusterGCPInit()
 	completionInit()
+	dashboardInit()
 	deleteInit()
 	deployInit()
 	envInit()
+	eventsInit()
+	execInit()
 	getInit()
+	initInit()
+	jobInit()
+	loadTestInit()
 	logsInit()
 	patchInit()
+	portForwardInit()
 	predictInit()
 	refreshInit()
+	schemaInit()
+	secretInit()
+	topInit()
+	validateInit()
 	versionInit()
 }
 
@@ -165,24 +179,38 @@ func Execute() {
 
 	cobra.EnableCommandSorting = false
 
+	_rootCmd.AddCommand(_initCmd)
+	_rootCmd.AddCommand(_validateCmd)
 	_rootCmd.AddCommand(_deployCmd)
 	_rootCmd.AddCommand(_getCmd)
 	_rootCmd.AddCommand(_patchCmd)
 	_rootCmd.AddCommand(_logsCmd)
+	_rootCmd.AddCommand(_eventsCmd)
+	_rootCmd.AddCommand(_topCmd)
+	_rootCmd.AddCommand(_dashboardCmd)
+	_rootCmd.AddCommand(_execCmd)
+	_rootCmd.AddCommand(_portForwardCmd)
 	_rootCmd.AddCommand(_refreshCmd)
 	_rootCmd.AddCommand(_predictCmd)
+	_rootCmd.AddCommand(_jobCmd)
+	_rootCmd.AddCommand(_loadTestCmd)
 	_rootCmd.AddCommand(_deleteCmd)
+	_rootCmd.AddCommand(_secretCmd)
 
 	_rootCmd.AddCommand(_clusterCmd)
 	_rootCmd.AddCommand(_clusterGCPCmd)
 
 	_rootCmd.AddCommand(_envCmd)
+	_rootCmd.AddCommand(_schemaCmd)
 	_rootCmd.AddCommand(_versionCmd)
 	_rootCmd.AddCommand(_completionCmd)
 
 	updateRootUsage()
 
-	_rootCmd.Execute()
+	if err := _rootCmd.Execute(); err != nil {
+		// cobra has already printed the error and the command's usage
+		exit.Code(_exitCodeUsage)
+	}
 
 	exit.Ok()
 }
@@ -257,3 +285,15 @@ func mixedPrint(a interface{}) error {
 	fmt.Print(fmt.Sprintf("~~cortex~~%s~~cortex~~", base64.StdEncoding.EncodeToString(jsonBytes)))
 	return nil
 }
+
+// applyFailFast returns only the first of the errors which were combined by errors.Join() if --fail-fast is set
+func applyFailFast(err error) error {
+	if _flagFailFast {
+		return errors.First(err)
+	}
+	return err
+}
+
+func addFailFastFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&_flagFailFast, "fail-fast", false, "report only the first configuration error instead of all of them")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/spf13/cobra"
+)
+
+var _flagSchemaProvider string
+
+func schemaInit() {
+	_schemaCmd.Flags().SortFlags = false
+	_schemaCmd.Flags().StringVarP(&_flagSchemaProvider, "provider", "p", types.AWSProviderType.String(), fmt.Sprintf("provider to generate the schema for: one of %s (only %s are supported for cluster configuration)", s.StrsOr(types.ProviderTypeStrings()), s.StrsAnd(types.ClusterProviderTypeStrings())))
+}
+
+var _schemaCmd = &cobra.Command{
+	Use:   "schema api|cluster",
+	Short: "print the json schema of api or cluster configuration files",
+	Long: `print the json schema of api or cluster configuration files
+
+the schema can be used by editors to autocomplete and validate configuration files, e.g.:
+    cortex schema api > cortex.schema.json
+    cortex schema cluster > cluster.schema.json`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"api", "cluster"},
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.schema", map[string]interface{}{"type": args[0], "provider": _flagSchemaProvider})
+
+		provider := types.ProviderTypeFromString(_flagSchemaProvider)
+		if provider == types.UnknownProviderType {
+			exit.Error(ErrorInvalidProvider(_flagSchemaProvider))
+		}
+
+		var schema map[string]interface{}
+		var err error
+
+		switch args[0] {
+		case "api":
+			schema, err = spec.APIConfigJSONSchema(provider)
+		case "cluster":
+			if provider == types.LocalProviderType {
+				exit.Error(ErrorInvalidFlagValue("--provider", "one of "+s.StrsOr(types.ClusterProviderTypeStrings())+" for cluster configuration"))
+			}
+			schema, err = clusterconfig.JSONSchema(provider)
+		default:
+			exit.Error(ErrorInvalidSchemaType(args[0]))
+		}
+		if err != nil {
+			exit.Error(err)
+		}
+
+		schemaBytes, err := json.MarshalIndent(schema)
+		if err != nil {
+			exit.Error(err)
+		}
+		fmt.Println(string(schemaBytes))
+	},
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagSecretEnv   string
+	_flagSecretForce bool
+)
+
+func secretInit() {
+	_secretSetCmd.Flags().SortFlags = false
+	_secretSetCmd.Flags().StringVarP(&_flagSecretEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_secretSetCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+	_secretCmd.AddCommand(_secretSetCmd)
+
+	_secretListCmd.Flags().SortFlags = false
+	_secretListCmd.Flags().StringVarP(&_flagSecretEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_secretListCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+	_secretCmd.AddCommand(_secretListCmd)
+
+	_secretDeleteCmd.Flags().SortFlags = false
+	_secretDeleteCmd.Flags().StringVarP(&_flagSecretEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_secretDeleteCmd.Flags().BoolVarP(&_flagSecretForce, "force", "f", false, "delete the secret even if it is referenced by running apis")
+	_secretDeleteCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+	_secretCmd.AddCommand(_secretDeleteCmd)
+}
+
+var _secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "manage secrets which can be referenced in api environment variables (contains subcommands)",
+}
+
+var _secretSetCmd = &cobra.Command{
+	Use:   "set SECRET_NAME KEY[=VALUE] [KEY[=VALUE]...]",
+	Short: "create or update a secret (you will be prompted for values which are not provided)",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetSecretEnv("cli.secret.set", cmd)
+
+		data := map[string]string{}
+		for _, arg := range args[1:] {
+			split := strings.SplitN(arg, "=", 2)
+			if len(split) == 2 {
+				data[split[0]] = split[1]
+				continue
+			}
+			data[arg] = prompt.Prompt(&prompt.Options{
+				Prompt:     fmt.Sprintf("value for %s", arg),
+				HideTyping: true,
+			})
+		}
+
+		secretResponse, err := cluster.SetSecret(MustGetOperatorConfig(env.Name), args[0], data)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(secretResponse); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		print.BoldFirstLine(secretResponse.Message)
+	},
+}
+
+var _secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list secrets and their keys (values are never displayed)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetSecretEnv("cli.secret.list", cmd)
+
+		secrets, err := cluster.ListSecrets(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(secrets); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		if len(secrets) == 0 {
+			fmt.Println(console.Bold("no secrets have been set; you can create one with `cortex secret set SECRET_NAME KEY=VALUE`"))
+			return
+		}
+
+		t := secretsTable(secrets)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _secretDeleteCmd = &cobra.Command{
+	Use:   "delete SECRET_NAME [KEY]",
+	Short: "delete a secret, or a single key from a secret",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := mustGetSecretEnv("cli.secret.delete", cmd)
+
+		var key string
+		if len(args) == 2 {
+			key = args[1]
+		}
+
+		secretResponse, err := cluster.DeleteSecret(MustGetOperatorConfig(env.Name), args[0], key, _flagSecretForce)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(secretResponse); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		print.BoldFirstLine(secretResponse.Message)
+	},
+}
+
+func mustGetSecretEnv(telemetryEvent string, cmd *cobra.Command) cliconfig.Environment {
+	env, err := ReadOrConfigureEnv(_flagSecretEnv)
+	if err != nil {
+		telemetry.Event(telemetryEvent)
+		exit.Error(err)
+	}
+	telemetry.Event(telemetryEvent, map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+	if err := printEnvIfNotSpecified(_flagSecretEnv, cmd); err != nil {
+		exit.Error(err)
+	}
+
+	if env.Provider == types.LocalProviderType {
+		exit.Error(errors.Append(ErrorNotSupportedInLocalEnvironment(), "; set the environment variable's value directly in your api configuration instead"))
+	}
+
+	return env
+}
+
+func secretsTable(secrets []schema.SecretSummary) table.Table {
+	rows := make([][]interface{}, 0, len(secrets))
+	for _, secret := range secrets {
+		createdAt := time.Unix(secret.CreatedAt, 0)
+
+		usedBy := "-"
+		if len(secret.UsedBy) > 0 {
+			usedBy = strings.Join(secret.UsedBy, ", ")
+		}
+
+		rows = append(rows, []interface{}{
+			secret.Name,
+			strings.Join(secret.Keys, ", "),
+			usedBy,
+			libtime.SinceStr(&createdAt),
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "secret"},
+			{Title: "keys"},
+			{Title: "used by"},
+			{Title: "age"},
+		},
+		Rows: rows,
+	}
+}
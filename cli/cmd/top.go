@@ -0,0 +1,197 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var _flagTopEnv string
+
+func topInit() {
+	_topCmd.Flags().SortFlags = false
+	_topCmd.Flags().StringVarP(&_flagTopEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_topCmd.Flags().BoolVarP(&_flagWatch, "watch", "w", false, "re-run the command every 2 seconds")
+	_topCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
+}
+
+var _topCmd = &cobra.Command{
+	Use:   "top [API_NAME]",
+	Short: "show the cpu and memory usage of api replicas and of the instances which run them",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagTopEnv)
+		if err != nil {
+			telemetry.Event("cli.top")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.top", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Append(ErrorNotSupportedInLocalEnvironment(), "; use `docker stats` to view the resource usage of apis running locally"))
+		}
+
+		apiName := ""
+		if len(args) == 1 {
+			apiName = args[0]
+		}
+
+		rerun(func() (string, error) {
+			topResponse, err := cluster.Top(MustGetOperatorConfig(env.Name), apiName)
+			if err != nil {
+				return "", err
+			}
+
+			if _flagOutput.IsMachineReadable() {
+				return formatOutput(topResponse)
+			}
+
+			out, err := envStringIfNotSpecified(_flagTopEnv, cmd)
+			if err != nil {
+				return "", err
+			}
+
+			return out + topStr(topResponse), nil
+		})
+	},
+}
+
+func topStr(topResponse schema.TopResponse) string {
+	if len(topResponse.Replicas) == 0 {
+		return console.Bold("no replicas are running") + "\n"
+	}
+
+	return replicaUsageTable(topResponse.Replicas) + "\n" + nodeUsageTable(topResponse.Nodes)
+}
+
+func replicaUsageTable(replicas []schema.ReplicaUsage) string {
+	var doReplicasHaveGPUs, doReplicasHaveInfs, didAnyReplicaRunOutOfMemory bool
+	for _, replica := range replicas {
+		if replica.Requested.GPU > 0 {
+			doReplicasHaveGPUs = true
+		}
+		if replica.Requested.Inf > 0 {
+			doReplicasHaveInfs = true
+		}
+		if replica.OOMKilled {
+			didAnyReplicaRunOutOfMemory = true
+		}
+	}
+
+	rows := make([][]interface{}, 0, len(replicas))
+	for _, replica := range replicas {
+		cpuStr, memStr := "-", "-"
+		if replica.Usage != nil {
+			cpuStr = usageStr(replica.Usage.CPU.MilliString(), replica.Usage.CPU, replica.Requested.CPU)
+			memStr = usageStr(memQuantityStr(replica.Usage.Mem), replica.Usage.Mem, replica.Requested.Mem)
+		}
+		cpuStr += " / " + replica.Requested.CPU.MilliString()
+		memStr += " / " + memQuantityStr(*replica.Requested.Mem)
+
+		restartsStr := s.Int32(replica.Restarts)
+		if replica.OOMKilled {
+			restartsStr += " (out of memory)"
+		}
+
+		rows = append(rows, []interface{}{replica.APIName, replica.Name, cpuStr, memStr, replica.Requested.GPU, replica.Requested.Inf, restartsStr})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "api"},
+			{Title: "replica"},
+			{Title: "CPU (used / requested)"},
+			{Title: "memory (used / requested)"},
+			{Title: "GPU (requested)", Hidden: !doReplicasHaveGPUs},
+			{Title: "Inf (requested)", Hidden: !doReplicasHaveInfs},
+			{Title: "restarts"},
+		},
+		Rows: rows,
+	}
+
+	out := t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+	if didAnyReplicaRunOutOfMemory {
+		out += "\n" + "some replicas were restarted because they ran out of memory; consider increasing `compute.mem` in your api configuration\n"
+	}
+	return out
+}
+
+func nodeUsageTable(nodes []schema.NodeUsage) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+
+	rows := make([][]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		lifecycle := "on-demand"
+		if node.IsSpot {
+			lifecycle = "spot"
+		}
+
+		cpuStr, memStr := "-", "-"
+		if node.Usage != nil {
+			cpuStr = usageStr(node.Usage.CPU.MilliString(), node.Usage.CPU, node.Allocatable.CPU)
+			memStr = usageStr(memQuantityStr(node.Usage.Mem), node.Usage.Mem, node.Allocatable.Mem)
+		}
+		cpuStr += " / " + node.Allocatable.CPU.MilliString()
+		memStr += " / " + memQuantityStr(*node.Allocatable.Mem)
+
+		rows = append(rows, []interface{}{node.Name, node.InstanceType, lifecycle, node.NumReplicas, cpuStr, memStr})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "instance"},
+			{Title: "instance type"},
+			{Title: "lifecycle"},
+			{Title: "replicas"},
+			{Title: "CPU (used / allocatable)"},
+			{Title: "memory (used / allocatable)"},
+		},
+		Rows: rows,
+	}
+
+	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+}
+
+// e.g. "250m (25%)"
+func usageStr(usedStr string, used k8s.Quantity, total *k8s.Quantity) string {
+	if total == nil || total.MilliValue() == 0 {
+		return usedStr
+	}
+	percent := float64(used.MilliValue()) / float64(total.MilliValue()) * 100
+	return fmt.Sprintf("%s (%s%%)", usedStr, s.Round(percent, 0, 0))
+}
+
+func memQuantityStr(mem k8s.Quantity) string {
+	return s.Int64ToBase2Byte(mem.Value())
+}
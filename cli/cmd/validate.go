@@ -0,0 +1,164 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagValidateEnv           string
+	_flagValidateProvider      string
+	_flagValidateClusterConfig string
+	_flagValidateValues        string
+)
+
+func validateInit() {
+	_validateCmd.Flags().SortFlags = false
+	_validateCmd.Flags().StringVarP(&_flagValidateEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment whose overlay file (e.g. cortex.<env>.yaml) and provider to use")
+	_validateCmd.Flags().StringVarP(&_flagValidateProvider, "provider", "p", "", fmt.Sprintf("provider to validate against: one of %s (defaults to the environment's provider)", s.StrsOr(types.ProviderTypeStrings())))
+	_validateCmd.Flags().StringVarP(&_flagValidateClusterConfig, "cluster-config", "c", "", "path to the cluster configuration file of the cluster which the apis will be deployed to")
+	_validateCmd.Flags().StringVar(&_flagValidateValues, "values", "", "path to a yaml file of variables used to resolve ${VAR} references in the configuration file (takes precedence over environment variables)")
+	addFailFastFlag(_validateCmd)
+}
+
+var _validateCmd = &cobra.Command{
+	Use:   "validate [CONFIG_FILE]",
+	Short: "validate an api configuration file without deploying it",
+	Long: `validate an api configuration file without deploying it
+
+checks which require network access (e.g. whether models in S3 or GCS and docker images exist) are skipped; all other errors are reported, and the command exits with a non-zero exit code if there are any`,
+	Args: cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, err := getValidateProvider()
+		if err != nil {
+			telemetry.Event("cli.validate")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.validate", map[string]interface{}{"provider": provider.String()})
+
+		awsClusterConfig, gcpClusterConfig, err := readValidateClusterConfig(provider)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		configPath := getConfigPath(args)
+		configFileName := filepath.Base(configPath)
+
+		numAPIs, skippedChecks, err := validateAPIConfigFile(configPath, provider, awsClusterConfig, gcpClusterConfig)
+		if err != nil {
+			err = applyFailFast(err)
+			errors.PrintErrorForUser(err)
+			fmt.Println()
+			exit.Error(ErrorInvalidAPIConfigFile(configFileName, len(errors.List(err))))
+		}
+
+		fmt.Println(fmt.Sprintf("%s is valid (%d %s)", configFileName, numAPIs, s.PluralS("api", numAPIs)))
+
+		if len(skippedChecks) > 0 {
+			fmt.Println("\nthe following checks require network access, and were skipped:")
+			for _, skippedCheck := range skippedChecks {
+				fmt.Println("  - " + skippedCheck)
+			}
+		}
+	},
+}
+
+func getValidateProvider() (types.ProviderType, error) {
+	if _flagValidateProvider != "" {
+		provider := types.ProviderTypeFromString(_flagValidateProvider)
+		if provider == types.UnknownProviderType {
+			return types.UnknownProviderType, ErrorInvalidProvider(_flagValidateProvider)
+		}
+		return provider, nil
+	}
+
+	env, err := readEnv(_flagValidateEnv)
+	if err != nil {
+		return types.UnknownProviderType, err
+	}
+	if env == nil {
+		return types.UnknownProviderType, ErrorEnvironmentNotFound(_flagValidateEnv)
+	}
+
+	return env.Provider, nil
+}
+
+func readValidateClusterConfig(provider types.ProviderType) (*clusterconfig.Config, *clusterconfig.GCPConfig, error) {
+	if _flagValidateClusterConfig == "" {
+		return nil, nil, nil
+	}
+
+	switch provider {
+	case types.AWSProviderType:
+		awsClusterConfig := &clusterconfig.Config{}
+		errs := cr.ParseYAMLFile(awsClusterConfig, clusterconfig.UserValidation, _flagValidateClusterConfig)
+		if errors.HasError(errs) {
+			return nil, nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/aws/install", consts.CortexVersionMinor))
+		}
+		return awsClusterConfig, nil, nil
+	case types.GCPProviderType:
+		gcpClusterConfig := &clusterconfig.GCPConfig{}
+		errs := cr.ParseYAMLFile(gcpClusterConfig, clusterconfig.UserGCPValidation, _flagValidateClusterConfig)
+		if errors.HasError(errs) {
+			return nil, nil, errors.Append(applyFailFast(errors.Join(errs...)), fmt.Sprintf("\n\ncluster configuration schema can be found here: https://docs.cortex.dev/v/%s/gcp/install", consts.CortexVersionMinor))
+		}
+		return nil, gcpClusterConfig, nil
+	}
+
+	return nil, nil, ErrorFlagNotSupportedInLocalEnvironment("--cluster-config")
+}
+
+// validateAPIConfigFile returns the number of apis in the file, the checks which were skipped because they require network access, and the validation errors (combined with errors.Join())
+func validateAPIConfigFile(configPath string, provider types.ProviderType, awsClusterConfig *clusterconfig.Config, gcpClusterConfig *clusterconfig.GCPConfig) (int, []string, error) {
+	configBytes, err := readConfigBytes(configPath, _flagValidateEnv, _flagValidateValues)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, provider, filepath.Base(configPath), awsClusterConfig, gcpClusterConfig)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// project files are always listed as they are in the local environment, to avoid prompting for large files
+	projectFileList, err := findProjectFiles(types.LocalProviderType, configPath, _flagValidateEnv, _flagValidateValues)
+	if err != nil {
+		return 0, nil, err
+	}
+	projectFiles, err := local.NewProjectFiles(projectFileList, files.Dir(configPath))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	skippedChecks, err := spec.ValidateAPIsOffline(apiConfigs, projectFiles, provider, awsClusterConfig)
+	return len(apiConfigs), skippedChecks, err
+}
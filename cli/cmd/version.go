@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
@@ -29,9 +31,15 @@ import (
 
 var _flagVersionEnv string
 
+type versionOutput struct {
+	CLIVersion     string  `json:"cli_version"`
+	ClusterVersion *string `json:"cluster_version"`
+}
+
 func versionInit() {
 	_versionCmd.Flags().SortFlags = false
 	_versionCmd.Flags().StringVarP(&_flagVersionEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_versionCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.UserOutputTypeStrings(), "|")))
 }
 
 var _versionCmd = &cobra.Command{
@@ -51,17 +59,29 @@ var _versionCmd = &cobra.Command{
 			exit.Error(err)
 		}
 
-		fmt.Println("cli version: " + consts.CortexVersion)
+		output := versionOutput{CLIVersion: consts.CortexVersion}
 
-		if env.Provider == types.LocalProviderType {
-			return
+		if !_flagOutput.IsMachineReadable() {
+			fmt.Println("cli version: " + output.CLIVersion)
 		}
 
-		infoResponse, err := cluster.Info(MustGetOperatorConfig(env.Name))
-		if err != nil {
-			exit.Error(err)
+		if env.Provider != types.LocalProviderType {
+			infoResponse, err := cluster.Info(MustGetOperatorConfig(env.Name))
+			if err != nil {
+				exit.Error(err)
+			}
+			output.ClusterVersion = &infoResponse.ClusterConfig.APIVersion
 		}
 
-		fmt.Println("cluster version: " + infoResponse.ClusterConfig.APIVersion)
+		if _flagOutput.IsMachineReadable() {
+			if err := printOutput(output); err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		if output.ClusterVersion != nil {
+			fmt.Println("cluster version: " + *output.ClusterVersion)
+		}
 	},
 }
@@ -78,6 +78,11 @@ func UpdateAPI(apiConfig *userconfig.API, models []spec.CuratedModelResource, pr
 
 	newAPISpec := spec.GetAPISpec(apiConfig, projectID, _deploymentID, "")
 
+	if newAPISpec.Kind == userconfig.TrafficSplitterKind {
+		newAPISpec.LocalProjectDir = projectRoot
+		return updateTrafficSplitter(newAPISpec, prevAPISpec, prevAPIContainers)
+	}
+
 	if newAPISpec != nil && TotalLocalModelVersions(models) > 0 {
 		if err := CacheLocalModels(newAPISpec, models); err != nil {
 			return nil, "", err
@@ -86,6 +91,10 @@ func UpdateAPI(apiConfig *userconfig.API, models []spec.CuratedModelResource, pr
 
 	newAPISpec.LocalProjectDir = projectRoot
 
+	if newAPISpec.Kind == userconfig.BatchAPIKind {
+		return updateBatchAPI(newAPISpec, prevAPISpec, prevAPIContainers)
+	}
+
 	if areAPIsEqual(newAPISpec, prevAPISpec) {
 		return toAPIResponse(newAPISpec), fmt.Sprintf("%s is up to date", newAPISpec.Resource.UserString()), nil
 	}
@@ -113,6 +122,12 @@ func UpdateAPI(apiConfig *userconfig.API, models []spec.CuratedModelResource, pr
 		return nil, "", err
 	}
 
+	if prevAPISpec != nil && prevAPISpec.Networking.LocalPort != nil && *prevAPISpec.Networking.LocalPort != *newAPISpec.Networking.LocalPort {
+		if err := redeployTrafficSplittersUsingAPI(newAPISpec.Name); err != nil {
+			return nil, "", err
+		}
+	}
+
 	if prevAPISpec == nil && len(prevAPIContainers) == 0 {
 		if encounteredVersionMismatch {
 			return toAPIResponse(newAPISpec), fmt.Sprintf(
@@ -129,6 +144,13 @@ func UpdateAPI(apiConfig *userconfig.API, models []spec.CuratedModelResource, pr
 }
 
 func toAPIResponse(api *spec.API) *schema.APIResponse {
+	if api.Kind == userconfig.BatchAPIKind {
+		// batch apis are invoked via `cortex job submit`
+		return &schema.APIResponse{
+			Spec: *api,
+		}
+	}
+
 	return &schema.APIResponse{
 		Spec:     *api,
 		Endpoint: fmt.Sprintf("http://localhost:%d", *api.Networking.LocalPort),
@@ -192,6 +214,14 @@ func DeleteAPI(apiName string) error {
 		}
 	}
 
+	if err := deleteJobVolumes(containers); err != nil {
+		errList = append(errList, err)
+	}
+
+	if err := deleteDir(jobsDir(apiName)); err != nil {
+		errList = append(errList, err)
+	}
+
 	_, err = FindAPISpec(apiName)
 	if err == nil {
 		_, err := files.DeleteDirIfPresent(filepath.Join(_localWorkspaceDir, "apis", apiName))
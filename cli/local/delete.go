@@ -66,6 +66,14 @@ func Delete(apiName string, keepCache, deleteForce bool) (schema.DeleteResponse,
 		return schema.DeleteResponse{}, DeleteAPI(apiName)
 	}
 
+	trafficSplitterNames, err := trafficSplittersUsingAPI(apiName)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+	if len(trafficSplitterNames) > 0 {
+		return schema.DeleteResponse{}, ErrorAPIUsedByTrafficSplitter(trafficSplitterNames)
+	}
+
 	if keepCache {
 		err = DeleteAPI(apiName)
 	} else {
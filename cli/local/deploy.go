@@ -35,7 +35,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func Deploy(env cliconfig.Environment, configPath string, projectFileList []string, disallowPrompt bool) ([]schema.DeployResult, error) {
+func Deploy(env cliconfig.Environment, configPath string, configBytes []byte, projectFileList []string, disallowPrompt bool) ([]schema.DeployResult, error) {
 	configFileName := filepath.Base(configPath)
 
 	_, err := docker.GetDockerClient()
@@ -43,17 +43,12 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 		return nil, err
 	}
 
-	configBytes, err := files.ReadFileBytes(configPath)
-	if err != nil {
-		return nil, err
-	}
-
 	if !files.IsAbsOrTildePrefixed(configPath) {
 		return nil, errors.ErrorUnexpected(fmt.Sprintf("%s is not an absolute path", configPath))
 	}
 	projectRoot := files.Dir(configPath)
 
-	projectFiles, err := newProjectFiles(projectFileList, projectRoot)
+	projectFiles, err := NewProjectFiles(projectFileList, projectRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -67,27 +62,9 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 }
 
 func deploy(env cliconfig.Environment, apiConfigs []userconfig.API, projectFiles ProjectFiles, disallowPrompt bool) ([]schema.DeployResult, error) {
-	var err error
-	var awsClient *aws.Client
-	var gcpClient *gcp.Client
-
-	if env.AWSAccessKeyID != nil {
-		awsClient, err = aws.NewFromCreds(*env.AWSRegion, *env.AWSAccessKeyID, *env.AWSSecretAccessKey)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		awsClient, err = aws.NewAnonymousClient()
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" {
-		gcpClient, err = gcp.NewFromEnv()
-		if err != nil {
-			return nil, err
-		}
+	awsClient, gcpClient, err := getClients(env)
+	if err != nil {
+		return nil, err
 	}
 
 	if awsClient == nil && hasAnyModelWithPrefix(apiConfigs, "s3://") {
@@ -101,7 +78,7 @@ func deploy(env cliconfig.Environment, apiConfigs []userconfig.API, projectFiles
 	models := []spec.CuratedModelResource{}
 	err = ValidateLocalAPIs(apiConfigs, &models, projectFiles, awsClient, gcpClient)
 	if err != nil {
-		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Realtime API can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration", consts.CortexVersionMinor))
+		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found here:\n  → Realtime API: https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration\n  → Batch API: https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration", consts.CortexVersionMinor, consts.CortexVersionMinor))
 		return nil, err
 	}
 
@@ -112,22 +89,60 @@ func deploy(env cliconfig.Environment, apiConfigs []userconfig.API, projectFiles
 	}
 
 	results := make([]schema.DeployResult, len(apiConfigs))
-	for i := range apiConfigs {
-		apiConfig := apiConfigs[i]
-		api, msg, err := UpdateAPI(&apiConfig, models, projectFiles.projectRoot, projectID, disallowPrompt, awsClient, gcpClient)
-		results[i].Message = msg
-		if err != nil {
-			results[i].Error = errors.Message(err)
-		} else {
-			results[i].API = api
+
+	// traffic splitters are deployed last, since they route requests to the ports of the realtime apis
+	for _, deployTrafficSplitters := range []bool{false, true} {
+		for i := range apiConfigs {
+			apiConfig := apiConfigs[i]
+			if (apiConfig.Kind == userconfig.TrafficSplitterKind) != deployTrafficSplitters {
+				continue
+			}
+
+			api, msg, err := UpdateAPI(&apiConfig, models, projectFiles.projectRoot, projectID, disallowPrompt, awsClient, gcpClient)
+			results[i].Message = msg
+			if err != nil {
+				results[i].Error = errors.Message(err)
+			} else {
+				results[i].API = api
+			}
 		}
 	}
 
 	return results, nil
 }
 
+func getClients(env cliconfig.Environment) (*aws.Client, *gcp.Client, error) {
+	var err error
+	var awsClient *aws.Client
+	var gcpClient *gcp.Client
+
+	if env.AWSAccessKeyID != nil {
+		awsClient, err = aws.NewFromCreds(*env.AWSRegion, *env.AWSAccessKeyID, *env.AWSSecretAccessKey)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		awsClient, err = aws.NewAnonymousClient()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" {
+		gcpClient, err = gcp.NewFromEnv()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return awsClient, gcpClient, nil
+}
+
 func hasAnyModelWithPrefix(apiConfigs []userconfig.API, modelPrefix string) bool {
 	for _, apiConfig := range apiConfigs {
+		if apiConfig.Predictor == nil {
+			continue
+		}
 		if apiConfig.Predictor.ModelPath != nil && strings.HasPrefix(*apiConfig.Predictor.ModelPath, modelPrefix) {
 			return true
 		}
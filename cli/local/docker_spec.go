@@ -52,8 +52,25 @@ const (
 	_cacheDir                  = "/mnt/cache"
 	_modelDir                  = "/mnt/model"
 	_workspaceDir              = "/mnt/workspace"
+	_jobDir                    = "/mnt/job"
 )
 
+// tensorflow serving doesn't exit on its own, so a job worker's tensorflow serving container runs the image's entrypoint in the
+// background and exits once the worker has exited, or once the worker's heartbeat is older than 30 seconds (i.e. the worker's
+// container was killed); the timeout must match LOCAL_WORKER_HEARTBEAT_TIMEOUT in pkg/workloads/cortex/serve/start/batch.py
+var _tfServingJobWorkerEntrypoint = []string{"/bin/bash", "-c", `
+"$@" &
+pid=$!
+while kill -0 $pid 2>/dev/null; do
+  if [ -f ` + _workspaceDir + `/` + _workerExitCodeFileName + ` ]; then break; fi
+  heartbeat=` + _workspaceDir + `/` + _workerHeartbeatFileName + `
+  if [ -f $heartbeat ] && [ $(( $(date +%s) - $(stat -c %Y $heartbeat) )) -gt 30 ]; then break; fi
+  sleep 1
+done
+if kill -0 $pid 2>/dev/null; then kill $pid; wait $pid; exit 0; fi
+wait $pid
+`, "--"}
+
 type ModelCaches []*spec.LocalModelCache
 
 func (modelCaches ModelCaches) IDs() string {
@@ -66,17 +83,22 @@ func (modelCaches ModelCaches) IDs() string {
 }
 
 func DeployContainers(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) error {
+	return deployContainers(api, nil, awsClient, gcpClient)
+}
+
+// worker is nil unless the containers are for one of the workers of a batch job
+func deployContainers(api *spec.API, worker *jobWorker, awsClient *aws.Client, gcpClient *gcp.Client) error {
 	switch api.Predictor.Type {
 	case userconfig.TensorFlowPredictorType:
-		return deployTensorFlowContainers(api, awsClient, gcpClient)
+		return deployTensorFlowContainers(api, worker, awsClient, gcpClient)
 	case userconfig.ONNXPredictorType:
-		return deployONNXContainer(api, awsClient, gcpClient)
+		return deployONNXContainer(api, worker, awsClient, gcpClient)
 	default:
-		return deployPythonContainer(api, awsClient, gcpClient)
+		return deployPythonContainer(api, worker, awsClient, gcpClient)
 	}
 }
 
-func getAPIEnv(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) []string {
+func getAPIEnv(api *spec.API, worker *jobWorker, awsClient *aws.Client, gcpClient *gcp.Client) []string {
 	envs := []string{}
 
 	for envName, envVal := range api.Predictor.Env {
@@ -89,13 +111,22 @@ func getAPIEnv(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) []st
 		"CORTEX_SERVING_PORT="+_defaultPortStr,
 		"CORTEX_PROVIDER="+"local",
 		"CORTEX_CACHE_DIR="+_cacheDir,
-		"CORTEX_API_SPEC="+filepath.Join("/mnt/workspace", filepath.Base(api.Key)),
 		"CORTEX_PROJECT_DIR="+_projectDir,
 		"CORTEX_PROCESSES_PER_REPLICA="+s.Int32(api.Predictor.ProcessesPerReplica),
 		"CORTEX_THREADS_PER_PROCESS="+s.Int32(api.Predictor.ThreadsPerProcess),
 		"CORTEX_MAX_REPLICA_CONCURRENCY="+s.Int32(api.Predictor.ProcessesPerReplica*api.Predictor.ThreadsPerProcess+1024), // allow a queue of 1024
 	)
 
+	if worker != nil {
+		envs = append(envs,
+			"CORTEX_API_SPEC="+filepath.Join(_jobDir, _jobAPISpecFileName),
+			"CORTEX_JOB_SPEC="+filepath.Join(_jobDir, _jobSpecFileName),
+			"CORTEX_JOB_WORKER_INDEX="+s.Int(worker.Index),
+		)
+	} else {
+		envs = append(envs, "CORTEX_API_SPEC="+filepath.Join(_workspaceDir, filepath.Base(api.Key)))
+	}
+
 	if api.Predictor.ModelPath != nil || api.Predictor.Models != nil {
 		envs = append(envs, "CORTEX_MODEL_DIR="+_modelDir)
 	}
@@ -128,7 +159,41 @@ func getAPIEnv(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) []st
 	return envs
 }
 
-func deployPythonContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) error {
+// the api's workspace is shared by its containers; each job worker has its own workspace, and the job's directory is shared by its workers
+func getWorkspaceMounts(api *spec.API, worker *jobWorker) []mount.Mount {
+	if worker == nil {
+		return []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key)),
+				Target: _workspaceDir,
+			},
+		}
+	}
+
+	return []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: worker.workspaceDir(),
+			Target: _workspaceDir,
+		},
+		{
+			Type:   mount.TypeBind,
+			Source: jobDir(worker.JobKey),
+			Target: _jobDir,
+		},
+	}
+}
+
+func addJobWorkerLabels(labels map[string]string, worker *jobWorker) map[string]string {
+	if worker != nil {
+		labels["jobID"] = worker.JobKey.ID
+		labels["workerIndex"] = s.Int(worker.Index)
+	}
+	return labels
+}
+
+func deployPythonContainer(api *spec.API, worker *jobWorker, awsClient *aws.Client, gcpClient *gcp.Client) error {
 	portBinding := nat.PortBinding{}
 	if api.Networking.LocalPort != nil {
 		portBinding.HostPort = s.Int(*api.Networking.LocalPort)
@@ -152,18 +217,13 @@ func deployPythonContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.
 		}
 	}
 
-	mounts := []mount.Mount{
+	mounts := append([]mount.Mount{
 		{
 			Type:   mount.TypeBind,
 			Source: api.LocalProjectDir,
 			Target: _projectDir,
 		},
-		{
-			Type:   mount.TypeBind,
-			Source: filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key)),
-			Target: _workspaceDir,
-		},
-	}
+	}, getWorkspaceMounts(api, worker)...)
 
 	for _, modelCache := range api.LocalModelCaches {
 		mounts = append(mounts, mount.Mount{
@@ -185,20 +245,26 @@ func deployPythonContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.
 		Image: api.Predictor.Image,
 		Tty:   true,
 		Env: append(
-			getAPIEnv(api, awsClient, gcpClient),
+			getAPIEnv(api, worker, awsClient, gcpClient),
 		),
 		ExposedPorts: nat.PortSet{
 			_defaultPortStr + "/tcp": struct{}{},
 		},
-		Labels: map[string]string{
+		Labels: addJobWorkerLabels(map[string]string{
 			"cortex":      "true",
 			"type":        _apiContainerName,
 			"apiID":       api.ID,
 			"specID":      api.SpecID,
 			"predictorID": api.PredictorID,
 			"apiName":     api.Name,
-		},
+		}, worker),
+	}
+	if worker != nil {
+		// job workers pull their batches from the job's directory, so they don't serve requests
+		hostConfig.PortBindings = nil
+		containerConfig.ExposedPorts = nil
 	}
+
 	containerInfo, err := docker.MustDockerClient().ContainerCreate(context.Background(), containerConfig, hostConfig, nil, "")
 	if err != nil {
 		if strings.Contains(err.Error(), "bind source path does not exist") {
@@ -232,7 +298,7 @@ func deployPythonContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.
 	return nil
 }
 
-func deployONNXContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) error {
+func deployONNXContainer(api *spec.API, worker *jobWorker, awsClient *aws.Client, gcpClient *gcp.Client) error {
 	portBinding := nat.PortBinding{}
 	if api.Networking.LocalPort != nil {
 		portBinding.HostPort = s.Int(*api.Networking.LocalPort)
@@ -256,18 +322,13 @@ func deployONNXContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Cl
 		}
 	}
 
-	mounts := []mount.Mount{
+	mounts := append([]mount.Mount{
 		{
 			Type:   mount.TypeBind,
 			Source: api.LocalProjectDir,
 			Target: _projectDir,
 		},
-		{
-			Type:   mount.TypeBind,
-			Source: filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key)),
-			Target: _workspaceDir,
-		},
-	}
+	}, getWorkspaceMounts(api, worker)...)
 	for _, modelCache := range api.LocalModelCaches {
 		mounts = append(mounts, mount.Mount{
 			Type:   mount.TypeBind,
@@ -288,12 +349,12 @@ func deployONNXContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Cl
 		Image: api.Predictor.Image,
 		Tty:   true,
 		Env: append(
-			getAPIEnv(api, awsClient, gcpClient),
+			getAPIEnv(api, worker, awsClient, gcpClient),
 		),
 		ExposedPorts: nat.PortSet{
 			_defaultPortStr + "/tcp": struct{}{},
 		},
-		Labels: map[string]string{
+		Labels: addJobWorkerLabels(map[string]string{
 			"cortex":        "true",
 			"type":          _apiContainerName,
 			"apiID":         api.ID,
@@ -301,8 +362,14 @@ func deployONNXContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Cl
 			"predictorID":   api.PredictorID,
 			"apiName":       api.Name,
 			"localModelIDs": ModelCaches(api.LocalModelCaches).IDs(),
-		},
+		}, worker),
+	}
+	if worker != nil {
+		// job workers pull their batches from the job's directory, so they don't serve requests
+		hostConfig.PortBindings = nil
+		containerConfig.ExposedPorts = nil
 	}
+
 	containerInfo, err := docker.MustDockerClient().ContainerCreate(context.Background(), containerConfig, hostConfig, nil, "")
 	if err != nil {
 		if strings.Contains(err.Error(), "bind source path does not exist") {
@@ -336,7 +403,7 @@ func deployONNXContainer(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Cl
 	return nil
 }
 
-func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient *gcp.Client) error {
+func deployTensorFlowContainers(api *spec.API, worker *jobWorker, awsClient *aws.Client, gcpClient *gcp.Client) error {
 	serveResources := container.Resources{}
 	apiResources := container.Resources{}
 
@@ -362,6 +429,9 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 	}
 
 	modelVolume := api.Name
+	if worker != nil {
+		modelVolume = worker.modelVolumeName()
+	}
 	if err := DeleteVolume(modelVolume); err != nil {
 		return errors.Wrap(err, api.Identify())
 	}
@@ -380,9 +450,31 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 		Target: _modelDir,
 	})
 
+	serveMounts := mounts
+	var serveEntrypoint []string
+	if worker != nil {
+		imageInfo, _, err := docker.MustDockerClient().ImageInspectWithRaw(context.Background(), api.Predictor.TensorFlowServingImage)
+		if err != nil {
+			return errors.Wrap(err, api.Identify())
+		}
+		serveEntrypoint = append([]string{}, _tfServingJobWorkerEntrypoint...)
+		if imageInfo.Config != nil {
+			serveEntrypoint = append(serveEntrypoint, imageInfo.Config.Entrypoint...)
+		}
+
+		serveMounts = append([]mount.Mount{
+			{
+				Type:     mount.TypeBind,
+				Source:   worker.workspaceDir(),
+				Target:   _workspaceDir,
+				ReadOnly: true,
+			},
+		}, mounts...)
+	}
+
 	serveHostConfig := &container.HostConfig{
 		Resources: serveResources,
-		Mounts:    mounts,
+		Mounts:    serveMounts,
 	}
 
 	envVars := []string{}
@@ -406,14 +498,15 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 	}
 
 	serveContainerConfig := &container.Config{
-		Image: api.Predictor.TensorFlowServingImage,
-		Tty:   true,
-		Env:   envVars,
-		Cmd:   cmdArgs,
+		Image:      api.Predictor.TensorFlowServingImage,
+		Tty:        true,
+		Env:        envVars,
+		Entrypoint: serveEntrypoint,
+		Cmd:        cmdArgs,
 		ExposedPorts: nat.PortSet{
 			_tfServingPortStr + "/tcp": struct{}{},
 		},
-		Labels: map[string]string{
+		Labels: addJobWorkerLabels(map[string]string{
 			"cortex":        "true",
 			"type":          _tfServingContainerName,
 			"apiID":         api.ID,
@@ -421,7 +514,7 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 			"predictorID":   api.PredictorID,
 			"apiName":       api.Name,
 			"localModelIDs": ModelCaches(api.LocalModelCaches).IDs(),
-		},
+		}, worker),
 	}
 
 	containerCreateRequest, err := docker.MustDockerClient().ContainerCreate(context.Background(), serveContainerConfig, serveHostConfig, nil, "")
@@ -458,32 +551,27 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 			_defaultPortStr + "/tcp": []nat.PortBinding{portBinding},
 		},
 		Resources: apiResources,
-		Mounts: append([]mount.Mount{
+		Mounts: append(append([]mount.Mount{
 			{
 				Type:   mount.TypeBind,
 				Source: api.LocalProjectDir,
 				Target: _projectDir,
 			},
-			{
-				Type:   mount.TypeBind,
-				Source: filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key)),
-				Target: _workspaceDir,
-			},
-		}, mounts...),
+		}, getWorkspaceMounts(api, worker)...), mounts...),
 	}
 
 	apiContainerConfig := &container.Config{
 		Image: api.Predictor.Image,
 		Tty:   true,
 		Env: append(
-			getAPIEnv(api, awsClient, gcpClient),
+			getAPIEnv(api, worker, awsClient, gcpClient),
 			"CORTEX_TF_BASE_SERVING_PORT="+_tfServingPortStr,
 			"CORTEX_TF_SERVING_HOST="+tfContainerHost,
 		),
 		ExposedPorts: nat.PortSet{
 			_defaultPortStr + "/tcp": struct{}{},
 		},
-		Labels: map[string]string{
+		Labels: addJobWorkerLabels(map[string]string{
 			"cortex":        "true",
 			"type":          _apiContainerName,
 			"apiID":         api.ID,
@@ -491,8 +579,13 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client, gcpClient
 			"predictorID":   api.PredictorID,
 			"apiName":       api.Name,
 			"localModelIDs": ModelCaches(api.LocalModelCaches).IDs(),
-		},
+		}, worker),
 	}
+	if worker != nil {
+		apiHostConfig.PortBindings = nil
+		apiContainerConfig.ExposedPorts = nil
+	}
+
 	containerCreateRequest, err = docker.MustDockerClient().ContainerCreate(context.Background(), apiContainerConfig, apiHostConfig, nil, "")
 	if err != nil {
 		if strings.Contains(err.Error(), "bind source path does not exist") {
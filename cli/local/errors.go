@@ -21,21 +21,30 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 const (
-	ErrAPINotDeployed                = "local.api_not_deployed"
-	ErrAPISpecNotFound               = "local.api_specification_not_found"
-	ErrCortexVersionMismatch         = "local.cortex_version_mismatch"
-	ErrAPIContainersNotFound         = "local.api_containers_not_found"
-	ErrFoundContainersWithoutAPISpec = "local.found_containers_without_api_spec"
-	ErrInvalidTensorFlowZip          = "local.invalid_tensorflow_zip"
-	ErrFailedToDeleteAPISpec         = "local.failed_to_delete_api_spec"
-	ErrDuplicateLocalPort            = "local.duplicate_local_port"
-	ErrPortAlreadyInUse              = "local.port_already_in_use"
-	ErrUnableToFindAvailablePorts    = "local.unable_to_find_available_ports"
-	ErrBindDockerInDocker            = "local.bind_docker_in_docker"
-	ErrMustSpecifyLocalAWSCreds      = "local.must_specify_local_aws_creds"
+	ErrAPINotDeployed                 = "local.api_not_deployed"
+	ErrAPISpecNotFound                = "local.api_specification_not_found"
+	ErrCortexVersionMismatch          = "local.cortex_version_mismatch"
+	ErrAPIContainersNotFound          = "local.api_containers_not_found"
+	ErrFoundContainersWithoutAPISpec  = "local.found_containers_without_api_spec"
+	ErrInvalidTensorFlowZip           = "local.invalid_tensorflow_zip"
+	ErrFailedToDeleteAPISpec          = "local.failed_to_delete_api_spec"
+	ErrDuplicateLocalPort             = "local.duplicate_local_port"
+	ErrPortAlreadyInUse               = "local.port_already_in_use"
+	ErrUnableToFindAvailablePorts     = "local.unable_to_find_available_ports"
+	ErrBindDockerInDocker             = "local.bind_docker_in_docker"
+	ErrMustSpecifyLocalAWSCreds       = "local.must_specify_local_aws_creds"
+	ErrJobsNotSupportedForKind        = "local.jobs_not_supported_for_kind"
+	ErrJobNotFound                    = "local.job_not_found"
+	ErrJobSubmissionRequiresItemList  = "local.job_submission_requires_item_list"
+	ErrJobIDRequired                  = "local.job_id_required"
+	ErrTrafficSplitterAPIsNotDeployed = "local.traffic_splitter_apis_not_deployed"
+	ErrAPIUsedByTrafficSplitter       = "local.api_used_by_traffic_splitter"
 )
 
 func ErrorAPINotDeployed(apiName string) error {
@@ -133,3 +142,49 @@ func ErrorMustSpecifyLocalAWSCreds() error {
 		Message: "you must configure your local environment with AWS credentials; please run `cortex env configure local`",
 	})
 }
+
+func ErrorJobsNotSupportedForKind(apiName string, kind userconfig.Kind) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobsNotSupportedForKind,
+		Message: fmt.Sprintf("jobs can only be submitted to a %s, but %s is a %s", userconfig.BatchAPIKind.String(), apiName, kind.String()),
+	})
+}
+
+func ErrorJobNotFound(apiName string, jobID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobNotFound,
+		Message: fmt.Sprintf("unable to find job %s for %s api", jobID, apiName),
+	})
+}
+
+func ErrorJobSubmissionRequiresItemList() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobSubmissionRequiresItemList,
+		Message: fmt.Sprintf("jobs submitted in the local environment must specify %s (%s and %s are only supported when running in a cluster)", schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey),
+	})
+}
+
+func ErrorJobIDRequired(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIDRequired,
+		Message: fmt.Sprintf("%s is a %s, so a job id must be specified (e.g. `cortex logs %s JOB_ID --env local`)", apiName, userconfig.BatchAPIKind.String(), apiName),
+	})
+}
+
+func ErrorTrafficSplitterAPIsNotDeployed(notDeployedAPIs []string) error {
+	message := fmt.Sprintf("apis %s were either not found or are not RealtimeAPIs", s.StrsAnd(notDeployedAPIs))
+	if len(notDeployedAPIs) == 1 {
+		message = fmt.Sprintf("api %s was either not found or is not a RealtimeAPI", notDeployedAPIs[0])
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTrafficSplitterAPIsNotDeployed,
+		Message: message,
+	})
+}
+
+func ErrorAPIUsedByTrafficSplitter(trafficSplitters []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIUsedByTrafficSplitter,
+		Message: fmt.Sprintf("cannot delete api because it is used by the following %s: %s", s.PluralS("TrafficSplitter", len(trafficSplitters)), s.StrsSentence(trafficSplitters, "")),
+	})
+}
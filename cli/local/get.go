@@ -28,6 +28,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 func GetAPIs() ([]schema.APIResponse, error) {
@@ -44,6 +45,19 @@ func GetAPIs() ([]schema.APIResponse, error) {
 	apiResponses := make([]schema.APIResponse, len(apiSpecList))
 	for i := range apiSpecList {
 		apiSpec := apiSpecList[i]
+		if apiSpec.Kind == userconfig.BatchAPIKind {
+			apiResponses[i], err = getBatchAPIResponse(&apiSpec)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if apiSpec.Kind == userconfig.TrafficSplitterKind {
+			apiResponses[i] = *toAPIResponse(&apiSpec)
+			continue
+		}
+
 		apiStatus, err := GetAPIStatus(&apiSpec)
 		if err != nil {
 			return nil, err
@@ -138,6 +152,25 @@ func GetAPI(apiName string) ([]schema.APIResponse, error) {
 		return nil, err
 	}
 
+	if apiSpec.Kind == userconfig.BatchAPIKind {
+		apiResponse, err := getBatchAPIResponse(apiSpec)
+		if err != nil {
+			return nil, err
+		}
+		return []schema.APIResponse{apiResponse}, nil
+	}
+
+	if apiSpec.Kind == userconfig.TrafficSplitterKind {
+		containers, err := GetContainersByAPI(apiName)
+		if err != nil {
+			return nil, err
+		}
+		if len(containers) == 0 {
+			return nil, ErrorAPIContainersNotFound(apiName)
+		}
+		return []schema.APIResponse{*toAPIResponse(apiSpec)}, nil
+	}
+
 	apiStatus, err := GetAPIStatus(apiSpec)
 	if err != nil {
 		return nil, err
@@ -170,3 +203,15 @@ func GetAPI(apiName string) ([]schema.APIResponse, error) {
 		},
 	}, nil
 }
+
+func getBatchAPIResponse(apiSpec *spec.API) (schema.APIResponse, error) {
+	jobStatuses, err := getJobStatuses(apiSpec.Name)
+	if err != nil {
+		return schema.APIResponse{}, err
+	}
+
+	return schema.APIResponse{
+		Spec:        *apiSpec,
+		JobStatuses: jobStatuses,
+	}, nil
+}
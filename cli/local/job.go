@@ -0,0 +1,618 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/metrics"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// Jobs in the local environment are backed by a directory for each job (e.g. ~/.cortex/workspace/jobs/<api_name>/<job_id>), which
+// is mounted into each of the job's workers. Each batch is a file in batches/, which a worker claims by moving it to in_progress/
+// (prefixed with the worker's index); once it has been processed, its outcome is written to results/. Workers periodically touch
+// the heartbeat file in their workspace, which is used to detect batches that were claimed by workers which have exited
+// (see pkg/workloads/cortex/serve/start/batch.py)
+const (
+	_jobSpecFileName         = "spec.json"
+	_jobAPISpecFileName      = "api_spec.json"
+	_jobBatchesDirName       = "batches"
+	_jobInProgressDirName    = "in_progress"
+	_jobResultsDirName       = "results"
+	_jobWorkersDirName       = "workers"
+	_jobCompleteFileName     = "job_complete" // claimed by the worker which waits for the other workers' batches and runs the predictor's on_job_complete()
+	_jobStoppedFileName      = "stopped"
+	_workerExitCodeFileName  = "exit_code.txt"
+	_workerHeartbeatFileName = "heartbeat.txt"
+	_workerReadinessFile     = "api_readiness.txt"
+)
+
+// jobWorker identifies one of the containers which process the batches of a job
+type jobWorker struct {
+	JobKey spec.JobKey
+	Index  int
+}
+
+func (worker *jobWorker) workspaceDir() string {
+	return filepath.Join(jobDir(worker.JobKey), _jobWorkersDirName, s.Int(worker.Index))
+}
+
+func (worker *jobWorker) modelVolumeName() string {
+	return fmt.Sprintf("%s-%d", worker.JobKey.K8sName(), worker.Index)
+}
+
+type batchResult struct {
+	Succeeded    bool    `json:"succeeded"`
+	TimePerBatch float64 `json:"time_per_batch"`
+}
+
+func jobsDir(apiName string) string {
+	return filepath.Join(_localWorkspaceDir, "jobs", apiName)
+}
+
+func jobDir(jobKey spec.JobKey) string {
+	return filepath.Join(jobsDir(jobKey.APIName), jobKey.ID)
+}
+
+// batch apis don't run any containers until a job is submitted, so only their spec is written
+func updateBatchAPI(newAPISpec *spec.API, prevAPISpec *spec.API, prevAPIContainers []dockertypes.Container) (*schema.APIResponse, string, error) {
+	if areAPIsEqual(newAPISpec, prevAPISpec) {
+		return toAPIResponse(newAPISpec), fmt.Sprintf("%s is up to date", newAPISpec.Resource.UserString()), nil
+	}
+
+	if prevAPISpec != nil && prevAPISpec.Kind == userconfig.BatchAPIKind {
+		// jobs which have already been submitted keep running, since each job has its own copy of the api spec
+		err := errors.FirstError(
+			deleteDir(filepath.Join(_localWorkspaceDir, "apis", newAPISpec.Name)),
+			deleteCachedModels(newAPISpec.Name, prevAPISpec.SubtractModelIDs(newAPISpec)),
+		)
+		if err != nil {
+			return nil, "", err
+		}
+	} else if prevAPISpec != nil || len(prevAPIContainers) != 0 {
+		err := DeleteAPI(newAPISpec.Name)
+		if err == nil && prevAPISpec != nil {
+			err = deleteCachedModels(newAPISpec.Name, prevAPISpec.SubtractModelIDs(newAPISpec))
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writeAPISpec(newAPISpec); err != nil {
+		deleteCachedModels(newAPISpec.Name, newAPISpec.ModelIDs())
+		return nil, "", err
+	}
+
+	if prevAPISpec == nil && len(prevAPIContainers) == 0 {
+		return toAPIResponse(newAPISpec), fmt.Sprintf("creating %s", newAPISpec.Resource.UserString()), nil
+	}
+	return toAPIResponse(newAPISpec), fmt.Sprintf("updating %s", newAPISpec.Resource.UserString()), nil
+}
+
+func SubmitJob(env cliconfig.Environment, apiName string, submissionBytes []byte) (*spec.Job, error) {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	apiSpec, err := FindAPISpec(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if apiSpec.Kind != userconfig.BatchAPIKind {
+		return nil, ErrorJobsNotSupportedForKind(apiName, apiSpec.Kind)
+	}
+
+	submission := schema.JobSubmission{}
+	if err := json.Unmarshal(submissionBytes, &submission); err != nil {
+		return nil, errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batch-api/endpoints", consts.CortexVersionMinor))
+	}
+	if err := validateJobSubmission(&submission); err != nil {
+		return nil, errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batch-api/endpoints", consts.CortexVersionMinor))
+	}
+
+	awsClient, gcpClient, err := getClients(env)
+	if err != nil {
+		return nil, err
+	}
+
+	jobKey := spec.JobKey{
+		APIName: apiSpec.Name,
+		ID:      spec.MonotonicallyDecreasingID(),
+	}
+
+	jobSpec := spec.Job{
+		RuntimeJobConfig: submission.RuntimeJobConfig,
+		JobKey:           jobKey,
+		APIID:            apiSpec.ID,
+		SpecID:           apiSpec.SpecID,
+		PredictorID:      apiSpec.PredictorID,
+		StartTime:        time.Now(),
+	}
+
+	if err := enqueueItems(&jobSpec, apiSpec, submission.ItemList); err != nil {
+		deleteJob(jobKey)
+		return nil, err
+	}
+
+	for i := 0; i < jobSpec.Workers; i++ {
+		worker := &jobWorker{JobKey: jobKey, Index: i}
+		if err := files.CreateDir(worker.workspaceDir()); err != nil {
+			deleteJob(jobKey)
+			return nil, err
+		}
+		if err := deployContainers(apiSpec, worker, awsClient, gcpClient); err != nil {
+			deleteJob(jobKey)
+			return nil, err
+		}
+	}
+
+	return &jobSpec, nil
+}
+
+func validateJobSubmission(submission *schema.JobSubmission) error {
+	if submission.ItemList == nil {
+		return ErrorJobSubmissionRequiresItemList()
+	}
+
+	if submission.FilePathLister != nil || submission.DelimitedFiles != nil {
+		return ErrorJobSubmissionRequiresItemList()
+	}
+
+	if len(submission.ItemList.Items) == 0 {
+		return errors.Wrap(cr.ErrorTooFewElements(1), schema.ItemListKey, schema.ItemsKey)
+	}
+
+	if submission.ItemList.BatchSize < 1 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.ItemList.BatchSize, 1), schema.ItemListKey, schema.BatchSizeKey)
+	}
+
+	if submission.Workers <= 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
+	}
+
+	return nil
+}
+
+// writes the job's spec and one file per batch to the job's directory
+func enqueueItems(jobSpec *spec.Job, apiSpec *spec.API, itemList *schema.ItemList) error {
+	dir := jobDir(jobSpec.JobKey)
+	for _, subDir := range []string{_jobBatchesDirName, _jobInProgressDirName, _jobResultsDirName, _jobWorkersDirName} {
+		if err := files.CreateDir(filepath.Join(dir, subDir)); err != nil {
+			return err
+		}
+	}
+
+	if err := libjson.WriteJSON(apiSpec, filepath.Join(dir, _jobAPISpecFileName)); err != nil {
+		return err
+	}
+
+	batchCount := len(itemList.Items) / itemList.BatchSize
+	if len(itemList.Items)%itemList.BatchSize != 0 {
+		batchCount++
+	}
+
+	for i := 0; i < batchCount; i++ {
+		min := i * itemList.BatchSize
+		max := (i + 1) * itemList.BatchSize
+		if max > len(itemList.Items) {
+			max = len(itemList.Items)
+		}
+
+		jsonBytes, err := json.Marshal(itemList.Items[min:max])
+		if err != nil {
+			if itemList.BatchSize == 1 {
+				return errors.Wrap(err, fmt.Sprintf("item %d", i))
+			}
+			return errors.Wrap(err, fmt.Sprintf("items with index between %d to %d", min, max))
+		}
+
+		// batch ids are zero-padded so that batches are processed in order
+		if err := files.WriteFile(jsonBytes, filepath.Join(dir, _jobBatchesDirName, fmt.Sprintf("%08d.json", i))); err != nil {
+			return err
+		}
+	}
+
+	if err := files.WriteFile([]byte{}, filepath.Join(dir, _jobCompleteFileName)); err != nil {
+		return err
+	}
+
+	jobSpec.TotalBatchCount = batchCount
+	if err := libjson.WriteJSON(jobSpec, filepath.Join(dir, _jobSpecFileName)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetJob(apiName string, jobID string) (schema.JobResponse, error) {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return schema.JobResponse{}, err
+	}
+
+	apiSpec, err := FindAPISpec(apiName)
+	if err != nil {
+		return schema.JobResponse{}, err
+	}
+
+	jobStatus, err := getJobStatus(spec.JobKey{APIName: apiName, ID: jobID})
+	if err != nil {
+		return schema.JobResponse{}, err
+	}
+
+	return schema.JobResponse{
+		APISpec:   *apiSpec,
+		JobStatus: *jobStatus,
+	}, nil
+}
+
+// returns the statuses of the api's jobs, most recent first
+func getJobStatuses(apiName string) ([]status.JobStatus, error) {
+	if !files.IsDir(jobsDir(apiName)) {
+		return nil, nil
+	}
+
+	jobIDs, err := files.ListDir(jobsDir(apiName), true)
+	if err != nil {
+		return nil, err
+	}
+	// job ids are monotonically decreasing
+	sort.Strings(jobIDs)
+
+	jobStatuses := make([]status.JobStatus, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		jobStatus, err := getJobStatus(spec.JobKey{APIName: apiName, ID: jobID})
+		if err != nil {
+			return nil, err
+		}
+		jobStatuses = append(jobStatuses, *jobStatus)
+	}
+
+	return jobStatuses, nil
+}
+
+func getJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
+	jobStatus, err := readJobStatus(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := getJobContainers(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := getJobWorkersState(jobKey, containers)
+	if err != nil {
+		return nil, err
+	}
+
+	setJobStatusCode(jobStatus, workers)
+	return jobStatus, nil
+}
+
+// the state of a job's workers, as determined from their containers
+type jobWorkersState struct {
+	Counts       status.WorkerCounts
+	WasOOMKilled bool
+	EndTime      time.Time // when the most recent worker exited
+}
+
+// reads the job's spec, queue, and batch metrics from the job's directory (the status code and worker counts are not set)
+func readJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
+	dir := jobDir(jobKey)
+	if !files.IsFile(filepath.Join(dir, _jobSpecFileName)) {
+		return nil, ErrorJobNotFound(jobKey.APIName, jobKey.ID)
+	}
+
+	var jobSpec spec.Job
+	jobSpecBytes, err := files.ReadFileBytes(filepath.Join(dir, _jobSpecFileName))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jobSpecBytes, &jobSpec); err != nil {
+		return nil, errors.Wrap(err, jobKey.UserString())
+	}
+
+	jobStatus := status.JobStatus{
+		Job:          jobSpec,
+		WorkerCounts: &status.WorkerCounts{},
+	}
+
+	queuedBatches, err := files.ListDir(filepath.Join(dir, _jobBatchesDirName), true)
+	if err != nil {
+		return nil, err
+	}
+	inProgressBatches, err := files.ListDir(filepath.Join(dir, _jobInProgressDirName), true)
+	if err != nil {
+		return nil, err
+	}
+	jobStatus.BatchesInQueue = len(queuedBatches) + len(inProgressBatches)
+
+	jobStatus.BatchMetrics, err = getBatchMetrics(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobStatus, nil
+}
+
+func getJobWorkersState(jobKey spec.JobKey, containers []dockertypes.Container) (jobWorkersState, error) {
+	var workers jobWorkersState
+	for _, container := range containers {
+		if container.Labels["type"] != _apiContainerName {
+			continue
+		}
+
+		worker := &jobWorker{JobKey: jobKey}
+		worker.Index, _ = s.ParseInt(container.Labels["workerIndex"])
+
+		switch container.State {
+		case "created":
+			workers.Counts.Pending++
+		case "running", "restarting":
+			if files.IsFile(filepath.Join(worker.workspaceDir(), _workerReadinessFile)) {
+				workers.Counts.Running++
+			} else {
+				workers.Counts.Initializing++
+			}
+		default:
+			containerInfo, err := docker.MustDockerClient().ContainerInspect(context.Background(), container.ID)
+			if err != nil {
+				return jobWorkersState{}, errors.Wrap(err, jobKey.UserString())
+			}
+
+			if finishedAt, err := time.Parse(time.RFC3339Nano, containerInfo.State.FinishedAt); err == nil && finishedAt.After(workers.EndTime) {
+				workers.EndTime = finishedAt
+			}
+
+			exitCode, _ := files.ReadFile(filepath.Join(worker.workspaceDir(), _workerExitCodeFileName))
+			if containerInfo.State.OOMKilled || containerInfo.State.ExitCode == 137 {
+				workers.WasOOMKilled = true
+				workers.Counts.Failed++
+			} else if containerInfo.State.ExitCode != 0 || strings.TrimSpace(exitCode) != "0" {
+				workers.Counts.Failed++
+			} else {
+				workers.Counts.Succeeded++
+			}
+		}
+	}
+
+	return workers, nil
+}
+
+func setJobStatusCode(jobStatus *status.JobStatus, workers jobWorkersState) {
+	dir := jobDir(jobStatus.JobKey)
+	*jobStatus.WorkerCounts = workers.Counts
+	endTime := workers.EndTime
+
+	isRunning := workers.Counts.Pending+workers.Counts.Initializing+workers.Counts.Running > 0
+
+	switch {
+	case files.IsFile(filepath.Join(dir, _jobStoppedFileName)):
+		jobStatus.Status = status.JobStopped
+		if stoppedAt, err := files.ReadFile(filepath.Join(dir, _jobStoppedFileName)); err == nil {
+			if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(stoppedAt)); err == nil {
+				endTime = t
+			}
+		}
+	case isRunning:
+		jobStatus.Status = status.JobRunning
+	case workers.WasOOMKilled:
+		jobStatus.Status = status.JobWorkerOOM
+	case workers.Counts.Failed > 0 || jobStatus.BatchesInQueue > 0:
+		jobStatus.Status = status.JobWorkerError
+	case jobStatus.BatchMetrics.Failed > 0:
+		jobStatus.Status = status.JobCompletedWithFailures
+	default:
+		jobStatus.Status = status.JobSucceeded
+	}
+
+	if jobStatus.Status.IsCompleted() && !endTime.IsZero() {
+		jobStatus.EndTime = &endTime
+	}
+}
+
+func getBatchMetrics(jobKey spec.JobKey) (*metrics.BatchMetrics, error) {
+	resultPaths, err := files.ListDir(filepath.Join(jobDir(jobKey), _jobResultsDirName), false)
+	if err != nil {
+		return nil, err
+	}
+
+	batchMetrics := metrics.BatchMetrics{}
+	totalTime := 0.0
+	for _, resultPath := range resultPaths {
+		if !strings.HasSuffix(resultPath, ".json") {
+			continue // the result is still being written
+		}
+
+		resultBytes, err := files.ReadFileBytes(resultPath)
+		if err != nil {
+			return nil, err
+		}
+
+		var result batchResult
+		if err := json.Unmarshal(resultBytes, &result); err != nil {
+			return nil, errors.Wrap(err, resultPath)
+		}
+
+		if result.Succeeded {
+			batchMetrics.Succeeded++
+		} else {
+			batchMetrics.Failed++
+		}
+		totalTime += result.TimePerBatch
+	}
+
+	if batchMetrics.TotalCompleted() > 0 {
+		batchMetrics.AverageTimePerBatch = pointer.Float64(totalTime / float64(batchMetrics.TotalCompleted()))
+	}
+
+	return &batchMetrics, nil
+}
+
+func StopJob(apiName string, jobID string) (schema.DeleteResponse, error) {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	jobKey := spec.JobKey{APIName: apiName, ID: jobID}
+	jobStatus, err := getJobStatus(jobKey)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	if jobStatus.Status.IsInProgress() {
+		err := files.WriteFile([]byte(time.Now().Format(time.RFC3339Nano)), filepath.Join(jobDir(jobKey), _jobStoppedFileName))
+		if err != nil {
+			return schema.DeleteResponse{}, err
+		}
+	}
+
+	containers, err := getJobContainers(jobKey)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	// the containers are kept so that the job's logs remain available until the api is deleted
+	for _, container := range containers {
+		if container.State == "running" {
+			if err := docker.MustDockerClient().ContainerKill(context.Background(), container.ID, "SIGKILL"); err != nil {
+				return schema.DeleteResponse{}, errors.Wrap(err, jobKey.UserString())
+			}
+		}
+	}
+
+	return schema.DeleteResponse{
+		Message: fmt.Sprintf("stopped job %s", jobID),
+	}, nil
+}
+
+func StreamJobLogs(apiName string, jobID string) error {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	jobKey := spec.JobKey{APIName: apiName, ID: jobID}
+	if !files.IsFile(filepath.Join(jobDir(jobKey), _jobSpecFileName)) {
+		return ErrorJobNotFound(apiName, jobID)
+	}
+
+	containers, err := getJobContainers(jobKey)
+	if err != nil {
+		return err
+	}
+
+	if len(containers) == 0 {
+		return ErrorJobNotFound(apiName, jobID)
+	}
+
+	var containerIDs []string
+	for _, container := range containers {
+		containerIDs = append(containerIDs, container.ID)
+	}
+
+	return docker.StreamDockerLogs(containerIDs[0], containerIDs[1:]...)
+}
+
+func getJobContainers(jobKey spec.JobKey) ([]dockertypes.Container, error) {
+	dargs := filters.NewArgs()
+	dargs.Add("label", "cortex=true")
+	dargs.Add("label", "apiName="+jobKey.APIName)
+	dargs.Add("label", "jobID="+jobKey.ID)
+
+	containers, err := docker.MustDockerClient().ContainerList(context.Background(), dockertypes.ContainerListOptions{
+		All:     true,
+		Filters: dargs,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, jobKey.UserString())
+	}
+
+	return containers, nil
+}
+
+// deletes the job's containers, their volumes, and the job's directory
+func deleteJob(jobKey spec.JobKey) error {
+	containers, err := getJobContainers(jobKey)
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	for _, container := range containers {
+		err := docker.MustDockerClient().ContainerRemove(context.Background(), container.ID, dockertypes.ContainerRemoveOptions{
+			RemoveVolumes: true,
+			Force:         true,
+		})
+		if err != nil {
+			errList = append(errList, errors.Wrap(err, jobKey.UserString()))
+		}
+	}
+
+	errList = append(errList, deleteJobVolumes(containers), deleteDir(jobDir(jobKey)))
+	return errors.FirstError(errList...)
+}
+
+// deletes the tensorflow serving model volumes of job workers (the containers must be removed first)
+func deleteJobVolumes(containers []dockertypes.Container) error {
+	errList := []error{}
+	for _, container := range containers {
+		if container.Labels["jobID"] == "" {
+			continue
+		}
+		for _, mounted := range container.Mounts {
+			if mounted.Type == mount.TypeVolume && mounted.Name != "" {
+				errList = append(errList, DeleteVolume(mounted.Name))
+			}
+		}
+	}
+	return errors.FirstError(errList...)
+}
+
+func deleteDir(dir string) error {
+	if _, err := files.DeleteDirIfPresent(dir); err != nil {
+		return ErrorFailedToDeleteAPISpec(dir, err)
+	}
+	return nil
+}
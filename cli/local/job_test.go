@@ -0,0 +1,330 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// points the local workspace at a temporary directory for the duration of the test
+func setTestWorkspace(t *testing.T) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "cortex-local-")
+	require.NoError(t, err)
+
+	prevWorkspaceDir := _localWorkspaceDir
+	_localWorkspaceDir = dir
+	t.Cleanup(func() {
+		_localWorkspaceDir = prevWorkspaceDir
+		os.RemoveAll(dir)
+	})
+}
+
+func testItemList(numItems int, batchSize int) *schema.ItemList {
+	itemList := &schema.ItemList{BatchSize: batchSize}
+	for i := 0; i < numItems; i++ {
+		itemList.Items = append(itemList.Items, json.RawMessage(s.Int(i)))
+	}
+	return itemList
+}
+
+func testEnqueueJob(t *testing.T, numItems int, batchSize int) spec.JobKey {
+	t.Helper()
+
+	jobSpec := spec.Job{
+		JobKey:           spec.JobKey{APIName: "my-api", ID: spec.MonotonicallyDecreasingID()},
+		RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 2},
+		StartTime:        time.Now(),
+	}
+	apiSpec := &spec.API{API: &userconfig.API{Resource: userconfig.Resource{Name: "my-api", Kind: userconfig.BatchAPIKind}}}
+
+	require.NoError(t, enqueueItems(&jobSpec, apiSpec, testItemList(numItems, batchSize)))
+	return jobSpec.JobKey
+}
+
+// processes the next queued batch the way a worker does (see pkg/workloads/cortex/serve/start/batch.py)
+func testProcessBatch(t *testing.T, jobKey spec.JobKey, workerIndex int, succeeded bool) {
+	t.Helper()
+
+	dir := jobDir(jobKey)
+	batchFileNames, err := files.ListDir(filepath.Join(dir, _jobBatchesDirName), true)
+	require.NoError(t, err)
+	require.NotEmpty(t, batchFileNames)
+
+	inProgressPath := filepath.Join(dir, _jobInProgressDirName, s.Int(workerIndex)+"_"+batchFileNames[0])
+	require.NoError(t, os.Rename(filepath.Join(dir, _jobBatchesDirName, batchFileNames[0]), inProgressPath))
+
+	resultBytes, err := json.Marshal(batchResult{Succeeded: succeeded, TimePerBatch: 2})
+	require.NoError(t, err)
+	require.NoError(t, files.WriteFile(resultBytes, filepath.Join(dir, _jobResultsDirName, batchFileNames[0])))
+	require.NoError(t, os.Remove(inProgressPath))
+}
+
+func TestValidateJobSubmission(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		submission schema.JobSubmission
+		errKind    string // empty if no error is expected
+	}{
+		{
+			name:       "valid",
+			submission: schema.JobSubmission{RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1}, ItemList: testItemList(3, 2)},
+		},
+		{
+			name:       "missing item list",
+			submission: schema.JobSubmission{RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1}},
+			errKind:    ErrJobSubmissionRequiresItemList,
+		},
+		{
+			name: "file path lister",
+			submission: schema.JobSubmission{
+				RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1},
+				ItemList:         testItemList(3, 2),
+				FilePathLister:   &schema.FilePathLister{},
+			},
+			errKind: ErrJobSubmissionRequiresItemList,
+		},
+		{
+			name: "delimited files",
+			submission: schema.JobSubmission{
+				RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1},
+				DelimitedFiles:   &schema.DelimitedFiles{},
+			},
+			errKind: ErrJobSubmissionRequiresItemList,
+		},
+		{
+			name:       "no items",
+			submission: schema.JobSubmission{RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1}, ItemList: testItemList(0, 2)},
+			errKind:    cr.ErrTooFewElements,
+		},
+		{
+			name:       "batch size of zero",
+			submission: schema.JobSubmission{RuntimeJobConfig: spec.RuntimeJobConfig{Workers: 1}, ItemList: testItemList(3, 0)},
+			errKind:    cr.ErrMustBeGreaterThanOrEqualTo,
+		},
+		{
+			name:       "no workers",
+			submission: schema.JobSubmission{ItemList: testItemList(3, 2)},
+			errKind:    cr.ErrMustBeGreaterThanOrEqualTo,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateJobSubmission(&tc.submission)
+			if tc.errKind == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Equal(t, tc.errKind, errors.GetKind(err))
+			}
+		})
+	}
+}
+
+func TestEnqueueItems(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		numItems  int
+		batchSize int
+		batches   [][]int
+	}{
+		{
+			name:      "one item per batch",
+			numItems:  3,
+			batchSize: 1,
+			batches:   [][]int{{0}, {1}, {2}},
+		},
+		{
+			name:      "partial last batch",
+			numItems:  5,
+			batchSize: 2,
+			batches:   [][]int{{0, 1}, {2, 3}, {4}},
+		},
+		{
+			name:      "batch size larger than the number of items",
+			numItems:  2,
+			batchSize: 10,
+			batches:   [][]int{{0, 1}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestWorkspace(t)
+			jobKey := testEnqueueJob(t, tc.numItems, tc.batchSize)
+			dir := jobDir(jobKey)
+
+			for _, subDir := range []string{_jobInProgressDirName, _jobResultsDirName, _jobWorkersDirName} {
+				require.True(t, files.IsDir(filepath.Join(dir, subDir)), subDir)
+			}
+			require.True(t, files.IsFile(filepath.Join(dir, _jobAPISpecFileName)))
+			require.True(t, files.IsFile(filepath.Join(dir, _jobCompleteFileName)))
+
+			batchFileNames, err := files.ListDir(filepath.Join(dir, _jobBatchesDirName), true)
+			require.NoError(t, err)
+			require.Len(t, batchFileNames, len(tc.batches))
+
+			for i, expectedItems := range tc.batches {
+				require.Equal(t, fmt.Sprintf("%08d.json", i), batchFileNames[i])
+
+				batchBytes, err := files.ReadFileBytes(filepath.Join(dir, _jobBatchesDirName, batchFileNames[i]))
+				require.NoError(t, err)
+				var items []int
+				require.NoError(t, json.Unmarshal(batchBytes, &items))
+				require.Equal(t, expectedItems, items)
+			}
+
+			jobStatus, err := readJobStatus(jobKey)
+			require.NoError(t, err)
+			require.Equal(t, jobKey, jobStatus.JobKey)
+			require.Equal(t, len(tc.batches), jobStatus.TotalBatchCount)
+			require.Equal(t, len(tc.batches), jobStatus.BatchesInQueue)
+		})
+	}
+}
+
+func TestReadJobStatusNotFound(t *testing.T) {
+	setTestWorkspace(t)
+
+	_, err := readJobStatus(spec.JobKey{APIName: "my-api", ID: "69b93378fa5c0218"})
+	require.Error(t, err)
+	require.Equal(t, ErrJobNotFound, errors.GetKind(err))
+}
+
+func TestJobLifecycle(t *testing.T) {
+	setTestWorkspace(t)
+	jobKey := testEnqueueJob(t, 3, 1)
+	workerEndTime := time.Now().Add(time.Minute)
+
+	getStatus := func(workers jobWorkersState) *status.JobStatus {
+		jobStatus, err := readJobStatus(jobKey)
+		require.NoError(t, err)
+		setJobStatusCode(jobStatus, workers)
+		return jobStatus
+	}
+
+	// the workers' containers have been created
+	jobStatus := getStatus(jobWorkersState{Counts: status.WorkerCounts{Pending: 2}})
+	require.Equal(t, status.JobRunning, jobStatus.Status)
+	require.Equal(t, int32(2), jobStatus.WorkerCounts.Pending)
+	require.Equal(t, 3, jobStatus.BatchesInQueue)
+	require.Nil(t, jobStatus.EndTime)
+
+	testProcessBatch(t, jobKey, 0, true)
+	testProcessBatch(t, jobKey, 1, false)
+
+	jobStatus = getStatus(jobWorkersState{Counts: status.WorkerCounts{Running: 2}})
+	require.Equal(t, status.JobRunning, jobStatus.Status)
+	require.Equal(t, 1, jobStatus.BatchesInQueue)
+	require.Equal(t, 1, jobStatus.BatchMetrics.Succeeded)
+	require.Equal(t, 1, jobStatus.BatchMetrics.Failed)
+	require.InDelta(t, 2, *jobStatus.BatchMetrics.AverageTimePerBatch, 1e-9)
+
+	// the workers exited before processing every batch
+	jobStatus = getStatus(jobWorkersState{Counts: status.WorkerCounts{Succeeded: 2}, EndTime: workerEndTime})
+	require.Equal(t, status.JobWorkerError, jobStatus.Status)
+
+	// a worker ran out of memory
+	jobStatus = getStatus(jobWorkersState{Counts: status.WorkerCounts{Succeeded: 1, Failed: 1}, WasOOMKilled: true, EndTime: workerEndTime})
+	require.Equal(t, status.JobWorkerOOM, jobStatus.Status)
+
+	testProcessBatch(t, jobKey, 0, true)
+
+	jobStatus = getStatus(jobWorkersState{Counts: status.WorkerCounts{Succeeded: 2}, EndTime: workerEndTime})
+	require.Equal(t, status.JobCompletedWithFailures, jobStatus.Status)
+	require.Equal(t, 0, jobStatus.BatchesInQueue)
+	require.Equal(t, 2, jobStatus.BatchMetrics.Succeeded)
+	require.Equal(t, 1, jobStatus.BatchMetrics.Failed)
+	require.NotNil(t, jobStatus.EndTime)
+	require.True(t, workerEndTime.Equal(*jobStatus.EndTime))
+}
+
+func TestJobLifecycleSucceeded(t *testing.T) {
+	setTestWorkspace(t)
+	jobKey := testEnqueueJob(t, 2, 1)
+
+	testProcessBatch(t, jobKey, 0, true)
+	testProcessBatch(t, jobKey, 1, true)
+
+	jobStatus, err := readJobStatus(jobKey)
+	require.NoError(t, err)
+	setJobStatusCode(jobStatus, jobWorkersState{Counts: status.WorkerCounts{Succeeded: 2}})
+	require.Equal(t, status.JobSucceeded, jobStatus.Status)
+	require.Nil(t, jobStatus.EndTime) // the workers' exit times are unknown
+}
+
+func TestJobLifecycleStopped(t *testing.T) {
+	setTestWorkspace(t)
+	jobKey := testEnqueueJob(t, 2, 1)
+
+	testProcessBatch(t, jobKey, 0, true)
+
+	stoppedAt := time.Now().Add(time.Minute).Round(0)
+	require.NoError(t, files.WriteFile([]byte(stoppedAt.Format(time.RFC3339Nano)), filepath.Join(jobDir(jobKey), _jobStoppedFileName)))
+
+	// the stopped status takes precedence over the workers which are still running
+	jobStatus, err := readJobStatus(jobKey)
+	require.NoError(t, err)
+	setJobStatusCode(jobStatus, jobWorkersState{Counts: status.WorkerCounts{Running: 1}})
+	require.Equal(t, status.JobStopped, jobStatus.Status)
+	require.Equal(t, 1, jobStatus.BatchesInQueue)
+	require.NotNil(t, jobStatus.EndTime)
+	require.True(t, stoppedAt.Equal(*jobStatus.EndTime))
+}
+
+func TestJobLifecycleOrphanedBatch(t *testing.T) {
+	setTestWorkspace(t)
+	jobKey := testEnqueueJob(t, 2, 1)
+	dir := jobDir(jobKey)
+
+	testProcessBatch(t, jobKey, 0, true)
+
+	// worker 1 exited while processing the last batch, so the batch is never completed
+	require.NoError(t, os.Rename(filepath.Join(dir, _jobBatchesDirName, "00000001.json"), filepath.Join(dir, _jobInProgressDirName, "1_00000001.json")))
+
+	jobStatus, err := readJobStatus(jobKey)
+	require.NoError(t, err)
+	require.Equal(t, 1, jobStatus.BatchesInQueue)
+	setJobStatusCode(jobStatus, jobWorkersState{Counts: status.WorkerCounts{Succeeded: 1, Failed: 1}})
+	require.Equal(t, status.JobWorkerError, jobStatus.Status)
+
+	// once the remaining worker reclaims the orphaned batch, it is recorded as failed
+	resultBytes, err := json.Marshal(batchResult{Succeeded: false})
+	require.NoError(t, err)
+	require.NoError(t, files.WriteFile(resultBytes, filepath.Join(dir, _jobResultsDirName, "00000001.json")))
+	require.NoError(t, os.Remove(filepath.Join(dir, _jobInProgressDirName, "1_00000001.json")))
+
+	jobStatus, err = readJobStatus(jobKey)
+	require.NoError(t, err)
+	require.Equal(t, 0, jobStatus.BatchesInQueue)
+	setJobStatusCode(jobStatus, jobWorkersState{Counts: status.WorkerCounts{Succeeded: 2}})
+	require.Equal(t, status.JobCompletedWithFailures, jobStatus.Status)
+}
@@ -18,6 +18,7 @@ package local
 
 import (
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 func StreamLogs(apiName string) error {
@@ -26,11 +27,15 @@ func StreamLogs(apiName string) error {
 		return err
 	}
 
-	_, err = FindAPISpec(apiName)
+	apiSpec, err := FindAPISpec(apiName)
 	if err != nil {
 		return err
 	}
 
+	if apiSpec.Kind == userconfig.BatchAPIKind {
+		return ErrorJobIDRequired(apiName)
+	}
+
 	containers, err := GetContainersByAPI(apiName)
 	if err != nil {
 		return err
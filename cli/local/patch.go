@@ -28,14 +28,9 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func Patch(env cliconfig.Environment, configPath string) ([]schema.DeployResult, error) {
+func Patch(env cliconfig.Environment, configPath string, configBytes []byte) ([]schema.DeployResult, error) {
 	configFileName := filepath.Base(configPath)
 
-	configBytes, err := files.ReadFileBytes(configPath)
-	if err != nil {
-		return nil, err
-	}
-
 	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, types.LocalProviderType, configFileName, nil, nil)
 	if err != nil {
 		return nil, err
@@ -56,7 +51,7 @@ func Patch(env cliconfig.Environment, configPath string) ([]schema.DeployResult,
 			return nil, err
 		}
 
-		projectFiles, err := newProjectFiles(projectFileList, localProjectDir)
+		projectFiles, err := NewProjectFiles(projectFileList, localProjectDir)
 		if err != nil {
 			return nil, err
 		}
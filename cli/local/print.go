@@ -25,19 +25,19 @@ import (
 var OutputType flags.OutputType = flags.PrettyOutputType
 
 func localPrintln(a ...interface{}) {
-	if OutputType != flags.JSONOutputType {
+	if !OutputType.IsMachineReadable() {
 		fmt.Println(a...)
 	}
 }
 
 func localPrint(a ...interface{}) {
-	if OutputType != flags.JSONOutputType {
+	if !OutputType.IsMachineReadable() {
 		fmt.Print(a...)
 	}
 }
 
 func localPrintf(format string, a ...interface{}) {
-	if OutputType != flags.JSONOutputType {
+	if !OutputType.IsMachineReadable() {
 		fmt.Printf(format, a...)
 	}
 }
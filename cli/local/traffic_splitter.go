@@ -0,0 +1,217 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	_trafficSplitterImage      = "nginx:1.19-alpine"
+	_trafficSplitterConfigFile = "nginx.conf"
+	_dockerHostName            = "host.docker.internal" // resolves to the host, where the ports of the realtime apis are published
+)
+
+// traffic splitters run as an nginx container which routes each request to the port of one of its apis on the host
+func updateTrafficSplitter(newAPISpec *spec.API, prevAPISpec *spec.API, prevAPIContainers []dockertypes.Container) (*schema.APIResponse, string, error) {
+	if areAPIsEqual(newAPISpec, prevAPISpec) {
+		return toAPIResponse(newAPISpec), fmt.Sprintf("%s is up to date", newAPISpec.Resource.UserString()), nil
+	}
+
+	if prevAPISpec != nil || len(prevAPIContainers) != 0 {
+		if err := DeleteAPI(newAPISpec.Name); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writeAPISpec(newAPISpec); err != nil {
+		DeleteAPI(newAPISpec.Name)
+		return nil, "", err
+	}
+
+	if err := deployTrafficSplitterContainer(newAPISpec); err != nil {
+		DeleteAPI(newAPISpec.Name)
+		return nil, "", err
+	}
+
+	if prevAPISpec == nil && len(prevAPIContainers) == 0 {
+		return toAPIResponse(newAPISpec), fmt.Sprintf("creating %s", newAPISpec.Resource.UserString()), nil
+	}
+	return toAPIResponse(newAPISpec), fmt.Sprintf("updating %s", newAPISpec.Resource.UserString()), nil
+}
+
+func deployTrafficSplitterContainer(api *spec.API) error {
+	nginxConfig, err := trafficSplitterNginxConfig(api)
+	if err != nil {
+		return errors.Wrap(err, api.Identify())
+	}
+
+	workspaceDir := filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key))
+	if err := files.WriteFile([]byte(nginxConfig), filepath.Join(workspaceDir, _trafficSplitterConfigFile)); err != nil {
+		return errors.Wrap(err, api.Identify())
+	}
+
+	hostConfig := &container.HostConfig{
+		PortBindings: nat.PortMap{
+			_defaultPortStr + "/tcp": []nat.PortBinding{{HostPort: s.Int(*api.Networking.LocalPort)}},
+		},
+		Mounts:     getWorkspaceMounts(api, nil),
+		ExtraHosts: []string{_dockerHostName + ":host-gateway"},
+	}
+
+	containerConfig := &container.Config{
+		Image: _trafficSplitterImage,
+		Tty:   true,
+		Cmd:   []string{"nginx", "-c", filepath.Join(_workspaceDir, _trafficSplitterConfigFile), "-g", "daemon off;"},
+		ExposedPorts: nat.PortSet{
+			_defaultPortStr + "/tcp": struct{}{},
+		},
+		Labels: map[string]string{
+			"cortex":  "true",
+			"type":    _apiContainerName,
+			"apiID":   api.ID,
+			"specID":  api.SpecID,
+			"apiName": api.Name,
+		},
+	}
+
+	containerInfo, err := docker.MustDockerClient().ContainerCreate(context.Background(), containerConfig, hostConfig, nil, "")
+	if err != nil {
+		if strings.Contains(err.Error(), "bind source path does not exist") {
+			return errors.Wrap(ErrorBindDockerInDocker(err), api.Identify())
+		}
+		return errors.Wrap(err, api.Identify())
+	}
+
+	err = docker.MustDockerClient().ContainerStart(context.Background(), containerInfo.ID, dockertypes.ContainerStartOptions{})
+	if err != nil {
+		return errors.Wrap(err, api.Identify())
+	}
+
+	return nil
+}
+
+// split_clients assigns each request to an upstream based on the hash of its request id, in proportion to the apis' weights
+func trafficSplitterNginxConfig(api *spec.API) (string, error) {
+	var upstreams strings.Builder
+	var splits strings.Builder
+
+	var lastSplit *userconfig.TrafficSplit
+	for _, trafficSplit := range api.APIs {
+		if trafficSplit.Weight > 0 {
+			lastSplit = trafficSplit
+		}
+	}
+
+	for _, trafficSplit := range api.APIs {
+		apiSpec, err := FindAPISpec(trafficSplit.Name)
+		if err != nil {
+			return "", errors.Wrap(err, userconfig.APIsKey)
+		}
+		if apiSpec.Kind != userconfig.RealtimeAPIKind || apiSpec.Networking.LocalPort == nil {
+			return "", errors.Wrap(ErrorTrafficSplitterAPIsNotDeployed([]string{trafficSplit.Name}), userconfig.APIsKey)
+		}
+
+		upstreams.WriteString(fmt.Sprintf("    upstream %s {\n        server %s:%d;\n    }\n\n", trafficSplit.Name, _dockerHostName, *apiSpec.Networking.LocalPort))
+
+		if trafficSplit == lastSplit {
+			splits.WriteString(fmt.Sprintf("        * %s;\n", trafficSplit.Name))
+		} else if trafficSplit.Weight > 0 {
+			splits.WriteString(fmt.Sprintf("        %d%% %s;\n", trafficSplit.Weight, trafficSplit.Name))
+		}
+	}
+
+	return fmt.Sprintf(`events {}
+
+http {
+%s    split_clients "${request_id}" $cortex_api {
+%s    }
+
+    server {
+        listen %s;
+        client_max_body_size 0;
+
+        location / {
+            proxy_pass http://$cortex_api;
+            proxy_http_version 1.1;
+            proxy_set_header Connection "";
+            proxy_read_timeout 3600s;
+            proxy_send_timeout 3600s;
+        }
+    }
+}
+`, upstreams.String(), splits.String(), _defaultPortStr), nil
+}
+
+// returns the names of the traffic splitters which route requests to the api
+func trafficSplittersUsingAPI(apiName string) ([]string, error) {
+	apiSpecList, err := ListAPISpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	var trafficSplitterNames []string
+	for _, apiSpec := range apiSpecList {
+		if apiSpec.Kind != userconfig.TrafficSplitterKind {
+			continue
+		}
+		for _, trafficSplit := range apiSpec.APIs {
+			if trafficSplit.Name == apiName {
+				trafficSplitterNames = append(trafficSplitterNames, apiSpec.Name)
+			}
+		}
+	}
+
+	return trafficSplitterNames, nil
+}
+
+// traffic splitters route requests to the ports of their apis, so they are redeployed when the port of one of their apis changes
+func redeployTrafficSplittersUsingAPI(apiName string) error {
+	trafficSplitterNames, err := trafficSplittersUsingAPI(apiName)
+	if err != nil {
+		return err
+	}
+
+	for _, trafficSplitterName := range trafficSplitterNames {
+		trafficSplitter, err := FindAPISpec(trafficSplitterName)
+		if err != nil {
+			return err
+		}
+		if err := DeleteContainers(trafficSplitterName); err != nil {
+			return err
+		}
+		if err := deployTrafficSplitterContainer(trafficSplitter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -24,7 +24,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -48,7 +47,7 @@ type ProjectFiles struct {
 	projectRoot  string
 }
 
-func newProjectFiles(projectFileList []string, projectRoot string) (ProjectFiles, error) {
+func NewProjectFiles(projectFileList []string, projectRoot string) (ProjectFiles, error) {
 	relFilePaths := make([]string, len(projectFileList))
 	for i, projectFilePath := range projectFileList {
 		if !files.IsAbsOrTildePrefixed(projectFilePath) {
@@ -122,11 +121,25 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 		return err
 	}
 
+	var errs []error
+
 	for i := range apis {
 		api := &apis[i]
 
+		if api.Kind == userconfig.TrafficSplitterKind {
+			if err := spec.ValidateTrafficSplitter(api, types.LocalProviderType, awsClient); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
+			}
+			if err := checkIfTrafficSplitterAPIsExist(api.APIs, apis); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify(), userconfig.APIsKey))
+			}
+			continue
+		}
+
 		if err := spec.ValidateAPI(api, models, projectFiles, types.LocalProviderType, awsClient, gcpClient, nil); err != nil {
-			return errors.Wrap(err, api.Identify())
+			errs = append(errs, errors.Wrap(err, api.Identify()))
+			continue
 		}
 
 		if api.Compute.CPU != nil && (api.Compute.CPU.MilliValue() > int64(dockerClient.Info.NCPU)*1000) {
@@ -136,11 +149,20 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 
 	dups := spec.FindDuplicateNames(apis)
 	if len(dups) > 0 {
-		return spec.ErrorDuplicateName(dups)
+		errs = append(errs, spec.ErrorDuplicateName(dups))
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 
 	imageSet := strset.New()
 	for _, api := range apis {
+		if api.Kind == userconfig.TrafficSplitterKind {
+			imageSet.Add(_trafficSplitterImage)
+			continue
+		}
+
 		imageSet.Add(api.Predictor.Image)
 		if api.Predictor.Type == userconfig.TensorFlowPredictorType {
 			imageSet.Add(api.Predictor.TensorFlowServingImage)
@@ -159,7 +181,7 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 		}
 
 		pullVerbosity := docker.PrintDots
-		if OutputType == flags.JSONOutputType {
+		if OutputType.IsMachineReadable() {
 			pullVerbosity = docker.NoPrint
 		}
 
@@ -195,6 +217,9 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 
 	for i := range apis {
 		api := &apis[i]
+		if api.Kind == userconfig.BatchAPIKind {
+			continue // batch api workers don't serve requests
+		}
 
 		updatingAPIToPortMap[api.Name] = api.Networking.LocalPort
 		if api.Networking.LocalPort != nil {
@@ -208,6 +233,10 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 
 	for i := range apis {
 		api := &apis[i]
+		if api.Kind == userconfig.BatchAPIKind {
+			continue
+		}
+
 		if api.Networking.LocalPort != nil {
 			// same port as previous deployment of this API
 			if *api.Networking.LocalPort == runningAPIsToPortMap[api.Name] {
@@ -240,6 +269,10 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 
 	for i := range apis {
 		api := &apis[i]
+		if api.Kind == userconfig.BatchAPIKind {
+			continue
+		}
+
 		if api.Networking.LocalPort == nil {
 			availablePort, err := findTheNextAvailablePort(usedPorts)
 			if err != nil {
@@ -253,6 +286,36 @@ func ValidateLocalAPIs(apis []userconfig.API, models *[]spec.CuratedModelResourc
 	return nil
 }
 
+// the apis of a traffic splitter must be realtime apis which are either deployed or defined in the same configuration file
+func checkIfTrafficSplitterAPIsExist(trafficSplitterAPIs []*userconfig.TrafficSplit, apis []userconfig.API) error {
+	var missingAPIs []string
+	for _, trafficSplitAPI := range trafficSplitterAPIs {
+		found := false
+		isDefined := false
+		for _, definedAPI := range apis {
+			if trafficSplitAPI.Name == definedAPI.Name {
+				isDefined = true
+				found = definedAPI.Kind == userconfig.RealtimeAPIKind
+			}
+		}
+
+		if !isDefined {
+			if apiSpec, err := FindAPISpec(trafficSplitAPI.Name); err == nil {
+				found = apiSpec.Kind == userconfig.RealtimeAPIKind
+			}
+		}
+
+		if !found {
+			missingAPIs = append(missingAPIs, trafficSplitAPI.Name)
+		}
+	}
+
+	if len(missingAPIs) != 0 {
+		return ErrorTrafficSplitterAPIsNotDeployed(missingAPIs)
+	}
+	return nil
+}
+
 func checkPortAvailability(port int) (bool, error) {
 	ln, err := net.Listen("tcp", ":"+s.Int(port))
 	if err != nil {
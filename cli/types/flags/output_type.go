@@ -23,6 +23,7 @@ const (
 	MixedOutputType              // Internal only
 	PrettyOutputType
 	JSONOutputType
+	YAMLOutputType
 )
 
 var _outputTypes = []string{
@@ -30,6 +31,7 @@ var _outputTypes = []string{
 	"mixed",
 	"pretty",
 	"json",
+	"yaml",
 }
 
 func OutputTypeFromString(s string) OutputType {
@@ -49,6 +51,11 @@ func OutputTypeStrings() []string {
 	return _outputTypes[1:]
 }
 
+// IsMachineReadable returns whether the output must only contain the command's data (i.e. no progress messages or formatting)
+func (t OutputType) IsMachineReadable() bool {
+	return t == JSONOutputType || t == YAMLOutputType
+}
+
 func (t OutputType) String() string {
 	return _outputTypes[t]
 }
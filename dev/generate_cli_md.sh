@@ -31,12 +31,15 @@ mv $HOME/.cortex/cli.yaml $cli_config_backup_path
 cat $ROOT/dev/cli_md_template.md >> $out_file
 
 commands=(
+  "init"
+  "validate"
   "deploy"
   "get"
   "logs"
   "patch"
   "refresh"
   "predict"
+  "job submit"
   "delete"
   "cluster up"
   "cluster info"
@@ -47,6 +50,7 @@ commands=(
   "env list"
   "env default"
   "env delete"
+  "schema"
   "version"
   "completion"
 )
@@ -27,6 +27,10 @@ def export(base_key, value):
         exportTags(value, "CORTEX_API_LOAD_BALANCER_TAGS", {"cortex.dev/load-balancer": "api"})
         return
 
+    # node groups are read directly from the cluster configuration file (see helpers.get_worker_node_groups())
+    if base_key.lower() == "cortex_node_groups":
+        return
+
     if value is None:
         return
     elif type(value) is list:
@@ -65,3 +69,9 @@ for config_path in sys.argv[1:]:
         config = yaml.safe_load(f)
 
     export("CORTEX", config)
+
+    if config.get("provider") != "gcp":
+        worker_instance_types = [config.get("instance_type")] + [
+            node_group["instance_type"] for node_group in config.get("node_groups") or []
+        ]
+        print(f'export CORTEX_WORKER_INSTANCE_TYPES="{" ".join(filter(None, worker_instance_types))}"')
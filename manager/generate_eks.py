@@ -16,6 +16,7 @@ import sys
 import yaml
 import os
 import collections
+from helpers import get_worker_node_groups, get_eksctl_nodegroup_name
 
 
 # kubelet config schema: https://github.com/kubernetes/kubernetes/blob/master/staging/src/k8s.io/kubelet/config/v1beta1/types.go
@@ -46,24 +47,25 @@ def merge_override(a, b):
     return a
 
 
-def apply_worker_settings(nodegroup):
+def apply_worker_settings(nodegroup, node_group):
     worker_settings = {
-        "name": "ng-cortex-worker-on-demand",
-        "labels": {"workload": "true"},
+        "name": get_eksctl_nodegroup_name(node_group["name"], "on-demand"),
+        "labels": {"workload": "true", "node-group": node_group["name"]},
         "taints": {"workload": "true:NoSchedule"},
         "tags": {
             "k8s.io/cluster-autoscaler/enabled": "true",
             "k8s.io/cluster-autoscaler/node-template/label/workload": "true",
+            "k8s.io/cluster-autoscaler/node-template/label/node-group": node_group["name"],
         },
     }
 
     return merge_override(nodegroup, worker_settings)
 
 
-def apply_clusterconfig(nodegroup, config):
+def apply_clusterconfig(nodegroup, config, availability_zones):
     clusterconfig_settings = {
         "instanceType": config["instance_type"],
-        "availabilityZones": config["availability_zones"],
+        "availabilityZones": availability_zones,
         "volumeSize": config["instance_volume_size"],
         "minSize": config["min_instances"],
         "maxSize": config["max_instances"],
@@ -79,7 +81,7 @@ def apply_clusterconfig(nodegroup, config):
 
 def apply_spot_settings(nodegroup, config):
     spot_settings = {
-        "name": "ng-cortex-worker-spot",
+        "name": get_eksctl_nodegroup_name(config["name"], "spot"),
         "instanceType": "mixed",
         "instancesDistribution": {
             "instanceTypes": config["spot_config"]["instance_distribution"],
@@ -117,9 +119,8 @@ def is_gpu(instance_type):
     return instance_type.startswith("g") or instance_type.startswith("p")
 
 
-def apply_inf_settings(nodegroup, cluster_config):
-    instance_type = cluster_config["instance_type"]
-    instance_region = cluster_config["region"]
+def apply_inf_settings(nodegroup, config):
+    instance_type = config["instance_type"]
 
     num_chips, hugepages_mem = get_inf_resources(instance_type)
     inf_settings = {
@@ -153,6 +154,43 @@ def get_inf_resources(instance_type):
     return num_chips, f"{128 * num_chips}Mi"
 
 
+def generate_worker_nodegroups(cluster_config, node_group):
+    "returns the eksctl nodegroups for a node group (the spot nodegroup may be accompanied by an on-demand backup)"
+    worker_nodegroup = default_nodegroup(cluster_config)
+    apply_worker_settings(worker_nodegroup, node_group)
+
+    apply_clusterconfig(worker_nodegroup, node_group, cluster_config["availability_zones"])
+
+    if node_group["spot"]:
+        apply_spot_settings(worker_nodegroup, node_group)
+
+    if is_gpu(node_group["instance_type"]):
+        apply_gpu_settings(worker_nodegroup)
+
+    if is_inf(node_group["instance_type"]):
+        apply_inf_settings(worker_nodegroup, node_group)
+
+    nodegroups = [worker_nodegroup]
+
+    if node_group.get("spot_config") is not None and node_group["spot_config"].get(
+        "on_demand_backup", False
+    ):
+        backup_nodegroup = default_nodegroup(cluster_config)
+        apply_worker_settings(backup_nodegroup, node_group)
+        apply_clusterconfig(backup_nodegroup, node_group, cluster_config["availability_zones"])
+        if is_gpu(node_group["instance_type"]):
+            apply_gpu_settings(backup_nodegroup)
+        if is_inf(node_group["instance_type"]):
+            apply_inf_settings(backup_nodegroup, node_group)
+
+        backup_nodegroup["minSize"] = 0
+        backup_nodegroup["desiredCapacity"] = 0
+
+        nodegroups.append(backup_nodegroup)
+
+    return nodegroups
+
+
 def generate_eks(cluster_config_path):
     with open(cluster_config_path, "r") as f:
         cluster_config = yaml.safe_load(f)
@@ -168,19 +206,9 @@ def generate_eks(cluster_config_path):
     }
     operator_nodegroup = merge_override(operator_nodegroup, operator_settings)
 
-    worker_nodegroup = default_nodegroup(cluster_config)
-    apply_worker_settings(worker_nodegroup)
-
-    apply_clusterconfig(worker_nodegroup, cluster_config)
-
-    if cluster_config["spot"]:
-        apply_spot_settings(worker_nodegroup, cluster_config)
-
-    if is_gpu(cluster_config["instance_type"]):
-        apply_gpu_settings(worker_nodegroup)
-
-    if is_inf(cluster_config["instance_type"]):
-        apply_inf_settings(worker_nodegroup, cluster_config)
+    worker_nodegroups = []
+    for node_group in get_worker_node_groups(cluster_config):
+        worker_nodegroups += generate_worker_nodegroups(cluster_config, node_group)
 
     nat_gateway = "Disable"
     if cluster_config["nat_gateway"] == "single":
@@ -199,28 +227,12 @@ def generate_eks(cluster_config_path):
         },
         "vpc": {"nat": {"gateway": nat_gateway}},
         "availabilityZones": cluster_config["availability_zones"],
-        "nodeGroups": [operator_nodegroup, worker_nodegroup],
+        "nodeGroups": [operator_nodegroup] + worker_nodegroups,
     }
 
     if cluster_config.get("vpc_cidr", "") != "":
         eks["vpc"]["cidr"] = cluster_config["vpc_cidr"]
 
-    if cluster_config.get("spot_config") is not None and cluster_config["spot_config"].get(
-        "on_demand_backup", False
-    ):
-        backup_nodegroup = default_nodegroup(cluster_config)
-        apply_worker_settings(backup_nodegroup)
-        apply_clusterconfig(backup_nodegroup, cluster_config)
-        if is_gpu(cluster_config["instance_type"]):
-            apply_gpu_settings(backup_nodegroup)
-        if is_inf(cluster_config["instance_type"]):
-            apply_inf_settings(backup_nodegroup, cluster_config)
-
-        backup_nodegroup["minSize"] = 0
-        backup_nodegroup["desiredCapacity"] = 0
-
-        eks["nodeGroups"].append(backup_nodegroup)
-
     print(yaml.dump(eks, Dumper=IgnoreAliases, default_flow_style=False, default_style=""))
 
 
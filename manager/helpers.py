@@ -43,3 +43,37 @@ def _get_load_balancer(load_balancer_tag, cluster_name, client_elbv2):
                 return load_balancers[tag_description["ResourceArn"]]
 
     raise Exception(f"unable to find {load_balancer_tag} load balancer")
+
+
+PRIMARY_NODE_GROUP_NAME = "primary"
+
+# the keys of a node group's configuration (the primary node group uses the top-level keys of the cluster configuration)
+_node_group_keys = [
+    "instance_type",
+    "min_instances",
+    "max_instances",
+    "instance_volume_size",
+    "instance_volume_type",
+    "instance_volume_iops",
+    "spot",
+    "spot_config",
+]
+
+
+def get_worker_node_groups(cluster_config):
+    "returns the configuration of each worker node group, starting with the primary node group"
+    primary_node_group = {key: cluster_config.get(key) for key in _node_group_keys}
+    primary_node_group["name"] = PRIMARY_NODE_GROUP_NAME
+
+    node_groups = [primary_node_group]
+    for node_group in cluster_config.get("node_groups") or []:
+        node_groups.append(node_group)
+    return node_groups
+
+
+def get_eksctl_nodegroup_name(node_group_name, lifecycle):
+    "returns the name of the eksctl nodegroup for a node group's on-demand or spot instances"
+    if node_group_name == PRIMARY_NODE_GROUP_NAME:
+        return f"ng-cortex-worker-{lifecycle}"
+    # the prefix prevents collisions with the primary and operator nodegroups (e.g. for a node group named "worker")
+    return f"ng-cortex-group-{node_group_name}-{lifecycle}"
@@ -73,13 +73,13 @@ function cluster_up_aws() {
   envsubst < manifests/statsd.yaml | kubectl apply -f - >/dev/null
   echo "✓"
 
-  if [[ "$CORTEX_INSTANCE_TYPE" == p* ]] || [[ "$CORTEX_INSTANCE_TYPE" == g* ]]; then
+  if has_gpu_instances; then
     echo -n "￮ configuring gpu support "
     envsubst < manifests/nvidia_aws.yaml | kubectl apply -f - >/dev/null
     echo "✓"
   fi
 
-  if [[ "$CORTEX_INSTANCE_TYPE" == inf* ]]; then
+  if has_inf_instances; then
     echo -n "￮ configuring inf support "
     envsubst < manifests/inferentia.yaml | kubectl apply -f - >/dev/null
     echo "✓"
@@ -136,7 +136,7 @@ function cluster_up_gcp() {
 function cluster_configure() {
   check_eks
 
-  resize_nodegroup
+  resize_nodegroups
 
   echo -n "￮ updating cluster configuration "
   setup_configmap
@@ -301,16 +301,50 @@ function restart_operator() {
   if [ "$printed_dot" == "true" ]; then echo " ✓"; else echo "✓"; fi
 }
 
+# returns 0 if any of the worker node groups uses gpu instances
+function has_gpu_instances() {
+  for instance_type in $CORTEX_WORKER_INSTANCE_TYPES; do
+    if [[ "$instance_type" == p* ]] || [[ "$instance_type" == g* ]]; then
+      return 0
+    fi
+  done
+  return 1
+}
+
+# returns 0 if any of the worker node groups uses inferentia instances
+function has_inf_instances() {
+  for instance_type in $CORTEX_WORKER_INSTANCE_TYPES; do
+    if [[ "$instance_type" == inf* ]]; then
+      return 0
+    fi
+  done
+  return 1
+}
+
+function resize_nodegroups() {
+  python list_node_groups.py $CORTEX_CLUSTER_CONFIG_FILE > /workspace/node_groups.txt
+  while read -r on_demand_nodegroup spot_nodegroup min_instances max_instances on_demand_backup; do
+    resize_nodegroup $on_demand_nodegroup $spot_nodegroup $min_instances $max_instances $on_demand_backup
+  done < /workspace/node_groups.txt
+}
+
+# usage: resize_nodegroup <on-demand nodegroup name> <spot nodegroup name> <min instances> <max instances> <on-demand backup (True/False)>
 function resize_nodegroup() {
+  on_demand_nodegroup="$1"
+  spot_nodegroup="$2"
+  min_instances="$3"
+  max_instances="$4"
+  on_demand_backup="$5"
+
   # check for change in min/max instances
-  asg_on_demand_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`ng-cortex-worker-on-demand\`)]")
+  asg_on_demand_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`$on_demand_nodegroup\`)]")
   asg_on_demand_length=$(echo "$asg_on_demand_info" | jq -r 'length')
   asg_on_demand_name=""
   if (( "$asg_on_demand_length" > "0" )); then
     asg_on_demand_name=$(echo "$asg_on_demand_info" | jq -r 'first | .AutoScalingGroupName')
   fi
 
-  asg_spot_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`ng-cortex-worker-spot\`)]")
+  asg_spot_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`$spot_nodegroup\`)]")
   asg_spot_length=$(echo "$asg_spot_info" | jq -r 'length')
   asg_spot_name=""
   if (( "$asg_spot_length" > "0" )); then
@@ -341,34 +375,40 @@ function resize_nodegroup() {
   asg_on_demand_resize_flags=""
   asg_spot_resize_flags=""
 
-  if [ "$asg_min_size" != "$CORTEX_MIN_INSTANCES" ]; then
+  if [ "$asg_min_size" != "$min_instances" ]; then
     # only update min for on-demand nodegroup if it's not a backup
-    if [[ -n $asg_on_demand_name ]] && [[ "$CORTEX_SPOT_CONFIG_ON_DEMAND_BACKUP" != "True" ]]; then
-      asg_on_demand_resize_flags+=" --min-size=$CORTEX_MIN_INSTANCES"
+    if [[ -n $asg_on_demand_name ]] && [[ "$on_demand_backup" != "True" ]]; then
+      asg_on_demand_resize_flags+=" --min-size=$min_instances"
     fi
     if [[ -n $asg_spot_name ]]; then
-      asg_spot_resize_flags+=" --min-size=$CORTEX_MIN_INSTANCES"
+      asg_spot_resize_flags+=" --min-size=$min_instances"
     fi
   fi
 
-  if [ "$asg_max_size" != "$CORTEX_MAX_INSTANCES" ]; then
+  if [ "$asg_max_size" != "$max_instances" ]; then
     if [[ -n $asg_on_demand_name ]]; then
-      asg_on_demand_resize_flags+=" --max-size=$CORTEX_MAX_INSTANCES"
+      asg_on_demand_resize_flags+=" --max-size=$max_instances"
     fi
     if [[ -n $asg_spot_name ]]; then
-      asg_spot_resize_flags+=" --max-size=$CORTEX_MAX_INSTANCES"
+      asg_spot_resize_flags+=" --max-size=$max_instances"
     fi
   fi
 
+  node_group_str=""
+  if [ "$on_demand_nodegroup" != "ng-cortex-worker-on-demand" ]; then
+    node_group_str="${on_demand_nodegroup#ng-cortex-group-}"
+    node_group_str=" for the ${node_group_str%-on-demand} node group"
+  fi
+
   is_resizing="false"
-  if [ "$asg_min_size" != "$CORTEX_MIN_INSTANCES" ] && [ "$asg_max_size" != "$CORTEX_MAX_INSTANCES" ]; then
-    echo -n "￮ updating min instances to $CORTEX_MIN_INSTANCES and max instances to $CORTEX_MAX_INSTANCES "
+  if [ "$asg_min_size" != "$min_instances" ] && [ "$asg_max_size" != "$max_instances" ]; then
+    echo -n "￮ updating min instances to $min_instances and max instances to $max_instances$node_group_str "
     is_resizing="true"
-  elif [ "$asg_min_size" != "$CORTEX_MIN_INSTANCES" ]; then
-    echo -n "￮ updating min instances to $CORTEX_MIN_INSTANCES "
+  elif [ "$asg_min_size" != "$min_instances" ]; then
+    echo -n "￮ updating min instances to $min_instances$node_group_str "
     is_resizing="true"
-  elif [ "$asg_max_size" != "$CORTEX_MAX_INSTANCES" ]; then
-    echo -n "￮ updating max instances to $CORTEX_MAX_INSTANCES "
+  elif [ "$asg_max_size" != "$max_instances" ]; then
+    echo -n "￮ updating max instances to $max_instances$node_group_str "
     is_resizing="true"
   fi
 
@@ -385,19 +425,17 @@ function resize_nodegroup() {
 }
 
 function suspend_az_rebalance() {
-  asg_on_demand_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`ng-cortex-worker-on-demand\`)]")
-  asg_on_demand_length=$(echo "$asg_on_demand_info" | jq -r 'length')
-  if (( "$asg_on_demand_length" > "0" )); then
-    asg_on_demand_name=$(echo "$asg_on_demand_info" | jq -r 'first | .AutoScalingGroupName')
-    aws autoscaling suspend-processes --region $CORTEX_REGION --auto-scaling-group-name $asg_on_demand_name --scaling-processes AZRebalance
-  fi
-
-  asg_spot_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`ng-cortex-worker-spot\`)]")
-  asg_spot_length=$(echo "$asg_spot_info" | jq -r 'length')
-  if (( "$asg_spot_length" > "0" )); then
-    asg_spot_name=$(echo "$asg_spot_info" | jq -r 'first | .AutoScalingGroupName')
-    aws autoscaling suspend-processes --region $CORTEX_REGION --auto-scaling-group-name $asg_spot_name --scaling-processes AZRebalance
-  fi
+  python list_node_groups.py $CORTEX_CLUSTER_CONFIG_FILE > /workspace/node_groups.txt
+  while read -r on_demand_nodegroup spot_nodegroup _; do
+    for nodegroup in $on_demand_nodegroup $spot_nodegroup; do
+      asg_info=$(aws autoscaling describe-auto-scaling-groups --region $CORTEX_REGION --query "AutoScalingGroups[?contains(Tags[?Key==\`alpha.eksctl.io/cluster-name\`].Value, \`$CORTEX_CLUSTER_NAME\`)]|[?contains(Tags[?Key==\`alpha.eksctl.io/nodegroup-name\`].Value, \`$nodegroup\`)]")
+      asg_length=$(echo "$asg_info" | jq -r 'length')
+      if (( "$asg_length" > "0" )); then
+        asg_name=$(echo "$asg_info" | jq -r 'first | .AutoScalingGroupName')
+        aws autoscaling suspend-processes --region $CORTEX_REGION --auto-scaling-group-name $asg_name --scaling-processes AZRebalance
+      fi
+    done
+  done < /workspace/node_groups.txt
 }
 
 function create_vpc_link() {
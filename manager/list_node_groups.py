@@ -0,0 +1,39 @@
+# Copyright 2020 Cortex Labs, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import sys
+import yaml
+from helpers import get_worker_node_groups, get_eksctl_nodegroup_name
+
+
+def list_node_groups(cluster_config_path):
+    "prints one line per worker node group: <on-demand nodegroup> <spot nodegroup> <min instances> <max instances> <on-demand backup>"
+    with open(cluster_config_path, "r") as f:
+        cluster_config = yaml.safe_load(f)
+
+    for node_group in get_worker_node_groups(cluster_config):
+        on_demand_backup = node_group["spot"] and (node_group.get("spot_config") or {}).get(
+            "on_demand_backup", False
+        )
+        print(
+            get_eksctl_nodegroup_name(node_group["name"], "on-demand"),
+            get_eksctl_nodegroup_name(node_group["name"], "spot"),
+            node_group["min_instances"],
+            node_group["max_instances"],
+            on_demand_backup,
+        )
+
+
+if __name__ == "__main__":
+    list_node_groups(cluster_config_path=sys.argv[1])
@@ -16,6 +16,7 @@ import boto3
 import sys
 import yaml
 import os
+from helpers import get_worker_node_groups, get_eksctl_nodegroup_name
 
 
 def get_autoscaling_group():
@@ -56,17 +57,19 @@ def extract_nodegroup_name(asg):
     )
 
 
-def refresh_yaml(configmap_yaml_path, output_yaml_path):
-    with open(configmap_yaml_path, "r") as f:
-        cluster_configmap = yaml.safe_load(f)
+def refresh_node_group(node_group, asgs):
+    "updates the node group's configuration in place from its autoscaling groups"
+    on_demand_nodegroup_name = get_eksctl_nodegroup_name(node_group["name"], "on-demand")
+    spot_nodegroup_name = get_eksctl_nodegroup_name(node_group["name"], "spot")
 
-    cluster_configmap_str = cluster_configmap["data"]["cluster.yaml"]
-    cluster_config = yaml.safe_load(cluster_configmap_str)
+    asgs = [
+        asg
+        for asg in asgs
+        if extract_nodegroup_name(asg) in [on_demand_nodegroup_name, spot_nodegroup_name]
+    ]
 
-    asgs = get_autoscaling_group()
     # only possible when backup is enabled
-
-    if cluster_config["spot"] and cluster_config.get("spot_config", {}).get(
+    if node_group["spot"] and (node_group.get("spot_config") or {}).get(
         "on_demand_backup", False
     ):
         if len(asgs) != 2:
@@ -76,30 +79,30 @@ def refresh_yaml(configmap_yaml_path, output_yaml_path):
         asg_names = set()
         for group in asgs:
             nodegroup_name = extract_nodegroup_name(group)
-            if nodegroup_name == "ng-cortex-worker-spot":
+            if nodegroup_name == spot_nodegroup_name:
                 asg = group
             asg_names.add(nodegroup_name)
-        if "ng-cortex-worker-on-demand" not in asg_names:
+        if on_demand_nodegroup_name not in asg_names:
             raise Exception(
                 "expected autoscaling group with tag eksctl.io/v1alpha2/nodegroup-name={}".format(
-                    "ng-cortex-worker-on-demand"
+                    on_demand_nodegroup_name
                 )
             )
-        if "ng-cortex-worker-spot" not in asg_names:
+        if spot_nodegroup_name not in asg_names:
             raise Exception(
                 "expected autoscaling group with tag eksctl.io/v1alpha2/nodegroup-name={}".format(
-                    "ng-cortex-worker-spot"
+                    spot_nodegroup_name
                 )
             )
-    elif cluster_config["spot"]:
+    elif node_group["spot"]:
         if len(asgs) != 1:
             raise Exception(
                 "expected 1 autoscaling groups but found {} autoscaling groups".format(len(asgs))
             )
-        if "ng-cortex-worker-spot" not in extract_nodegroup_name(asgs[0]):
+        if spot_nodegroup_name not in extract_nodegroup_name(asgs[0]):
             raise Exception(
                 "unable to find autoscaling group with tag eksctl.io/v1alpha2/nodegroup-name={}".format(
-                    "ng-cortex-worker-spot"
+                    spot_nodegroup_name
                 )
             )
         asg = asgs[0]
@@ -108,16 +111,15 @@ def refresh_yaml(configmap_yaml_path, output_yaml_path):
             raise Exception(
                 "expected 1 autoscaling groups but found {} autoscaling groups".format(len(asgs))
             )
-        if "ng-cortex-worker-on-demand" not in extract_nodegroup_name(asgs[0]):
+        if on_demand_nodegroup_name not in extract_nodegroup_name(asgs[0]):
             raise Exception(
                 "unable to find autoscaling group with tag eksctl.io/v1alpha2/nodegroup-name={}".format(
-                    "ng-cortex-worker-on-demand"
+                    on_demand_nodegroup_name
                 )
             )
         asg = asgs[0]
-    cluster_config["min_instances"] = asg["MinSize"]
-    cluster_config["max_instances"] = asg["MaxSize"]
-    cluster_config["availability_zones"] = asg["AvailabilityZones"]
+    node_group["min_instances"] = asg["MinSize"]
+    node_group["max_instances"] = asg["MaxSize"]
     if asg.get("MixedInstancesPolicy") is not None:
         launch_template = get_launch_template(
             asg["MixedInstancesPolicy"]["LaunchTemplate"]["LaunchTemplateSpecification"][
@@ -127,22 +129,22 @@ def refresh_yaml(configmap_yaml_path, output_yaml_path):
     else:
         launch_template = get_launch_template(asg["LaunchTemplate"]["LaunchTemplateId"])
 
-    cluster_config["instance_type"] = launch_template["InstanceType"]
+    node_group["instance_type"] = launch_template["InstanceType"]
 
     if launch_template.get("BlockDeviceMappings"):
-        cluster_config["instance_volume_size"] = launch_template["BlockDeviceMappings"][0]["Ebs"][
+        node_group["instance_volume_size"] = launch_template["BlockDeviceMappings"][0]["Ebs"][
             "VolumeSize"
         ]
     else:
-        cluster_config["instance_volume_size"] = 20  # AWS volume default
+        node_group["instance_volume_size"] = 20  # AWS volume default
 
     if asg.get("LaunchTemplate") is not None:
-        cluster_config["spot"] = False
-        cluster_config["spot_config"] = None
+        node_group["spot"] = False
+        node_group["spot_config"] = None
 
     if asg.get("MixedInstancesPolicy") is not None:
         mixed_instance_policy = asg["MixedInstancesPolicy"]
-        cluster_config["spot"] = True
+        node_group["spot"] = True
         spot_config = {"on_demand_backup": len(asgs) == 2}
         instances_distribution_metadata = mixed_instance_policy["InstancesDistribution"]
         spot_config["on_demand_base_capacity"] = instances_distribution_metadata[
@@ -159,7 +161,33 @@ def refresh_yaml(configmap_yaml_path, output_yaml_path):
         ]
         spot_config["instance_distribution"] = instance_distribution
 
-        cluster_config["spot_config"] = spot_config
+        node_group["spot_config"] = spot_config
+
+    return asg
+
+
+def refresh_yaml(configmap_yaml_path, output_yaml_path):
+    with open(configmap_yaml_path, "r") as f:
+        cluster_configmap = yaml.safe_load(f)
+
+    cluster_configmap_str = cluster_configmap["data"]["cluster.yaml"]
+    cluster_config = yaml.safe_load(cluster_configmap_str)
+
+    asgs = get_autoscaling_group()
+
+    node_groups = get_worker_node_groups(cluster_config)
+
+    # the primary node group is configured by the top-level fields of the cluster configuration
+    primary_node_group = node_groups[0]
+    primary_asg = refresh_node_group(primary_node_group, asgs)
+    for key, value in primary_node_group.items():
+        if key != "name":
+            cluster_config[key] = value
+    cluster_config["availability_zones"] = primary_asg["AvailabilityZones"]
+
+    # the additional node groups are updated in place
+    for node_group in node_groups[1:]:
+        refresh_node_group(node_group, asgs)
 
     with open(output_yaml_path, "w") as f:
         yaml.dump(cluster_config, f)
@@ -71,6 +71,9 @@ const (
 	ErrCortexResourceOnlyAllowed     = "configreader.cortex_resource_only_allowed"
 	ErrCortexResourceNotAllowed      = "configreader.cortex_resource_not_allowed"
 	ErrImageVersionMismatch          = "configreader.image_version_mismatch"
+	ErrUnresolvedVariable            = "configreader.unresolved_variable"
+	ErrInvalidVariableReference      = "configreader.invalid_variable_reference"
+	ErrInvalidVariableName           = "configreader.invalid_variable_name"
 )
 
 func ErrorParseConfig() error {
@@ -87,11 +90,25 @@ func ErrorUnsupportedFieldValidation() error {
 	})
 }
 
-func ErrorUnsupportedKey(key interface{}) error {
-	return errors.WithStack(&errors.Error{
+// allowedKeys (optional) are used to suggest a replacement for a misspelled key;
+// the key is added to the error's path (but not its message), so that its line number can be found
+func ErrorUnsupportedKey(key interface{}, allowedKeys ...string) error {
+	message := fmt.Sprintf("key %s is not supported", s.UserStr(key))
+	keyStr, isStr := key.(string)
+	if isStr {
+		if suggestion, ok := s.ClosestMatch(keyStr, allowedKeys); ok {
+			message += fmt.Sprintf(" (did you mean %s?)", s.UserStr(suggestion))
+		}
+	}
+
+	err := errors.WithStack(&errors.Error{
 		Kind:    ErrUnsupportedKey,
-		Message: fmt.Sprintf("key %s is not supported", s.UserStr(key)),
+		Message: message,
 	})
+	if isStr {
+		err = errors.AppendPath(err, keyStr)
+	}
+	return err
 }
 
 func ErrorInvalidYAML(err error) error {
@@ -222,9 +239,14 @@ func ErrorInvalidInt(provided int, allowed int, allowedVals ...int) error {
 
 func ErrorInvalidStr(provided string, allowed string, allowedVals ...string) error {
 	allAllowedVals := append([]string{allowed}, allowedVals...)
+	message := fmt.Sprintf("invalid value (got %s, must be %s)", s.UserStr(provided), s.UserStrsOr(allAllowedVals))
+	if suggestion, ok := s.ClosestMatch(provided, allAllowedVals); ok {
+		message += fmt.Sprintf("; did you mean %s?", s.UserStr(suggestion))
+	}
+
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrInvalidStr,
-		Message: fmt.Sprintf("invalid value (got %s, must be %s)", s.UserStr(provided), s.UserStrsOr(allAllowedVals)),
+		Message: message,
 	})
 }
 
@@ -423,3 +445,24 @@ func ErrorImageVersionMismatch(image, tag, cortexVersion string) error {
 		Message: fmt.Sprintf("the specified image (%s) has a tag (%s) which does not match your Cortex version (%s); please update the image tag, remove the image registry path from your configuration file (to use the default value), or update your CLI (pip install cortex==%s)", image, tag, cortexVersion, cortexVersion),
 	})
 }
+
+func ErrorUnresolvedVariable(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnresolvedVariable,
+		Message: fmt.Sprintf("variable ${%s} is not set; set it in your environment, define it in a --values file, or provide a default value (e.g. ${%s:-default})", name, name),
+	})
+}
+
+func ErrorInvalidVariableReference(reference string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidVariableReference,
+		Message: fmt.Sprintf("%s is not a valid variable reference (expected ${VAR} or ${VAR:-default}, where VAR contains only letters, numbers, and underscores and does not start with a number; use $${ to write a literal ${)", s.UserStr(reference)),
+	})
+}
+
+func ErrorInvalidVariableName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidVariableName,
+		Message: fmt.Sprintf("%s is not a valid variable name (it must contain only letters, numbers, and underscores, and must not start with a number)", s.UserStr(name)),
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cast"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"gopkg.in/yaml.v3"
+)
+
+// SingleReferencePattern matches values which consist of a single variable reference (e.g. "${MIN_REPLICAS}")
+const SingleReferencePattern = `^\$\{[^}]+\}$`
+
+var _variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var _singleReferenceRegex = regexp.MustCompile(SingleReferencePattern)
+
+// VariableLookupFn returns the value of a variable, and whether it is set
+type VariableLookupFn func(name string) (string, bool)
+
+// InterpolateYAMLNode replaces ${VAR} and ${VAR:-default} references in all scalar values under node (map keys are left as is);
+// "$${" can be used to write a literal "${". A value which consists of a single unquoted reference (e.g. `min_replicas: ${MIN_REPLICAS}`)
+// is resolved as if the variable's value had been written in its place, so it is an int, float, or bool if the value represents one
+// exactly (e.g. "5", but not "05" or "+5"). All other values, including quoted references (e.g. `PORT: "${PORT}"`), are strings
+func InterpolateYAMLNode(node *yaml.Node, lookup VariableLookupFn) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := InterpolateYAMLNode(child, lookup); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := InterpolateYAMLNode(child, lookup); err != nil {
+				return errors.Wrap(err, s.Index(i))
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := InterpolateYAMLNode(node.Content[i+1], lookup); err != nil {
+				return errors.Wrap(err, node.Content[i].Value)
+			}
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		interpolated, err := InterpolateStr(node.Value, lookup)
+		if err != nil {
+			return err
+		}
+		node.Tag = "!!str"
+		if node.Style == 0 && _singleReferenceRegex.MatchString(node.Value) {
+			node.Tag = resolvedScalarTag(interpolated)
+		}
+		if node.Tag == "!!str" {
+			// YAML 1.1 parsers read some unquoted strings as other types (e.g. yes and no as bools)
+			node.Style = yaml.DoubleQuotedStyle
+		}
+		node.Value = interpolated
+	}
+
+	return nil
+}
+
+// returns the tag of the int, float, or bool which str represents exactly, or the string tag otherwise
+func resolvedScalarTag(str string) string {
+	if val, err := strconv.ParseInt(str, 10, 64); err == nil && strconv.FormatInt(val, 10) == str {
+		return "!!int"
+	}
+	if val, err := strconv.ParseFloat(str, 64); err == nil && !math.IsNaN(val) && !math.IsInf(val, 0) && strconv.FormatFloat(val, 'f', -1, 64) == str {
+		return "!!float"
+	}
+	if str == "true" || str == "false" {
+		return "!!bool"
+	}
+	return "!!str"
+}
+
+// InterpolateStr replaces ${VAR} and ${VAR:-default} references in str
+func InterpolateStr(str string, lookup VariableLookupFn) (string, error) {
+	var builder strings.Builder
+
+	for i := 0; i < len(str); {
+		if strings.HasPrefix(str[i:], "$${") {
+			builder.WriteString("${")
+			i += 3
+			continue
+		}
+
+		if !strings.HasPrefix(str[i:], "${") {
+			builder.WriteByte(str[i])
+			i++
+			continue
+		}
+
+		end := strings.Index(str[i:], "}")
+		if end == -1 {
+			return "", ErrorInvalidVariableReference(str[i:])
+		}
+		reference := str[i : i+end+1]
+		expression := reference[2 : len(reference)-1]
+
+		name := expression
+		defaultVal := ""
+		hasDefault := false
+		if sepIndex := strings.Index(expression, ":-"); sepIndex != -1 {
+			name = expression[:sepIndex]
+			defaultVal = expression[sepIndex+2:]
+			hasDefault = true
+		}
+
+		if !_variableNameRegex.MatchString(name) {
+			return "", ErrorInvalidVariableReference(reference)
+		}
+
+		val, ok := lookup(name)
+		if hasDefault && (!ok || val == "") {
+			val = defaultVal
+		} else if !ok {
+			return "", ErrorUnresolvedVariable(name)
+		}
+
+		builder.WriteString(val)
+		i += end + 1
+	}
+
+	return builder.String(), nil
+}
+
+// LookupFnFromMaps returns a VariableLookupFn which checks each map in order, falling back to lookupFallback (if not nil)
+func LookupFnFromMaps(lookupFallback VariableLookupFn, varMaps ...map[string]string) VariableLookupFn {
+	return func(name string) (string, bool) {
+		for _, varMap := range varMaps {
+			if val, ok := varMap[name]; ok {
+				return val, true
+			}
+		}
+		if lookupFallback != nil {
+			return lookupFallback(name)
+		}
+		return "", false
+	}
+}
+
+// ReadVariablesFile reads a YAML file containing a map of variable names to scalar values
+func ReadVariablesFile(filePath string) (map[string]string, error) {
+	parsed, err := ReadYAMLFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if parsed == nil {
+		return map[string]string{}, nil
+	}
+
+	varMap, ok := cast.InterfaceToStrInterfaceMap(parsed)
+	if !ok {
+		return nil, errors.Wrap(ErrorInvalidPrimitiveType(parsed, PrimTypeMap), filePath)
+	}
+
+	variables := make(map[string]string, len(varMap))
+	for name, val := range varMap {
+		if !_variableNameRegex.MatchString(name) {
+			return nil, errors.Wrap(ErrorInvalidVariableName(name), filePath)
+		}
+		if val == nil {
+			variables[name] = ""
+			continue
+		}
+		switch val.(type) {
+		case string, bool, int, int64, uint64, float64:
+			variables[name] = s.ObjFlatNoQuotes(val)
+		default:
+			return nil, errors.Wrap(ErrorInvalidPrimitiveType(val, PrimTypeString, PrimTypeInt, PrimTypeFloat, PrimTypeBool), filePath, name)
+		}
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testLookupFn(vars map[string]string) VariableLookupFn {
+	return func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	}
+}
+
+func TestInterpolateStr(t *testing.T) {
+	lookup := testLookupFn(map[string]string{
+		"BUCKET": "my-bucket",
+		"ENV":    "prod",
+		"EMPTY":  "",
+	})
+
+	str, err := InterpolateStr("s3://${BUCKET}/models/${ENV}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "s3://my-bucket/models/prod", str)
+
+	str, err = InterpolateStr("${BUCKET}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "my-bucket", str)
+
+	str, err = InterpolateStr("${MISSING:-default-val}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "default-val", str)
+
+	str, err = InterpolateStr("${EMPTY:-default-val}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "default-val", str)
+
+	str, err = InterpolateStr("a${EMPTY}b", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "ab", str)
+
+	str, err = InterpolateStr("${MISSING:-}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "", str)
+
+	str, err = InterpolateStr("$${BUCKET}", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "${BUCKET}", str)
+
+	str, err = InterpolateStr("price: $5", lookup)
+	require.NoError(t, err)
+	require.Equal(t, "price: $5", str)
+
+	_, err = InterpolateStr("${MISSING}", lookup)
+	require.Equal(t, ErrUnresolvedVariable, errors.GetKind(err))
+
+	_, err = InterpolateStr("${BUCKET", lookup)
+	require.Equal(t, ErrInvalidVariableReference, errors.GetKind(err))
+
+	_, err = InterpolateStr("${1BUCKET}", lookup)
+	require.Equal(t, ErrInvalidVariableReference, errors.GetKind(err))
+}
+
+// interpolates the YAML document, and returns the result as read by configreader
+func interpolateYAMLStr(t *testing.T, yamlStr string, lookup VariableLookupFn) (interface{}, error) {
+	t.Helper()
+
+	var document yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &document))
+
+	if err := InterpolateYAMLNode(&document, lookup); err != nil {
+		return nil, err
+	}
+
+	interpolatedBytes, err := yaml.Marshal(&document)
+	require.NoError(t, err)
+	return ReadYAMLBytes(interpolatedBytes)
+}
+
+func TestInterpolateYAMLNode(t *testing.T) {
+	lookup := testLookupFn(map[string]string{
+		"BUCKET":        "my-bucket",
+		"MIN_REPLICAS":  "2",
+		"GPU":           "true",
+		"NAME":          "iris",
+		"MODEL_VERSION": "1.10",
+		"ID":            "0123",
+		"FLAG":          "yes",
+		"CONCURRENCY":   "0.5",
+		"PORT":          "8080",
+	})
+
+	interpolated, err := interpolateYAMLStr(t,
+		`
+    name: ${NAME}-classifier
+    predictor:
+      model_path: s3://${BUCKET}/model
+      config:
+        literal: $${NAME}
+        id: ${ID}
+        flag: ${FLAG}
+      env:
+        MODEL_VERSION: ${MODEL_VERSION}
+        USE_GPU: "${GPU}"
+        PORT: '${PORT}'
+        QUOTED_LITERAL: "5"
+    compute:
+      cpu: 1
+    autoscaling:
+      min_replicas: ${MIN_REPLICAS}
+      max_replicas: ${MAX_REPLICAS:-5}
+      target_replica_concurrency: ${CONCURRENCY}
+    paths:
+      - a
+      - "${NAME}"
+      - ${GPU}
+    `, lookup)
+	require.NoError(t, err)
+
+	// only unquoted values which consist of a single reference are converted, and only if they represent the type exactly
+	require.Equal(t, map[interface{}]interface{}{
+		"name": "iris-classifier",
+		"predictor": map[interface{}]interface{}{
+			"model_path": "s3://my-bucket/model",
+			"config": map[interface{}]interface{}{
+				"literal": "${NAME}",
+				"id":      "0123",
+				"flag":    "yes",
+			},
+			"env": map[interface{}]interface{}{
+				"MODEL_VERSION":  "1.10",
+				"USE_GPU":        "true",
+				"PORT":           "8080",
+				"QUOTED_LITERAL": "5",
+			},
+		},
+		"compute": map[interface{}]interface{}{
+			"cpu": int64(1),
+		},
+		"autoscaling": map[interface{}]interface{}{
+			"min_replicas":               int64(2),
+			"max_replicas":               int64(5),
+			"target_replica_concurrency": 0.5,
+		},
+		"paths": []interface{}{"a", "iris", true},
+	}, interpolated)
+
+	_, err = interpolateYAMLStr(t,
+		`
+    predictor:
+      models:
+        - name: a
+          model_path: s3://${MISSING_BUCKET}/a
+    `, lookup)
+	require.Equal(t, ErrUnresolvedVariable, errors.GetKind(err))
+	require.Contains(t, errors.Message(err), "predictor: models: index 0: model_path: variable ${MISSING_BUCKET} is not set")
+}
+
+func TestResolvedScalarTag(t *testing.T) {
+	for str, tag := range map[string]string{
+		"5":     "!!int",
+		"-5":    "!!int",
+		"0.5":   "!!float",
+		"true":  "!!bool",
+		"false": "!!bool",
+		"05":    "!!str",
+		"+5":    "!!str",
+		"1.10":  "!!str",
+		"1e3":   "!!str",
+		"NaN":   "!!str",
+		"yes":   "!!str",
+		"True":  "!!str",
+		"":      "!!str",
+	} {
+		require.Equal(t, tag, resolvedScalarTag(str), str)
+	}
+}
+
+func TestReadVariablesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configreader")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	valuesPath := filepath.Join(dir, "values.yaml")
+	err = ioutil.WriteFile(valuesPath, []byte("BUCKET: my-bucket\nREPLICAS: 3\nGPU: false\nEMPTY:\n"), 0644)
+	require.NoError(t, err)
+
+	values, err := ReadVariablesFile(valuesPath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"BUCKET":   "my-bucket",
+		"REPLICAS": "3",
+		"GPU":      "false",
+		"EMPTY":    "",
+	}, values)
+
+	lookup := LookupFnFromMaps(testLookupFn(map[string]string{"BUCKET": "env-bucket", "ENV": "prod"}), values)
+	val, ok := lookup("BUCKET")
+	require.True(t, ok)
+	require.Equal(t, "my-bucket", val)
+	val, ok = lookup("ENV")
+	require.True(t, ok)
+	require.Equal(t, "prod", val)
+	_, ok = lookup("MISSING")
+	require.False(t, ok)
+
+	err = ioutil.WriteFile(valuesPath, []byte("BUCKET:\n  nested: value\n"), 0644)
+	require.NoError(t, err)
+	_, err = ReadVariablesFile(valuesPath)
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+
+	err = ioutil.WriteFile(valuesPath, []byte("my-var: value\n"), 0644)
+	require.NoError(t, err)
+	_, err = ReadVariablesFile(valuesPath)
+	require.Equal(t, ErrInvalidVariableName, errors.GetKind(err))
+}
+
+func TestQuotedLiteralsAreNotConverted(t *testing.T) {
+	_, err := Int64("5", &Int64Validation{})
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+
+	_, err = Int32Ptr("5", &Int32PtrValidation{})
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+
+	_, err = Float64("0.5", &Float64Validation{})
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+
+	_, err = Bool("true", &BoolValidation{})
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+
+	_, err = BoolPtr("true", &BoolPtrValidation{})
+	require.Equal(t, ErrInvalidPrimitiveType, errors.GetKind(err))
+}
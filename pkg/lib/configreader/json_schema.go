@@ -0,0 +1,570 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+)
+
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+const (
+	_jsonTypeString  = "string"
+	_jsonTypeInteger = "integer"
+	_jsonTypeNumber  = "number"
+	_jsonTypeBoolean = "boolean"
+	_jsonTypeObject  = "object"
+	_jsonTypeArray   = "array"
+	_jsonTypeNull    = "null"
+)
+
+// StructJSONSchema returns a JSON Schema (draft-07) describing the values which are accepted by the struct validation
+// (dest must be a pointer to the struct type). Constraints which are implemented by Validator, Parser, or DefaultField
+// functions can't be represented, and are not included in the schema.
+func StructJSONSchema(dest interface{}, v *StructValidation) (map[string]interface{}, error) {
+	schema, err := structJSONSchema(reflect.TypeOf(dest), v)
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = JSONSchemaDraft
+	return schema, nil
+}
+
+func structJSONSchema(structType reflect.Type, v *StructValidation) (map[string]interface{}, error) {
+	return structFieldsJSONSchema(structType, v.StructFieldValidations, v.AllowExtraFields, v.AllowExplicitNull || v.TreatNullAsEmpty)
+}
+
+func structFieldsJSONSchema(structType reflect.Type, structFieldValidations []*StructFieldValidation, allowExtraFields bool, allowNull bool) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for _, structFieldValidation := range structFieldValidations {
+		key := jsonSchemaKey(structType, structFieldValidation)
+		fieldSchema, isRequired, err := structFieldJSONSchema(structType, structFieldValidation)
+		if err != nil {
+			return nil, errors.Wrap(err, key)
+		}
+		properties[key] = fieldSchema
+		if isRequired {
+			required = append(required, key)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       jsonSchemaType(allowNull, _jsonTypeObject),
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if !allowExtraFields {
+		schema["additionalProperties"] = false
+	}
+	return schema, nil
+}
+
+func jsonSchemaKey(structType reflect.Type, v *StructFieldValidation) string {
+	if structType == nil {
+		if v.Key != "" {
+			return v.Key
+		}
+		return v.StructField
+	}
+	return inferKey(structType, v.StructField, v.Key)
+}
+
+// returns a pointer to the struct type of the field (for fields which are structs, struct pointers, or lists of either)
+func nestedStructType(structType reflect.Type, structField string) reflect.Type {
+	if structType == nil {
+		return nil
+	}
+	field, ok := structType.Elem().FieldByName(structField)
+	if !ok {
+		return nil
+	}
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct {
+		return nil
+	}
+	return reflect.PtrTo(fieldType)
+}
+
+// returns the field's schema, and whether the field is required
+func structFieldJSONSchema(structType reflect.Type, v *StructFieldValidation) (map[string]interface{}, bool, error) {
+	switch {
+	case v.Nil:
+		return map[string]interface{}{}, false, nil
+
+	case v.StringValidation != nil:
+		sv := v.StringValidation
+		return withJSONSchemaDefault(stringJSONSchema(sv, sv.TreatNullAsEmpty), sv.Default), sv.Required, nil
+	case v.StringPtrValidation != nil:
+		sv := v.StringPtrValidation
+		return withJSONSchemaDefault(stringJSONSchema(makeStringValValidation(sv), sv.AllowExplicitNull), sv.Default), sv.Required, nil
+	case v.StringListValidation != nil:
+		sv := v.StringListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeString}, sv.AllowExplicitNull, sv.AllowEmpty, sv.MinLength, sv.MaxLength, sv.CastSingleItem, sv.DisallowDups)
+		return withJSONSchemaDefault(schema, sv.Default), sv.Required, nil
+
+	case v.BoolValidation != nil:
+		bv := v.BoolValidation
+		return withJSONSchemaDefault(boolJSONSchema(bv.TreatNullAsFalse, bv.StrToBool), bv.Default), bv.Required, nil
+	case v.BoolPtrValidation != nil:
+		bv := v.BoolPtrValidation
+		return withJSONSchemaDefault(boolJSONSchema(bv.AllowExplicitNull, bv.StrToBool), bv.Default), bv.Required, nil
+	case v.BoolListValidation != nil:
+		bv := v.BoolListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeBoolean}, bv.AllowExplicitNull, bv.AllowEmpty, bv.MinLength, bv.MaxLength, bv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, bv.Default), bv.Required, nil
+
+	case v.IntValidation != nil:
+		iv := v.IntValidation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.TreatNullAsZero, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.IntPtrValidation != nil:
+		iv := v.IntPtrValidation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.AllowExplicitNull, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.IntListValidation != nil:
+		iv := v.IntListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeInteger}, iv.AllowExplicitNull, iv.AllowEmpty, iv.MinLength, iv.MaxLength, iv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+
+	case v.Int32Validation != nil:
+		iv := v.Int32Validation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.TreatNullAsZero, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.Int32PtrValidation != nil:
+		iv := v.Int32PtrValidation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.AllowExplicitNull, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.Int32ListValidation != nil:
+		iv := v.Int32ListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeInteger}, iv.AllowExplicitNull, iv.AllowEmpty, iv.MinLength, iv.MaxLength, iv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+
+	case v.Int64Validation != nil:
+		iv := v.Int64Validation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.TreatNullAsZero, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.Int64PtrValidation != nil:
+		iv := v.Int64PtrValidation
+		schema := numericJSONSchema(_jsonTypeInteger, iv.AllowExplicitNull, iv.AllowedValues, iv.DisallowedValues, iv.GreaterThan, iv.GreaterThanOrEqualTo, iv.LessThan, iv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+	case v.Int64ListValidation != nil:
+		iv := v.Int64ListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeInteger}, iv.AllowExplicitNull, iv.AllowEmpty, iv.MinLength, iv.MaxLength, iv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, iv.Default), iv.Required, nil
+
+	case v.Float32Validation != nil:
+		fv := v.Float32Validation
+		schema := numericJSONSchema(_jsonTypeNumber, fv.TreatNullAsZero, fv.AllowedValues, fv.DisallowedValues, fv.GreaterThan, fv.GreaterThanOrEqualTo, fv.LessThan, fv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+	case v.Float32PtrValidation != nil:
+		fv := v.Float32PtrValidation
+		schema := numericJSONSchema(_jsonTypeNumber, fv.AllowExplicitNull, fv.AllowedValues, fv.DisallowedValues, fv.GreaterThan, fv.GreaterThanOrEqualTo, fv.LessThan, fv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+	case v.Float32ListValidation != nil:
+		fv := v.Float32ListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeNumber}, fv.AllowExplicitNull, fv.AllowEmpty, fv.MinLength, fv.MaxLength, fv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+
+	case v.Float64Validation != nil:
+		fv := v.Float64Validation
+		schema := numericJSONSchema(_jsonTypeNumber, fv.TreatNullAsZero, fv.AllowedValues, fv.DisallowedValues, fv.GreaterThan, fv.GreaterThanOrEqualTo, fv.LessThan, fv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+	case v.Float64PtrValidation != nil:
+		fv := v.Float64PtrValidation
+		schema := numericJSONSchema(_jsonTypeNumber, fv.AllowExplicitNull, fv.AllowedValues, fv.DisallowedValues, fv.GreaterThan, fv.GreaterThanOrEqualTo, fv.LessThan, fv.LessThanOrEqualTo)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+	case v.Float64ListValidation != nil:
+		fv := v.Float64ListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeNumber}, fv.AllowExplicitNull, fv.AllowEmpty, fv.MinLength, fv.MaxLength, fv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, fv.Default), fv.Required, nil
+
+	case v.StringMapValidation != nil:
+		mv := v.StringMapValidation
+		schema := map[string]interface{}{
+			"type":                 jsonSchemaType(mv.AllowExplicitNull || mv.ConvertNullToEmpty, _jsonTypeObject),
+			"additionalProperties": map[string]interface{}{"type": _jsonTypeString},
+		}
+		if mv.ValueStringValidator != nil {
+			schema["additionalProperties"] = stringJSONSchema(mv.ValueStringValidator, mv.ValueStringValidator.TreatNullAsEmpty)
+		}
+		if mv.KeyStringValidator != nil {
+			schema["propertyNames"] = stringJSONSchema(mv.KeyStringValidator, false)
+		}
+		if !mv.AllowEmpty {
+			schema["minProperties"] = 1
+		}
+		return withJSONSchemaDefault(schema, mv.Default), mv.Required, nil
+	case v.InterfaceMapValidation != nil:
+		mv := v.InterfaceMapValidation
+		schema := map[string]interface{}{
+			"type": jsonSchemaType(mv.AllowExplicitNull || mv.ConvertNullToEmpty, _jsonTypeObject),
+		}
+		if mv.ScalarsOnly {
+			schema["additionalProperties"] = map[string]interface{}{"type": []string{_jsonTypeString, _jsonTypeInteger, _jsonTypeNumber, _jsonTypeBoolean}}
+		}
+		if !mv.AllowEmpty {
+			schema["minProperties"] = 1
+		}
+		return withJSONSchemaDefault(schema, mv.Default), mv.Required, nil
+	case v.InterfaceMapListValidation != nil:
+		mv := v.InterfaceMapListValidation
+		schema := listJSONSchema(map[string]interface{}{"type": _jsonTypeObject}, mv.AllowExplicitNull, mv.AllowEmpty, mv.MinLength, mv.MaxLength, mv.CastSingleItem, false)
+		return withJSONSchemaDefault(schema, mv.Default), mv.Required, nil
+	case v.InterfaceValidation != nil:
+		iv := v.InterfaceValidation
+		return withJSONSchemaDefault(map[string]interface{}{}, iv.Default), iv.Required, nil
+
+	case v.StructValidation != nil:
+		schema, err := structJSONSchema(nestedStructType(structType, v.StructField), v.StructValidation)
+		if err != nil {
+			return nil, false, err
+		}
+		return schema, v.StructValidation.Required, nil
+	case v.StructListValidation != nil:
+		lv := v.StructListValidation
+		itemSchema, err := structJSONSchema(nestedStructType(structType, v.StructField), lv.StructValidation)
+		if err != nil {
+			return nil, false, err
+		}
+		return listJSONSchema(itemSchema, lv.AllowExplicitNull || lv.TreatNullAsEmpty, true, 0, 0, false, false), lv.Required, nil
+	case v.InterfaceStructValidation != nil:
+		iv := v.InterfaceStructValidation
+		schema, err := interfaceStructJSONSchema(iv)
+		if err != nil {
+			return nil, false, err
+		}
+		return schema, iv.Required, nil
+	case v.InterfaceStructListValidation != nil:
+		lv := v.InterfaceStructListValidation
+		itemSchema, err := interfaceStructJSONSchema(lv.InterfaceStructValidation)
+		if err != nil {
+			return nil, false, err
+		}
+		return listJSONSchema(itemSchema, lv.AllowExplicitNull || lv.TreatNullAsEmpty, true, 0, 0, false, false), lv.Required, nil
+	}
+
+	return nil, false, ErrorUnsupportedFieldValidation()
+}
+
+// JSONSchemaWithReferences returns a copy of the schema in which integer, number, and boolean values may also be a single
+// variable reference (e.g. "${MIN_REPLICAS}"), which InterpolateYAMLNode resolves to the type of the variable's value
+func JSONSchemaWithReferences(schema interface{}) interface{} {
+	switch typed := schema.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(typed))
+		for key, val := range typed {
+			copied[key] = JSONSchemaWithReferences(val)
+		}
+		if !isTypedScalarJSONSchema(copied) {
+			return copied
+		}
+
+		referenceSchema := map[string]interface{}{"type": _jsonTypeString, "pattern": SingleReferencePattern}
+		wrapped := map[string]interface{}{"anyOf": []interface{}{copied, referenceSchema}}
+		if defaultVal, ok := copied["default"]; ok {
+			delete(copied, "default")
+			wrapped["default"] = defaultVal
+		}
+		return wrapped
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for i, val := range typed {
+			copied[i] = JSONSchemaWithReferences(val)
+		}
+		return copied
+	}
+	return schema
+}
+
+// returns true if the schema accepts integer, number, or boolean values
+func isTypedScalarJSONSchema(schema map[string]interface{}) bool {
+	var jsonTypes []string
+	switch typed := schema["type"].(type) {
+	case string:
+		jsonTypes = []string{typed}
+	case []string:
+		jsonTypes = typed
+	}
+
+	for _, jsonType := range jsonTypes {
+		if jsonType == _jsonTypeInteger || jsonType == _jsonTypeNumber || jsonType == _jsonTypeBoolean {
+			return true
+		}
+	}
+	return false
+}
+
+func interfaceStructJSONSchema(v *InterfaceStructValidation) (map[string]interface{}, error) {
+	structTypes := map[string]*InterfaceStructType{}
+	for typeName, structType := range v.InterfaceStructTypes {
+		structTypes[typeName] = structType
+	}
+	for typeVal, structType := range v.ParsedInterfaceStructTypes {
+		structTypes[fmt.Sprint(typeVal)] = structType
+	}
+
+	typeNames := make([]string, 0, len(structTypes))
+	for typeName := range structTypes {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	oneOf := make([]interface{}, 0, len(typeNames)+1)
+	for _, typeName := range typeNames {
+		structType := structTypes[typeName]
+		schema, err := structFieldsJSONSchema(reflect.TypeOf(structType.Type), structType.StructFieldValidations, v.AllowExtraFields, false)
+		if err != nil {
+			return nil, errors.Wrap(err, typeName)
+		}
+		schema["properties"].(map[string]interface{})[v.TypeKey] = map[string]interface{}{"const": typeName}
+		required, _ := schema["required"].([]string)
+		schema["required"] = append([]string{v.TypeKey}, required...)
+		oneOf = append(oneOf, schema)
+	}
+
+	if v.AllowExplicitNull || v.TreatNullAsEmpty {
+		oneOf = append(oneOf, map[string]interface{}{"type": _jsonTypeNull})
+	}
+
+	return map[string]interface{}{"oneOf": oneOf}, nil
+}
+
+func stringJSONSchema(v *StringValidation, allowNull bool) map[string]interface{} {
+	types := []string{_jsonTypeString}
+	if v.CastScalar {
+		types = append(types, _jsonTypeInteger, _jsonTypeNumber, _jsonTypeBoolean)
+	} else if v.CastNumeric {
+		types = append(types, _jsonTypeInteger, _jsonTypeNumber)
+	} else if v.CastInt {
+		types = append(types, _jsonTypeInteger)
+	}
+
+	schema := map[string]interface{}{
+		"type": jsonSchemaType(allowNull, types...),
+	}
+
+	if len(v.AllowedValues) > 0 {
+		schema["enum"] = jsonSchemaEnum(v.AllowedValues, allowNull)
+	}
+
+	minLength := v.MinLength
+	if !v.AllowEmpty && minLength < 1 {
+		minLength = 1
+	}
+	if minLength > 0 {
+		schema["minLength"] = minLength
+	}
+	if v.MaxLength > 0 {
+		schema["maxLength"] = v.MaxLength
+	}
+
+	var patterns []string
+	if v.Prefix != "" {
+		patterns = append(patterns, "^"+regexp.QuoteMeta(v.Prefix))
+	}
+	if v.AlphaNumericDashDotUnderscore {
+		patterns = append(patterns, regex.AlphaNumericDashDotUnderscorePattern)
+	}
+	if v.AlphaNumericDashDotUnderscoreOrEmpty {
+		patterns = append(patterns, "^$|"+regex.AlphaNumericDashDotUnderscorePattern)
+	}
+	if v.AlphaNumericDashUnderscore {
+		patterns = append(patterns, regex.AlphaNumericDashUnderscorePattern)
+	}
+	if v.AWSTag {
+		patterns = append(patterns, "^$|"+regex.AWSTagPattern)
+	}
+	if v.DNS1035 {
+		patterns = append(patterns, urls.DNS1035Pattern)
+	}
+	if v.DNS1123 {
+		patterns = append(patterns, urls.DNS1123Pattern)
+	}
+
+	var disallowedPatterns []string
+	for _, invalidPrefix := range v.InvalidPrefixes {
+		disallowedPatterns = append(disallowedPatterns, "^"+regexp.QuoteMeta(invalidPrefix))
+	}
+	if v.DisallowLeadingWhitespace {
+		disallowedPatterns = append(disallowedPatterns, `^\s`)
+	}
+	if v.DisallowTrailingWhitespace {
+		disallowedPatterns = append(disallowedPatterns, `\s$`)
+	}
+
+	var allOf []interface{}
+	if len(patterns) == 1 {
+		schema["pattern"] = patterns[0]
+	} else {
+		for _, pattern := range patterns {
+			allOf = append(allOf, map[string]interface{}{"pattern": pattern})
+		}
+	}
+	for _, pattern := range disallowedPatterns {
+		// the type is included so that non-string values (e.g. when casting numbers) are not rejected
+		allOf = append(allOf, map[string]interface{}{"not": map[string]interface{}{"type": _jsonTypeString, "pattern": pattern}})
+	}
+	if len(v.DisallowedValues) > 0 {
+		allOf = append(allOf, map[string]interface{}{"not": map[string]interface{}{"enum": v.DisallowedValues}})
+	}
+	if len(allOf) > 0 {
+		schema["allOf"] = allOf
+	}
+
+	return schema
+}
+
+func boolJSONSchema(allowNull bool, strToBool map[string]bool) map[string]interface{} {
+	if len(strToBool) == 0 {
+		return map[string]interface{}{"type": jsonSchemaType(allowNull, _jsonTypeBoolean)}
+	}
+
+	strs := make([]string, 0, len(strToBool))
+	for str := range strToBool {
+		strs = append(strs, str)
+	}
+	sort.Strings(strs)
+
+	enum := []interface{}{true, false}
+	for _, str := range strs {
+		enum = append(enum, str)
+	}
+	if allowNull {
+		enum = append(enum, nil)
+	}
+
+	return map[string]interface{}{
+		"type": jsonSchemaType(allowNull, _jsonTypeBoolean, _jsonTypeString),
+		"enum": enum,
+	}
+}
+
+// allowedValues and disallowedValues must be slices, and the bounds must be pointers
+func numericJSONSchema(jsonType string, allowNull bool, allowedValues interface{}, disallowedValues interface{}, greaterThan interface{}, greaterThanOrEqualTo interface{}, lessThan interface{}, lessThanOrEqualTo interface{}) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type": jsonSchemaType(allowNull, jsonType),
+	}
+
+	if reflect.ValueOf(allowedValues).Len() > 0 {
+		schema["enum"] = jsonSchemaEnum(allowedValues, allowNull)
+	}
+	if reflect.ValueOf(disallowedValues).Len() > 0 {
+		schema["not"] = map[string]interface{}{"enum": disallowedValues}
+	}
+
+	if val, ok := indirectJSONSchemaValue(greaterThan); ok {
+		schema["exclusiveMinimum"] = val
+	}
+	if val, ok := indirectJSONSchemaValue(greaterThanOrEqualTo); ok {
+		schema["minimum"] = val
+	}
+	if val, ok := indirectJSONSchemaValue(lessThan); ok {
+		schema["exclusiveMaximum"] = val
+	}
+	if val, ok := indirectJSONSchemaValue(lessThanOrEqualTo); ok {
+		schema["maximum"] = val
+	}
+
+	return schema
+}
+
+func listJSONSchema(itemSchema map[string]interface{}, allowNull bool, allowEmpty bool, minLength int, maxLength int, castSingleItem bool, disallowDups bool) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":  jsonSchemaType(allowNull, _jsonTypeArray),
+		"items": itemSchema,
+	}
+
+	if !allowEmpty && minLength < 1 {
+		minLength = 1
+	}
+	if minLength > 0 {
+		schema["minItems"] = minLength
+	}
+	if maxLength > 0 {
+		schema["maxItems"] = maxLength
+	}
+	if disallowDups {
+		schema["uniqueItems"] = true
+	}
+
+	if castSingleItem {
+		return map[string]interface{}{"anyOf": []interface{}{schema, itemSchema}}
+	}
+	return schema
+}
+
+func jsonSchemaType(allowNull bool, jsonTypes ...string) interface{} {
+	if allowNull {
+		jsonTypes = append(jsonTypes, _jsonTypeNull)
+	}
+	if len(jsonTypes) == 1 {
+		return jsonTypes[0]
+	}
+	return jsonTypes
+}
+
+// values must be a slice
+func jsonSchemaEnum(values interface{}, allowNull bool) []interface{} {
+	valuesVal := reflect.ValueOf(values)
+	enum := make([]interface{}, 0, valuesVal.Len()+1)
+	for i := 0; i < valuesVal.Len(); i++ {
+		enum = append(enum, valuesVal.Index(i).Interface())
+	}
+	if allowNull {
+		enum = append(enum, nil)
+	}
+	return enum
+}
+
+// sets the default if it is not nil or the zero value of its type
+func withJSONSchemaDefault(schema map[string]interface{}, defaultVal interface{}) map[string]interface{} {
+	if val, ok := indirectJSONSchemaValue(defaultVal); ok && !reflect.ValueOf(val).IsZero() {
+		schema["default"] = val
+	}
+	return schema
+}
+
+func indirectJSONSchemaValue(val interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		return nil, false
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			return nil, false
+		}
+	}
+	if rv.Kind() == reflect.Ptr {
+		return rv.Elem().Interface(), true
+	}
+	return val, true
+}
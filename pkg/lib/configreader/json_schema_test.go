@@ -0,0 +1,436 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonSchemaTestConfig struct {
+	Name     string                 `json:"name"`
+	Replicas *int64                 `json:"replicas"`
+	Ratio    float64                `json:"ratio"`
+	Tags     []string               `json:"tags"`
+	Env      map[string]string      `json:"env"`
+	Compute  *jsonSchemaTestCompute `json:"compute"`
+	Models   []*jsonSchemaTestModel `json:"models"`
+	Config   map[string]interface{} `json:"config"`
+	Other    interface{}            `json:"other"`
+	Verbose  bool                   `json:"verbose"`
+	Untagged string                 // key is inferred from the struct field name
+}
+
+type jsonSchemaTestCompute struct {
+	CPU string `json:"cpu"`
+	GPU int64  `json:"gpu"`
+}
+
+type jsonSchemaTestModel struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+func TestStructJSONSchema(t *testing.T) {
+	structValidation := &StructValidation{
+		StructFieldValidations: []*StructFieldValidation{
+			{
+				StructField: "Name",
+				StringValidation: &StringValidation{
+					Required:  true,
+					DNS1035:   true,
+					MaxLength: 42,
+				},
+			},
+			{
+				StructField: "Replicas",
+				Int64PtrValidation: &Int64PtrValidation{
+					Default:              pointer.Int64(1),
+					GreaterThanOrEqualTo: pointer.Int64(0),
+					LessThan:             pointer.Int64(100),
+				},
+			},
+			{
+				StructField: "Ratio",
+				Float64Validation: &Float64Validation{
+					Default:     0.5,
+					GreaterThan: pointer.Float64(0),
+				},
+			},
+			{
+				StructField: "Tags",
+				StringListValidation: &StringListValidation{
+					AllowEmpty:   true,
+					DisallowDups: true,
+					MaxLength:    3,
+				},
+			},
+			{
+				StructField: "Env",
+				StringMapValidation: &StringMapValidation{
+					AllowEmpty:         true,
+					ConvertNullToEmpty: true,
+				},
+			},
+			{
+				StructField: "Compute",
+				StructValidation: &StructValidation{
+					DefaultNil: true,
+					StructFieldValidations: []*StructFieldValidation{
+						{
+							StructField: "CPU",
+							StringValidation: &StringValidation{
+								Default:       "200m",
+								CastNumeric:   true,
+								AllowedValues: []string{"200m", "1"},
+							},
+						},
+						{
+							StructField: "GPU",
+							Int64Validation: &Int64Validation{
+								GreaterThanOrEqualTo: pointer.Int64(0),
+							},
+						},
+					},
+				},
+			},
+			{
+				StructField: "Models",
+				StructListValidation: &StructListValidation{
+					AllowExplicitNull: true,
+					StructValidation: &StructValidation{
+						StructFieldValidations: []*StructFieldValidation{
+							{
+								StructField: "Name",
+								StringValidation: &StringValidation{
+									Required:         true,
+									DisallowedValues: []string{"default"},
+								},
+							},
+							{
+								StructField: "Path",
+								StringValidation: &StringValidation{
+									Required:        true,
+									Prefix:          "s3://",
+									InvalidPrefixes: []string{"s3://private"},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				StructField: "Config",
+				InterfaceMapValidation: &InterfaceMapValidation{
+					AllowEmpty:  true,
+					ScalarsOnly: true,
+				},
+			},
+			{
+				StructField:         "Other",
+				InterfaceValidation: &InterfaceValidation{},
+			},
+			{
+				StructField: "Verbose",
+				BoolValidation: &BoolValidation{
+					Default: true,
+				},
+			},
+			{
+				StructField: "Untagged",
+				StringValidation: &StringValidation{
+					AllowEmpty: true,
+				},
+			},
+			{
+				Key: "renamed",
+				Nil: true,
+			},
+		},
+	}
+
+	schema, err := StructJSONSchema(&jsonSchemaTestConfig{}, structValidation)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"$schema":              JSONSchemaDraft,
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"name"},
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":      "string",
+				"minLength": 1,
+				"maxLength": 42,
+				"pattern":   urls.DNS1035Pattern,
+			},
+			"replicas": map[string]interface{}{
+				"type":             "integer",
+				"default":          int64(1),
+				"minimum":          int64(0),
+				"exclusiveMaximum": int64(100),
+			},
+			"ratio": map[string]interface{}{
+				"type":             "number",
+				"default":          0.5,
+				"exclusiveMinimum": float64(0),
+			},
+			"tags": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"maxItems":    3,
+				"uniqueItems": true,
+			},
+			"env": map[string]interface{}{
+				"type":                 []string{"object", "null"},
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"compute": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"cpu": map[string]interface{}{
+						"type":      []string{"string", "integer", "number"},
+						"default":   "200m",
+						"enum":      []interface{}{"200m", "1"},
+						"minLength": 1,
+					},
+					"gpu": map[string]interface{}{
+						"type":    "integer",
+						"minimum": int64(0),
+					},
+				},
+			},
+			"models": map[string]interface{}{
+				"type": []string{"array", "null"},
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"name", "path"},
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":      "string",
+							"minLength": 1,
+							"allOf": []interface{}{
+								map[string]interface{}{"not": map[string]interface{}{"enum": []string{"default"}}},
+							},
+						},
+						"path": map[string]interface{}{
+							"type":      "string",
+							"minLength": 1,
+							"pattern":   `^s3://`,
+							"allOf": []interface{}{
+								map[string]interface{}{"not": map[string]interface{}{"type": "string", "pattern": `^s3://private`}},
+							},
+						},
+					},
+				},
+			},
+			"config": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": []string{"string", "integer", "number", "boolean"}},
+			},
+			"other": map[string]interface{}{},
+			"verbose": map[string]interface{}{
+				"type":    "boolean",
+				"default": true,
+			},
+			"Untagged": map[string]interface{}{
+				"type": "string",
+			},
+			"renamed": map[string]interface{}{},
+		},
+	}
+
+	require.Equal(t, expected, schema)
+}
+
+func TestStructJSONSchemaKeysMatchStructKeys(t *testing.T) {
+	structValidation := &StructValidation{
+		StructFieldValidations: []*StructFieldValidation{
+			{StructField: "Name", StringValidation: &StringValidation{}},
+			{StructField: "Untagged", StringValidation: &StringValidation{}},
+			{Key: "renamed", StructField: "Other", InterfaceValidation: &InterfaceValidation{}},
+		},
+	}
+
+	schema, err := StructJSONSchema(&jsonSchemaTestConfig{}, structValidation)
+	require.NoError(t, err)
+
+	schemaKeys := []string{}
+	for key := range schema["properties"].(map[string]interface{}) {
+		schemaKeys = append(schemaKeys, key)
+	}
+	require.ElementsMatch(t, StructKeys(&jsonSchemaTestConfig{}, structValidation), schemaKeys)
+}
+
+// Ensures that every type of field validation is supported by the JSON Schema generator
+func TestStructJSONSchemaSupportsAllFieldValidations(t *testing.T) {
+	structFieldValidationType := reflect.TypeOf(StructFieldValidation{})
+
+	numValidationTypes := 0
+	for i := 0; i < structFieldValidationType.NumField(); i++ {
+		field := structFieldValidationType.Field(i)
+		if !strings.HasSuffix(field.Name, "Validation") || field.Type.Kind() != reflect.Ptr {
+			continue
+		}
+		numValidationTypes++
+
+		validation := reflect.New(field.Type.Elem())
+		fillNestedJSONSchemaTestValidations(validation)
+
+		structFieldValidation := &StructFieldValidation{StructField: "Other"}
+		reflect.ValueOf(structFieldValidation).Elem().Field(i).Set(validation)
+
+		_, err := StructJSONSchema(&jsonSchemaTestConfig{}, &StructValidation{
+			StructFieldValidations: []*StructFieldValidation{structFieldValidation},
+		})
+		require.NoError(t, err, field.Name)
+	}
+
+	require.Greater(t, numValidationTypes, 0)
+
+	_, err := StructJSONSchema(&jsonSchemaTestConfig{}, &StructValidation{
+		StructFieldValidations: []*StructFieldValidation{{StructField: "Other"}},
+	})
+	require.Error(t, err)
+}
+
+// sets nil nested struct validations (e.g. StructListValidation.StructValidation) to empty validations
+func fillNestedJSONSchemaTestValidations(validation reflect.Value) {
+	elem := validation.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+		if field.Kind() != reflect.Ptr || !field.IsNil() {
+			continue
+		}
+		switch field.Type() {
+		case reflect.TypeOf(&StructValidation{}), reflect.TypeOf(&InterfaceStructValidation{}):
+			nested := reflect.New(field.Type().Elem())
+			fillNestedJSONSchemaTestValidations(nested)
+			field.Set(nested)
+		}
+	}
+}
+
+func TestInterfaceStructJSONSchema(t *testing.T) {
+	structValidation := &StructValidation{
+		StructFieldValidations: []*StructFieldValidation{
+			{
+				StructField: "Other",
+				InterfaceStructValidation: &InterfaceStructValidation{
+					TypeKey: "type",
+					InterfaceStructTypes: map[string]*InterfaceStructType{
+						"compute": {
+							Type: (*jsonSchemaTestCompute)(nil),
+							StructFieldValidations: []*StructFieldValidation{
+								{StructField: "CPU", StringValidation: &StringValidation{Required: true}},
+							},
+						},
+						"model": {
+							Type: (*jsonSchemaTestModel)(nil),
+							StructFieldValidations: []*StructFieldValidation{
+								{StructField: "Path", StringValidation: &StringValidation{AllowEmpty: true}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	schema, err := StructJSONSchema(&jsonSchemaTestConfig{}, structValidation)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"type", "cpu"},
+				"properties": map[string]interface{}{
+					"type": map[string]interface{}{"const": "compute"},
+					"cpu":  map[string]interface{}{"type": "string", "minLength": 1},
+				},
+			},
+			map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"type"},
+				"properties": map[string]interface{}{
+					"type": map[string]interface{}{"const": "model"},
+					"path": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}, schema["properties"].(map[string]interface{})["other"])
+}
+
+func TestJSONSchemaWithReferences(t *testing.T) {
+	referenceSchema := map[string]interface{}{"type": "string", "pattern": SingleReferencePattern}
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":     map[string]interface{}{"type": "string"},
+			"replicas": map[string]interface{}{"type": "integer", "default": int64(1), "minimum": int64(0)},
+			"ratio":    map[string]interface{}{"type": []string{"number", "null"}},
+			"verbose":  map[string]interface{}{"type": "boolean"},
+			"gpus": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "integer"},
+			},
+			"type": map[string]interface{}{"const": "compute"},
+		},
+		"required": []string{"name"},
+	}
+
+	expected := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"replicas": map[string]interface{}{
+				"anyOf":   []interface{}{map[string]interface{}{"type": "integer", "minimum": int64(0)}, referenceSchema},
+				"default": int64(1),
+			},
+			"ratio": map[string]interface{}{
+				"anyOf": []interface{}{map[string]interface{}{"type": []string{"number", "null"}}, referenceSchema},
+			},
+			"verbose": map[string]interface{}{
+				"anyOf": []interface{}{map[string]interface{}{"type": "boolean"}, referenceSchema},
+			},
+			"gpus": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"anyOf": []interface{}{map[string]interface{}{"type": "integer"}, referenceSchema},
+				},
+			},
+			"type": map[string]interface{}{"const": "compute"},
+		},
+		"required": []string{"name"},
+	}
+
+	require.Equal(t, expected, JSONSchemaWithReferences(schema))
+
+	// the original schema is not modified
+	require.Equal(t, map[string]interface{}{"type": "integer", "default": int64(1), "minimum": int64(0)}, schema["properties"].(map[string]interface{})["replicas"])
+}
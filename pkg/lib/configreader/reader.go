@@ -361,7 +361,7 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 	if !v.AllowExtraFields {
 		extraFields := slices.SubtractStrSlice(maps.InterfaceMapKeys(interMap), allowedFields)
 		for _, extraField := range extraFields {
-			allErrs = append(allErrs, ErrorUnsupportedKey(extraField))
+			allErrs = append(allErrs, ErrorUnsupportedKey(extraField, allowedFields...))
 		}
 	}
 	if errors.HasError(allErrs) {
@@ -941,7 +941,7 @@ func StructFromStringMap(dest interface{}, strMap map[string]string, v *StructVa
 	if !v.AllowExtraFields {
 		extraFields := slices.SubtractStrSlice(maps.StrMapKeys(strMap), allowedFields)
 		for _, extraField := range extraFields {
-			allErrs = append(allErrs, ErrorUnsupportedKey(extraField))
+			allErrs = append(allErrs, ErrorUnsupportedKey(extraField, allowedFields...))
 		}
 	}
 	if errors.HasError(allErrs) {
@@ -987,13 +987,19 @@ func ReadEnvVar(envVarName string) *string {
 //
 
 func ParseYAMLFile(dest interface{}, validation *StructValidation, filePath string) []error {
-	fileInterface, err := ReadYAMLFile(filePath)
+	fileBytes, err := files.ReadFileBytes(filePath)
 	if err != nil {
 		return []error{err}
 	}
 
+	fileInterface, err := ReadYAMLBytes(fileBytes)
+	if err != nil {
+		return []error{errors.Wrap(err, filePath)}
+	}
+
 	errs := Struct(dest, fileInterface, validation)
 	if errors.HasError(errs) {
+		errs = NewYAMLSource(fileBytes).AddLineNumbers(errs, nil)
 		return errors.WrapAll(errs, filePath)
 	}
 
@@ -1144,6 +1150,15 @@ func structHasKey(val interface{}, fieldName string) bool {
 	return false
 }
 
+// StructKeys returns the keys which are allowed by the struct validation (dest must be a pointer to the struct type)
+func StructKeys(dest interface{}, v *StructValidation) []string {
+	keys := make([]string, 0, len(v.StructFieldValidations))
+	for _, structFieldValidation := range v.StructFieldValidations {
+		keys = append(keys, inferKey(reflect.TypeOf(dest), structFieldValidation.StructField, structFieldValidation.Key))
+	}
+	return keys
+}
+
 func inferKey(structType reflect.Type, typeStructField string, typeKey string) string {
 	if typeKey != "" {
 		return typeKey
@@ -0,0 +1,229 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"gopkg.in/yaml.v3"
+)
+
+// SourceLine is the position of a key in one of the user's files
+type SourceLine struct {
+	File string `json:"file,omitempty"` // empty if the key is in the file which is being read
+	Line int    `json:"line"`
+}
+
+func (sourceLine SourceLine) String() string {
+	if sourceLine.File == "" {
+		return fmt.Sprintf("line %d", sourceLine.Line)
+	}
+	return fmt.Sprintf("%s, line %d", sourceLine.File, sourceLine.Line)
+}
+
+// YAMLSource records the position of each key in a YAML document, so that errors can refer to line numbers
+type YAMLSource struct {
+	root        *yaml.Node
+	sourceLines map[int]SourceLine // set if the document was generated from the user's files (see NewGeneratedYAMLSource)
+}
+
+// NewYAMLSource returns nil if the document cannot be parsed (or is empty)
+func NewYAMLSource(yamlBytes []byte) *YAMLSource {
+	var document yaml.Node
+	if err := yaml.Unmarshal(yamlBytes, &document); err != nil {
+		return nil
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	return &YAMLSource{root: document.Content[0]}
+}
+
+// NewGeneratedYAMLSource is like NewYAMLSource, for documents which were generated from the user's files (e.g. by interpolating variables);
+// sourceLines maps the line of each key in the generated document to its position in the user's files (see SourceLines)
+func NewGeneratedYAMLSource(yamlBytes []byte, sourceLines map[int]SourceLine) *YAMLSource {
+	source := NewYAMLSource(yamlBytes)
+	if source != nil {
+		source.sourceLines = sourceLines
+		if source.sourceLines == nil {
+			source.sourceLines = map[int]SourceLine{}
+		}
+	}
+	return source
+}
+
+// SourceLines returns the position in the user's files of each key in the generated document yamlBytes;
+// keyLine returns the position of the key at keyPath in the user's files (or false if the key was not read from the user's files)
+func SourceLines(yamlBytes []byte, keyLine func(keyPath []string) (SourceLine, bool)) map[int]SourceLine {
+	generated := NewYAMLSource(yamlBytes)
+	if generated == nil {
+		return nil
+	}
+
+	sourceLines := map[int]SourceLine{}
+
+	var addSourceLines func(node *yaml.Node, keyPath []string)
+	addSourceLines = func(node *yaml.Node, keyPath []string) {
+		node = resolveAlias(node)
+
+		var keyNodes, valNodes []*yaml.Node
+		var keys []string
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keyNodes = append(keyNodes, node.Content[i])
+				valNodes = append(valNodes, node.Content[i+1])
+				keys = append(keys, node.Content[i].Value)
+			}
+		case yaml.SequenceNode:
+			for i, itemNode := range node.Content {
+				keyNodes = append(keyNodes, itemNode)
+				valNodes = append(valNodes, itemNode)
+				keys = append(keys, s.Index(i))
+			}
+		}
+
+		for i, key := range keys {
+			childKeyPath := concatKeyPaths(keyPath, []string{key})
+			if sourceLine, ok := keyLine(childKeyPath); ok {
+				sourceLines[keyNodes[i].Line] = sourceLine
+			}
+			addSourceLines(valNodes[i], childKeyPath)
+		}
+	}
+	addSourceLines(generated.root, nil)
+
+	return sourceLines
+}
+
+// KeyLine returns the position (the line number starting at 1) of the key at keyPath; list items are identified by
+// their index (e.g. "index 2", as added by configreader when wrapping errors)
+func (source *YAMLSource) KeyLine(keyPath ...string) (SourceLine, bool) {
+	line, depth := source.closestKeyLine(keyPath)
+	if depth != len(keyPath) {
+		return SourceLine{}, false
+	}
+	return source.sourceLine(line)
+}
+
+// sourceLine returns the position in the user's files of the given line of the document
+func (source *YAMLSource) sourceLine(line int) (SourceLine, bool) {
+	if source.sourceLines == nil {
+		return SourceLine{Line: line}, true
+	}
+	sourceLine, ok := source.sourceLines[line]
+	return sourceLine, ok
+}
+
+// closestKeyLine returns the line number of the deepest key in keyPath which exists in the document, and its depth
+func (source *YAMLSource) closestKeyLine(keyPath []string) (int, int) {
+	if source == nil {
+		return 0, -1
+	}
+
+	node := resolveAlias(source.root)
+	line := node.Line
+	for depth, key := range keyPath {
+		var keyNode, valNode *yaml.Node
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					keyNode, valNode = node.Content[i], node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(strings.TrimPrefix(key, "index "))
+			if err == nil && index >= 0 && index < len(node.Content) {
+				keyNode, valNode = node.Content[index], node.Content[index]
+			}
+		}
+
+		if keyNode == nil {
+			return line, depth
+		}
+		line = keyNode.Line
+		node = resolveAlias(valNode)
+	}
+
+	return line, len(keyPath)
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// AddLineNumbers appends the line number of the offending key to each error which was returned from validating the value at keyPath;
+// fallbackKeyPaths are searched (in order) when the key does not appear under keyPath (e.g. if its value was inherited from elsewhere in the document).
+// If the key is not in the document at all (e.g. a missing required field), the line of its closest ancestor is used
+func (source *YAMLSource) AddLineNumbers(errs []error, keyPath []string, fallbackKeyPaths ...[]string) []error {
+	if source == nil {
+		return errs
+	}
+
+	basePaths := append([][]string{keyPath}, fallbackKeyPaths...)
+
+	for i, err := range errs {
+		if err == nil || len(errors.List(err)) != 1 {
+			continue
+		}
+
+		errKeyPath := errors.GetPath(err)
+
+		sourceLine, ok := SourceLine{}, false
+		for _, basePath := range basePaths {
+			if sourceLine, ok = source.KeyLine(concatKeyPaths(basePath, errKeyPath)...); ok {
+				break
+			}
+		}
+
+		if !ok {
+			// use the deepest ancestor which was found; the value at keyPath itself is only used if no deeper ancestor was found under any of the paths
+			maxRelativeDepth := -1
+			for j, basePath := range basePaths {
+				ancestorLine, depth := source.closestKeyLine(concatKeyPaths(basePath, errKeyPath))
+				relativeDepth := depth - len(basePath)
+				if depth <= 0 || relativeDepth < 0 || (j > 0 && relativeDepth == 0) || relativeDepth <= maxRelativeDepth {
+					continue
+				}
+				if ancestorSourceLine, found := source.sourceLine(ancestorLine); found {
+					sourceLine, ok = ancestorSourceLine, true
+					maxRelativeDepth = relativeDepth
+				}
+			}
+		}
+
+		if ok {
+			errs[i] = errors.Append(err, fmt.Sprintf(" (%s)", sourceLine))
+		}
+	}
+
+	return errs
+}
+
+func concatKeyPaths(keyPath1 []string, keyPath2 []string) []string {
+	return append(append([]string{}, keyPath1...), keyPath2...)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type suggestionConfig struct {
+	Name      string           `json:"name"`
+	Predictor *suggestionInner `json:"predictor"`
+}
+
+type suggestionInner struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+var _suggestionValidation = &StructValidation{
+	StructFieldValidations: []*StructFieldValidation{
+		{
+			StructField: "Name",
+			StringValidation: &StringValidation{
+				Required: true,
+			},
+		},
+		{
+			StructField: "Predictor",
+			StructValidation: &StructValidation{
+				Required: true,
+				StructFieldValidations: []*StructFieldValidation{
+					{
+						StructField: "Type",
+						StringValidation: &StringValidation{
+							Required:      true,
+							AllowedValues: []string{"python", "tensorflow", "onnx"},
+						},
+					},
+					{
+						StructField: "Path",
+						StringValidation: &StringValidation{
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestSuggestions(t *testing.T) {
+	configData := MustReadYAMLStr(
+		`
+    name: test
+    predictor:
+      type: pyton
+      path: predictor.py
+      pth: predictor.py
+    `)
+
+	errs := Struct(&suggestionConfig{}, configData, _suggestionValidation)
+	require.Len(t, errs, 2)
+	require.Equal(t, `predictor: type: invalid value (got "pyton", must be "python", "tensorflow", or "onnx"); did you mean "python"?`, errs[0].Error())
+	require.Equal(t, `predictor: key "pth" is not supported (did you mean "path"?)`, errs[1].Error())
+
+	configData = MustReadYAMLStr(
+		`
+    name: test
+    predictor:
+      type: pytorch
+      path: predictor.py
+      something: else
+    `)
+
+	errs = Struct(&suggestionConfig{}, configData, _suggestionValidation)
+	require.Len(t, errs, 2)
+	require.Equal(t, `predictor: type: invalid value (got "pytorch", must be "python", "tensorflow", or "onnx")`, errs[0].Error())
+	require.Equal(t, `predictor: key "something" is not supported`, errs[1].Error())
+}
+
+func TestAddLineNumbers(t *testing.T) {
+	yamlStr := `
+name: test
+predictor:
+  type: pyton
+  path: predictor.py
+  pth: predictor.py
+`
+
+	errs := Struct(&suggestionConfig{}, MustReadYAMLStr(yamlStr), _suggestionValidation)
+	errs = NewYAMLSource([]byte(yamlStr)).AddLineNumbers(errs, nil)
+	require.Len(t, errs, 2)
+	require.Equal(t, `predictor: type: invalid value (got "pyton", must be "python", "tensorflow", or "onnx"); did you mean "python"? (line 4)`, errs[0].Error())
+	require.Equal(t, `predictor: key "pth" is not supported (did you mean "path"?) (line 6)`, errs[1].Error())
+
+	// missing keys refer to the closest ancestor
+	yamlStr = `
+name: test
+predictor:
+  type: python
+`
+
+	errs = Struct(&suggestionConfig{}, MustReadYAMLStr(yamlStr), _suggestionValidation)
+	errs = NewYAMLSource([]byte(yamlStr)).AddLineNumbers(errs, nil)
+	require.Len(t, errs, 1)
+	require.Equal(t, `predictor: path: must be defined (line 3)`, errs[0].Error())
+
+	// list items, and keys which are found in a fallback path
+	yamlStr = `
+defaults:
+  predictor:
+    type: pyton
+items:
+  - name: first
+  - name: second
+    predictor:
+      path: predictor.py
+`
+
+	source := NewYAMLSource([]byte(yamlStr))
+
+	line, ok := source.KeyLine("items", "index 1", "predictor", "path")
+	require.True(t, ok)
+	require.Equal(t, SourceLine{Line: 9}, line)
+
+	_, ok = source.KeyLine("items", "index 2")
+	require.False(t, ok)
+
+	errs = []error{
+		errors.Wrap(ErrorInvalidStr("pyton", "python"), "predictor", "type"),
+		errors.Wrap(ErrorMustBeDefined(), "predictor", "path"),
+		ErrorUnsupportedKey("other"),
+	}
+	errs = source.AddLineNumbers(errs, []string{"items", "index 1"}, []string{"defaults"})
+	require.Equal(t, `predictor: type: invalid value (got "pyton", must be "python"); did you mean "python"? (line 4)`, errs[0].Error())
+	require.Equal(t, `predictor: path: must be defined (line 9)`, errs[1].Error())
+	require.Equal(t, `key "other" is not supported (line 7)`, errs[2].Error())
+
+	errs = []error{errors.Wrap(ErrorMustBeDefined(), "predictor", "path")}
+	errs = source.AddLineNumbers(errs, []string{"items", "index 0"}, []string{"defaults"})
+	require.Equal(t, `predictor: path: must be defined (line 3)`, errs[0].Error())
+
+	require.Nil(t, NewYAMLSource([]byte("")))
+	require.Nil(t, NewYAMLSource([]byte("key: [")))
+}
+
+func TestUnsupportedKeyPath(t *testing.T) {
+	err := errors.Wrap(ErrorUnsupportedKey("pth", "path"), "predictor")
+	require.Equal(t, `predictor: key "pth" is not supported (did you mean "path"?)`, err.Error())
+	require.Equal(t, []string{"predictor", "pth"}, errors.GetPath(err))
+	require.Nil(t, errors.GetMetadata(err))
+
+	err = ErrorUnsupportedKey(2)
+	require.Equal(t, `key 2 is not supported`, err.Error())
+	require.Empty(t, errors.GetPath(err))
+}
+
+func TestGeneratedYAMLSource(t *testing.T) {
+	originalStr := `
+# comments and blank lines are not preserved in the generated document
+
+name: test
+
+predictor:
+  type: pyton
+  pth: predictor.py
+`
+
+	generatedStr := `# generated
+name: test
+predictor:
+  type: pyton
+  pth: predictor.py
+  path: default.py
+`
+
+	original := NewYAMLSource([]byte(originalStr))
+	sourceLines := SourceLines([]byte(generatedStr), func(keyPath []string) (SourceLine, bool) {
+		return original.KeyLine(keyPath...)
+	})
+	require.Equal(t, map[int]SourceLine{2: {Line: 4}, 3: {Line: 6}, 4: {Line: 7}, 5: {Line: 8}}, sourceLines)
+
+	source := NewGeneratedYAMLSource([]byte(generatedStr), sourceLines)
+
+	line, ok := source.KeyLine("predictor", "pth")
+	require.True(t, ok)
+	require.Equal(t, SourceLine{Line: 8}, line)
+
+	// keys which were not read from the user's files have no position
+	_, ok = source.KeyLine("predictor", "path")
+	require.False(t, ok)
+
+	errs := Struct(&suggestionConfig{}, MustReadYAMLStr(generatedStr), _suggestionValidation)
+	errs = source.AddLineNumbers(errs, nil)
+	require.Len(t, errs, 2)
+	require.Equal(t, `predictor: type: invalid value (got "pyton", must be "python", "tensorflow", or "onnx"); did you mean "python"? (line 7)`, errs[0].Error())
+	require.Equal(t, `predictor: key "pth" is not supported (did you mean "path"?) (line 8)`, errs[1].Error())
+
+	// keys from other files are identified by their file name
+	source = NewGeneratedYAMLSource([]byte(generatedStr), map[int]SourceLine{5: {File: "cortex.prod.yaml", Line: 3}})
+	errs = source.AddLineNumbers([]error{errors.Wrap(ErrorUnsupportedKey("pth"), "predictor")}, nil)
+	require.Equal(t, `predictor: key "pth" is not supported (cortex.prod.yaml, line 3)`, errs[0].Error())
+
+	// generated documents without source lines don't add line numbers
+	source = NewGeneratedYAMLSource([]byte(generatedStr), nil)
+	errs = source.AddLineNumbers([]error{errors.Wrap(ErrorUnsupportedKey("pth"), "predictor")}, nil)
+	require.Equal(t, `predictor: key "pth" is not supported`, errs[0].Error())
+}
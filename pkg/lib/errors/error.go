@@ -34,6 +34,8 @@ type Error struct {
 	NoPrint     bool
 	Cause       error
 	stack       *stack
+	path        []string // the strings which the error was wrapped with, outermost first
+	errs        []error  // the errors which were combined by Join()
 }
 
 func (cortexError *Error) Error() string {
@@ -78,12 +80,39 @@ func Wrap(err error, strs ...string) error {
 	cortexError := WithStack(err).(*Error)
 
 	strs = removeEmptyStrs(strs)
+	if len(strs) == 0 {
+		return cortexError
+	}
+
+	if len(cortexError.errs) > 0 {
+		// wrap each of the combined errors, and keep any text which was appended to the combined error
+		appended := strings.TrimPrefix(cortexError.Message, joinedMessage(cortexError.errs))
+		for i := range cortexError.errs {
+			cortexError.errs[i] = Wrap(cortexError.errs[i], strs...)
+		}
+		cortexError.Message = joinedMessage(cortexError.errs) + appended
+		return cortexError
+	}
+
+	cortexError.path = append(append([]string{}, strs...), cortexError.path...)
 	strs = append(strs, cortexError.Message)
 	cortexError.Message = strings.Join(strs, ": ")
 
 	return cortexError
 }
 
+// adds strs to the end (i.e. the innermost part) of the error's path without changing its message,
+// e.g. to record the key which the error refers to without exposing it as metadata
+func AppendPath(err error, strs ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	cortexError := WithStack(err).(*Error)
+	cortexError.path = append(cortexError.path, removeEmptyStrs(strs)...)
+	return cortexError
+}
+
 // adds to the end of the error message (without adding any whitespace or punctuation)
 func Append(err error, str string) error {
 	if err == nil {
@@ -116,6 +145,14 @@ func GetMetadata(err error) interface{} {
 	return nil
 }
 
+// returns the strings which the error was wrapped with, outermost first
+func GetPath(err error) []string {
+	if cortexError, ok := err.(*Error); ok {
+		return append([]string{}, cortexError.path...)
+	}
+	return nil
+}
+
 func IsNoTelemetry(err error) bool {
 	if cortexError, ok := err.(*Error); ok {
 		return cortexError.NoTelemetry
@@ -16,6 +16,10 @@ limitations under the License.
 
 package errors
 
+import (
+	"strings"
+)
+
 func AddError(errs []error, err error, strs ...string) ([]error, bool) {
 	ok := false
 	if err != nil {
@@ -110,3 +114,121 @@ func NonNilErrorMapKeys(errs map[string]error) []string {
 	}
 	return keys
 }
+
+// Dedupe removes errors which have the same message as a previous error
+func Dedupe(errs []error) []error {
+	var dedupedErrs []error
+	messages := map[string]bool{}
+	for _, err := range errs {
+		if err == nil || messages[err.Error()] {
+			continue
+		}
+		messages[err.Error()] = true
+		dedupedErrs = append(dedupedErrs, err)
+	}
+	return dedupedErrs
+}
+
+// Join combines errors into a single error (with the kind of the first error), whose message lists the errors
+// grouped by the outermost string that they were wrapped with (e.g. the api or the file name). Duplicate errors
+// are removed; nil is returned if there are no errors, and the error itself is returned if there is only one.
+func Join(errs ...error) error {
+	var nonNilErrs []error
+	for _, err := range errs {
+		if err != nil {
+			nonNilErrs = append(nonNilErrs, err)
+		}
+	}
+	if len(nonNilErrs) == 1 {
+		return nonNilErrs[0]
+	}
+
+	var flatErrs []error
+	for _, err := range nonNilErrs {
+		flatErrs = append(flatErrs, List(err)...)
+	}
+
+	flatErrs = Dedupe(flatErrs)
+	if len(flatErrs) == 0 {
+		return nil
+	}
+	if len(flatErrs) == 1 {
+		return flatErrs[0]
+	}
+
+	for i := range flatErrs {
+		flatErrs[i] = WithStack(flatErrs[i])
+	}
+
+	return &Error{
+		Kind:    GetKind(flatErrs[0]),
+		Message: joinedMessage(flatErrs),
+		stack:   callers(),
+		errs:    flatErrs,
+	}
+}
+
+// List returns the errors which were combined by Join(), or the error itself if it wasn't created by Join()
+func List(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if cortexError, ok := err.(*Error); ok && len(cortexError.errs) > 0 {
+		return cortexError.errs
+	}
+	return []error{err}
+}
+
+// First returns the first of the errors which were combined by Join() (with any text which was appended to the
+// combined error), or the error itself if it wasn't created by Join()
+func First(err error) error {
+	cortexError, ok := err.(*Error)
+	if !ok || len(cortexError.errs) == 0 {
+		return err
+	}
+
+	appended := strings.TrimPrefix(cortexError.Message, joinedMessage(cortexError.errs))
+	return Append(cortexError.errs[0], appended)
+}
+
+func joinedMessage(errs []error) string {
+	var groups []string
+	groupMessages := map[string][]string{}
+
+	for _, err := range errs {
+		group := ""
+		message := strings.TrimSpace(err.Error())
+		if cortexError, ok := err.(*Error); ok && len(cortexError.path) > 0 {
+			if trimmed := strings.TrimPrefix(message, cortexError.path[0]+": "); trimmed != message {
+				group = cortexError.path[0]
+				message = trimmed
+			}
+		}
+
+		if _, ok := groupMessages[group]; !ok {
+			groups = append(groups, group)
+		}
+		groupMessages[group] = append(groupMessages[group], message)
+	}
+
+	var sections []string
+	for _, group := range groups {
+		if group == "" {
+			sections = append(sections, strings.Join(groupMessages[group], "\n\n"))
+			continue
+		}
+
+		lines := []string{group + ":"}
+		for _, message := range groupMessages[group] {
+			for _, line := range strings.Split(message, "\n") {
+				if line != "" {
+					line = "  " + line
+				}
+				lines = append(lines, line)
+			}
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
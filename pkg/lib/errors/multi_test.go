@@ -0,0 +1,88 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestError(kind string, message string) error {
+	return WithStack(&Error{
+		Kind:    kind,
+		Message: message,
+	})
+}
+
+func TestJoin(t *testing.T) {
+	require.Nil(t, Join())
+	require.Nil(t, Join(nil, nil))
+
+	err := newTestError("test.a", "a")
+	require.Equal(t, err, Join(nil, err))
+
+	err = Join(
+		Wrap(newTestError("test.a", "must be defined"), "cortex.yaml: api-1", "predictor", "path"),
+		Wrap(newTestError("test.b", "must be less than 10"), "cortex.yaml: api-1", "autoscaling", "max_replicas"),
+		Wrap(newTestError("test.a", "must be defined"), "cortex.yaml: api-2", "predictor", "path"),
+		newTestError("test.c", "name must be unique"),
+		Wrap(newTestError("test.b", "must be less than 10"), "cortex.yaml: api-1", "autoscaling", "max_replicas"),
+	)
+
+	require.Equal(t, "test.a", GetKind(err))
+	require.Len(t, List(err), 4)
+	require.Equal(t, `cortex.yaml: api-1:
+  predictor: path: must be defined
+  autoscaling: max_replicas: must be less than 10
+
+cortex.yaml: api-2:
+  predictor: path: must be defined
+
+name must be unique`, err.Error())
+}
+
+func TestJoinNested(t *testing.T) {
+	err := Join(
+		Join(newTestError("test.a", "a"), newTestError("test.b", "b")),
+		newTestError("test.c", "c"),
+	)
+	require.Len(t, List(err), 3)
+}
+
+func TestWrapJoined(t *testing.T) {
+	err := Join(
+		Wrap(newTestError("test.a", "must be defined"), "path"),
+		Wrap(newTestError("test.b", "must be less than 10"), "autoscaling", "max_replicas"),
+	)
+	err = Append(err, "\n\nsee the docs")
+	err = Wrap(err, "cortex.yaml: api-1")
+
+	require.Equal(t, `cortex.yaml: api-1:
+  path: must be defined
+  autoscaling: max_replicas: must be less than 10
+
+see the docs`, err.Error())
+
+	require.Equal(t, "cortex.yaml: api-1: path: must be defined\n\nsee the docs", First(err).Error())
+}
+
+func TestFirst(t *testing.T) {
+	err := newTestError("test.a", "a")
+	require.Equal(t, err, First(err))
+	require.Nil(t, First(nil))
+}
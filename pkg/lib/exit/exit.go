@@ -18,16 +18,47 @@ package exit
 
 import (
 	"os"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 )
 
+// _errorExitCodes maps error kinds (e.g. "cli.api_not_ready") or the packages which define them (e.g. "spec") to exit codes
+var _errorExitCodes = map[string]int{}
+
+// SetErrorExitCode sets the exit code used by Error() for errors of the given kinds, or of all kinds defined by the given packages
+// (errors of other kinds exit with code 1)
+func SetErrorExitCode(code int, kindsOrPackages ...string) {
+	for _, kindOrPackage := range kindsOrPackages {
+		_errorExitCodes[kindOrPackage] = code
+	}
+}
+
+func errorExitCode(err error) int {
+	kind := errors.GetKind(err)
+	if code, ok := _errorExitCodes[kind]; ok {
+		return code
+	}
+	if i := strings.Index(kind, "."); i != -1 {
+		if code, ok := _errorExitCodes[kind[:i]]; ok {
+			return code
+		}
+	}
+	return 1
+}
+
 func Ok() {
 	telemetry.Close()
 	os.Exit(0)
 }
 
+// Code exits with the given exit code (e.g. to propagate the exit code of a remote command)
+func Code(code int) {
+	telemetry.Close()
+	os.Exit(code)
+}
+
 func Error(err error, wrapStrs ...string) {
 	for _, str := range wrapStrs {
 		err = errors.Wrap(err, str)
@@ -43,7 +74,7 @@ func Error(err error, wrapStrs ...string) {
 
 	telemetry.Close()
 
-	os.Exit(1)
+	os.Exit(errorExitCode(err))
 }
 
 func Panic(err error, wrapStrs ...string) {
@@ -23,6 +23,7 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"google.golang.org/api/compute/v1"
+	logging "google.golang.org/api/logging/v2"
 	"google.golang.org/api/option"
 )
 
@@ -30,6 +31,7 @@ type clients struct {
 	gcs     *storage.Client
 	compute *compute.Service
 	gke     *container.ClusterManagerClient
+	logging *logging.Service
 }
 
 func (c *Client) GCS() (*storage.Client, error) {
@@ -64,3 +66,14 @@ func (c *Client) GKE() (*container.ClusterManagerClient, error) {
 	}
 	return c.clients.gke, nil
 }
+
+func (c *Client) Logging() (*logging.Service, error) {
+	if c.clients.logging == nil {
+		loggingService, err := logging.NewService(context.Background(), option.WithCredentialsJSON(c.CredentialsJSON))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c.clients.logging = loggingService
+	}
+	return c.clients.logging, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	logging "google.golang.org/api/logging/v2"
+)
+
+// ListLogEntries returns up to maxEntries log entries from the client's project which match the filter, in ascending order of timestamp
+func (c *Client) ListLogEntries(filter string, maxEntries int64) ([]*logging.LogEntry, error) {
+	entries, _, err := c.ListLogEntriesPage(filter, maxEntries, "")
+	return entries, err
+}
+
+// ListLogEntriesPage returns a page of log entries which match the filter, in ascending order of timestamp, along with the token for the next page ("" if there are no more pages)
+func (c *Client) ListLogEntriesPage(filter string, pageSize int64, pageToken string) ([]*logging.LogEntry, string, error) {
+	loggingService, err := c.Logging()
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := loggingService.Entries.List(&logging.ListLogEntriesRequest{
+		ResourceNames: []string{"projects/" + c.ProjectID},
+		Filter:        filter,
+		OrderBy:       "timestamp asc",
+		PageSize:      pageSize,
+		PageToken:     pageToken,
+	}).Do()
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	return res.Entries, res.NextPageToken, nil
+}
@@ -30,6 +30,8 @@ const (
 	ErrParseLabel         = "k8s.parse_label"
 	ErrParseAnnotation    = "k8s.parse_annotation"
 	ErrParseQuantity      = "k8s.parse_quantity"
+	ErrPortForward        = "k8s.port_forward"
+	ErrMetricsUnavailable = "k8s.metrics_unavailable"
 )
 
 func ErrorLabelNotFound(labelName string) error {
@@ -66,3 +68,17 @@ func ErrorParseQuantity(qtyStr string) error {
 		Message: fmt.Sprintf("%s: invalid kubernetes quantity, some valid examples are 1, 200m, 500Mi, 2G (see here for more information: https://docs.cortex.dev/v/%s/advanced/compute)", qtyStr, consts.CortexVersionMinor),
 	})
 }
+
+func ErrorPortForward(podName string, port int32, message string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPortForward,
+		Message: fmt.Sprintf("unable to forward port %d of %s: %s", port, podName, message),
+	})
+}
+
+func ErrorMetricsUnavailable() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMetricsUnavailable,
+		Message: "resource usage metrics are not available yet (metrics-server may still be starting up, or may not be installed in the cluster); please try again in a minute",
+	})
+}
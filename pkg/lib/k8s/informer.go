@@ -0,0 +1,36 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"time"
+
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kinformers "k8s.io/client-go/informers"
+)
+
+// NewInformerFactory returns an informer factory scoped to the client's namespace and restricted to objects which have all of the label keys
+func (c *Client) NewInformerFactory(resyncPeriod time.Duration, labelKeys ...string) kinformers.SharedInformerFactory {
+	return kinformers.NewSharedInformerFactoryWithOptions(
+		c.clientset,
+		resyncPeriod,
+		kinformers.WithNamespace(c.Namespace),
+		kinformers.WithTweakListOptions(func(opts *kmeta.ListOptions) {
+			opts.LabelSelector = LabelExistsSelector(labelKeys...)
+		}),
+	)
+}
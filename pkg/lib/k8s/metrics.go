@@ -0,0 +1,123 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	kcore "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+// metrics-server's api group (the types are defined here to avoid depending on k8s.io/metrics)
+const _metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// ResourceUsage is the current cpu and memory usage of a pod (summed across its containers) or a node
+type ResourceUsage struct {
+	CPU kresource.Quantity
+	Mem kresource.Quantity
+}
+
+type podMetricsList struct {
+	Items []struct {
+		Metadata   kmeta.ObjectMeta `json:"metadata"`
+		Containers []struct {
+			Name  string             `json:"name"`
+			Usage kcore.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+type nodeMetricsList struct {
+	Items []struct {
+		Metadata kmeta.ObjectMeta   `json:"metadata"`
+		Usage    kcore.ResourceList `json:"usage"`
+	} `json:"items"`
+}
+
+// ListPodMetrics returns the usage of each pod which matches the label selector (pod name -> usage); pods which metrics-server hasn't scraped yet are omitted
+func (c *Client) ListPodMetrics(labelSelector string) (map[string]ResourceUsage, error) {
+	req := c.clientset.CoreV1().RESTClient().Get().AbsPath(_metricsAPIPath, "namespaces", c.Namespace, "pods")
+	if labelSelector != "" {
+		req = req.Param("labelSelector", labelSelector)
+	}
+
+	bytes, err := req.DoRaw(context.Background())
+	if err != nil {
+		return nil, metricsError(err)
+	}
+
+	var metricsList podMetricsList
+	if err := json.Unmarshal(bytes, &metricsList); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	usages := make(map[string]ResourceUsage, len(metricsList.Items))
+	for _, item := range metricsList.Items {
+		var usage ResourceUsage
+		for _, container := range item.Containers {
+			usage.CPU.Add(*container.Usage.Cpu())
+			usage.Mem.Add(*container.Usage.Memory())
+		}
+		usages[item.Metadata.Name] = usage
+	}
+
+	return usages, nil
+}
+
+func (c *Client) ListPodMetricsByLabel(labelKey string, labelValue string) (map[string]ResourceUsage, error) {
+	return c.ListPodMetrics(klabels.SelectorFromSet(map[string]string{labelKey: labelValue}).String())
+}
+
+func (c *Client) ListPodMetricsWithLabelKeys(labelKeys ...string) (map[string]ResourceUsage, error) {
+	return c.ListPodMetrics(LabelExistsSelector(labelKeys...))
+}
+
+// ListNodeMetrics returns the usage of each node (node name -> usage)
+func (c *Client) ListNodeMetrics() (map[string]ResourceUsage, error) {
+	bytes, err := c.clientset.CoreV1().RESTClient().Get().AbsPath(_metricsAPIPath, "nodes").DoRaw(context.Background())
+	if err != nil {
+		return nil, metricsError(err)
+	}
+
+	var metricsList nodeMetricsList
+	if err := json.Unmarshal(bytes, &metricsList); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	usages := make(map[string]ResourceUsage, len(metricsList.Items))
+	for _, item := range metricsList.Items {
+		usages[item.Metadata.Name] = ResourceUsage{
+			CPU: *item.Usage.Cpu(),
+			Mem: *item.Usage.Memory(),
+		}
+	}
+
+	return usages, nil
+}
+
+func metricsError(err error) error {
+	if kerrors.IsNotFound(err) || kerrors.IsServiceUnavailable(err) {
+		return ErrorMetricsUnavailable()
+	}
+	return errors.WithStack(err)
+}
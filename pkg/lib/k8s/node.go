@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	kcore "k8s.io/api/core/v1"
@@ -61,3 +62,11 @@ func (c *Client) ListNodesWithLabelKeys(labelKeys ...string) ([]kcore.Node, erro
 	}
 	return c.ListNodes(opts)
 }
+
+// IsSpotNode returns true if the node is an AWS spot instance (labeled by eksctl) or a GKE preemptible or spot VM
+func IsSpotNode(node *kcore.Node) bool {
+	if strings.Contains(strings.ToLower(node.Labels["lifecycle"]), "spot") {
+		return true
+	}
+	return node.Labels["cloud.google.com/gke-preemptible"] == "true" || node.Labels["cloud.google.com/gke-spot"] == "true"
+}
@@ -19,18 +19,25 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	kcore "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
 	kremotecommand "k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	kexec "k8s.io/client-go/util/exec"
 )
 
 var _podTypeMeta = kmeta.TypeMeta{
@@ -319,6 +326,46 @@ func (c *Client) GetPod(name string) (*kcore.Pod, error) {
 	return pod, nil
 }
 
+// GetPodLogs returns the last tailLines lines of the container's logs; if previous is true, the logs of the previously terminated container are returned
+func (c *Client) GetPodLogs(podName string, containerName string, tailLines int64, previous bool) (string, error) {
+	opts := &kcore.PodLogOptions{
+		Container: containerName,
+		Previous:  previous,
+	}
+	if tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+
+	logBytes, err := c.podClient.GetLogs(podName, opts).DoRaw(context.Background())
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.WithStack(err)
+	}
+	return string(logBytes), nil
+}
+
+// StreamPodLogs follows the container's logs until the container terminates or ctx is cancelled; tailLines <= 0 returns all lines, and sinceTime (if not nil) excludes older lines
+func (c *Client) StreamPodLogs(ctx context.Context, podName string, containerName string, tailLines int64, sinceTime *time.Time) (io.ReadCloser, error) {
+	opts := &kcore.PodLogOptions{
+		Container: containerName,
+		Follow:    true,
+	}
+	if tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+	if sinceTime != nil {
+		opts.SinceTime = &kmeta.Time{Time: *sinceTime}
+	}
+
+	stream, err := c.podClient.GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return stream, nil
+}
+
 func (c *Client) DeletePod(name string) (bool, error) {
 	err := c.podClient.Delete(context.Background(), name, _deleteOpts)
 	if err != nil {
@@ -437,3 +484,118 @@ func (c *Client) Exec(podName string, containerName string, command []string) (s
 	return buf.String(), nil
 
 }
+
+type ExecOptions struct {
+	Command           []string
+	Stdin             io.Reader // optional
+	Stdout            io.Writer
+	Stderr            io.Writer // ignored if TTY is true, since the tty merges stderr into stdout
+	TTY               bool
+	TerminalSizeQueue kremotecommand.TerminalSizeQueue // optional
+}
+
+// ExecStream runs a command in the container, streaming its input and output, and returns the command's exit code
+func (c *Client) ExecStream(podName string, containerName string, opts *ExecOptions) (int, error) {
+	options := &kcore.PodExecOptions{
+		Container: containerName,
+		Command:   opts.Command,
+		Stdin:     opts.Stdin != nil,
+		Stdout:    true,
+		Stderr:    !opts.TTY,
+		TTY:       opts.TTY,
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().Namespace(c.Namespace).Resource("pods").Name(podName).SubResource("exec")
+	req.VersionedParams(options, kscheme.ParameterCodec)
+
+	exec, err := kremotecommand.NewSPDYExecutor(c.RestConfig, "POST", req.URL())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	streamOptions := kremotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: opts.TerminalSizeQueue,
+	}
+	if !opts.TTY {
+		streamOptions.Stderr = opts.Stderr
+	}
+
+	err = exec.Stream(streamOptions)
+	if err != nil {
+		if exitErr, ok := err.(kexec.ExitError); ok && exitErr.Exited() {
+			return exitErr.ExitStatus(), nil
+		}
+		return 0, errors.WithStack(err)
+	}
+
+	return 0, nil
+}
+
+// PortForward forwards a single connection to a port of the pod (via the portforward subresource), and returns once either side closes the connection
+func (c *Client) PortForward(podName string, port int32, conn io.ReadWriter) error {
+	req := c.clientset.CoreV1().RESTClient().Post().Namespace(c.Namespace).Resource("pods").Name(podName).SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.RestConfig)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer streamConn.Close()
+
+	headers := http.Header{}
+	headers.Set(kcore.StreamType, kcore.StreamTypeError)
+	headers.Set(kcore.PortHeader, s.Int32(port))
+	headers.Set(kcore.PortForwardRequestIDHeader, "0")
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	errorStream.Close() // the error stream is only read from
+
+	remoteErr := make(chan error, 1)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		if err != nil {
+			remoteErr <- errors.WithStack(err)
+		} else if len(message) > 0 {
+			remoteErr <- ErrorPortForward(podName, port, string(message))
+		}
+		close(remoteErr)
+	}()
+
+	headers.Set(kcore.StreamType, kcore.StreamTypeData)
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	remoteDone := make(chan struct{})
+	localDone := make(chan struct{})
+
+	go func() {
+		io.Copy(conn, dataStream)
+		close(remoteDone)
+	}()
+
+	go func() {
+		io.Copy(dataStream, conn)
+		dataStream.Close() // signals to the pod that no more data will be sent
+		close(localDone)
+	}()
+
+	select {
+	case <-remoteDone:
+	case <-localDone:
+		<-remoteDone
+	}
+
+	return <-remoteErr
+}
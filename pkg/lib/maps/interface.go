@@ -67,3 +67,40 @@ func MergeStrInterfaceMaps(maps ...map[string]interface{}) map[string]interface{
 	}
 	return merged
 }
+
+// DeepMergeStrInterfaceMaps merges the maps in order, recursively merging nested maps (all other values, including lists, are replaced)
+func DeepMergeStrInterfaceMaps(maps ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, m := range maps {
+		for k, v := range m {
+			vMap, vIsMap := toStrInterfaceMap(v)
+			mergedMap, mergedIsMap := toStrInterfaceMap(merged[k])
+			if vIsMap && mergedIsMap {
+				merged[k] = DeepMergeStrInterfaceMaps(mergedMap, vMap)
+			} else if vIsMap {
+				merged[k] = DeepMergeStrInterfaceMaps(vMap)
+			} else {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
+
+func toStrInterfaceMap(val interface{}) (map[string]interface{}, bool) {
+	switch typed := val.(type) {
+	case map[string]interface{}:
+		return typed, true
+	case map[interface{}]interface{}:
+		strMap := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			kStr, ok := k.(string)
+			if !ok {
+				return nil, false
+			}
+			strMap[kStr] = v
+		}
+		return strMap, true
+	}
+	return nil, false
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepMergeStrInterfaceMaps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		maps     []map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no maps",
+			maps:     nil,
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "flat",
+			maps: []map[string]interface{}{
+				{"a": 1, "b": 2},
+				{"b": 3, "c": 4},
+			},
+			expected: map[string]interface{}{"a": 1, "b": 3, "c": 4},
+		},
+		{
+			name: "nested maps are merged",
+			maps: []map[string]interface{}{
+				{"compute": map[string]interface{}{"cpu": 1, "mem": "1G"}, "name": "a"},
+				{"compute": map[string]interface{}{"cpu": 2, "gpu": 1}},
+			},
+			expected: map[string]interface{}{"compute": map[string]interface{}{"cpu": 2, "mem": "1G", "gpu": 1}, "name": "a"},
+		},
+		{
+			name: "deeply nested maps with interface keys are merged",
+			maps: []map[string]interface{}{
+				{"predictor": map[interface{}]interface{}{"config": map[interface{}]interface{}{"a": 1, "b": 2}}},
+				{"predictor": map[string]interface{}{"config": map[string]interface{}{"b": 3}}},
+			},
+			expected: map[string]interface{}{"predictor": map[string]interface{}{"config": map[string]interface{}{"a": 1, "b": 3}}},
+		},
+		{
+			name: "lists are replaced",
+			maps: []map[string]interface{}{
+				{"paths": []interface{}{"a", "b"}},
+				{"paths": []interface{}{"c"}},
+			},
+			expected: map[string]interface{}{"paths": []interface{}{"c"}},
+		},
+		{
+			name: "non-map values override maps",
+			maps: []map[string]interface{}{
+				{"compute": map[string]interface{}{"cpu": 1}},
+				{"compute": nil},
+			},
+			expected: map[string]interface{}{"compute": nil},
+		},
+		{
+			name: "maps override non-map values",
+			maps: []map[string]interface{}{
+				{"compute": "small"},
+				{"compute": map[string]interface{}{"cpu": 1}},
+			},
+			expected: map[string]interface{}{"compute": map[string]interface{}{"cpu": 1}},
+		},
+		{
+			name: "three maps",
+			maps: []map[string]interface{}{
+				{"compute": map[string]interface{}{"cpu": 1}},
+				{"compute": map[string]interface{}{"mem": "1G"}},
+				{"compute": map[string]interface{}{"cpu": 3}},
+			},
+			expected: map[string]interface{}{"compute": map[string]interface{}{"cpu": 3, "mem": "1G"}},
+		},
+	} {
+		require.Equal(t, tc.expected, DeepMergeStrInterfaceMaps(tc.maps...), tc.name)
+	}
+
+	// the inputs are not modified
+	base := map[string]interface{}{"compute": map[string]interface{}{"cpu": 1}}
+	DeepMergeStrInterfaceMaps(base, map[string]interface{}{"compute": map[string]interface{}{"cpu": 2}})
+	require.Equal(t, map[string]interface{}{"compute": map[string]interface{}{"cpu": 1}}, base)
+}
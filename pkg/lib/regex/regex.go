@@ -43,19 +43,25 @@ func HasTrailingWhitespace(s string) bool {
 
 // letters, numbers, spaces representable in UTF-8, and the following characters: _ . : / + - @
 // = is not supported because it doesn't propagate to the NLB correctly (via the k8s service annotation)
-var _awsTagRegex = regexp.MustCompile(`^[\sa-zA-Z0-9_\-\.:/+@]+$`)
+const AWSTagPattern = `^[\sa-zA-Z0-9_\-\.:/+@]+$`
+
+var _awsTagRegex = regexp.MustCompile(AWSTagPattern)
 
 func IsValidAWSTag(s string) bool {
 	return _awsTagRegex.MatchString(s)
 }
 
-var _alphaNumericDashDotUnderscoreRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-\.]+$`)
+const AlphaNumericDashDotUnderscorePattern = `^[a-zA-Z0-9_\-\.]+$`
+
+var _alphaNumericDashDotUnderscoreRegex = regexp.MustCompile(AlphaNumericDashDotUnderscorePattern)
 
 func IsAlphaNumericDashDotUnderscore(s string) bool {
 	return _alphaNumericDashDotUnderscoreRegex.MatchString(s)
 }
 
-var _alphaNumericDashUnderscoreRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+const AlphaNumericDashUnderscorePattern = `^[a-zA-Z0-9_\-]+$`
+
+var _alphaNumericDashUnderscoreRegex = regexp.MustCompile(AlphaNumericDashUnderscorePattern)
 
 func IsAlphaNumericDashUnderscore(s string) bool {
 	return _alphaNumericDashUnderscoreRegex.MatchString(s)
@@ -151,6 +151,76 @@ func MaxLen(strs ...string) int {
 	return maxLen
 }
 
+// EditDistance returns the Levenshtein distance between two strings (the minimum number of single-character
+// insertions, deletions, and substitutions required to change one into the other)
+func EditDistance(str1 string, str2 string) int {
+	runes1 := []rune(str1)
+	runes2 := []rune(str2)
+
+	prevRow := make([]int, len(runes2)+1)
+	currRow := make([]int, len(runes2)+1)
+	for j := range prevRow {
+		prevRow[j] = j
+	}
+
+	for i := 1; i <= len(runes1); i++ {
+		currRow[0] = i
+		for j := 1; j <= len(runes2); j++ {
+			substitutionCost := 1
+			if runes1[i-1] == runes2[j-1] {
+				substitutionCost = 0
+			}
+			currRow[j] = minInt(prevRow[j]+1, currRow[j-1]+1, prevRow[j-1]+substitutionCost)
+		}
+		prevRow, currRow = currRow, prevRow
+	}
+
+	return prevRow[len(runes2)]
+}
+
+// ClosestMatch returns the candidate which is most similar to str (ignoring case), if it is similar enough to be a likely typo
+func ClosestMatch(str string, candidates []string) (string, bool) {
+	lowerStr := strings.ToLower(str)
+
+	bestMatch := ""
+	bestDistance := -1
+	for _, candidate := range candidates {
+		if candidate == str {
+			continue
+		}
+		distance := EditDistance(lowerStr, strings.ToLower(candidate))
+		if bestDistance == -1 || distance < bestDistance {
+			bestMatch = candidate
+			bestDistance = distance
+		}
+	}
+
+	if bestDistance == -1 {
+		return "", false
+	}
+
+	// allow roughly one edit for every three characters
+	maxDistance := MaxLen(str, bestMatch) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	if bestDistance > maxDistance {
+		return "", false
+	}
+
+	return bestMatch, true
+}
+
+func minInt(val int, vals ...int) int {
+	min := val
+	for _, v := range vals {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
 func TrimPrefixIfPresentInAll(strs []string, prefix string) ([]string, bool) {
 	if prefix == "" {
 		return strs, false
@@ -54,3 +54,44 @@ func TestLongestCommonPrefix(t *testing.T) {
 	expected = ""
 	require.Equal(t, expected, LongestCommonPrefix(strs...))
 }
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, EditDistance("", ""))
+	require.Equal(t, 3, EditDistance("", "abc"))
+	require.Equal(t, 3, EditDistance("abc", ""))
+	require.Equal(t, 0, EditDistance("abc", "abc"))
+	require.Equal(t, 1, EditDistance("max_replica", "max_replicas"))
+	require.Equal(t, 1, EditDistance("pyton", "python"))
+	require.Equal(t, 2, EditDistance("tenosrflow", "tensorflow"))
+	require.Equal(t, 3, EditDistance("kitten", "sitting"))
+}
+
+func TestClosestMatch(t *testing.T) {
+	var match string
+	var ok bool
+
+	match, ok = ClosestMatch("max_replica", []string{"min_replicas", "max_replicas", "init_replicas"})
+	require.True(t, ok)
+	require.Equal(t, "max_replicas", match)
+
+	match, ok = ClosestMatch("pyton", []string{"python", "tensorflow", "onnx"})
+	require.True(t, ok)
+	require.Equal(t, "python", match)
+
+	match, ok = ClosestMatch("Python", []string{"python", "tensorflow", "onnx"})
+	require.True(t, ok)
+	require.Equal(t, "python", match)
+
+	match, ok = ClosestMatch("onx", []string{"python", "tensorflow", "onnx"})
+	require.True(t, ok)
+	require.Equal(t, "onnx", match)
+
+	_, ok = ClosestMatch("pytorch", []string{"python", "tensorflow", "onnx"})
+	require.False(t, ok)
+
+	_, ok = ClosestMatch("gpu", []string{"cpu", "mem", "inf"})
+	require.True(t, ok)
+
+	_, ok = ClosestMatch("abc", []string{})
+	require.False(t, ok)
+}
@@ -24,9 +24,14 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 )
 
+const (
+	DNS1035Pattern = `^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	DNS1123Pattern = `^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+)
+
 var (
-	_dns1035Regex   = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
-	_dns1123Regex   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	_dns1035Regex   = regexp.MustCompile(DNS1035Pattern)
+	_dns1123Regex   = regexp.MustCompile(DNS1123Pattern)
 	_endpointRegex  = regexp.MustCompile(`^[a-zA-Z0-9_\-\./]*$`)
 	_urlQParamRegex = regexp.MustCompile(`(https?://.*)\?[^:\s]*`)
 )
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wsconn
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn(t *testing.T) {
+	// echoes everything it receives, then closes the connection once the client closes its side
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		socket, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		conn := New(socket)
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}))
+	defer server.Close()
+
+	socket, _, err := websocket.DefaultDialer.Dial(strings.Replace(server.URL, "http", "ws", 1), nil)
+	require.NoError(t, err)
+	conn := New(socket)
+
+	_, err = conn.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("world"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 3)
+	n, err := io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, "hel", string(buf[:n]))
+
+	rest := make([]byte, 8)
+	n, err = io.ReadFull(conn, rest)
+	require.NoError(t, err)
+	require.Equal(t, "lo world", string(rest[:n]))
+
+	socket.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	remaining, err := ioutil.ReadAll(conn)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+}
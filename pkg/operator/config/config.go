@@ -68,7 +68,7 @@ func Init() error {
 
 		errs := cr.ParseYAMLFile(Cluster, clusterconfig.Validation, clusterConfigPath)
 		if errors.HasError(errs) {
-			return errors.FirstError(errs...)
+			return errors.Join(errs...)
 		}
 
 		Cluster.InstanceMetadata = aws.InstanceMetadatas[*Cluster.Region][*Cluster.InstanceType]
@@ -122,7 +122,7 @@ func Init() error {
 
 		errs := cr.ParseYAMLFile(GCPCluster, clusterconfig.GCPValidation, clusterConfigPath)
 		if errors.HasError(errs) {
-			return errors.FirstError(errs...)
+			return errors.Join(errs...)
 		}
 
 		GCP, err = gcp.NewFromEnvCheckProjectID(*GCPCluster.Project)
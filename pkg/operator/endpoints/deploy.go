@@ -26,6 +26,7 @@ import (
 
 func Deploy(w http.ResponseWriter, r *http.Request) {
 	force := getOptionalBoolQParam("force", false, r)
+	failFast := getOptionalBoolQParam("failFast", false, r)
 
 	configFileName, err := getRequiredQueryParam("configFileName", r)
 	if err != nil {
@@ -50,6 +51,9 @@ func Deploy(w http.ResponseWriter, r *http.Request) {
 
 	response, err := resources.Deploy(projectBytes, configFileName, configBytes, force)
 	if err != nil {
+		if failFast {
+			err = errors.First(err)
+		}
 		respondError(w, r, err)
 		return
 	}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	_eventsSocketWriteDeadline = 10 * time.Second
+	_eventsSocketMaxMessage    = 8192
+)
+
+// ReadEvents streams api lifecycle events as json messages; if apiName is not provided, events for all apis are streamed
+func ReadEvents(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	recentEvents, eventsChan, unsubscribe := operator.SubscribeToEvents(apiName)
+	defer unsubscribe()
+
+	// the client doesn't send anything, but reading is necessary to detect when the connection is closed
+	clientClosed := make(chan struct{})
+	go func() {
+		defer close(clientClosed)
+		socket.SetReadLimit(_eventsSocketMaxMessage)
+		for {
+			if _, _, err := socket.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, event := range recentEvents {
+		socket.SetWriteDeadline(time.Now().Add(_eventsSocketWriteDeadline))
+		if err := socket.WriteJSON(event); err != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-clientClosed:
+			return
+		case event := <-eventsChan:
+			socket.SetWriteDeadline(time.Now().Add(_eventsSocketWriteDeadline))
+			if err := socket.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
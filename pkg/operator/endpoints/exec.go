@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	kcore "k8s.io/api/core/v1"
+)
+
+// Exec runs a command (one or more command query params) in a replica of an api, and tunnels its input and output over a websocket
+func Exec(w http.ResponseWriter, r *http.Request) {
+	command := r.URL.Query()["command"]
+	if len(command) == 0 {
+		respondError(w, r, ErrorQueryParamRequired("command"))
+		return
+	}
+
+	pod, err := getReplicaForTunnel(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	realtimeapi.Exec(pod.Name, command, getOptionalBoolQParam("tty", false, r), socket)
+}
+
+// PortForward tunnels a single tcp connection to the serving port of a replica of an api over a websocket
+func PortForward(w http.ResponseWriter, r *http.Request) {
+	pod, err := getReplicaForTunnel(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	if err := realtimeapi.PortForward(pod.Name, socket); err != nil {
+		errors.PrintError(err)
+	}
+}
+
+func getReplicaForTunnel(r *http.Request) (*kcore.Pod, error) {
+	apiName := mux.Vars(r)["apiName"]
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployedResource.Kind != userconfig.RealtimeAPIKind {
+		return nil, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind)
+	}
+
+	replica := getOptionalQParam("replica", r)
+	if replica == "" {
+		replica = "0"
+	}
+
+	return realtimeapi.GetReplica(apiName, replica)
+}
@@ -20,12 +20,12 @@ import (
 	"net/http"
 	"os"
 	"sort"
-	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
@@ -71,7 +71,7 @@ func getNodeInfos() ([]schema.NodeInfo, int, error) {
 
 	for _, node := range nodes {
 		instanceType := node.Labels["beta.kubernetes.io/instance-type"]
-		isSpot := strings.Contains(strings.ToLower(node.Labels["lifecycle"]), "spot")
+		isSpot := k8s.IsSpotNode(&node)
 
 		price := aws.InstanceMetadatas[*config.Cluster.Region][instanceType].Price
 		if isSpot {
@@ -90,6 +90,7 @@ func getNodeInfos() ([]schema.NodeInfo, int, error) {
 
 		nodeInfoMap[node.Name] = &schema.NodeInfo{
 			Name:                 node.Name,
+			NodeGroup:            operator.NodeGroupForNode(&node).Name,
 			InstanceType:         instanceType,
 			IsSpot:               isSpot,
 			Price:                price,
@@ -18,17 +18,28 @@ package endpoints
 
 import (
 	"net/http"
+	"time"
 
-	"github.com/cortexlabs/cortex/pkg/operator/config"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/resources"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
-	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 )
 
+// logs are read from the cluster's log pipeline by default, or directly from the replicas if the source query param is set to "pods"
+const _logSourcePods = "pods"
+
+func getLogSource(r *http.Request) (string, error) {
+	source := getOptionalQParam("source", r)
+	if source != "" && source != _logSourcePods {
+		return "", ErrorInvalidQueryParam("source", source, `"`+_logSourcePods+`"`)
+	}
+	return source, nil
+}
+
 func ReadLogs(w http.ResponseWriter, r *http.Request) {
 	apiName := mux.Vars(r)["apiName"]
 	jobID := getOptionalQParam("jobID", r)
@@ -52,19 +63,97 @@ func ReadLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if config.Provider == types.AWSProviderType {
-		upgrader := websocket.Upgrader{}
-		socket, err := upgrader.Upgrade(w, r, nil)
-		if err != nil {
-			respondError(w, r, err)
-			return
-		}
-		defer socket.Close()
-		realtimeapi.ReadLogs(apiName, socket)
+	source, err := getLogSource(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	if source == _logSourcePods {
+		realtimeapi.ReadPodLogs(apiName, socket)
+		return
+	}
+	realtimeapi.ReadLogs(apiName, socket)
+}
+
+// GetReplicaLogs returns the recent logs of the api's up-to-date replicas which are not ready
+func GetReplicaLogs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if deployedResource.Kind != userconfig.RealtimeAPIKind {
+		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind))
+		return
+	}
+
+	replicaLogs, err := realtimeapi.GetFailedReplicaLogs(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.ReplicaLogsResponse{Replicas: replicaLogs})
+}
+
+// SearchLogs returns a page of an api's historical logs; start and end are milliseconds since the epoch
+func SearchLogs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if deployedResource.Kind != userconfig.RealtimeAPIKind {
+		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind))
+		return
+	}
+
+	start, err := getOptionalInt64QParam("start", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if start == nil {
+		respondError(w, r, ErrorQueryParamRequired("start"))
+		return
+	}
+
+	end, err := getOptionalInt64QParam("end", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	endTime := time.Now()
+	if end != nil {
+		endTime = libtime.MillisToTime(*end)
 	}
 
-	if config.Provider == types.GCPProviderType {
-		queryParams := gcpLogsQueryParams(apiName)
-		respond(w, schema.GCPLogsResponse{QueryParams: queryParams})
+	response, err := realtimeapi.SearchLogs(apiName, realtimeapi.LogSearch{
+		StartTime: libtime.MillisToTime(*start),
+		EndTime:   endTime,
+		Grep:      getOptionalQParam("grep", r),
+		Replica:   getOptionalQParam("replica", r),
+		NextToken: getOptionalQParam("nextToken", r),
+	})
+	if err != nil {
+		respondError(w, r, err)
+		return
 	}
+
+	respond(w, response)
 }
@@ -45,6 +45,12 @@ func ReadJobLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	source, err := getLogSource(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	upgrader := websocket.Upgrader{}
 	socket, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -53,8 +59,14 @@ func ReadJobLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	defer socket.Close()
 
-	batchapi.ReadLogs(spec.JobKey{
+	jobKey := spec.JobKey{
 		APIName: deployedResource.Name,
 		ID:      jobID,
-	}, socket)
+	}
+
+	if source == _logSourcePods {
+		batchapi.ReadPodLogs(jobKey, socket)
+		return
+	}
+	batchapi.ReadLogs(jobKey, socket)
 }
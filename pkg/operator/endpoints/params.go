@@ -51,3 +51,16 @@ func getOptionalBoolQParam(paramName string, defaultVal bool, r *http.Request) b
 	}
 	return defaultVal
 }
+
+// returns nil if the param is not set
+func getOptionalInt64QParam(paramName string, r *http.Request) (*int64, error) {
+	param := r.URL.Query().Get(paramName)
+	if param == "" {
+		return nil, nil
+	}
+	paramInt64, ok := s.ParseInt64(param)
+	if !ok {
+		return nil, ErrorInvalidQueryParam(paramName, param, "an integer")
+	}
+	return &paramInt64, nil
+}
@@ -26,6 +26,7 @@ import (
 
 func Patch(w http.ResponseWriter, r *http.Request) {
 	force := getOptionalBoolQParam("force", false, r)
+	failFast := getOptionalBoolQParam("failFast", false, r)
 
 	configFileName, err := getRequiredQueryParam("configFileName", r)
 	if err != nil {
@@ -43,6 +44,9 @@ func Patch(w http.ResponseWriter, r *http.Request) {
 
 	response, err := resources.Patch(bodyBytes, configFileName, force)
 	if err != nil {
+		if failFast {
+			err = errors.First(err)
+		}
 		respondError(w, r, err)
 		return
 	}
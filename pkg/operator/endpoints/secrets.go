@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/gorilla/mux"
+)
+
+func SetSecret(w http.ResponseWriter, r *http.Request) {
+	secretName := mux.Vars(r)["secretName"]
+
+	// secrets are small, k8s limits them to 1MiB
+	rw := http.MaxBytesReader(w, r.Body, 1<<20)
+
+	bodyBytes, err := ioutil.ReadAll(rw)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	data := map[string]string{}
+	if err := json.Unmarshal(bodyBytes, &data); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := resources.SetSecret(secretName, data)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respond(w, response)
+}
+
+func ListSecrets(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.ListSecrets()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respond(w, response)
+}
+
+func DeleteSecret(w http.ResponseWriter, r *http.Request) {
+	secretName := mux.Vars(r)["secretName"]
+	key := getOptionalQParam("key", r)
+	force := getOptionalBoolQParam("force", false, r)
+
+	response, err := resources.DeleteSecret(secretName, key, force)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respond(w, response)
+}
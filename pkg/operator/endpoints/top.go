@@ -0,0 +1,164 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/gorilla/mux"
+	kcore "k8s.io/api/core/v1"
+)
+
+// Top reports the resource usage of the replicas of all apis (or of a specific api), and of the nodes which run them
+func Top(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if apiName != "" {
+		deployedResource, err := resources.GetDeployedResourceByName(apiName)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		if deployedResource.Kind == userconfig.TrafficSplitterKind {
+			respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind, userconfig.BatchAPIKind))
+			return
+		}
+	}
+
+	var pods []kcore.Pod
+	var nodes []kcore.Node
+	var podUsages, nodeUsages map[string]k8s.ResourceUsage
+
+	err := parallel.RunFirstErr(
+		func() error {
+			var err error
+			if apiName == "" {
+				pods, err = config.K8s.ListPodsWithLabelKeys("apiName")
+			} else {
+				pods, err = config.K8s.ListPodsByLabel("apiName", apiName)
+			}
+			return err
+		},
+		func() error {
+			var err error
+			nodes, err = config.K8sAllNamspaces.ListNodesByLabel("workload", "true")
+			return err
+		},
+		func() error {
+			var err error
+			if apiName == "" {
+				podUsages, err = config.K8s.ListPodMetricsWithLabelKeys("apiName")
+			} else {
+				podUsages, err = config.K8s.ListPodMetricsByLabel("apiName", apiName)
+			}
+			return err
+		},
+		func() error {
+			var err error
+			nodeUsages, err = config.K8sAllNamspaces.ListNodeMetrics()
+			return err
+		},
+	)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	replicaUsages := make([]schema.ReplicaUsage, 0, len(pods))
+	numReplicasPerNode := map[string]int{}
+	for i := range pods {
+		pod := &pods[i]
+		replicaUsages = append(replicaUsages, replicaUsage(pod, podUsages))
+		if pod.Spec.NodeName != "" {
+			numReplicasPerNode[pod.Spec.NodeName]++
+		}
+	}
+
+	sort.Slice(replicaUsages, func(i, j int) bool {
+		if replicaUsages[i].APIName != replicaUsages[j].APIName {
+			return replicaUsages[i].APIName < replicaUsages[j].APIName
+		}
+		return replicaUsages[i].Name < replicaUsages[j].Name
+	})
+
+	nodeUsageList := []schema.NodeUsage{}
+	for i := range nodes {
+		node := &nodes[i]
+
+		// when reporting on a specific api, only the nodes which run its replicas are relevant
+		if apiName != "" && numReplicasPerNode[node.Name] == 0 {
+			continue
+		}
+
+		nodeUsage := schema.NodeUsage{
+			Name:         node.Name,
+			InstanceType: node.Labels["beta.kubernetes.io/instance-type"],
+			IsSpot:       k8s.IsSpotNode(node),
+			NumReplicas:  numReplicasPerNode[node.Name],
+			Allocatable:  nodeComputeAllocatable(node),
+		}
+		if usage, ok := nodeUsages[node.Name]; ok {
+			nodeUsage.Usage = &schema.ResourceUsage{CPU: *k8s.WrapQuantity(usage.CPU), Mem: *k8s.WrapQuantity(usage.Mem)}
+		}
+		nodeUsageList = append(nodeUsageList, nodeUsage)
+	}
+
+	sort.Slice(nodeUsageList, func(i, j int) bool {
+		return nodeUsageList[i].Name < nodeUsageList[j].Name
+	})
+
+	respond(w, schema.TopResponse{
+		Replicas: replicaUsages,
+		Nodes:    nodeUsageList,
+	})
+}
+
+func replicaUsage(pod *kcore.Pod, podUsages map[string]k8s.ResourceUsage) schema.ReplicaUsage {
+	cpu, mem, gpu, inf := k8s.TotalPodCompute(&pod.Spec)
+
+	replicaUsage := schema.ReplicaUsage{
+		APIName:  pod.Labels["apiName"],
+		Name:     pod.Name,
+		NodeName: pod.Spec.NodeName,
+		Requested: userconfig.Compute{
+			CPU: &cpu,
+			Mem: &mem,
+			GPU: gpu,
+			Inf: inf,
+		},
+	}
+
+	if usage, ok := podUsages[pod.Name]; ok {
+		replicaUsage.Usage = &schema.ResourceUsage{CPU: *k8s.WrapQuantity(usage.CPU), Mem: *k8s.WrapQuantity(usage.Mem)}
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		replicaUsage.Restarts += containerStatus.RestartCount
+		if terminated := containerStatus.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			replicaUsage.OOMKilled = true
+		}
+	}
+
+	return replicaUsage
+}
@@ -45,6 +45,7 @@ func main() {
 	telemetry.Event("operator.init", map[string]interface{}{"provider": config.Provider})
 
 	cron.Run(operator.DeleteEvictedPods, operator.ErrorHandler("delete evicted pods"), 12*time.Hour)
+	cron.Run(realtimeapi.ManageRollouts, operator.ErrorHandler("manage rollouts"), realtimeapi.ManageRolloutsCronPeriod)
 
 	switch config.Provider {
 	case types.AWSProviderType:
@@ -65,7 +66,7 @@ func main() {
 		}
 
 		for _, deployment := range deployments {
-			if userconfig.KindFromString(deployment.Labels["apiKind"]) == userconfig.RealtimeAPIKind {
+			if userconfig.KindFromString(deployment.Labels["apiKind"]) == userconfig.RealtimeAPIKind && realtimeapi.IsActiveDeployment(&deployment) {
 				if err := realtimeapi.UpdateAutoscalerCron(&deployment); err != nil {
 					exit.Error(errors.Wrap(err, "init"))
 				}
@@ -75,6 +76,10 @@ func main() {
 		cron.Run(batchapi.ManageJobResources, operator.ErrorHandler("manage jobs"), batchapi.ManageJobResourcesCronPeriod)
 	}
 
+	if err := operator.WatchEvents(); err != nil {
+		exit.Error(errors.Wrap(err, "init"))
+	}
+
 	router := mux.NewRouter()
 
 	routerWithoutAuth := router.NewRoute().Subrouter()
@@ -103,6 +108,17 @@ func main() {
 	routerWithAuth.HandleFunc("/get/{apiName}", endpoints.GetAPI).Methods("GET")
 	routerWithAuth.HandleFunc("/get/{apiName}/{apiID}", endpoints.GetAPIByID).Methods("GET")
 	routerWithAuth.HandleFunc("/logs/{apiName}", endpoints.ReadLogs)
+	routerWithAuth.HandleFunc("/logs/{apiName}/replicas", endpoints.GetReplicaLogs).Methods("GET")
+	routerWithAuth.HandleFunc("/logs/{apiName}/search", endpoints.SearchLogs).Methods("GET")
+	routerWithAuth.HandleFunc("/top", endpoints.Top).Methods("GET")
+	routerWithAuth.HandleFunc("/top/{apiName}", endpoints.Top).Methods("GET")
+	routerWithAuth.HandleFunc("/exec/{apiName}", endpoints.Exec)
+	routerWithAuth.HandleFunc("/port-forward/{apiName}", endpoints.PortForward)
+	routerWithAuth.HandleFunc("/events", endpoints.ReadEvents)
+	routerWithAuth.HandleFunc("/events/{apiName}", endpoints.ReadEvents)
+	routerWithAuth.HandleFunc("/secrets", endpoints.ListSecrets).Methods("GET")
+	routerWithAuth.HandleFunc("/secrets/{secretName}", endpoints.SetSecret).Methods("POST")
+	routerWithAuth.HandleFunc("/secrets/{secretName}", endpoints.DeleteSecret).Methods("DELETE")
 
 	log.Print("Running on port " + _operatorPortStr)
 	log.Fatal(http.ListenAndServe(":"+_operatorPortStr, router))
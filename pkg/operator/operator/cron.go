@@ -17,8 +17,6 @@ limitations under the License.
 package operator
 
 import (
-	"strings"
-
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
@@ -53,10 +51,12 @@ func DeleteEvictedPods() error {
 }
 
 type instanceInfo struct {
+	NodeGroup     string  `json:"node_group" yaml:"node_group"` // currently only used in AWS
 	InstanceType  string  `json:"instance_type" yaml:"instance_type"`
 	IsSpot        bool    `json:"is_spot" yaml:"is_spot"`
 	Price         float64 `json:"price" yaml:"price"`
 	OnDemandPrice float64 `json:"on_demand_price" yaml:"on_demand_price"`
+	EBSPrice      float64 `json:"ebs_price" yaml:"ebs_price"` // currently only used in AWS
 	Count         int32   `json:"count" yaml:"count"`
 	Memory        int64   `json:"memory" yaml:"memory"`
 	CPU           float64 `json:"cpu" yaml:"cpu"`
@@ -84,16 +84,15 @@ func InstanceTelemetryAWS() error {
 			instanceType = "unknown"
 		}
 
-		isSpot := false
-		if strings.Contains(strings.ToLower(node.Labels["lifecycle"]), "spot") {
-			isSpot = true
-		}
+		isSpot := k8s.IsSpotNode(&node)
+
+		nodeGroup := NodeGroupForNode(&node)
 
 		totalInstances++
 
-		instanceInfosKey := instanceType + "_ondemand"
+		instanceInfosKey := nodeGroup.Name + "_" + instanceType + "_ondemand"
 		if isSpot {
-			instanceInfosKey = instanceType + "_spot"
+			instanceInfosKey = nodeGroup.Name + "_" + instanceType + "_spot"
 		}
 
 		if info, ok := instanceInfos[instanceInfosKey]; ok {
@@ -120,10 +119,12 @@ func InstanceTelemetryAWS() error {
 		}
 
 		info := instanceInfo{
+			NodeGroup:     nodeGroup.Name,
 			InstanceType:  instanceType,
 			IsSpot:        isSpot,
 			Price:         price,
 			OnDemandPrice: onDemandPrice,
+			EBSPrice:      nodeGroup.EBSPrice(*config.Cluster.Region),
 			Count:         1,
 			Memory:        node.Status.Capacity.Memory().Value(),
 			CPU:           float64(node.Status.Capacity.Cpu().MilliValue()) / 1000,
@@ -135,16 +136,11 @@ func InstanceTelemetryAWS() error {
 		instanceInfos[instanceInfosKey] = &info
 	}
 
-	apiEBSPrice := aws.EBSMetadatas[*config.Cluster.Region][config.Cluster.InstanceVolumeType.String()].PriceGB * float64(config.Cluster.InstanceVolumeSize) / 30 / 24
-	if config.Cluster.InstanceVolumeType.String() == "io1" && config.Cluster.InstanceVolumeIOPS != nil {
-		apiEBSPrice += aws.EBSMetadatas[*config.Cluster.Region][config.Cluster.InstanceVolumeType.String()].PriceIOPS * float64(*config.Cluster.InstanceVolumeIOPS) / 30 / 24
-	}
-
 	var totalInstancePrice float64
 	var totalInstancePriceIfOnDemand float64
 	for _, info := range instanceInfos {
-		totalInstancePrice += (info.Price + apiEBSPrice) * float64(info.Count)
-		totalInstancePriceIfOnDemand += (info.OnDemandPrice + apiEBSPrice) * float64(info.Count)
+		totalInstancePrice += (info.Price + info.EBSPrice) * float64(info.Count)
+		totalInstancePriceIfOnDemand += (info.OnDemandPrice + info.EBSPrice) * float64(info.Count)
 	}
 
 	fixedPrice := clusterFixedPriceAWS()
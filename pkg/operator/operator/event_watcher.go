@@ -0,0 +1,263 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+	kcache "k8s.io/client-go/tools/cache"
+)
+
+const _eventInformerResyncPeriod = 0 // resyncs would only replay the current state, which doesn't produce any events
+
+var (
+	_eventWatcherStartTime time.Time
+	_updatingAPIsMutex     sync.Mutex
+	_updatingAPIs          = map[string]bool{} // api name -> whether a rolling update is in progress
+)
+
+// WatchEvents publishes events derived from changes to the deployments and pods of all apis
+func WatchEvents() error {
+	_eventWatcherStartTime = time.Now().Truncate(time.Second)
+	informerFactory := config.K8s.NewInformerFactory(_eventInformerResyncPeriod, "apiName")
+
+	deploymentInformer := informerFactory.Apps().V1().Deployments().Informer()
+	deploymentInformer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+		AddFunc:    onDeploymentAdd,
+		UpdateFunc: onDeploymentUpdate,
+		DeleteFunc: onDeploymentDelete,
+	})
+
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	podInformer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+		UpdateFunc: onPodUpdate,
+	})
+
+	informerFactory.Start(make(chan struct{})) // the informers run for the lifetime of the operator
+
+	if !kcache.WaitForCacheSync(make(chan struct{}), deploymentInformer.HasSynced, podInformer.HasSynced) {
+		return errors.ErrorUnexpected("unable to sync the event informers")
+	}
+
+	return nil
+}
+
+func onDeploymentAdd(obj interface{}) {
+	deployment, ok := obj.(*kapps.Deployment)
+	if !ok {
+		return
+	}
+
+	// existing deployments are reported as additions during the initial sync
+	if deployment.CreationTimestamp.Time.Before(_eventWatcherStartTime) || isInactiveDeployment(deployment) {
+		return
+	}
+
+	setAPIUpdating(deployment.Labels["apiName"], true)
+
+	PublishEvent(events.Event{
+		Type:    events.APIDeployed,
+		APIName: deployment.Labels["apiName"],
+		APIKind: userconfig.KindFromString(deployment.Labels["apiKind"]),
+		Message: fmt.Sprintf("deployed with %d requested %s", requestedReplicas(deployment), s.PluralS("replica", requestedReplicas(deployment))),
+	})
+}
+
+func onDeploymentUpdate(oldObj interface{}, newObj interface{}) {
+	prevDeployment, ok := oldObj.(*kapps.Deployment)
+	if !ok {
+		return
+	}
+	deployment, ok := newObj.(*kapps.Deployment)
+	if !ok {
+		return
+	}
+
+	// blue/green updates publish their own events
+	if isInactiveDeployment(prevDeployment) || isInactiveDeployment(deployment) {
+		return
+	}
+
+	apiName := deployment.Labels["apiName"]
+	apiKind := userconfig.KindFromString(deployment.Labels["apiKind"])
+
+	prevTemplateLabels := prevDeployment.Spec.Template.Labels
+	templateLabels := deployment.Spec.Template.Labels
+	if prevTemplateLabels["deploymentID"] != templateLabels["deploymentID"] || prevTemplateLabels["predictorID"] != templateLabels["predictorID"] {
+		setAPIUpdating(apiName, true)
+		PublishEvent(events.Event{
+			Type:    events.UpdateStarted,
+			APIName: apiName,
+			APIKind: apiKind,
+			Message: fmt.Sprintf("rolling update started (%d requested %s)", requestedReplicas(deployment), s.PluralS("replica", requestedReplicas(deployment))),
+		})
+		return
+	}
+
+	if isAPIUpdating(apiName) && isRolloutComplete(deployment) {
+		setAPIUpdating(apiName, false)
+		PublishEvent(events.Event{
+			Type:    events.UpdateFinished,
+			APIName: apiName,
+			APIKind: apiKind,
+			Message: fmt.Sprintf("rolling update finished (%d ready %s)", deployment.Status.ReadyReplicas, s.PluralS("replica", deployment.Status.ReadyReplicas)),
+		})
+	}
+}
+
+func onDeploymentDelete(obj interface{}) {
+	if tombstone, ok := obj.(kcache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	deployment, ok := obj.(*kapps.Deployment)
+	if !ok || isInactiveDeployment(deployment) {
+		return
+	}
+
+	setAPIUpdating(deployment.Labels["apiName"], false)
+
+	PublishEvent(events.Event{
+		Type:    events.APIDeleted,
+		APIName: deployment.Labels["apiName"],
+		APIKind: userconfig.KindFromString(deployment.Labels["apiKind"]),
+		Message: "deleted",
+	})
+}
+
+func onPodUpdate(oldObj interface{}, newObj interface{}) {
+	prevPod, ok := oldObj.(*kcore.Pod)
+	if !ok {
+		return
+	}
+	pod, ok := newObj.(*kcore.Pod)
+	if !ok {
+		return
+	}
+
+	event := events.Event{
+		APIName: pod.Labels["apiName"],
+		APIKind: userconfig.KindFromString(pod.Labels["apiKind"]),
+		Replica: pod.Name,
+		JobID:   pod.Labels["jobID"],
+	}
+
+	if !k8s.IsPodReady(prevPod) && k8s.IsPodReady(pod) {
+		event.Type = events.ReplicaReady
+		event.Message = fmt.Sprintf("replica %s is ready", pod.Name)
+		PublishEvent(event)
+		return
+	}
+
+	prevContainerStatuses := map[string]kcore.ContainerStatus{}
+	for _, containerStatus := range prevPod.Status.ContainerStatuses {
+		prevContainerStatuses[containerStatus.Name] = containerStatus
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		prevContainerStatus := prevContainerStatuses[containerStatus.Name]
+
+		var terminated *kcore.ContainerStateTerminated
+		if containerStatus.RestartCount > prevContainerStatus.RestartCount {
+			terminated = containerStatus.LastTerminationState.Terminated
+		} else if containerStatus.State.Terminated != nil && prevContainerStatus.State.Terminated == nil {
+			terminated = containerStatus.State.Terminated
+		}
+
+		if terminated != nil {
+			if strings.Contains(strings.ToLower(terminated.Reason), "oom") {
+				event.Type = events.ReplicaOOM
+				event.Message = fmt.Sprintf("container %s in replica %s was killed because it ran out of memory", containerStatus.Name, pod.Name)
+				PublishEvent(event)
+			} else if terminated.ExitCode != 0 {
+				event.Type = events.ReplicaFailed
+				event.Message = fmt.Sprintf("container %s in replica %s exited with code %d (%s)", containerStatus.Name, pod.Name, terminated.ExitCode, terminated.Reason)
+				PublishEvent(event)
+			}
+			continue
+		}
+
+		if isImagePullError(containerStatus.State) && !isImagePullError(prevContainerStatus.State) {
+			event.Type = events.ReplicaFailed
+			event.Message = fmt.Sprintf("container %s in replica %s is unable to pull its image (%s)", containerStatus.Name, pod.Name, containerStatus.State.Waiting.Reason)
+			PublishEvent(event)
+		}
+	}
+}
+
+func isRolloutComplete(deployment *kapps.Deployment) bool {
+	requested := requestedReplicas(deployment)
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == requested &&
+		deployment.Status.Replicas == requested &&
+		deployment.Status.ReadyReplicas == requested
+}
+
+// inactive deployments are blue/green candidates, or previous versions which are waiting to be torn down
+func isInactiveDeployment(deployment *kapps.Deployment) bool {
+	return deployment.Labels["active"] == "false"
+}
+
+func isImagePullError(state kcore.ContainerState) bool {
+	return state.Waiting != nil && (state.Waiting.Reason == "ErrImagePull" || state.Waiting.Reason == "ImagePullBackOff")
+}
+
+func requestedReplicas(deployment *kapps.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+func isAPIUpdating(apiName string) bool {
+	_updatingAPIsMutex.Lock()
+	defer _updatingAPIsMutex.Unlock()
+	return _updatingAPIs[apiName]
+}
+
+func setAPIUpdating(apiName string, updating bool) {
+	_updatingAPIsMutex.Lock()
+	defer _updatingAPIsMutex.Unlock()
+	if updating {
+		_updatingAPIs[apiName] = true
+	} else {
+		delete(_updatingAPIs, apiName)
+	}
+}
+
+// PublishAutoscalingEvent publishes an event for a change in an api's requested replicas which was made by the autoscaler
+func PublishAutoscalingEvent(apiName string, oldReplicas int32, newReplicas int32) {
+	PublishEvent(events.Event{
+		Type:        events.Autoscaling,
+		APIName:     apiName,
+		APIKind:     userconfig.RealtimeAPIKind,
+		Message:     fmt.Sprintf("autoscaling from %d to %d %s", oldReplicas, newReplicas, s.PluralS("replica", newReplicas)),
+		OldReplicas: pointer.Int32(oldReplicas),
+		NewReplicas: pointer.Int32(newReplicas),
+	})
+}
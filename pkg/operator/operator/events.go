@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/events"
+)
+
+const (
+	_maxRecentEvents        = 500
+	_eventSubscriberBufSize = 100
+)
+
+type eventSubscriber struct {
+	apiName string // if empty, all events are sent
+	events  chan events.Event
+}
+
+var (
+	_eventsMutex      sync.Mutex
+	_recentEvents     []events.Event
+	_eventSubscribers = map[*eventSubscriber]struct{}{}
+)
+
+// PublishEvent records the event and sends it to all subscribers (subscribers which aren't keeping up will miss events)
+func PublishEvent(event events.Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	_eventsMutex.Lock()
+	defer _eventsMutex.Unlock()
+
+	_recentEvents = append(_recentEvents, event)
+	if len(_recentEvents) > _maxRecentEvents {
+		_recentEvents = _recentEvents[len(_recentEvents)-_maxRecentEvents:]
+	}
+
+	for subscriber := range _eventSubscribers {
+		if subscriber.apiName != "" && subscriber.apiName != event.APIName {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+		}
+	}
+}
+
+// SubscribeToEvents returns the most recent events (oldest first), a channel of new events, and a function to unsubscribe
+// if apiName is empty, events for all apis are returned
+func SubscribeToEvents(apiName string) ([]events.Event, <-chan events.Event, func()) {
+	subscriber := &eventSubscriber{
+		apiName: apiName,
+		events:  make(chan events.Event, _eventSubscriberBufSize),
+	}
+
+	_eventsMutex.Lock()
+	defer _eventsMutex.Unlock()
+
+	var recentEvents []events.Event
+	for _, event := range _recentEvents {
+		if apiName == "" || apiName == event.APIName {
+			recentEvents = append(recentEvents, event)
+		}
+	}
+
+	_eventSubscribers[subscriber] = struct{}{}
+
+	unsubscribe := func() {
+		_eventsMutex.Lock()
+		defer _eventsMutex.Unlock()
+		delete(_eventSubscribers, subscriber)
+	}
+
+	return recentEvents, subscriber.events, unsubscribe
+}
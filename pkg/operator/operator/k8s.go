@@ -31,6 +31,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
@@ -347,6 +348,21 @@ func getEnvVars(api *spec.API, container string) []kcore.EnvVar {
 	}
 
 	for name, val := range api.Predictor.Env {
+		if ref, ok, err := userconfig.ParseSecretRef(val); ok && err == nil {
+			envVars = append(envVars, kcore.EnvVar{
+				Name: name,
+				ValueFrom: &kcore.EnvVarSource{
+					SecretKeyRef: &kcore.SecretKeySelector{
+						LocalObjectReference: kcore.LocalObjectReference{
+							Name: ref.Name,
+						},
+						Key: ref.Key,
+					},
+				},
+			})
+			continue
+		}
+
 		envVars = append(envVars, kcore.EnvVar{
 			Name:  name,
 			Value: val,
@@ -859,6 +875,24 @@ var Tolerations = []kcore.Toleration{
 	},
 }
 
+func NodeSelector(api *spec.API) map[string]string {
+	nodeSelector := map[string]string{
+		"workload": "true",
+	}
+	if api.Compute != nil && api.Compute.NodeGroup != nil {
+		nodeSelector[clusterconfig.NodeGroupLabelKey] = *api.Compute.NodeGroup
+	}
+	return nodeSelector
+}
+
+// Returns the node group of a worker node (nodes which are not labeled with a node group belong to the primary node group)
+func NodeGroupForNode(node *kcore.Node) *clusterconfig.NodeGroup {
+	if nodeGroup, ok := config.Cluster.GetNodeGroup(node.Labels[clusterconfig.NodeGroupLabelKey]); ok {
+		return nodeGroup
+	}
+	return config.Cluster.PrimaryNodeGroup()
+}
+
 func K8sName(apiName string) string {
 	return "api-" + apiName
 }
@@ -19,9 +19,11 @@ package operator
 import (
 	"math"
 
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	kresource "k8s.io/apimachinery/pkg/api/resource"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
@@ -30,11 +32,25 @@ import (
 const _memConfigMapName = "cortex-instance-memory"
 const _memConfigMapKey = "capacity"
 
-func getMemoryCapacityFromNodes() (*kresource.Quantity, error) {
+// The config map key which stores the memory capacity of a node group (the primary node group uses the original key)
+func memConfigMapKey(nodeGroupName string) string {
+	if nodeGroupName == clusterconfig.PrimaryNodeGroupName {
+		return _memConfigMapKey
+	}
+	return _memConfigMapKey + "-" + nodeGroupName
+}
+
+func getMemoryCapacityFromNodes(nodeGroupName string) (*kresource.Quantity, error) {
+	labels := map[string]string{
+		"workload": "true",
+	}
+	// nodes in clusters without additional node groups may have been created before the node group label was introduced
+	if config.Provider == types.AWSProviderType && len(config.Cluster.NodeGroups) > 0 {
+		labels[clusterconfig.NodeGroupLabelKey] = nodeGroupName
+	}
+
 	opts := kmeta.ListOptions{
-		LabelSelector: klabels.SelectorFromSet(map[string]string{
-			"workload": "true",
-		}).String(),
+		LabelSelector: klabels.SelectorFromSet(labels).String(),
 	}
 	nodes, err := config.K8s.ListNodes(&opts)
 	if err != nil {
@@ -57,17 +73,11 @@ func getMemoryCapacityFromNodes() (*kresource.Quantity, error) {
 	return minMem, nil
 }
 
-func getMemoryCapacityFromConfigMap() (*kresource.Quantity, error) {
-	configMapData, err := config.K8s.GetConfigMapData(_memConfigMapName)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(configMapData) == 0 {
+func parseMemoryCapacity(memoryUserStr string) (*kresource.Quantity, error) {
+	if memoryUserStr == "" {
 		return nil, nil
 	}
 
-	memoryUserStr := configMapData[_memConfigMapKey]
 	mem, err := kresource.ParseQuantity(memoryUserStr)
 	if err != nil {
 		return nil, err
@@ -78,43 +88,68 @@ func getMemoryCapacityFromConfigMap() (*kresource.Quantity, error) {
 	return &mem, nil
 }
 
-func UpdateMemoryCapacityConfigMap() (kresource.Quantity, error) {
-	minMem := *kresource.NewQuantity(math.MaxInt64, kresource.DecimalSI)
+// Returns the memory capacity of each node group's instances, keyed by node group name
+func UpdateMemoryCapacityConfigMap() (map[string]kresource.Quantity, error) {
+	instanceMems := map[string]kresource.Quantity{
+		clusterconfig.PrimaryNodeGroupName: *kresource.NewQuantity(math.MaxInt64, kresource.DecimalSI),
+	}
 	if config.Provider == types.AWSProviderType {
-		minMem = config.Cluster.InstanceMetadata.Memory
+		for _, nodeGroup := range config.Cluster.AllNodeGroups() {
+			instanceMems[nodeGroup.Name] = aws.InstanceMetadatas[*config.Cluster.Region][nodeGroup.InstanceType].Memory
+		}
 	}
 
-	nodeMemCapacity, err := getMemoryCapacityFromNodes()
+	configMapData, err := config.K8s.GetConfigMapData(_memConfigMapName)
 	if err != nil {
-		return kresource.Quantity{}, err
+		return nil, err
 	}
 
-	previousMinMem, err := getMemoryCapacityFromConfigMap()
-	if err != nil {
-		return kresource.Quantity{}, err
+	updatedConfigMapData := map[string]string{}
+	for key, value := range configMapData {
+		updatedConfigMapData[key] = value
 	}
 
-	if nodeMemCapacity != nil && minMem.Cmp(*nodeMemCapacity) > 0 {
-		minMem = *nodeMemCapacity
-	}
+	shouldUpdateConfigMap := false
+	memCapacities := map[string]kresource.Quantity{}
+
+	for nodeGroupName, minMem := range instanceMems {
+		nodeMemCapacity, err := getMemoryCapacityFromNodes(nodeGroupName)
+		if err != nil {
+			return nil, err
+		}
+
+		previousMinMem, err := parseMemoryCapacity(configMapData[memConfigMapKey(nodeGroupName)])
+		if err != nil {
+			return nil, err
+		}
 
-	if previousMinMem != nil && minMem.Cmp(*previousMinMem) > 0 {
-		minMem = *previousMinMem
+		if nodeMemCapacity != nil && minMem.Cmp(*nodeMemCapacity) > 0 {
+			minMem = *nodeMemCapacity
+		}
+
+		if previousMinMem != nil && minMem.Cmp(*previousMinMem) > 0 {
+			minMem = *previousMinMem
+		}
+
+		if previousMinMem == nil || minMem.Cmp(*previousMinMem) < 0 {
+			updatedConfigMapData[memConfigMapKey(nodeGroupName)] = minMem.String()
+			shouldUpdateConfigMap = true
+		}
+
+		memCapacities[nodeGroupName] = minMem
 	}
 
-	if previousMinMem == nil || minMem.Cmp(*previousMinMem) < 0 {
+	if shouldUpdateConfigMap {
 		configMap := k8s.ConfigMap(&k8s.ConfigMapSpec{
 			Name: _memConfigMapName,
-			Data: map[string]string{
-				_memConfigMapKey: minMem.String(),
-			},
+			Data: updatedConfigMapData,
 		})
 
 		_, err := config.K8s.ApplyConfigMap(configMap)
 		if err != nil {
-			return kresource.Quantity{}, err
+			return nil, err
 		}
 	}
 
-	return minMem, nil
+	return memCapacities, nil
 }
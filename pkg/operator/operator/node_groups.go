@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+/*
+CPU Reservations:
+
+FluentD 200
+StatsD 100
+KubeProxy 100
+AWS cni 10
+Reserved (150 + 150) see eks.yaml for details
+*/
+var _cortexCPUReserve = kresource.MustParse("710m")
+
+/*
+Memory Reservations:
+
+FluentD 200
+StatsD 100
+Reserved (300 + 300 + 200) see eks.yaml for details
+*/
+var _cortexMemReserve = kresource.MustParse("1100Mi")
+
+var _nvidiaCPUReserve = kresource.MustParse("100m")
+var _nvidiaMemReserve = kresource.MustParse("100Mi")
+
+var _inferentiaCPUReserve = kresource.MustParse("100m")
+var _inferentiaMemReserve = kresource.MustParse("100Mi")
+
+// The compute resources which are available to apis on each instance of a node group
+type NodeCapacity struct {
+	CPU kresource.Quantity
+	Mem kresource.Quantity
+	GPU int64
+	Inf int64
+}
+
+// maxMem is the memory capacity of the node group's instances (as returned by UpdateMemoryCapacityConfigMap())
+func NodeGroupCapacity(nodeGroup *clusterconfig.NodeGroup, region string, maxMem kresource.Quantity) NodeCapacity {
+	instanceMetadata := aws.InstanceMetadatas[region][nodeGroup.InstanceType]
+
+	maxMem.Sub(_cortexMemReserve)
+
+	maxCPU := instanceMetadata.CPU
+	maxCPU.Sub(_cortexCPUReserve)
+
+	maxGPU := instanceMetadata.GPU
+	if maxGPU > 0 {
+		// Reserve resources for nvidia device plugin daemonset
+		maxCPU.Sub(_nvidiaCPUReserve)
+		maxMem.Sub(_nvidiaMemReserve)
+	}
+
+	maxInf := instanceMetadata.Inf
+	if maxInf > 0 {
+		// Reserve resources for inferentia device plugin daemonset
+		maxCPU.Sub(_inferentiaCPUReserve)
+		maxMem.Sub(_inferentiaMemReserve)
+	}
+
+	return NodeCapacity{CPU: maxCPU, Mem: maxMem, GPU: maxGPU, Inf: maxInf}
+}
+
+func (capacity NodeCapacity) CanSatisfy(compute *userconfig.Compute) bool {
+	if compute.CPU != nil && capacity.CPU.Cmp(compute.CPU.Quantity) < 0 {
+		return false
+	}
+	if compute.Mem != nil && capacity.Mem.Cmp(compute.Mem.Quantity) < 0 {
+		return false
+	}
+	return compute.GPU <= capacity.GPU && compute.Inf <= capacity.Inf
+}
+
+// SetNodeGroup sets the node group of apis which don't specify one, so that they are scheduled on the node group with the
+// cheapest instances which can satisfy their compute; this must be called before the api spec is built (since the node group
+// is part of the spec). Apis in clusters without additional node groups can be scheduled on any worker node, so they are not modified
+func SetNodeGroup(apiConfig *userconfig.API) error {
+	if config.Provider != types.AWSProviderType || len(config.Cluster.NodeGroups) == 0 {
+		return nil
+	}
+	if apiConfig.Compute == nil || apiConfig.Compute.NodeGroup != nil {
+		return nil
+	}
+
+	maxMems, err := UpdateMemoryCapacityConfigMap()
+	if err != nil {
+		return err
+	}
+
+	nodeGroup := cheapestNodeGroup(apiConfig.Compute, config.Cluster.AllNodeGroups(), *config.Cluster.Region, maxMems, nodeGroupInstancePrice)
+	if nodeGroup == nil {
+		return errors.ErrorUnexpected("no node group can satisfy the requested compute", apiConfig.Name) // this is checked during validation
+	}
+
+	apiConfig.Compute.NodeGroup = pointer.String(nodeGroup.Name)
+	return nil
+}
+
+// returns nil if none of the node groups can satisfy the compute
+func cheapestNodeGroup(
+	compute *userconfig.Compute,
+	nodeGroups []*clusterconfig.NodeGroup,
+	region string,
+	maxMems map[string]kresource.Quantity,
+	instancePrice func(nodeGroup *clusterconfig.NodeGroup) float64,
+) *clusterconfig.NodeGroup {
+
+	var cheapest *clusterconfig.NodeGroup
+	var cheapestPrice float64
+	for _, nodeGroup := range nodeGroups {
+		if !NodeGroupCapacity(nodeGroup, region, maxMems[nodeGroup.Name]).CanSatisfy(compute) {
+			continue
+		}
+
+		price := instancePrice(nodeGroup)
+		if cheapest == nil || price < cheapestPrice {
+			cheapest = nodeGroup
+			cheapestPrice = price
+		}
+	}
+
+	return cheapest
+}
+
+// The hourly price which the node group pays for each of its instances; for spot node groups, this is the spot price
+// weighted by the percentage of on-demand instances above the on-demand base capacity
+func nodeGroupInstancePrice(nodeGroup *clusterconfig.NodeGroup) float64 {
+	onDemandPrice := aws.InstanceMetadatas[*config.Cluster.Region][nodeGroup.InstanceType].Price
+	if !nodeGroup.Spot {
+		return onDemandPrice
+	}
+
+	spotPrice, err := config.AWS.SpotInstancePrice(*config.Cluster.Region, nodeGroup.InstanceType)
+	if err != nil || spotPrice == 0 {
+		return onDemandPrice
+	}
+
+	return spotNodeGroupInstancePrice(nodeGroup.SpotConfig, onDemandPrice, spotPrice)
+}
+
+func spotNodeGroupInstancePrice(spotConfig *clusterconfig.SpotConfig, onDemandPrice float64, spotPrice float64) float64 {
+	onDemandFraction := 0.0
+	if spotConfig != nil && spotConfig.OnDemandPercentageAboveBaseCapacity != nil {
+		onDemandFraction = float64(*spotConfig.OnDemandPercentageAboveBaseCapacity) / 100
+	}
+
+	return onDemandFraction*onDemandPrice + (1-onDemandFraction)*spotPrice
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testCompute(cpu string, mem string, gpu int64, inf int64) *userconfig.Compute {
+	compute := &userconfig.Compute{GPU: gpu, Inf: inf}
+	if cpu != "" {
+		compute.CPU = k8s.WrapQuantity(kresource.MustParse(cpu))
+	}
+	if mem != "" {
+		compute.Mem = k8s.WrapQuantity(kresource.MustParse(mem))
+	}
+	return compute
+}
+
+func TestNodeGroupCapacity(t *testing.T) {
+	cpuNodeGroup := &clusterconfig.NodeGroup{Name: "cpu", InstanceType: "m5.large"}
+	capacity := NodeGroupCapacity(cpuNodeGroup, "us-west-2", kresource.MustParse("7Gi"))
+	require.Equal(t, int64(1290), capacity.CPU.MilliValue()) // 2 - 710m
+	expectedMem := kresource.MustParse("6068Mi")             // 7Gi - 1100Mi
+	require.Equal(t, expectedMem.Value(), capacity.Mem.Value())
+	require.Equal(t, int64(0), capacity.GPU)
+	require.Equal(t, int64(0), capacity.Inf)
+
+	require.True(t, capacity.CanSatisfy(testCompute("1", "5Gi", 0, 0)))
+	require.True(t, capacity.CanSatisfy(testCompute("", "", 0, 0)))
+	require.False(t, capacity.CanSatisfy(testCompute("1500m", "", 0, 0)))
+	require.False(t, capacity.CanSatisfy(testCompute("", "6Gi", 1, 0)))
+	require.False(t, capacity.CanSatisfy(testCompute("", "7Gi", 0, 0)))
+
+	gpuNodeGroup := &clusterconfig.NodeGroup{Name: "gpu", InstanceType: "g4dn.xlarge"}
+	capacity = NodeGroupCapacity(gpuNodeGroup, "us-west-2", kresource.MustParse("15Gi"))
+	require.Equal(t, int64(3190), capacity.CPU.MilliValue()) // 4 - 710m - 100m
+	require.Equal(t, int64(1), capacity.GPU)
+	require.True(t, capacity.CanSatisfy(testCompute("3", "12Gi", 1, 0)))
+	require.False(t, capacity.CanSatisfy(testCompute("1", "1Gi", 2, 0)))
+}
+
+func TestCheapestNodeGroup(t *testing.T) {
+	nodeGroups := []*clusterconfig.NodeGroup{
+		{Name: clusterconfig.PrimaryNodeGroupName, InstanceType: "m5.xlarge"},
+		{Name: "small", InstanceType: "m5.large"},
+		{Name: "gpu", InstanceType: "g4dn.xlarge"},
+		{Name: "large", InstanceType: "m5.2xlarge"},
+	}
+	maxMems := map[string]kresource.Quantity{
+		clusterconfig.PrimaryNodeGroupName: kresource.MustParse("15Gi"),
+		"small":                            kresource.MustParse("7Gi"),
+		"gpu":                              kresource.MustParse("15Gi"),
+		"large":                            kresource.MustParse("31Gi"),
+	}
+	onDemandPrices := map[string]float64{
+		clusterconfig.PrimaryNodeGroupName: 0.192,
+		"small":                            0.096,
+		"gpu":                              0.526,
+		"large":                            0.384,
+	}
+	// the primary node group is spot, so its effective price is lower than the small node group's
+	spotPrices := map[string]float64{
+		clusterconfig.PrimaryNodeGroupName: 0.06,
+		"small":                            0.096,
+		"gpu":                              0.526,
+		"large":                            0.384,
+	}
+
+	for _, tc := range []struct {
+		name      string
+		compute   *userconfig.Compute
+		prices    map[string]float64
+		nodeGroup string // empty if no node group can satisfy the compute
+	}{
+		{
+			name:      "fits on all node groups",
+			compute:   testCompute("500m", "1Gi", 0, 0),
+			prices:    onDemandPrices,
+			nodeGroup: "small",
+		},
+		{
+			name:      "fits on all node groups with a cheaper spot node group",
+			compute:   testCompute("500m", "1Gi", 0, 0),
+			prices:    spotPrices,
+			nodeGroup: clusterconfig.PrimaryNodeGroupName,
+		},
+		{
+			name:      "too much cpu for the smallest node group",
+			compute:   testCompute("2", "1Gi", 0, 0),
+			prices:    onDemandPrices,
+			nodeGroup: clusterconfig.PrimaryNodeGroupName,
+		},
+		{
+			name:      "too much memory for the smallest node group",
+			compute:   testCompute("", "10Gi", 0, 0),
+			prices:    onDemandPrices,
+			nodeGroup: clusterconfig.PrimaryNodeGroupName,
+		},
+		{
+			name:      "gpu",
+			compute:   testCompute("1", "1Gi", 1, 0),
+			prices:    onDemandPrices,
+			nodeGroup: "gpu",
+		},
+		{
+			name:      "only fits on the largest node group",
+			compute:   testCompute("6", "20Gi", 0, 0),
+			prices:    onDemandPrices,
+			nodeGroup: "large",
+		},
+		{
+			name:    "doesn't fit on any node group",
+			compute: testCompute("16", "", 0, 0),
+			prices:  onDemandPrices,
+		},
+		{
+			name:    "inferentia",
+			compute: testCompute("", "", 0, 1),
+			prices:  onDemandPrices,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			instancePrice := func(nodeGroup *clusterconfig.NodeGroup) float64 {
+				return tc.prices[nodeGroup.Name]
+			}
+
+			nodeGroup := cheapestNodeGroup(tc.compute, nodeGroups, "us-west-2", maxMems, instancePrice)
+			if tc.nodeGroup == "" {
+				require.Nil(t, nodeGroup)
+			} else {
+				require.NotNil(t, nodeGroup)
+				require.Equal(t, tc.nodeGroup, nodeGroup.Name)
+			}
+		})
+	}
+}
+
+func TestSpotNodeGroupInstancePrice(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		spotConfig *clusterconfig.SpotConfig
+		expected   float64
+	}{
+		{
+			name:       "no spot config",
+			spotConfig: nil,
+			expected:   0.03,
+		},
+		{
+			name:       "all spot",
+			spotConfig: &clusterconfig.SpotConfig{OnDemandPercentageAboveBaseCapacity: pointer.Int64(0)},
+			expected:   0.03,
+		},
+		{
+			name:       "half on-demand",
+			spotConfig: &clusterconfig.SpotConfig{OnDemandPercentageAboveBaseCapacity: pointer.Int64(50)},
+			expected:   0.06,
+		},
+		{
+			name:       "all on-demand",
+			spotConfig: &clusterconfig.SpotConfig{OnDemandPercentageAboveBaseCapacity: pointer.Int64(100)},
+			expected:   0.09,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDelta(t, tc.expected, spotNodeGroupInstancePrice(tc.spotConfig, 0.09, 0.03), 1e-9)
+		})
+	}
+}
+
+func TestSetNodeGroupWithoutAdditionalNodeGroups(t *testing.T) {
+	prevProvider, prevCluster := config.Provider, config.Cluster
+	defer func() {
+		config.Provider, config.Cluster = prevProvider, prevCluster
+	}()
+
+	config.Provider = types.AWSProviderType
+	config.Cluster = &clusterconfig.InternalConfig{}
+
+	apiConfig := &userconfig.API{Compute: testCompute("1", "1Gi", 0, 0)}
+	require.NoError(t, SetNodeGroup(apiConfig))
+	require.Nil(t, apiConfig.Compute.NodeGroup)
+
+	config.Provider = types.GCPProviderType
+	require.NoError(t, SetNodeGroup(apiConfig))
+	require.Nil(t, apiConfig.Compute.NodeGroup)
+}
+
+func TestNodeSelector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		compute  *userconfig.Compute
+		expected map[string]string
+	}{
+		{
+			name:     "no compute",
+			compute:  nil,
+			expected: map[string]string{"workload": "true"},
+		},
+		{
+			name:     "no node group",
+			compute:  &userconfig.Compute{},
+			expected: map[string]string{"workload": "true"},
+		},
+		{
+			name:     "node group",
+			compute:  &userconfig.Compute{NodeGroup: pointer.String("gpu")},
+			expected: map[string]string{"workload": "true", clusterconfig.NodeGroupLabelKey: "gpu"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &spec.API{API: &userconfig.API{Compute: tc.compute}}
+			require.Equal(t, tc.expected, NodeSelector(api))
+		})
+	}
+}
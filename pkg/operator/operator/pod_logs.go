@@ -0,0 +1,173 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/gorilla/websocket"
+	kcore "k8s.io/api/core/v1"
+)
+
+const (
+	_podLogsRefreshPeriod     = 2 * time.Second
+	_podLogsWriteDeadline     = 10 * time.Second
+	_podLogsInitialTailLines  = 100
+	_podLogsMaxLineSize       = 1024 * 1024
+	_podLogsScannerBufferSize = 64 * 1024
+)
+
+type podLogStream struct {
+	isActive bool
+	endedAt  *time.Time // when the previous stream of this pod's logs ended (e.g. due to a container restart)
+}
+
+// StreamPodLogs reads the logs of the api container of each pod which matches the labels directly from kubernetes, and writes them to the socket prefixed with the pod's name.
+// Pods which are created while streaming are picked up automatically. Returns when stop is closed.
+func StreamPodLogs(socket *websocket.Conn, labels map[string]string, stop chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mutex sync.Mutex                  // websocket connections only support one concurrent writer; also guards streams
+	streams := map[string]*podLogStream{} // pod name -> stream
+
+	writeLine := func(line string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		socket.SetWriteDeadline(time.Now().Add(_podLogsWriteDeadline))
+		socket.WriteMessage(websocket.TextMessage, []byte(line))
+	}
+
+	isFirstRefresh := true
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+			pods, err := config.K8s.ListPodsByLabels(labels)
+			if err != nil {
+				telemetry.Error(err)
+				writeLine("error encountered while listing replicas: " + errors.Message(err))
+				timer.Reset(_podLogsRefreshPeriod)
+				continue
+			}
+
+			if isFirstRefresh && len(pods) == 0 {
+				writeLine("waiting for replicas to start ...")
+			}
+
+			podNames := make(map[string]bool, len(pods))
+			mutex.Lock()
+			for i := range pods {
+				pod := &pods[i]
+				podNames[pod.Name] = true
+
+				if !hasContainerStarted(pod, APIContainerName) {
+					continue
+				}
+
+				stream, ok := streams[pod.Name]
+				if !ok {
+					stream = &podLogStream{}
+					streams[pod.Name] = stream
+				}
+				// the logs of a container which has exited have already been read in full
+				if stream.isActive || (stream.endedAt != nil && !isContainerRunning(pod, APIContainerName)) {
+					continue
+				}
+				stream.isActive = true
+
+				// only the recent logs of replicas which were already running are shown
+				var tailLines int64
+				if isFirstRefresh && stream.endedAt == nil {
+					tailLines = _podLogsInitialTailLines
+				}
+
+				go followPodLogs(ctx, pod.Name, stream, tailLines, writeLine, &mutex)
+			}
+
+			for podName, stream := range streams {
+				if !podNames[podName] && !stream.isActive {
+					delete(streams, podName)
+				}
+			}
+			mutex.Unlock()
+
+			isFirstRefresh = false
+			timer.Reset(_podLogsRefreshPeriod)
+		}
+	}
+}
+
+func followPodLogs(ctx context.Context, podName string, stream *podLogStream, tailLines int64, writeLine func(string), mutex *sync.Mutex) {
+	mutex.Lock()
+	sinceTime := stream.endedAt
+	mutex.Unlock()
+
+	didRead := false
+	defer func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		stream.isActive = false
+		if didRead {
+			endedAt := time.Now()
+			stream.endedAt = &endedAt
+		}
+	}()
+
+	logs, err := config.K8s.StreamPodLogs(ctx, podName, APIContainerName, tailLines, sinceTime)
+	if err != nil {
+		// e.g. the container is restarting; it will be retried on the next refresh
+		return
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, _podLogsScannerBufferSize), _podLogsMaxLineSize)
+	for scanner.Scan() {
+		didRead = true
+		writeLine(fmt.Sprintf("%s: %s", podName, scanner.Text()))
+	}
+}
+
+func hasContainerStarted(pod *kcore.Pod, containerName string) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == containerName {
+			return containerStatus.State.Running != nil || containerStatus.State.Terminated != nil
+		}
+	}
+	return false
+}
+
+func isContainerRunning(pod *kcore.Pod, containerName string) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == containerName {
+			return containerStatus.State.Running != nil
+		}
+	}
+	return false
+}
@@ -42,6 +42,10 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, string,
 		return nil, "", err
 	}
 
+	if err := operator.SetNodeGroup(apiConfig); err != nil {
+		return nil, "", err
+	}
+
 	api := spec.GetAPISpec(apiConfig, projectID, "", config.Cluster.ClusterName) // Deployment ID not needed for BatchAPI spec
 
 	if prevVirtualService == nil {
@@ -25,8 +25,11 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/events"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	kbatch "k8s.io/api/batch/v1"
 	kcore "k8s.io/api/core/v1"
 )
@@ -207,6 +210,8 @@ func setEnqueuingStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobEnqueuing)
+
 	return nil
 }
 
@@ -221,6 +226,8 @@ func setRunningStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobRunning)
+
 	return nil
 }
 
@@ -235,6 +242,8 @@ func setStoppedStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobStopped)
+
 	return nil
 }
 
@@ -249,6 +258,8 @@ func setSucceededStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobSucceeded)
+
 	return nil
 }
 
@@ -263,6 +274,8 @@ func setCompletedWithFailuresStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobCompletedWithFailures)
+
 	return nil
 }
 
@@ -277,6 +290,8 @@ func setWorkerErrorStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobWorkerError)
+
 	return nil
 }
 
@@ -291,6 +306,8 @@ func setWorkerOOMStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobWorkerOOM)
+
 	return nil
 }
 
@@ -305,6 +322,8 @@ func setEnqueueFailedStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobEnqueueFailed)
+
 	return nil
 }
 
@@ -319,9 +338,22 @@ func setUnexpectedErrorStatus(jobKey spec.JobKey) error {
 		return err
 	}
 
+	publishJobStatusEvent(jobKey, status.JobUnexpectedError)
+
 	return nil
 }
 
+func publishJobStatusEvent(jobKey spec.JobKey, jobCode status.JobCode) {
+	operator.PublishEvent(events.Event{
+		Type:      events.JobStatusChange,
+		APIName:   jobKey.APIName,
+		APIKind:   userconfig.BatchAPIKind,
+		Message:   fmt.Sprintf("job %s status changed to %s", jobKey.ID, jobCode.Message()),
+		JobID:     jobKey.ID,
+		JobStatus: &jobCode,
+	})
+}
+
 func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pods []kcore.Pod) (*status.JobStatus, error) {
 	jobKey := initialJobState.JobKey
 
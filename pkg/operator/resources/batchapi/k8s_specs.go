@@ -82,10 +82,8 @@ func pythonPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, error) {
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
-				Containers: containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
+				Containers:         containers,
+				NodeSelector:       operator.NodeSelector(api),
 				Tolerations:        operator.Tolerations,
 				Volumes:            volumes,
 				ServiceAccountName: "default",
@@ -131,10 +129,8 @@ func tensorFlowPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, erro
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
-				Containers: containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
+				Containers:         containers,
+				NodeSelector:       operator.NodeSelector(api),
 				Tolerations:        operator.Tolerations,
 				Volumes:            volumes,
 				ServiceAccountName: "default",
@@ -181,10 +177,8 @@ func onnxPredictorJobSpec(api *spec.API, job *spec.Job) (*kbatch.Job, error) {
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
-				Containers: containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
+				Containers:         containers,
+				NodeSelector:       operator.NodeSelector(api),
 				Tolerations:        operator.Tolerations,
 				Volumes:            operator.DefaultVolumes(),
 				ServiceAccountName: "default",
@@ -30,6 +30,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/gorilla/websocket"
@@ -72,6 +73,14 @@ func ReadLogs(jobKey spec.JobKey, socket *websocket.Conn) {
 	podCheckCancel <- struct{}{}
 }
 
+// ReadPodLogs streams the logs of the job's workers directly from kubernetes, rather than from the cluster's log pipeline
+func ReadPodLogs(jobKey spec.JobKey, socket *websocket.Conn) {
+	stop := make(chan struct{})
+	go operator.StreamPodLogs(socket, map[string]string{"apiName": jobKey.APIName, "jobID": jobKey.ID}, stop)
+	pumpStdin(socket)
+	close(stop)
+}
+
 func pumpStdin(socket *websocket.Conn) {
 	socket.SetReadLimit(_socketMaxMessageSize)
 	for {
@@ -32,10 +32,17 @@ const (
 	ErrAPIIDNotFound                    = "resources.api_id_not_found"
 	ErrCannotChangeTypeOfDeployedAPI    = "resources.cannot_change_kind_of_deployed_api"
 	ErrNoAvailableNodeComputeLimit      = "resources.no_available_node_compute_limit"
+	ErrNodeGroupNotFound                = "resources.node_group_not_found"
+	ErrNoNodeGroupCanSatisfyCompute     = "resources.no_node_group_can_satisfy_compute"
 	ErrJobIDRequired                    = "resources.job_id_required"
 	ErrRealtimeAPIUsedByTrafficSplitter = "resources.realtime_api_used_by_traffic_splitter"
 	ErrAPIsNotDeployed                  = "resources.apis_not_deployed"
-	ErrAPIGatewayDisabled               = "resources.api_gateway_disabled"
+	ErrSecretNotFound                   = "resources.secret_not_found"
+	ErrSecretKeyNotFound                = "resources.secret_key_not_found"
+	ErrSecretNameReserved               = "resources.secret_name_reserved"
+	ErrSecretNoKeys                     = "resources.secret_no_keys"
+	ErrInvalidSecretKey                 = "resources.invalid_secret_key"
+	ErrSecretUsedByAPIs                 = "resources.secret_used_by_apis"
 )
 
 func ErrorOperationIsOnlySupportedForKind(resource operator.DeployedResource, supportedKind userconfig.Kind, supportedKinds ...userconfig.Kind) error {
@@ -84,6 +91,20 @@ func ErrorNoAvailableNodeComputeLimit(resource string, reqStr string, maxStr str
 	})
 }
 
+func ErrorNodeGroupNotFound(nodeGroupName string, availableNodeGroupNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNodeGroupNotFound,
+		Message: fmt.Sprintf("node group %s does not exist in the cluster; the following node groups are available: %s", s.UserStr(nodeGroupName), s.StrsAnd(availableNodeGroupNames)),
+	})
+}
+
+func ErrorNoNodeGroupCanSatisfyCompute(nodeGroupNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoNodeGroupCanSatisfyCompute,
+		Message: fmt.Sprintf("none of the cluster's node groups (%s) have instances which can satisfy the requested compute resources", s.StrsAnd(nodeGroupNames)),
+	})
+}
+
 func ErrorAPIUsedByTrafficSplitter(trafficSplitters []string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrRealtimeAPIUsedByTrafficSplitter,
@@ -102,9 +123,48 @@ func ErrorAPIsNotDeployed(notDeployedAPIs []string) error {
 	})
 }
 
-func ErrorAPIGatewayDisabled(apiGatewayType userconfig.APIGatewayType) error {
+func ErrorSecretNotFound(secretName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretNotFound,
+		Message: fmt.Sprintf("secret %s does not exist", secretName),
+	})
+}
+
+func ErrorSecretKeyNotFound(secretName string, key string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretKeyNotFound,
+		Message: fmt.Sprintf("secret %s does not contain key %s", secretName, key),
+	})
+}
+
+func ErrorSecretNameReserved(secretName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretNameReserved,
+		Message: fmt.Sprintf("secret name %s is reserved by the cluster; please choose a different name", secretName),
+	})
+}
+
+func ErrorSecretNoKeys() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretNoKeys,
+		Message: "at least one key must be specified",
+	})
+}
+
+func ErrorInvalidSecretKey(key string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSecretKey,
+		Message: fmt.Sprintf("invalid secret key %s; keys may only contain alphanumeric characters, dashes, periods, and underscores", key),
+	})
+}
+
+func ErrorSecretUsedByAPIs(secretName string, key string, apiNames []string) error {
+	subject := fmt.Sprintf("secret %s", secretName)
+	if key != "" {
+		subject = fmt.Sprintf("key %s of secret %s", key, secretName)
+	}
 	return errors.WithStack(&errors.Error{
-		Kind:    ErrAPIGatewayDisabled,
-		Message: fmt.Sprintf("%s is not permitted because api gateway is disabled cluster-wide (valid values are %s)", s.UserStr(apiGatewayType), s.UserStrsAnd(userconfig.APIGatewayTypeStrings())),
+		Kind:    ErrSecretUsedByAPIs,
+		Message: fmt.Sprintf("%s is referenced by the following %s: %s; delete or update the %s first, or use the --force flag", subject, strings.PluralS("api", len(apiNames)), strings.StrsAnd(apiNames), strings.PluralS("api", len(apiNames))),
 	})
 }
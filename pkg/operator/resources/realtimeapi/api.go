@@ -54,6 +54,10 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 		deploymentID = prevDeployment.Labels["deploymentID"]
 	}
 
+	if err := operator.SetNodeGroup(apiConfig); err != nil {
+		return nil, "", err
+	}
+
 	api := spec.GetAPISpec(apiConfig, projectID, deploymentID, config.ClusterName())
 
 	if prevDeployment == nil {
@@ -87,11 +91,19 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 	}
 
 	if prevVirtualService.Labels["specID"] != api.SpecID || prevVirtualService.Labels["deploymentID"] != api.DeploymentID {
+		candidate, err := getBlueGreenCandidate(api.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		if candidate != nil && candidate.Labels["apiID"] == api.ID {
+			return api, fmt.Sprintf("%s is already updating", api.Resource.UserString()), nil
+		}
+
 		isUpdating, err := isAPIUpdating(prevDeployment)
 		if err != nil {
 			return nil, "", err
 		}
-		if isUpdating && !force {
+		if (isUpdating || candidate != nil) && !force {
 			return nil, "", ErrorAPIUpdating(api.Name)
 		}
 
@@ -104,6 +116,20 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 			return nil, "", errors.Wrap(err, "upload predictor spec")
 		}
 
+		if api.UpdateStrategy.Type == userconfig.BlueGreenUpdateStrategyType && didPredictorChange(prevDeployment, api) {
+			// traffic is switched to the new version by ManageRollouts once all of its replicas are ready
+			if err := startBlueGreenUpdate(api, prevDeployment, prevService); err != nil {
+				return nil, "", err
+			}
+			return api, fmt.Sprintf("updating %s (blue/green)", api.Resource.UserString()), nil
+		}
+
+		if candidate != nil {
+			if err := teardownDeployment(candidate); err != nil {
+				return nil, "", err
+			}
+		}
+
 		if err := applyK8sResources(api, prevDeployment, prevService, prevVirtualService); err != nil {
 			return nil, "", err
 		}
@@ -120,29 +146,42 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 	if err != nil {
 		return nil, "", err
 	}
-	if isUpdating {
+	candidate, err := getBlueGreenCandidate(api.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	if isUpdating || candidate != nil {
 		return api, fmt.Sprintf("%s is already updating", api.Resource.UserString()), nil
 	}
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
 func RefreshAPI(apiName string, force bool) (string, error) {
-	prevDeployment, err := config.K8s.GetDeployment(operator.K8sName(apiName))
+	prevDeployment, candidate, err := getAPIDeployments(apiName)
 	if err != nil {
 		return "", err
 	} else if prevDeployment == nil {
 		return "", errors.ErrorUnexpected("unable to find deployment", apiName)
 	}
+	if candidate != nil && isDraining(candidate) {
+		candidate = nil
+	}
 
 	isUpdating, err := isAPIUpdating(prevDeployment)
 	if err != nil {
 		return "", err
 	}
 
-	if isUpdating && !force {
+	if (isUpdating || candidate != nil) && !force {
 		return "", ErrorAPIUpdating(apiName)
 	}
 
+	if candidate != nil {
+		if err := teardownDeployment(candidate); err != nil {
+			return "", err
+		}
+	}
+
 	apiID, err := k8s.GetLabel(prevDeployment, "apiID")
 	if err != nil {
 		return "", err
@@ -299,13 +338,19 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 		dashboardURL = pointer.String(DashboardURL())
 	}
 
+	colorStatuses, err := getColorStatuses(deployedResource.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	return []schema.APIResponse{
 		{
-			Spec:         *api,
-			Status:       status,
-			Metrics:      metrics,
-			Endpoint:     apiEndpoint,
-			DashboardURL: dashboardURL,
+			Spec:          *api,
+			Status:        status,
+			Metrics:       metrics,
+			Endpoint:      apiEndpoint,
+			DashboardURL:  dashboardURL,
+			ColorStatuses: colorStatuses,
 		},
 	}, nil
 }
@@ -318,7 +363,7 @@ func getK8sResources(apiConfig *userconfig.API) (*kapps.Deployment, *kcore.Servi
 	err := parallel.RunFirstErr(
 		func() error {
 			var err error
-			deployment, err = config.K8s.GetDeployment(operator.K8sName(apiConfig.Name))
+			deployment, err = getActiveDeployment(apiConfig.Name)
 			return err
 		},
 		func() error {
@@ -342,7 +387,7 @@ func applyK8sResources(api *spec.API, prevDeployment *kapps.Deployment, prevServ
 			return applyK8sDeployment(api, prevDeployment)
 		},
 		func() error {
-			return applyK8sService(api, prevService)
+			return applyK8sService(api, prevService, deploymentColor(prevDeployment))
 		},
 		func() error {
 			return applyK8sVirtualService(api, prevVirtualService)
@@ -352,6 +397,8 @@ func applyK8sResources(api *spec.API, prevDeployment *kapps.Deployment, prevServ
 
 func applyK8sDeployment(api *spec.API, prevDeployment *kapps.Deployment) error {
 	newDeployment := deploymentSpec(api, prevDeployment)
+	setDeploymentColor(newDeployment, api.Name, deploymentColor(prevDeployment))
+	setRolloutAnnotations(newDeployment, prevDeployment)
 
 	if prevDeployment == nil {
 		_, err := config.K8s.CreateDeployment(newDeployment)
@@ -360,7 +407,7 @@ func applyK8sDeployment(api *spec.API, prevDeployment *kapps.Deployment) error {
 		}
 	} else if prevDeployment.Status.ReadyReplicas == 0 {
 		// Delete deployment if it never became ready
-		config.K8s.DeleteDeployment(operator.K8sName(api.Name))
+		config.K8s.DeleteDeployment(newDeployment.Name)
 		_, err := config.K8s.CreateDeployment(newDeployment)
 		if err != nil {
 			return err
@@ -398,8 +445,9 @@ func UpdateAutoscalerCron(deployment *kapps.Deployment) error {
 	return nil
 }
 
-func applyK8sService(api *spec.API, prevService *kcore.Service) error {
-	newService := serviceSpec(api)
+// color is the color of the deployment which should receive traffic ("" if it wasn't created by a blue/green update)
+func applyK8sService(api *spec.API, prevService *kcore.Service, color string) error {
+	newService := serviceSpec(api, color)
 
 	if prevService == nil {
 		_, err := config.K8s.CreateService(newService)
@@ -430,12 +478,24 @@ func deleteK8sResources(apiName string) error {
 				delete(_autoscalerCrons, apiName)
 			}
 
-			_, err := config.K8s.DeleteDeployment(operator.K8sName(apiName))
-			return err
+			deployments, err := config.K8s.ListDeploymentsByLabel("apiName", apiName)
+			if err != nil {
+				return err
+			}
+			for _, deployment := range deployments {
+				if _, err := config.K8s.DeleteDeployment(deployment.Name); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 		func() error {
-			_, err := config.K8s.DeleteService(operator.K8sName(apiName))
-			return err
+			for _, serviceName := range []string{operator.K8sName(apiName), candidateServiceName(apiName, _colorBlue), candidateServiceName(apiName, _colorGreen)} {
+				if _, err := config.K8s.DeleteService(serviceName); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 		func() error {
 			_, err := config.K8s.DeleteVirtualService(operator.K8sName(apiName))
@@ -450,16 +510,26 @@ func deleteBucketResources(apiName string) error {
 }
 
 func IsAPIUpdating(apiName string) (bool, error) {
-	deployment, err := config.K8s.GetDeployment(operator.K8sName(apiName))
+	deployment, candidate, err := getAPIDeployments(apiName)
 	if err != nil {
 		return false, err
 	}
+	if deployment == nil {
+		return false, errors.ErrorUnexpected("unable to find deployment", apiName)
+	}
+	if candidate != nil && !isDraining(candidate) {
+		return true, nil
+	}
 
 	return isAPIUpdating(deployment)
 }
 
 // returns true if min_replicas are not ready and no updated replicas have errored
 func isAPIUpdating(deployment *kapps.Deployment) (bool, error) {
+	if isRolloutFailed(deployment) {
+		return false, nil
+	}
+
 	pods, err := config.K8s.ListPodsByLabel("apiName", deployment.Labels["apiName"])
 	if err != nil {
 		return false, err
@@ -27,6 +27,7 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	kapps "k8s.io/api/apps/v1"
@@ -190,6 +191,8 @@ func autoscaleFn(initialDeployment *kapps.Deployment) (func() error, error) {
 				return err
 			}
 
+			operator.PublishAutoscalingEvent(apiName, currentReplicas, request)
+
 			currentReplicas = request
 		}
 
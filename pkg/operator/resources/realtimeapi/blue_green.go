@@ -0,0 +1,375 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+/*
+Blue/green updates bring up a complete deployment of the new version (the candidate) alongside the current one.
+Deployments created by blue/green updates are labeled with a color, and the candidate is labeled as inactive.
+Once all of the candidate's replicas are ready (and the smoke test has passed), the selector of the api's service
+is switched to the candidate's color, which moves all traffic (including traffic from TrafficSplitters) at once.
+The previous deployment is then labeled as inactive and deleted after the teardown grace period.
+*/
+
+const (
+	_colorBlue  = "blue"
+	_colorGreen = "green"
+
+	_blueGreenTeardownAtAnnotationKey = "blue-green.cortex.dev/teardown-at"
+
+	_smokeTestTimeout      = 30 * time.Second
+	_smokeTestMaxBodyBytes = 1024
+)
+
+// returns "" for deployments which weren't created by a blue/green update
+func deploymentColor(deployment *kapps.Deployment) string {
+	if deployment == nil {
+		return ""
+	}
+	return deployment.Labels["color"]
+}
+
+func displayColor(deployment *kapps.Deployment) string {
+	if color := deploymentColor(deployment); color != "" {
+		return color
+	}
+	return _colorBlue
+}
+
+func candidateColor(activeDeployment *kapps.Deployment) string {
+	if deploymentColor(activeDeployment) == _colorGreen {
+		return _colorBlue
+	}
+	return _colorGreen
+}
+
+// deployments which weren't created by a blue/green update use the api's k8s name
+func deploymentName(apiName string, color string) string {
+	if color == "" {
+		return operator.K8sName(apiName)
+	}
+	return operator.K8sName(apiName) + "-" + color
+}
+
+// the candidate's service is only used for the smoke test, and is named after its deployment
+func candidateServiceName(apiName string, color string) string {
+	return deploymentName(apiName, color)
+}
+
+// IsActiveDeployment returns false if the deployment is the candidate of a blue/green update, or is waiting to be torn down after one
+func IsActiveDeployment(deployment *kapps.Deployment) bool {
+	return deployment.Labels["active"] != "false"
+}
+
+func isDraining(deployment *kapps.Deployment) bool {
+	_, ok := deployment.Annotations[_blueGreenTeardownAtAnnotationKey]
+	return ok
+}
+
+func isPodOfDeployment(pod *kcore.Pod, deployment *kapps.Deployment) bool {
+	return pod.Labels["apiName"] == deployment.Labels["apiName"] && pod.Labels["color"] == deployment.Labels["color"]
+}
+
+func setDeploymentColor(deployment *kapps.Deployment, apiName string, color string) {
+	if color == "" {
+		return
+	}
+	deployment.Name = deploymentName(apiName, color)
+	deployment.Labels["color"] = color
+	deployment.Spec.Selector.MatchLabels["color"] = color
+	deployment.Spec.Template.Labels["color"] = color
+}
+
+// getAPIDeployments returns the deployment which is receiving traffic, and the inactive deployment (a blue/green candidate or a deployment which is being torn down), if either exist
+func getAPIDeployments(apiName string) (*kapps.Deployment, *kapps.Deployment, error) {
+	deployments, err := config.K8s.ListDeploymentsByLabel("apiName", apiName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var activeDeployment *kapps.Deployment
+	var inactiveDeployment *kapps.Deployment
+	for i := range deployments {
+		if IsActiveDeployment(&deployments[i]) {
+			activeDeployment = &deployments[i]
+		} else {
+			inactiveDeployment = &deployments[i]
+		}
+	}
+
+	return activeDeployment, inactiveDeployment, nil
+}
+
+func getActiveDeployment(apiName string) (*kapps.Deployment, error) {
+	activeDeployment, _, err := getAPIDeployments(apiName)
+	return activeDeployment, err
+}
+
+// returns nil if there is no blue/green update in progress
+func getBlueGreenCandidate(apiName string) (*kapps.Deployment, error) {
+	_, inactiveDeployment, err := getAPIDeployments(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if inactiveDeployment == nil || isDraining(inactiveDeployment) {
+		return nil, nil
+	}
+	return inactiveDeployment, nil
+}
+
+func didPredictorChange(deployment *kapps.Deployment, api *spec.API) bool {
+	return deployment.Spec.Template.Labels["predictorID"] != api.PredictorID ||
+		deployment.Spec.Template.Labels["deploymentID"] != api.DeploymentID
+}
+
+func startBlueGreenUpdate(api *spec.API, activeDeployment *kapps.Deployment, prevService *kcore.Service) error {
+	_, inactiveDeployment, err := getAPIDeployments(api.Name)
+	if err != nil {
+		return err
+	}
+	if inactiveDeployment != nil {
+		if err := teardownDeployment(inactiveDeployment); err != nil {
+			return err
+		}
+	}
+
+	color := candidateColor(activeDeployment)
+
+	candidate := deploymentSpec(api, activeDeployment)
+	setDeploymentColor(candidate, api.Name, color)
+	candidate.Labels["active"] = "false"
+	setRolloutAnnotations(candidate, activeDeployment)
+
+	if _, err := config.K8s.CreateDeployment(candidate); err != nil {
+		return err
+	}
+
+	candidateService := serviceSpec(api, color)
+	candidateService.Name = candidateServiceName(api.Name, color)
+	candidateService.Labels["active"] = "false"
+	if _, err := config.K8s.ApplyService(candidateService); err != nil {
+		return err
+	}
+
+	// the service of a deployment which wasn't created by a blue/green update selects all of the api's pods, so it must be pinned to the current pods before the candidate's pods are created
+	if deploymentColor(activeDeployment) == "" && prevService != nil {
+		pinnedService := prevService.DeepCopy()
+		pinnedService.Spec.Selector = map[string]string{
+			"apiName":      api.Name,
+			"apiKind":      api.Kind.String(),
+			"predictorID":  activeDeployment.Spec.Template.Labels["predictorID"],
+			"deploymentID": activeDeployment.Spec.Template.Labels["deploymentID"],
+		}
+		if _, err := config.K8s.UpdateService(prevService, pinnedService); err != nil {
+			return err
+		}
+	}
+
+	operator.PublishEvent(events.Event{
+		Type:      events.UpdateStarted,
+		APIName:   api.Name,
+		APIKind:   userconfig.RealtimeAPIKind,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("blue/green update started (bringing up %d %s %s)", *candidate.Spec.Replicas, color, s.PluralS("replica", *candidate.Spec.Replicas)),
+	})
+
+	return nil
+}
+
+// switchToCandidate runs the smoke test against the candidate, and if it passes, moves all traffic to the candidate
+func switchToCandidate(candidate *kapps.Deployment) error {
+	apiName := candidate.Labels["apiName"]
+	color := deploymentColor(candidate)
+
+	api, err := operator.DownloadAPISpec(apiName, candidate.Labels["apiID"])
+	if err != nil {
+		return err
+	}
+
+	if api.UpdateStrategy.SmokeTestPayload != nil {
+		if err := runSmokeTest(candidateServiceName(apiName, color), *api.UpdateStrategy.SmokeTestPayload); err != nil {
+			return failRollout(candidate, fmt.Sprintf("smoke test failed: %s", errors.Message(err)))
+		}
+	}
+
+	activeDeployment, err := getActiveDeployment(apiName)
+	if err != nil {
+		return err
+	}
+
+	_, prevService, prevVirtualService, err := getK8sResources(api.API)
+	if err != nil {
+		return err
+	}
+
+	if err := applyK8sService(api, prevService, color); err != nil {
+		return err
+	}
+
+	// update the virtual service's labels so that the new version is reported as live
+	if err := applyK8sVirtualService(api, prevVirtualService); err != nil {
+		return err
+	}
+
+	if config.Provider == types.AWSProviderType {
+		if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api); err != nil {
+			return err
+		}
+	}
+
+	candidate.Labels["active"] = "true"
+	candidate.Annotations[_rolloutStatusAnnotationKey] = _rolloutComplete
+	candidate, err = config.K8s.UpdateDeployment(candidate)
+	if err != nil {
+		return err
+	}
+
+	if activeDeployment != nil {
+		activeDeployment.Labels["active"] = "false"
+		if activeDeployment.Annotations == nil {
+			activeDeployment.Annotations = map[string]string{}
+		}
+		activeDeployment.Annotations[_blueGreenTeardownAtAnnotationKey] = time.Now().Add(api.UpdateStrategy.TeardownGracePeriod).Format(time.RFC3339)
+		if _, err := config.K8s.UpdateDeployment(activeDeployment); err != nil {
+			return err
+		}
+	}
+
+	if config.Provider == types.AWSProviderType {
+		if err := UpdateAutoscalerCron(candidate); err != nil {
+			return err
+		}
+	}
+
+	// best effort, the candidate's service is no longer needed
+	config.K8s.DeleteService(candidateServiceName(apiName, color))
+
+	operator.PublishEvent(events.Event{
+		Type:      events.UpdateFinished,
+		APIName:   apiName,
+		APIKind:   userconfig.RealtimeAPIKind,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("blue/green update finished (switched traffic to %s)", color),
+	})
+
+	return nil
+}
+
+func runSmokeTest(serviceName string, payload string) error {
+	url := fmt.Sprintf("http://%s.%s:%d/predict", serviceName, config.K8s.Namespace, operator.DefaultPortInt32)
+
+	client := http.Client{Timeout: _smokeTestTimeout}
+	response, err := client.Post(url, "application/json", strings.NewReader(payload))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, _smokeTestMaxBodyBytes))
+		return ErrorSmokeTestFailed(response.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func teardownDeployment(deployment *kapps.Deployment) error {
+	apiName := deployment.Labels["apiName"]
+
+	if _, err := config.K8s.DeleteDeployment(deployment.Name); err != nil {
+		return err
+	}
+
+	if color := deploymentColor(deployment); color != "" {
+		if _, err := config.K8s.DeleteService(candidateServiceName(apiName, color)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isTeardownDue(deployment *kapps.Deployment) bool {
+	teardownAt, err := time.Parse(time.RFC3339, deployment.Annotations[_blueGreenTeardownAtAnnotationKey])
+	if err != nil {
+		return true
+	}
+	return time.Now().After(teardownAt)
+}
+
+// getColorStatuses returns the status of each of the api's deployments if a blue/green update is in progress, or nil otherwise
+func getColorStatuses(apiName string) ([]schema.ColorStatus, error) {
+	activeDeployment, inactiveDeployment, err := getAPIDeployments(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if activeDeployment == nil || inactiveDeployment == nil {
+		return nil, nil
+	}
+
+	pods, err := config.K8s.ListPodsByLabel("apiName", apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	activeStatus, err := apiStatus(activeDeployment, pods)
+	if err != nil {
+		return nil, err
+	}
+	inactiveStatus, err := apiStatus(inactiveDeployment, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	inactiveRole := schema.CandidateColorRole
+	if isDraining(inactiveDeployment) {
+		inactiveRole = schema.DrainingColorRole
+	}
+
+	return []schema.ColorStatus{
+		{
+			Color:  displayColor(activeDeployment),
+			Role:   schema.ActiveColorRole,
+			Status: *activeStatus,
+		},
+		{
+			Color:  displayColor(inactiveDeployment),
+			Role:   inactiveRole,
+			Status: *inactiveStatus,
+		},
+	}, nil
+}
@@ -18,12 +18,17 @@ package realtimeapi
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 )
 
 const (
-	ErrAPIUpdating = "realtimeapi.api_updating"
+	ErrAPIUpdating           = "realtimeapi.api_updating"
+	ErrSmokeTestFailed       = "realtimeapi.smoke_test_failed"
+	ErrReplicaNotFound       = "realtimeapi.replica_not_found"
+	ErrInvalidLogSearchToken = "realtimeapi.invalid_log_search_token"
 )
 
 func ErrorAPIUpdating(apiName string) error {
@@ -32,3 +37,32 @@ func ErrorAPIUpdating(apiName string) error {
 		Message: fmt.Sprintf("%s is updating (override with --force)", apiName),
 	})
 }
+
+func ErrorSmokeTestFailed(statusCode int, body string) error {
+	message := fmt.Sprintf("received status code %d", statusCode)
+	if body = strings.TrimSpace(body); body != "" {
+		message += ": " + body
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSmokeTestFailed,
+		Message: message,
+	})
+}
+
+func ErrorReplicaNotFound(apiName string, replica string, numReplicas int) error {
+	message := fmt.Sprintf("replica %s of %s was not found (%s has %d running %s; specify a replica by index or name)", replica, apiName, apiName, numReplicas, s.PluralS("replica", numReplicas))
+	if numReplicas == 0 {
+		message = fmt.Sprintf("%s does not have any running replicas", apiName)
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrReplicaNotFound,
+		Message: message,
+	})
+}
+
+func ErrorInvalidLogSearchToken(token string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidLogSearchToken,
+		Message: fmt.Sprintf("invalid log search token: %s", token),
+	})
+}
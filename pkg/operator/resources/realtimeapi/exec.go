@@ -0,0 +1,201 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/wsconn"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/websocket"
+	kcore "k8s.io/api/core/v1"
+	kremotecommand "k8s.io/client-go/tools/remotecommand"
+)
+
+// GetReplica returns the running replica of the api which is identified by replica, which is either an index into the api's running replicas (sorted by name) or a replica name
+func GetReplica(apiName string, replica string) (*kcore.Pod, error) {
+	deployment, err := getActiveDeployment(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if deployment == nil {
+		return nil, errors.ErrorUnexpected("unable to find deployment", apiName)
+	}
+
+	pods, err := config.K8s.ListPodsByLabel("apiName", apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	var runningPods []kcore.Pod
+	for i := range pods {
+		if isPodOfDeployment(&pods[i], deployment) && pods[i].Status.Phase == kcore.PodRunning && pods[i].DeletionTimestamp == nil {
+			runningPods = append(runningPods, pods[i])
+		}
+	}
+	sort.Slice(runningPods, func(i, j int) bool {
+		return runningPods[i].Name < runningPods[j].Name
+	})
+
+	if index, ok := s.ParseInt(replica); ok {
+		if index >= 0 && index < len(runningPods) {
+			return &runningPods[index], nil
+		}
+		return nil, ErrorReplicaNotFound(apiName, replica, len(runningPods))
+	}
+
+	for i := range runningPods {
+		if runningPods[i].Name == replica {
+			return &runningPods[i], nil
+		}
+	}
+
+	return nil, ErrorReplicaNotFound(apiName, replica, len(runningPods))
+}
+
+// Exec runs a command in the api container of the pod, tunneling its input and output over the socket (see schema.ExecStdinChannel)
+func Exec(podName string, command []string, tty bool, socket *websocket.Conn) {
+	var writeMutex sync.Mutex
+	stdinReader, stdinWriter := io.Pipe()
+	sizeQueue := &terminalSizeQueue{sizes: make(chan kremotecommand.TerminalSize, 1)}
+
+	go readExecInput(socket, stdinWriter, sizeQueue)
+
+	exitCode, err := config.K8s.ExecStream(podName, operator.APIContainerName, &k8s.ExecOptions{
+		Command:           command,
+		Stdin:             stdinReader,
+		Stdout:            &channelWriter{socket: socket, channel: schema.ExecStdoutChannel, mutex: &writeMutex},
+		Stderr:            &channelWriter{socket: socket, channel: schema.ExecStderrChannel, mutex: &writeMutex},
+		TTY:               tty,
+		TerminalSizeQueue: sizeQueue,
+	})
+
+	execStatus := schema.ExecStatus{ExitCode: exitCode}
+	if err != nil {
+		execStatus.ExitCode = 1
+		execStatus.Error = errors.Message(err)
+	}
+
+	statusBytes, _ := json.Marshal(execStatus)
+	writeMutex.Lock()
+	socket.SetWriteDeadline(time.Now().Add(_socketWriteDeadlineWait))
+	socket.WriteMessage(websocket.BinaryMessage, append([]byte{schema.ExecStatusChannel}, statusBytes...))
+	socket.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	writeMutex.Unlock()
+}
+
+// PortForward tunnels a single connection to the pod's serving port over the socket
+func PortForward(podName string, socket *websocket.Conn) error {
+	conn := wsconn.New(socket)
+	defer conn.Close()
+	return config.K8s.PortForward(podName, operator.DefaultPortInt32, conn)
+}
+
+func readExecInput(socket *websocket.Conn, stdin *io.PipeWriter, sizeQueue *terminalSizeQueue) {
+	defer stdin.Close()
+	defer sizeQueue.close()
+
+	socket.SetReadLimit(_socketMaxMessageSize)
+	for {
+		_, message, err := socket.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case schema.ExecStdinChannel:
+			if len(message) == 1 {
+				stdin.Close() // an empty stdin message signals the end of the client's input
+				continue
+			}
+			if _, err := stdin.Write(message[1:]); err != nil {
+				return
+			}
+		case schema.ExecResizeChannel:
+			var size schema.TerminalSize
+			if err := json.Unmarshal(message[1:], &size); err == nil {
+				sizeQueue.push(kremotecommand.TerminalSize{Width: size.Width, Height: size.Height})
+			}
+		}
+	}
+}
+
+type channelWriter struct {
+	socket  *websocket.Conn
+	channel byte
+	mutex   *sync.Mutex // websocket connections only support one concurrent writer
+}
+
+func (w *channelWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.socket.SetWriteDeadline(time.Now().Add(_socketWriteDeadlineWait))
+	if err := w.socket.WriteMessage(websocket.BinaryMessage, append([]byte{w.channel}, p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// terminalSizeQueue implements kremotecommand.TerminalSizeQueue; only the most recent size is kept
+type terminalSizeQueue struct {
+	sizes    chan kremotecommand.TerminalSize
+	isClosed bool
+	mutex    sync.Mutex
+}
+
+func (q *terminalSizeQueue) push(size kremotecommand.TerminalSize) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.isClosed {
+		return
+	}
+	select {
+	case <-q.sizes:
+	default:
+	}
+	q.sizes <- size
+}
+
+func (q *terminalSizeQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.isClosed {
+		q.isClosed = true
+		close(q.sizes)
+	}
+}
+
+// Next blocks until the terminal is resized, and returns nil once the session has ended
+func (q *terminalSizeQueue) Next() *kremotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
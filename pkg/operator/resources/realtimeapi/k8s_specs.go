@@ -86,10 +86,8 @@ func tensorflowAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.D
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
-				Containers: containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
+				Containers:         containers,
+				NodeSelector:       operator.NodeSelector(api),
 				Tolerations:        operator.Tolerations,
 				Volumes:            volumes,
 				ServiceAccountName: "default",
@@ -139,10 +137,8 @@ func pythonAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Deplo
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
 				},
-				Containers: containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
+				Containers:         containers,
+				NodeSelector:       operator.NodeSelector(api),
 				Tolerations:        operator.Tolerations,
 				Volumes:            volumes,
 				ServiceAccountName: "default",
@@ -192,18 +188,25 @@ func onnxAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Deploym
 				},
 				TerminationGracePeriodSeconds: pointer.Int64(_terminationGracePeriodSeconds),
 				Containers:                    containers,
-				NodeSelector: map[string]string{
-					"workload": "true",
-				},
-				Tolerations:        operator.Tolerations,
-				Volumes:            operator.DefaultVolumes(),
-				ServiceAccountName: "default",
+				NodeSelector:                  operator.NodeSelector(api),
+				Tolerations:                   operator.Tolerations,
+				Volumes:                       operator.DefaultVolumes(),
+				ServiceAccountName:            "default",
 			},
 		},
 	})
 }
 
-func serviceSpec(api *spec.API) *kcore.Service {
+// if color is not empty, the service only selects the pods of the deployment with that color
+func serviceSpec(api *spec.API, color string) *kcore.Service {
+	selector := map[string]string{
+		"apiName": api.Name,
+		"apiKind": api.Kind.String(),
+	}
+	if color != "" {
+		selector["color"] = color
+	}
+
 	return k8s.Service(&k8s.ServiceSpec{
 		Name:        operator.K8sName(api.Name),
 		Port:        operator.DefaultPortInt32,
@@ -213,10 +216,7 @@ func serviceSpec(api *spec.API) *kcore.Service {
 			"apiName": api.Name,
 			"apiKind": api.Kind.String(),
 		},
-		Selector: map[string]string{
-			"apiName": api.Name,
-			"apiKind": api.Kind.String(),
-		},
+		Selector: selector,
 	})
 }
 
@@ -31,6 +31,8 @@ import (
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/websocket"
 	kapps "k8s.io/api/apps/v1"
 )
@@ -56,11 +58,23 @@ type fluentdLog struct {
 func ReadLogs(apiName string, socket *websocket.Conn) {
 	podCheckCancel := make(chan struct{})
 	defer close(podCheckCancel)
-	go streamFromCloudWatch(apiName, podCheckCancel, socket)
+	if config.Provider == types.GCPProviderType {
+		go streamFromCloudLogging(apiName, podCheckCancel, socket)
+	} else {
+		go streamFromCloudWatch(apiName, podCheckCancel, socket)
+	}
 	pumpStdin(socket)
 	podCheckCancel <- struct{}{}
 }
 
+// ReadPodLogs streams the logs of the api's replicas directly from kubernetes, rather than from the cluster's log pipeline
+func ReadPodLogs(apiName string, socket *websocket.Conn) {
+	stop := make(chan struct{})
+	go operator.StreamPodLogs(socket, map[string]string{"apiName": apiName, "apiKind": userconfig.RealtimeAPIKind.String()}, stop)
+	pumpStdin(socket)
+	close(stop)
+}
+
 func pumpStdin(socket *websocket.Conn) {
 	socket.SetReadLimit(_socketMaxMessageSize)
 	for {
@@ -92,7 +106,7 @@ func streamFromCloudWatch(apiName string, podCheckCancel chan struct{}, socket *
 		case <-timer.C:
 			if deployment == nil || time.Since(lastDeploymentRefresh) > _deploymentRefreshPeriod {
 				var err error
-				deployment, err = config.K8s.GetDeployment(operator.K8sName(apiName))
+				deployment, err = getActiveDeployment(apiName)
 				if err != nil {
 					telemetry.Error(err)
 					writeAndCloseSocket(socket, "error: "+errors.Message(err))
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cache"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/gcp"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/gorilla/websocket"
+	logging "google.golang.org/api/logging/v2"
+	kapps "k8s.io/api/apps/v1"
+)
+
+// Cloud Logging allows 60 read requests per minute per project, so each API is polled by a single poller which is shared by all of its log streams,
+// and the poller backs off when the quota is exceeded
+const (
+	_cloudLoggingPollPeriod    = 5 * time.Second
+	_cloudLoggingMaxPollPeriod = time.Minute
+)
+
+var (
+	_cloudLoggingPollers     = map[string]*cloudLoggingPoller{}
+	_cloudLoggingPollersLock sync.Mutex
+)
+
+type cloudLoggingMessage struct {
+	Text    string
+	IsFinal bool // the poller has stopped, and the socket should be closed once this message is written
+}
+
+type cloudLoggingPoller struct {
+	subscribers    map[chan cloudLoggingMessage]struct{}
+	recentMessages []string // replayed to streams which subscribe after the poller has started
+}
+
+func streamFromCloudLogging(apiName string, podCheckCancel chan struct{}, socket *websocket.Conn) {
+	writeString(socket, "fetching logs ...")
+
+	messages := subscribeToCloudLogging(apiName)
+	defer unsubscribeFromCloudLogging(apiName, messages)
+
+	for {
+		select {
+		case <-podCheckCancel:
+			return
+		case message, ok := <-messages:
+			if !ok {
+				// the poller stopped, and the final message was dropped because this stream fell behind
+				closeSocket(socket)
+				messages = nil
+				continue
+			}
+			if message.IsFinal {
+				writeAndCloseSocket(socket, message.Text)
+				messages = nil
+				continue
+			}
+			writeString(socket, message.Text)
+		}
+	}
+}
+
+func subscribeToCloudLogging(apiName string) chan cloudLoggingMessage {
+	_cloudLoggingPollersLock.Lock()
+	defer _cloudLoggingPollersLock.Unlock()
+
+	poller, ok := _cloudLoggingPollers[apiName]
+	if !ok {
+		poller = &cloudLoggingPoller{subscribers: map[chan cloudLoggingMessage]struct{}{}}
+		_cloudLoggingPollers[apiName] = poller
+		go poller.poll(apiName)
+	}
+
+	messages := make(chan cloudLoggingMessage, _maxLogLinesPerRequest+len(poller.recentMessages))
+	for _, text := range poller.recentMessages {
+		messages <- cloudLoggingMessage{Text: text}
+	}
+	poller.subscribers[messages] = struct{}{}
+
+	return messages
+}
+
+func unsubscribeFromCloudLogging(apiName string, messages chan cloudLoggingMessage) {
+	_cloudLoggingPollersLock.Lock()
+	defer _cloudLoggingPollersLock.Unlock()
+
+	if poller, ok := _cloudLoggingPollers[apiName]; ok {
+		delete(poller.subscribers, messages)
+	}
+}
+
+// removes the poller if all of its streams have unsubscribed (this is checked under the same lock as subscribeToCloudLogging, so that a new stream is never attached to a stopped poller)
+func (poller *cloudLoggingPoller) hasSubscribers(apiName string) bool {
+	_cloudLoggingPollersLock.Lock()
+	defer _cloudLoggingPollersLock.Unlock()
+
+	if len(poller.subscribers) == 0 {
+		delete(_cloudLoggingPollers, apiName)
+		return false
+	}
+	return true
+}
+
+// lines are dropped for streams which are not keeping up, rather than blocking the streams of other clients
+func (poller *cloudLoggingPoller) broadcast(texts []string) {
+	_cloudLoggingPollersLock.Lock()
+	defer _cloudLoggingPollersLock.Unlock()
+
+	for _, text := range texts {
+		poller.recentMessages = append(poller.recentMessages, text)
+		for subscriber := range poller.subscribers {
+			select {
+			case subscriber <- cloudLoggingMessage{Text: text}:
+			default:
+			}
+		}
+	}
+
+	if len(poller.recentMessages) > _maxLogLinesPerRequest {
+		poller.recentMessages = poller.recentMessages[len(poller.recentMessages)-_maxLogLinesPerRequest:]
+	}
+}
+
+func (poller *cloudLoggingPoller) stop(apiName string, text string) {
+	_cloudLoggingPollersLock.Lock()
+	defer _cloudLoggingPollersLock.Unlock()
+
+	delete(_cloudLoggingPollers, apiName)
+	for subscriber := range poller.subscribers {
+		select {
+		case subscriber <- cloudLoggingMessage{Text: text, IsFinal: true}:
+		default:
+		}
+		close(subscriber)
+	}
+	poller.subscribers = nil
+}
+
+func (poller *cloudLoggingPoller) poll(apiName string) {
+	entryCache := cache.NewFifoCache(_maxCacheSize)
+	lastDeploymentRefresh := time.Time{}
+	var lastLogTime time.Time
+	var deployment *kapps.Deployment
+	pollPeriod := _cloudLoggingPollPeriod
+
+	for ; poller.hasSubscribers(apiName); time.Sleep(pollPeriod) {
+		if deployment == nil || time.Since(lastDeploymentRefresh) > _deploymentRefreshPeriod {
+			var err error
+			deployment, err = getActiveDeployment(apiName)
+			if err != nil {
+				telemetry.Error(err)
+				poller.stop(apiName, "error: "+errors.Message(err))
+				return
+			}
+			lastDeploymentRefresh = time.Now()
+		}
+
+		if deployment == nil {
+			poller.stop(apiName, "\n"+apiName+" not found")
+			return
+		}
+
+		if lastLogTime.IsZero() {
+			lastLogTime = deployment.CreationTimestamp.Time
+		}
+
+		endTime := time.Now()
+
+		// entries can be ingested out of order, so the previous poll period is searched again (duplicates are skipped via the cache)
+		entries, err := config.GCP.ListLogEntries(cloudLoggingFilter(apiName, lastLogTime.Add(-_cloudLoggingPollPeriod), endTime), _maxLogLinesPerRequest)
+		if err != nil {
+			if gcp.IsErrCode(err, 429, nil) {
+				pollPeriod *= 2
+				if pollPeriod > _cloudLoggingMaxPollPeriod {
+					pollPeriod = _cloudLoggingMaxPollPeriod
+				}
+				continue
+			}
+			telemetry.Error(err)
+			poller.stop(apiName, "error encountered while fetching logs from cloud logging: "+errors.Message(err))
+			return
+		}
+		pollPeriod = _cloudLoggingPollPeriod
+
+		var texts []string
+		for _, entry := range entries {
+			if entryCache.Has(entry.InsertId) {
+				continue
+			}
+			entryCache.Add(entry.InsertId)
+
+			texts = append(texts, logEntryMessage(entry))
+
+			if timestamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil && timestamp.After(lastLogTime) {
+				lastLogTime = timestamp
+			}
+		}
+
+		if len(entries) == _maxLogLinesPerRequest {
+			texts = append(texts, "---- Showing at most "+s.Int(_maxLogLinesPerRequest)+" lines. Visit the GCP logs explorer for complete logs ----")
+			lastLogTime = endTime
+		}
+
+		poller.broadcast(texts)
+	}
+}
+
+func cloudLoggingFilter(apiName string, startTime time.Time, endTime time.Time, extraConditions ...string) string {
+	conditions := []string{
+		`resource.type="k8s_container"`,
+		fmt.Sprintf(`resource.labels.namespace_name="%s"`, config.K8s.Namespace),
+		fmt.Sprintf(`resource.labels.project_id="%s"`, *config.GCPCluster.Project),
+		fmt.Sprintf(`resource.labels.location="%s"`, *config.GCPCluster.Zone),
+		fmt.Sprintf(`resource.labels.cluster_name="%s"`, config.GCPCluster.ClusterName),
+		`resource.labels.container_name!="istio-proxy"`,
+		fmt.Sprintf(`labels."k8s-pod/apiName"="%s"`, apiName),
+		fmt.Sprintf(`timestamp>="%s"`, startTime.UTC().Format(time.RFC3339Nano)),
+		fmt.Sprintf(`timestamp<="%s"`, endTime.UTC().Format(time.RFC3339Nano)),
+	}
+	conditions = append(conditions, extraConditions...)
+	return strings.Join(conditions, " AND ")
+}
+
+// logs which are valid JSON are stored as structured payloads by the GKE logging agent
+func logEntryMessage(entry *logging.LogEntry) string {
+	if len(entry.JsonPayload) == 0 {
+		return strings.TrimSuffix(entry.TextPayload, "\n")
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(entry.JsonPayload, &payload); err == nil {
+		if message, ok := payload["message"].(string); ok {
+			return message
+		}
+	}
+
+	return string(entry.JsonPayload)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// registers a poller without starting its polling goroutine
+func testCloudLoggingPoller(t *testing.T, apiName string) *cloudLoggingPoller {
+	t.Helper()
+
+	poller := &cloudLoggingPoller{subscribers: map[chan cloudLoggingMessage]struct{}{}}
+	_cloudLoggingPollersLock.Lock()
+	_cloudLoggingPollers[apiName] = poller
+	_cloudLoggingPollersLock.Unlock()
+
+	t.Cleanup(func() {
+		_cloudLoggingPollersLock.Lock()
+		delete(_cloudLoggingPollers, apiName)
+		_cloudLoggingPollersLock.Unlock()
+	})
+
+	return poller
+}
+
+func receiveCloudLoggingMessages(messages chan cloudLoggingMessage) []cloudLoggingMessage {
+	var received []cloudLoggingMessage
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return received
+			}
+			received = append(received, message)
+		default:
+			return received
+		}
+	}
+}
+
+func TestCloudLoggingPollerIsShared(t *testing.T) {
+	poller := testCloudLoggingPoller(t, "test-api")
+
+	first := subscribeToCloudLogging("test-api")
+	poller.broadcast([]string{"a", "b"})
+	second := subscribeToCloudLogging("test-api")
+	poller.broadcast([]string{"c"})
+
+	require.Len(t, poller.subscribers, 2)
+	require.Equal(t, []cloudLoggingMessage{{Text: "a"}, {Text: "b"}, {Text: "c"}}, receiveCloudLoggingMessages(first))
+	require.Equal(t, []cloudLoggingMessage{{Text: "a"}, {Text: "b"}, {Text: "c"}}, receiveCloudLoggingMessages(second))
+
+	unsubscribeFromCloudLogging("test-api", first)
+	require.True(t, poller.hasSubscribers("test-api"))
+	unsubscribeFromCloudLogging("test-api", second)
+	require.False(t, poller.hasSubscribers("test-api"))
+
+	_cloudLoggingPollersLock.Lock()
+	_, ok := _cloudLoggingPollers["test-api"]
+	_cloudLoggingPollersLock.Unlock()
+	require.False(t, ok)
+}
+
+func TestCloudLoggingPollerRecentMessages(t *testing.T) {
+	poller := testCloudLoggingPoller(t, "test-api")
+
+	texts := make([]string, _maxLogLinesPerRequest+10)
+	for i := range texts {
+		texts[i] = "line"
+	}
+	poller.broadcast(texts)
+	require.Len(t, poller.recentMessages, _maxLogLinesPerRequest)
+
+	messages := subscribeToCloudLogging("test-api")
+	require.Len(t, receiveCloudLoggingMessages(messages), _maxLogLinesPerRequest)
+}
+
+func TestCloudLoggingPollerDropsLinesForSlowStreams(t *testing.T) {
+	poller := testCloudLoggingPoller(t, "test-api")
+
+	messages := subscribeToCloudLogging("test-api")
+	for i := 0; i < 3; i++ {
+		texts := make([]string, _maxLogLinesPerRequest)
+		for j := range texts {
+			texts[j] = "line"
+		}
+		poller.broadcast(texts)
+	}
+
+	require.Len(t, receiveCloudLoggingMessages(messages), _maxLogLinesPerRequest)
+}
+
+func TestCloudLoggingPollerStop(t *testing.T) {
+	poller := testCloudLoggingPoller(t, "test-api")
+
+	messages := subscribeToCloudLogging("test-api")
+	poller.broadcast([]string{"a"})
+	poller.stop("test-api", "test-api not found")
+
+	require.Equal(t, []cloudLoggingMessage{{Text: "a"}, {Text: "test-api not found", IsFinal: true}}, receiveCloudLoggingMessages(messages))
+
+	_cloudLoggingPollersLock.Lock()
+	_, ok := _cloudLoggingPollers["test-api"]
+	_cloudLoggingPollersLock.Unlock()
+	require.False(t, ok)
+
+	// unsubscribing after the poller has stopped is a no-op
+	unsubscribeFromCloudLogging("test-api", messages)
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+)
+
+const (
+	_maxLogSearchLinesPerPage = 1000
+	_maxLogSearchLinesScanned = 10000 // the maximum number of lines which are read from cloudwatch to build a page
+	_maxLogSearchStreams      = 100   // the maximum number of log stream names which FilterLogEvents accepts
+)
+
+// LogSearch filters the historical logs of an api
+type LogSearch struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Grep      string // only return lines which contain this (case-sensitive on AWS)
+	Replica   string // only return lines from the replica with this name
+	NextToken string
+}
+
+// SearchLogs returns a page of the api's logs which match the search, in ascending order of timestamp
+func SearchLogs(apiName string, search LogSearch) (*schema.LogSearchResponse, error) {
+	var response *schema.LogSearchResponse
+	var err error
+	// cloud logging orders entries across pages (see ListLogEntriesPage()), but cloudwatch doesn't
+	if config.Provider == types.GCPProviderType {
+		response, err = searchCloudLogging(apiName, search)
+	} else {
+		response, err = searchCloudWatch(apiName, search)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// The pages of cloudwatch search results are built from time windows which are read in full and then sorted, since FilterLogEvents doesn't
+// order events across log streams. If a window has too many lines to read, it is narrowed until it doesn't. Each page's token is a
+// logSearchCursor, which points to the first line which hasn't been returned yet
+func searchCloudWatch(apiName string, search LogSearch) (*schema.LogSearchResponse, error) {
+	logGroupName := getLogGroupName(apiName)
+
+	cursor := logSearchCursor{Timestamp: libtime.ToMillis(search.StartTime)}
+	if search.NextToken != "" {
+		var err error
+		cursor, err = parseLogSearchCursor(search.NextToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+	endTime := libtime.ToMillis(search.EndTime)
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroupName),
+	}
+
+	if search.Grep != "" {
+		input.FilterPattern = aws.String(`"` + strings.ReplaceAll(search.Grep, `"`, `\"`) + `"`)
+	}
+
+	if search.Replica != "" {
+		logStreamNames, err := getReplicaLogStreams(logGroupName, search.Replica)
+		if err != nil {
+			return nil, err
+		}
+		if len(logStreamNames) == 0 {
+			return &schema.LogSearchResponse{Lines: []schema.LogLine{}}, nil
+		}
+		input.LogStreamNames = aws.StringSlice(logStreamNames.Slice())
+	}
+
+	windowEnd := endTime
+	var lines []schema.LogLine
+	for {
+		var isComplete bool
+		var err error
+		lines, isComplete, err = filterCloudWatchLogs(input, cursor.Timestamp, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+		// a window of a single millisecond can't be narrowed further
+		if isComplete || windowEnd <= cursor.Timestamp {
+			break
+		}
+		windowEnd = cursor.Timestamp + (windowEnd-cursor.Timestamp)/2
+	}
+
+	sortLogLines(lines)
+	return logSearchPage(lines, cursor, windowEnd, endTime, _maxLogSearchLinesPerPage), nil
+}
+
+// returns the lines between startTime and endTime (inclusive), and whether all of them were read
+func filterCloudWatchLogs(input *cloudwatchlogs.FilterLogEventsInput, startTime int64, endTime int64) ([]schema.LogLine, bool, error) {
+	windowInput := *input
+	windowInput.StartTime = aws.Int64(startTime)
+	windowInput.EndTime = aws.Int64(endTime)
+
+	lines := []schema.LogLine{}
+	isComplete := true
+	err := config.AWS.CloudWatchLogs().FilterLogEventsPages(&windowInput, func(output *cloudwatchlogs.FilterLogEventsOutput, lastPage bool) bool {
+		for _, logEvent := range output.Events {
+			message := aws.StringValue(logEvent.Message)
+			var log fluentdLog
+			if err := json.Unmarshal([]byte(message), &log); err == nil {
+				message = log.Log
+			}
+
+			lines = append(lines, schema.LogLine{
+				Timestamp: aws.Int64Value(logEvent.Timestamp),
+				Replica:   replicaFromLogStream(aws.StringValue(logEvent.LogStreamName)),
+				Message:   message,
+			})
+		}
+
+		if len(lines) > _maxLogSearchLinesScanned && !lastPage {
+			isComplete = false
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		if awslib.IsErrCode(err, cloudwatchlogs.ErrCodeResourceNotFoundException) {
+			return []schema.LogLine{}, true, nil
+		}
+		return nil, false, err
+	}
+
+	return lines, isComplete, nil
+}
+
+// logSearchCursor is the position of the next page of cloudwatch search results: the page starts at Timestamp, after the first Skip lines
+// with that timestamp (which were returned by previous pages)
+type logSearchCursor struct {
+	Timestamp int64
+	Skip      int
+}
+
+func (cursor logSearchCursor) String() string {
+	return fmt.Sprintf("%d-%d", cursor.Timestamp, cursor.Skip)
+}
+
+func parseLogSearchCursor(token string) (logSearchCursor, error) {
+	split := strings.Split(token, "-")
+	if len(split) != 2 {
+		return logSearchCursor{}, ErrorInvalidLogSearchToken(token)
+	}
+
+	timestamp, err := strconv.ParseInt(split[0], 10, 64)
+	if err != nil {
+		return logSearchCursor{}, ErrorInvalidLogSearchToken(token)
+	}
+	skip, err := strconv.Atoi(split[1])
+	if err != nil || skip < 0 {
+		return logSearchCursor{}, ErrorInvalidLogSearchToken(token)
+	}
+
+	return logSearchCursor{Timestamp: timestamp, Skip: skip}, nil
+}
+
+// lines are sorted by timestamp; the order of lines with the same timestamp must be deterministic, so that pages can skip the lines which
+// were returned by previous pages
+func sortLogLines(lines []schema.LogLine) {
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Timestamp != lines[j].Timestamp {
+			return lines[i].Timestamp < lines[j].Timestamp
+		}
+		if lines[i].Replica != lines[j].Replica {
+			return lines[i].Replica < lines[j].Replica
+		}
+		return lines[i].Message < lines[j].Message
+	})
+}
+
+// lines are all of the sorted lines from cursor.Timestamp to windowEnd (inclusive), and endTime is the end of the search
+func logSearchPage(lines []schema.LogLine, cursor logSearchCursor, windowEnd int64, endTime int64, maxLines int) *schema.LogSearchResponse {
+	skip := 0
+	for skip < cursor.Skip && skip < len(lines) && lines[skip].Timestamp == cursor.Timestamp {
+		skip++
+	}
+	lines = lines[skip:]
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lastTimestamp := lines[len(lines)-1].Timestamp
+
+		next := logSearchCursor{Timestamp: lastTimestamp}
+		if lastTimestamp == cursor.Timestamp {
+			next.Skip = cursor.Skip
+		}
+		for _, line := range lines {
+			if line.Timestamp == lastTimestamp {
+				next.Skip++
+			}
+		}
+
+		return &schema.LogSearchResponse{Lines: lines, NextToken: next.String()}
+	}
+
+	response := &schema.LogSearchResponse{Lines: lines}
+	if windowEnd < endTime {
+		response.NextToken = logSearchCursor{Timestamp: windowEnd + 1}.String()
+	}
+	return response
+}
+
+// log streams are named <predictor_id>_<pod_name>_<container_name>
+func replicaFromLogStream(logStreamName string) string {
+	split := strings.Split(logStreamName, "_")
+	if len(split) < 3 {
+		return ""
+	}
+	return strings.Join(split[1:len(split)-1], "_")
+}
+
+func getReplicaLogStreams(logGroupName string, replica string) (strset.Set, error) {
+	streams := strset.New()
+	err := config.AWS.CloudWatchLogs().DescribeLogStreamsPages(
+		&cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: aws.String(logGroupName),
+		}, func(output *cloudwatchlogs.DescribeLogStreamsOutput, lastPage bool) bool {
+			for _, stream := range output.LogStreams {
+				if replicaFromLogStream(aws.StringValue(stream.LogStreamName)) == replica {
+					streams.Add(*stream.LogStreamName)
+				}
+			}
+			return len(streams) < _maxLogSearchStreams
+		},
+	)
+	if err != nil {
+		if awslib.IsErrCode(err, cloudwatchlogs.ErrCodeResourceNotFoundException) {
+			return strset.New(), nil
+		}
+		return nil, err
+	}
+
+	return streams, nil
+}
+
+func searchCloudLogging(apiName string, search LogSearch) (*schema.LogSearchResponse, error) {
+	var conditions []string
+	if search.Replica != "" {
+		conditions = append(conditions, fmt.Sprintf(`resource.labels.pod_name="%s"`, search.Replica))
+	}
+	if search.Grep != "" {
+		grep := strings.ReplaceAll(search.Grep, `"`, `\"`)
+		conditions = append(conditions, fmt.Sprintf(`(textPayload:"%s" OR jsonPayload.message:"%s")`, grep, grep))
+	}
+
+	entries, nextToken, err := config.GCP.ListLogEntriesPage(cloudLoggingFilter(apiName, search.StartTime, search.EndTime, conditions...), _maxLogSearchLinesPerPage, search.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]schema.LogLine, 0, len(entries))
+	for _, entry := range entries {
+		var timestamp int64
+		if t, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+			timestamp = libtime.ToMillis(t)
+		}
+
+		var replica string
+		if entry.Resource != nil {
+			replica = entry.Resource.Labels["pod_name"]
+		}
+
+		lines = append(lines, schema.LogLine{
+			Timestamp: timestamp,
+			Replica:   replica,
+			Message:   logEntryMessage(entry),
+		})
+	}
+
+	return &schema.LogSearchResponse{
+		Lines:     lines,
+		NextToken: nextToken,
+	}, nil
+}
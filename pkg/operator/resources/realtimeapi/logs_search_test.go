@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSearchCursor(t *testing.T) {
+	cursor, err := parseLogSearchCursor(logSearchCursor{Timestamp: 1600000000123, Skip: 4}.String())
+	require.NoError(t, err)
+	require.Equal(t, logSearchCursor{Timestamp: 1600000000123, Skip: 4}, cursor)
+
+	for _, token := range []string{"", "1600000000123", "a-4", "1600000000123-b", "1600000000123--4", "1-2-3"} {
+		_, err := parseLogSearchCursor(token)
+		require.Error(t, err, token)
+		require.Equal(t, ErrInvalidLogSearchToken, errors.GetKind(err), token)
+	}
+}
+
+func TestSortLogLines(t *testing.T) {
+	lines := []schema.LogLine{
+		{Timestamp: 3, Replica: "a", Message: "5"},
+		{Timestamp: 1, Replica: "b", Message: "2"},
+		{Timestamp: 2, Replica: "a", Message: "4"},
+		{Timestamp: 1, Replica: "a", Message: "1"},
+		{Timestamp: 1, Replica: "b", Message: "3"},
+	}
+	sortLogLines(lines)
+
+	var messages []string
+	for _, line := range lines {
+		messages = append(messages, line.Message)
+	}
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, messages)
+}
+
+func TestLogSearchPages(t *testing.T) {
+	// lines which were read from the search's time range, sorted
+	allLines := []schema.LogLine{
+		{Timestamp: 10, Message: "a"},
+		{Timestamp: 11, Message: "b"},
+		{Timestamp: 11, Message: "c"},
+		{Timestamp: 11, Message: "d"},
+		{Timestamp: 11, Message: "e"},
+		{Timestamp: 12, Message: "f"},
+		{Timestamp: 14, Message: "g"},
+	}
+	linesFrom := func(cursor logSearchCursor, windowEnd int64) []schema.LogLine {
+		var lines []schema.LogLine
+		for _, line := range allLines {
+			if line.Timestamp >= cursor.Timestamp && line.Timestamp <= windowEnd {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	for _, tc := range []struct {
+		name      string
+		maxLines  int
+		windowLen int64 // the length of the time window which is read for each page (the window ends at the end of the search if zero)
+		pages     [][]string
+	}{
+		{
+			name:     "single page",
+			maxLines: 10,
+			pages:    [][]string{{"a", "b", "c", "d", "e", "f", "g"}},
+		},
+		{
+			name:     "pages which end with lines with the same timestamp",
+			maxLines: 2,
+			pages:    [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}, {"g"}},
+		},
+		{
+			name:     "page which starts and ends with the same timestamp",
+			maxLines: 3,
+			pages:    [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g"}},
+		},
+		{
+			name:      "narrowed windows",
+			maxLines:  10,
+			windowLen: 2,
+			pages:     [][]string{{"a", "b", "c", "d", "e"}, {"f"}, {"g"}},
+		},
+		{
+			name:      "narrowed windows and pages",
+			maxLines:  2,
+			windowLen: 2,
+			pages:     [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}, {"g"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			endTime := int64(15)
+			cursor := logSearchCursor{Timestamp: 10}
+
+			var pages [][]string
+			for i := 0; i < 10; i++ {
+				windowEnd := endTime
+				if tc.windowLen > 0 && cursor.Timestamp+tc.windowLen-1 < endTime {
+					windowEnd = cursor.Timestamp + tc.windowLen - 1
+				}
+
+				response := logSearchPage(linesFrom(cursor, windowEnd), cursor, windowEnd, endTime, tc.maxLines)
+				var messages []string
+				for _, line := range response.Lines {
+					messages = append(messages, line.Message)
+				}
+				if len(messages) > 0 {
+					pages = append(pages, messages)
+				}
+
+				if response.NextToken == "" {
+					break
+				}
+				var err error
+				cursor, err = parseLogSearchCursor(response.NextToken)
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.pages, pages)
+		})
+	}
+}
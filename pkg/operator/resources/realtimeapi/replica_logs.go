@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+const _replicaLogsTailLines = 50
+
+// GetFailedReplicaLogs returns the recent logs of the api's up-to-date replicas which are not ready (the candidate's replicas during a blue/green update)
+func GetFailedReplicaLogs(apiName string) ([]schema.ReplicaLogs, error) {
+	var deployment *kapps.Deployment
+	var pods []kcore.Pod
+
+	err := parallel.RunFirstErr(
+		func() error {
+			var err error
+			activeDeployment, candidate, err := getAPIDeployments(apiName)
+			deployment = activeDeployment
+			if candidate != nil && !isDraining(candidate) {
+				deployment = candidate
+			}
+			return err
+		},
+		func() error {
+			var err error
+			pods, err = config.K8s.ListPodsByLabel("apiName", apiName)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment == nil {
+		return nil, errors.ErrorUnexpected("unable to find deployment", apiName)
+	}
+
+	replicaLogs := []schema.ReplicaLogs{}
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodOfDeployment(pod, deployment) || !isPodSpecLatest(deployment, pod) || k8s.IsPodReady(pod) {
+			continue
+		}
+
+		logs, err := config.K8s.GetPodLogs(pod.Name, operator.APIContainerName, _replicaLogsTailLines, shouldReadPreviousContainerLogs(pod))
+		if err != nil {
+			// e.g. the container has not been created yet
+			logs = ""
+		}
+
+		replicaLogs = append(replicaLogs, schema.ReplicaLogs{
+			Name:   pod.Name,
+			Status: string(k8s.GetPodStatus(pod)),
+			Logs:   logs,
+		})
+	}
+
+	sort.Slice(replicaLogs, func(i, j int) bool {
+		return replicaLogs[i].Name < replicaLogs[j].Name
+	})
+
+	return replicaLogs, nil
+}
+
+// the current container of a crash-looping replica has no logs, so the previous container's logs are more useful
+func shouldReadPreviousContainerLogs(pod *kcore.Pod) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == operator.APIContainerName {
+			return containerStatus.RestartCount > 0 && containerStatus.State.Running == nil
+		}
+	}
+	return false
+}
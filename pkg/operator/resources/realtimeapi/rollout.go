@@ -0,0 +1,284 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/events"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+const (
+	ManageRolloutsCronPeriod = 10 * time.Second
+
+	_rolloutStatusAnnotationKey        = "rollout.cortex.dev/status"
+	_rolloutStartedAtAnnotationKey     = "rollout.cortex.dev/started-at"
+	_rolloutPreviousAPIIDAnnotationKey = "rollout.cortex.dev/previous-api-id" // the most recent api id which finished rolling out
+
+	_rolloutInProgress = "in_progress"
+	_rolloutComplete   = "complete"
+	_rolloutFailed     = "failed"
+)
+
+var _rolloutAnnotationKeys = []string{
+	_rolloutStatusAnnotationKey,
+	_rolloutStartedAtAnnotationKey,
+	_rolloutPreviousAPIIDAnnotationKey,
+}
+
+// setRolloutAnnotations records the start of a rolling update on the new deployment, or carries over the state of the current one if the pod template didn't change
+func setRolloutAnnotations(deployment *kapps.Deployment, prevDeployment *kapps.Deployment) {
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+
+	if prevDeployment != nil && !didPodTemplateChange(prevDeployment, deployment) {
+		for _, key := range _rolloutAnnotationKeys {
+			if val, ok := prevDeployment.Annotations[key]; ok {
+				deployment.Annotations[key] = val
+			}
+		}
+		return
+	}
+
+	deployment.Annotations[_rolloutStatusAnnotationKey] = _rolloutInProgress
+	deployment.Annotations[_rolloutStartedAtAnnotationKey] = time.Now().Format(time.RFC3339)
+
+	if prevDeployment == nil {
+		return
+	}
+
+	previousAPIID := prevDeployment.Labels["apiID"]
+	if prevStatus, ok := prevDeployment.Annotations[_rolloutStatusAnnotationKey]; ok && prevStatus != _rolloutComplete {
+		// the previous version never finished rolling out, so the version to roll back to is the one before it
+		previousAPIID = prevDeployment.Annotations[_rolloutPreviousAPIIDAnnotationKey]
+	}
+	if previousAPIID != "" {
+		deployment.Annotations[_rolloutPreviousAPIIDAnnotationKey] = previousAPIID
+	}
+}
+
+func didPodTemplateChange(prevDeployment *kapps.Deployment, deployment *kapps.Deployment) bool {
+	return prevDeployment.Spec.Template.Labels["predictorID"] != deployment.Spec.Template.Labels["predictorID"] ||
+		prevDeployment.Spec.Template.Labels["deploymentID"] != deployment.Spec.Template.Labels["deploymentID"]
+}
+
+func isRolloutFailed(deployment *kapps.Deployment) bool {
+	return deployment.Annotations[_rolloutStatusAnnotationKey] == _rolloutFailed
+}
+
+// ManageRollouts marks rolling updates as complete or failed, rolls back failed updates if auto_rollback is enabled, and switches traffic / tears down deployments for blue/green updates
+func ManageRollouts() error {
+	deployments, err := config.K8s.ListDeploymentsByLabel("apiKind", userconfig.RealtimeAPIKind.String())
+	if err != nil {
+		return err
+	}
+
+	pods, err := config.K8s.ListPodsByLabel("apiKind", userconfig.RealtimeAPIKind.String())
+	if err != nil {
+		return err
+	}
+
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		if isDraining(deployment) {
+			if !isTeardownDue(deployment) {
+				continue
+			}
+			if err := teardownDeployment(deployment); err != nil {
+				err = errors.Wrap(err, deployment.Labels["apiName"])
+				telemetry.Error(err)
+				errors.PrintError(err)
+			}
+			continue
+		}
+
+		if deployment.Annotations[_rolloutStatusAnnotationKey] != _rolloutInProgress {
+			continue
+		}
+
+		if err := manageRollout(deployment, pods); err != nil {
+			err = errors.Wrap(err, deployment.Labels["apiName"])
+			telemetry.Error(err)
+			errors.PrintError(err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+func manageRollout(deployment *kapps.Deployment, pods []kcore.Pod) error {
+	counts := getReplicaCounts(deployment, pods)
+
+	if counts.Updated.Ready >= counts.Requested && counts.Stale.Ready == 0 && counts.Stale.Initializing == 0 && counts.Stale.Pending == 0 {
+		if !IsActiveDeployment(deployment) {
+			return switchToCandidate(deployment)
+		}
+		return setRolloutStatus(deployment, _rolloutComplete)
+	}
+
+	autoscalingSpec, err := userconfig.AutoscalingFromAnnotations(deployment)
+	if err != nil {
+		return err
+	}
+
+	var reason string
+	switch statusCode := getStatusCode(&counts, autoscalingSpec.MinReplicas); statusCode {
+	case status.Error, status.ErrorImagePull, status.OOM, status.Stalled:
+		reason = fmt.Sprintf("updated replicas failed to start: %s", statusCode.Message())
+	default:
+		progressDeadline, err := k8s.ParseDurationAnnotation(deployment, userconfig.ProgressDeadlineAnnotationKey)
+		if err != nil {
+			return err
+		}
+		startedAt, err := time.Parse(time.RFC3339, deployment.Annotations[_rolloutStartedAtAnnotationKey])
+		if err != nil {
+			return errors.Wrap(err, _rolloutStartedAtAnnotationKey)
+		}
+		if time.Since(startedAt) > progressDeadline {
+			reason = fmt.Sprintf("updated replicas did not become ready within the %s (%s)", userconfig.ProgressDeadlineKey, progressDeadline.String())
+		}
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	return failRollout(deployment, reason)
+}
+
+func failRollout(deployment *kapps.Deployment, reason string) error {
+	apiName := deployment.Labels["apiName"]
+	apiID := deployment.Labels["apiID"]
+
+	autoRollback, err := k8s.ParseBoolAnnotation(deployment, userconfig.AutoRollbackAnnotationKey)
+	if err != nil {
+		return err
+	}
+
+	// a failed blue/green candidate never received traffic, so it is torn down rather than rolled back
+	isCandidate := !IsActiveDeployment(deployment)
+
+	previousAPIID := deployment.Annotations[_rolloutPreviousAPIIDAnnotationKey]
+	shouldRollBack := !isCandidate && autoRollback && previousAPIID != "" && previousAPIID != apiID
+
+	rolloutFailure := schema.RolloutFailure{
+		Reason:   reason,
+		FailedAt: time.Now().Unix(),
+	}
+	if shouldRollBack {
+		rolloutFailure.RolledBackTo = previousAPIID
+	}
+
+	if err := config.UploadJSONToBucket(rolloutFailure, spec.RolloutFailedKey(apiName, apiID, config.ClusterName())); err != nil {
+		return errors.Wrap(err, "upload rollout failure")
+	}
+
+	operator.PublishEvent(events.Event{
+		Type:      events.UpdateFailed,
+		APIName:   apiName,
+		APIKind:   userconfig.RealtimeAPIKind,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("update to api id %s failed: %s", apiID, reason),
+	})
+
+	if isCandidate {
+		return teardownDeployment(deployment)
+	}
+
+	// the failed deployment is marked before rolling back, so that it remains marked as failed if the rollback doesn't succeed
+	if err := setRolloutStatus(deployment, _rolloutFailed); err != nil {
+		return err
+	}
+
+	if !shouldRollBack {
+		return nil
+	}
+
+	if err := rollBackAPI(apiName, previousAPIID); err != nil {
+		return errors.Wrap(err, "roll back to api id "+previousAPIID)
+	}
+
+	operator.PublishEvent(events.Event{
+		Type:      events.RolledBack,
+		APIName:   apiName,
+		APIKind:   userconfig.RealtimeAPIKind,
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("rolled back to api id %s", previousAPIID),
+	})
+
+	return nil
+}
+
+// rollBackAPI re-applies a previously deployed api spec
+func rollBackAPI(apiName string, apiID string) error {
+	api, err := operator.DownloadAPISpec(apiName, apiID)
+	if err != nil {
+		return err
+	}
+
+	prevDeployment, prevService, prevVirtualService, err := getK8sResources(api.API)
+	if err != nil {
+		return err
+	}
+
+	if err := applyK8sResources(api, prevDeployment, prevService, prevVirtualService); err != nil {
+		return err
+	}
+
+	if config.Provider == types.AWSProviderType {
+		if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api); err != nil {
+			return err
+		}
+	}
+
+	// the restored api id had already finished rolling out, so its deployment is marked as complete (rather than as an update in progress,
+	// which could be rolled back again if its replicas take longer than the progress deadline to restart)
+	restoredDeployment, err := getActiveDeployment(apiName)
+	if err != nil {
+		return err
+	}
+	if restoredDeployment == nil {
+		return errors.ErrorUnexpected("deployment not found after rolling back", apiName)
+	}
+	restoredDeployment.Annotations[_rolloutPreviousAPIIDAnnotationKey] = apiID
+	return setRolloutStatus(restoredDeployment, _rolloutComplete)
+}
+
+func setRolloutStatus(deployment *kapps.Deployment, rolloutStatus string) error {
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[_rolloutStatusAnnotationKey] = rolloutStatus
+	_, err := config.K8s.UpdateDeployment(deployment)
+	return err
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
-	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	kapps "k8s.io/api/apps/v1"
@@ -40,7 +39,7 @@ func GetStatus(apiName string) (*status.Status, error) {
 	err := parallel.RunFirstErr(
 		func() error {
 			var err error
-			deployment, err = config.K8s.GetDeployment(operator.K8sName(apiName))
+			deployment, err = getActiveDeployment(apiName)
 			return err
 		},
 		func() error {
@@ -61,13 +60,16 @@ func GetStatus(apiName string) (*status.Status, error) {
 }
 
 func GetAllStatuses(deployments []kapps.Deployment, pods []kcore.Pod) ([]status.Status, error) {
-	statuses := make([]status.Status, len(deployments))
-	for i, deployment := range deployments {
-		status, err := apiStatus(&deployment, pods)
+	statuses := make([]status.Status, 0, len(deployments))
+	for i := range deployments {
+		if !IsActiveDeployment(&deployments[i]) {
+			continue
+		}
+		status, err := apiStatus(&deployments[i], pods)
 		if err != nil {
 			return nil, err
 		}
-		statuses[i] = *status
+		statuses = append(statuses, *status)
 	}
 
 	sort.Slice(statuses, func(i, j int) bool {
@@ -97,7 +99,7 @@ func getReplicaCounts(deployment *kapps.Deployment, pods []kcore.Pod) status.Rep
 	counts.Requested = *deployment.Spec.Replicas
 
 	for _, pod := range pods {
-		if pod.Labels["apiName"] != deployment.Labels["apiName"] {
+		if !isPodOfDeployment(&pod, deployment) {
 			continue
 		}
 		addPodToReplicaCounts(&pod, deployment, &counts)
@@ -480,27 +480,59 @@ func GetAPIByID(apiName string, apiID string) ([]schema.APIResponse, error) {
 }
 
 func getPastAPIDeploys(apiName string) ([]schema.APIVersion, error) {
-	var apiVersions []schema.APIVersion
-
 	apiIDs, err := config.ListBucketDirOneLevel(spec.KeysPrefix(apiName, config.ClusterName()), pointer.Int64(10))
 	if err != nil {
 		return nil, err
 	}
 
-	for _, apiID := range apiIDs {
-		lastUpdated, err := spec.TimeFromAPIID(apiID)
+	apiVersions := make([]schema.APIVersion, len(apiIDs))
+	fns := make([]func() error, len(apiIDs))
+	for i := range apiIDs {
+		localIdx := i
+		fns[i] = func() error {
+			apiID := apiIDs[localIdx]
+			lastUpdated, err := spec.TimeFromAPIID(apiID)
+			if err != nil {
+				return err
+			}
+			rolloutFailure, err := getRolloutFailure(apiName, apiID)
+			if err != nil {
+				return err
+			}
+			apiVersions[localIdx] = schema.APIVersion{
+				APIID:          apiID,
+				LastUpdated:    lastUpdated.Unix(),
+				RolloutFailure: rolloutFailure,
+			}
+			return nil
+		}
+	}
+
+	if len(fns) > 0 {
+		err := parallel.RunFirstErr(fns[0], fns[1:]...)
 		if err != nil {
 			return nil, err
 		}
-		apiVersions = append(apiVersions, schema.APIVersion{
-			APIID:       apiID,
-			LastUpdated: lastUpdated.Unix(),
-		})
 	}
 
 	return apiVersions, nil
 }
 
+// returns nil if the api id did not fail to roll out
+func getRolloutFailure(apiName string, apiID string) (*schema.RolloutFailure, error) {
+	key := spec.RolloutFailedKey(apiName, apiID, config.ClusterName())
+	exists, err := config.IsBucketFile(key)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var rolloutFailure schema.RolloutFailure
+	if err := config.ReadJSONFromBucket(&rolloutFailure, key); err != nil {
+		return nil, err
+	}
+	return &rolloutFailure, nil
+}
+
 //checkIfUsedByTrafficSplitter checks if api is used by a deployed TrafficSplitter
 func checkIfUsedByTrafficSplitter(apiName string) error {
 	virtualServices, err := config.K8s.ListVirtualServicesByLabel("apiKind", userconfig.TrafficSplitterKind.String())
@@ -0,0 +1,245 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcore "k8s.io/api/core/v1"
+)
+
+// SetSecret creates the secret if it doesn't exist, otherwise the provided keys are merged into the existing secret
+func SetSecret(secretName string, data map[string]string) (*schema.SecretResponse, error) {
+	if err := urls.CheckDNS1123(secretName); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrorSecretNoKeys()
+	}
+	for key := range data {
+		if !regex.IsAlphaNumericDashDotUnderscore(key) {
+			return nil, ErrorInvalidSecretKey(key)
+		}
+	}
+
+	existing, err := getUserSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData := map[string][]byte{}
+	if existing != nil {
+		for key, val := range existing.Data {
+			secretData[key] = val
+		}
+	}
+	for key, val := range data {
+		secretData[key] = []byte(val)
+	}
+
+	secret := k8s.Secret(&k8s.SecretSpec{
+		Name: secretName,
+		Data: secretData,
+		Labels: map[string]string{
+			userconfig.SecretLabelKey: "true",
+		},
+	})
+	if existing != nil {
+		secret.ResourceVersion = existing.ResourceVersion
+	}
+
+	if _, err := config.K8s.ApplySecret(secret); err != nil {
+		return nil, err
+	}
+
+	keys := maps.StrMapKeys(data)
+	sort.Strings(keys)
+
+	return &schema.SecretResponse{
+		Message: fmt.Sprintf("set %s in secret %s", s.StrsAnd(keys), secretName),
+	}, nil
+}
+
+// ListSecrets returns the names and keys of all user secrets (values are never returned)
+func ListSecrets() ([]schema.SecretSummary, error) {
+	secrets, err := config.K8s.ListSecretsByLabel(userconfig.SecretLabelKey, "true")
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy, err := getSecretUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]schema.SecretSummary, 0, len(secrets))
+	for _, secret := range secrets {
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		apiNames := usedBy.apisUsingSecret(secret.Name).SliceSorted()
+
+		summaries = append(summaries, schema.SecretSummary{
+			Name:      secret.Name,
+			Keys:      keys,
+			UsedBy:    apiNames,
+			CreatedAt: secret.CreationTimestamp.Unix(),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, nil
+}
+
+// DeleteSecret deletes a single key from the secret if key is not empty, otherwise the entire secret is deleted
+func DeleteSecret(secretName string, key string, force bool) (*schema.SecretResponse, error) {
+	existing, err := getUserSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrorSecretNotFound(secretName)
+	}
+
+	if key != "" {
+		if _, ok := existing.Data[key]; !ok {
+			return nil, ErrorSecretKeyNotFound(secretName, key)
+		}
+	}
+
+	if !force {
+		usedBy, err := getSecretUsage()
+		if err != nil {
+			return nil, err
+		}
+		apiNames := usedBy.apisUsingSecret(secretName)
+		if key != "" {
+			apiNames = usedBy.apisUsingKey(secretName, key)
+		}
+		if len(apiNames) > 0 {
+			return nil, ErrorSecretUsedByAPIs(secretName, key, apiNames.SliceSorted())
+		}
+	}
+
+	if key == "" || len(existing.Data) == 1 {
+		if _, err := config.K8s.DeleteSecret(secretName); err != nil {
+			return nil, err
+		}
+		return &schema.SecretResponse{
+			Message: fmt.Sprintf("deleted secret %s", secretName),
+		}, nil
+	}
+
+	delete(existing.Data, key)
+	if _, err := config.K8s.UpdateSecret(existing); err != nil {
+		return nil, err
+	}
+
+	return &schema.SecretResponse{
+		Message: fmt.Sprintf("deleted %s from secret %s", key, secretName),
+	}, nil
+}
+
+// returns an error if a secret with the name exists but is not managed by cortex
+func getUserSecret(secretName string) (*kcore.Secret, error) {
+	secret, err := config.K8s.GetSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	if secret.Labels[userconfig.SecretLabelKey] != "true" {
+		return nil, ErrorSecretNameReserved(secretName)
+	}
+	return secret, nil
+}
+
+// secret name -> secret key -> names of the apis which reference the key
+type secretUsage map[string]map[string]strset.Set
+
+func (usage secretUsage) apisUsingSecret(secretName string) strset.Set {
+	apiNames := strset.New()
+	for _, keyAPINames := range usage[secretName] {
+		apiNames.Merge(keyAPINames)
+	}
+	return apiNames
+}
+
+func (usage secretUsage) apisUsingKey(secretName string, key string) strset.Set {
+	if apiNames, ok := usage[secretName][key]; ok {
+		return apiNames
+	}
+	return strset.New()
+}
+
+func getSecretUsage() (secretUsage, error) {
+	deployments, err := config.K8s.ListDeploymentsWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := config.K8s.ListJobsWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy := secretUsage{}
+	addUsage := func(apiName string, podSpec kcore.PodSpec) {
+		for _, container := range podSpec.Containers {
+			for _, envVar := range container.Env {
+				if envVar.ValueFrom == nil || envVar.ValueFrom.SecretKeyRef == nil {
+					continue
+				}
+				secretName := envVar.ValueFrom.SecretKeyRef.Name
+				key := envVar.ValueFrom.SecretKeyRef.Key
+				if _, ok := usedBy[secretName]; !ok {
+					usedBy[secretName] = map[string]strset.Set{}
+				}
+				if _, ok := usedBy[secretName][key]; !ok {
+					usedBy[secretName][key] = strset.New()
+				}
+				usedBy[secretName][key].Add(apiName)
+			}
+		}
+	}
+
+	for _, deployment := range deployments {
+		addUsage(deployment.Labels["apiName"], deployment.Spec.Template.Spec)
+	}
+	for _, job := range jobs {
+		addUsage(job.Labels["apiName"], job.Spec.Template.Spec)
+	}
+
+	return usedBy, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
@@ -37,9 +38,9 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 		return nil, "", err
 	}
 
-	api := spec.GetAPISpec(apiConfig, "", "", config.Cluster.ClusterName)
+	api := spec.GetAPISpec(apiConfig, "", "", config.ClusterName())
 	if prevVirtualService == nil {
-		if err := config.AWS.UploadJSONToS3(api, config.Cluster.Bucket, api.Key); err != nil {
+		if err := config.UploadJSONToBucket(api, api.Key); err != nil {
 			return nil, "", errors.Wrap(err, "upload api spec")
 		}
 
@@ -48,16 +49,18 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 
-		err = operator.AddAPIToAPIGateway(*api.Networking.Endpoint, api.Networking.APIGateway)
-		if err != nil {
-			go deleteK8sResources(api.Name)
-			return nil, "", err
+		if config.Provider == types.AWSProviderType {
+			err = operator.AddAPIToAPIGateway(*api.Networking.Endpoint, api.Networking.APIGateway)
+			if err != nil {
+				go deleteK8sResources(api.Name)
+				return nil, "", err
+			}
 		}
 		return api, fmt.Sprintf("created %s", api.Resource.UserString()), nil
 	}
 
 	if prevVirtualService.Labels["specID"] != api.SpecID {
-		if err := config.AWS.UploadJSONToS3(api, config.Cluster.Bucket, api.Key); err != nil {
+		if err := config.UploadJSONToBucket(api, api.Key); err != nil {
 			return nil, "", errors.Wrap(err, "upload api spec")
 		}
 
@@ -65,8 +68,10 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 
-		if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api); err != nil {
-			return nil, "", err
+		if config.Provider == types.AWSProviderType {
+			if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api); err != nil {
+				return nil, "", err
+			}
 		}
 		return api, fmt.Sprintf("updated %s", api.Resource.UserString()), nil
 	}
@@ -89,14 +94,13 @@ func DeleteAPI(apiName string, keepCache bool) error {
 				return nil
 			}
 			// best effort deletion
-			deleteS3Resources(apiName)
+			deleteBucketResources(apiName)
 			return nil
 		},
 		// delete API from API Gateway
 		func() error {
-			err := operator.RemoveAPIFromAPIGatewayK8s(virtualService)
-			if err != nil {
-				return err
+			if config.Provider == types.AWSProviderType {
+				return operator.RemoveAPIFromAPIGatewayK8s(virtualService)
 			}
 			return nil
 		},
@@ -189,7 +193,7 @@ func deleteK8sResources(apiName string) error {
 	return err
 }
 
-func deleteS3Resources(apiName string) error {
-	prefix := filepath.Join(config.Cluster.ClusterName, "apis", apiName)
-	return config.AWS.DeleteS3Dir(config.Cluster.Bucket, prefix, true)
+func deleteBucketResources(apiName string) error {
+	prefix := filepath.Join(config.ClusterName(), "apis", apiName)
+	return config.DeleteBucketDir(prefix, true)
 }
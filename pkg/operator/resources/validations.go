@@ -81,7 +81,7 @@ func ValidateClusterAPIs(apis []userconfig.API, projectFiles spec.ProjectFiles)
 		return spec.ErrorNoAPIs()
 	}
 
-	virtualServices, maxMem, err := getValidationK8sResources()
+	virtualServices, maxMems, err := getValidationK8sResources()
 	if err != nil {
 		return err
 	}
@@ -96,16 +96,20 @@ func ValidateClusterAPIs(apis []userconfig.API, projectFiles spec.ProjectFiles)
 
 	didPrintWarning := false
 
+	var errs []error
+
 	realtimeAPIs := InclusiveFilterAPIsByKind(apis, userconfig.RealtimeAPIKind)
 
 	for i := range apis {
 		api := &apis[i]
 		if api.Kind == userconfig.RealtimeAPIKind || api.Kind == userconfig.BatchAPIKind {
 			if err := spec.ValidateAPI(api, nil, projectFiles, config.Provider, config.AWS, config.GCP, config.K8s); err != nil {
-				return errors.Wrap(err, api.Identify())
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
 			}
-			if err := validateK8s(api, virtualServices, maxMem); err != nil {
-				return errors.Wrap(err, api.Identify())
+			if err := validateK8s(api, virtualServices, maxMems); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
 			}
 
 			if !didPrintWarning && api.Networking.LocalPort != nil {
@@ -116,35 +120,40 @@ func ValidateClusterAPIs(apis []userconfig.API, projectFiles spec.ProjectFiles)
 
 		if api.Kind == userconfig.TrafficSplitterKind {
 			if err := spec.ValidateTrafficSplitter(api, config.Provider, config.AWS); err != nil {
-				return errors.Wrap(err, api.Identify())
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
 			}
 			if err := checkIfAPIExists(api.APIs, realtimeAPIs, deployedRealtimeAPIs); err != nil {
-				return errors.Wrap(err, api.Identify())
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
 			}
 			if err := validateEndpointCollisions(api, virtualServices); err != nil {
-				return errors.Wrap(err, api.Identify())
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
 			}
 		}
 
-		if config.Provider == types.AWSProviderType && api.Networking.APIGateway != userconfig.NoneAPIGatewayType && config.Cluster.APIGatewaySetting == clusterconfig.NoneAPIGatewaySetting {
-			return errors.Wrap(ErrorAPIGatewayDisabled(api.Networking.APIGateway), api.Identify(), userconfig.NetworkingKey, userconfig.APIGatewayKey)
+		if config.Provider == types.AWSProviderType {
+			if err := spec.ValidateAPIGateway(api, &config.Cluster.Config); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+			}
 		}
 	}
 
 	dups := spec.FindDuplicateNames(apis)
 	if len(dups) > 0 {
-		return spec.ErrorDuplicateName(dups)
+		errs = append(errs, spec.ErrorDuplicateName(dups))
 	}
 	dups = findDuplicateEndpoints(apis)
 	if len(dups) > 0 {
-		return spec.ErrorDuplicateEndpointInOneDeploy(dups)
+		errs = append(errs, spec.ErrorDuplicateEndpointInOneDeploy(dups))
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func validateK8s(api *userconfig.API, virtualServices []istioclientnetworking.VirtualService, maxMem kresource.Quantity) error {
-	if err := validateK8sCompute(api.Compute, maxMem); err != nil {
+func validateK8s(api *userconfig.API, virtualServices []istioclientnetworking.VirtualService, maxMems map[string]kresource.Quantity) error {
+	if err := validateK8sCompute(api.Compute, maxMems); err != nil {
 		return errors.Wrap(err, userconfig.ComputeKey)
 	}
 
@@ -155,67 +164,48 @@ func validateK8s(api *userconfig.API, virtualServices []istioclientnetworking.Vi
 	return nil
 }
 
-/*
-CPU Reservations:
-
-FluentD 200
-StatsD 100
-KubeProxy 100
-AWS cni 10
-Reserved (150 + 150) see eks.yaml for details
-*/
-var _cortexCPUReserve = kresource.MustParse("710m")
-
-/*
-Memory Reservations:
-
-FluentD 200
-StatsD 100
-Reserved (300 + 300 + 200) see eks.yaml for details
-*/
-var _cortexMemReserve = kresource.MustParse("1100Mi")
-
-var _nvidiaCPUReserve = kresource.MustParse("100m")
-var _nvidiaMemReserve = kresource.MustParse("100Mi")
-
-var _inferentiaCPUReserve = kresource.MustParse("100m")
-var _inferentiaMemReserve = kresource.MustParse("100Mi")
-
-func validateK8sCompute(compute *userconfig.Compute, maxMem kresource.Quantity) error {
+func validateK8sCompute(compute *userconfig.Compute, maxMems map[string]kresource.Quantity) error {
 	if config.Provider != types.AWSProviderType {
 		return nil
 	}
 
-	maxMem.Sub(_cortexMemReserve)
-
-	maxCPU := config.Cluster.InstanceMetadata.CPU
-	maxCPU.Sub(_cortexCPUReserve)
+	if compute.NodeGroup != nil {
+		nodeGroup, ok := config.Cluster.GetNodeGroup(*compute.NodeGroup)
+		if !ok {
+			return errors.Wrap(ErrorNodeGroupNotFound(*compute.NodeGroup, config.Cluster.NodeGroupNames()), userconfig.NodeGroupKey)
+		}
+		return validateNodeGroupCompute(compute, nodeGroup, maxMems[nodeGroup.Name])
+	}
 
-	maxGPU := config.Cluster.InstanceMetadata.GPU
-	if maxGPU > 0 {
-		// Reserve resources for nvidia device plugin daemonset
-		maxCPU.Sub(_nvidiaCPUReserve)
-		maxMem.Sub(_nvidiaMemReserve)
+	// without additional node groups, apis can be scheduled on any worker node
+	if len(config.Cluster.NodeGroups) == 0 {
+		return validateNodeGroupCompute(compute, config.Cluster.PrimaryNodeGroup(), maxMems[clusterconfig.PrimaryNodeGroupName])
 	}
 
-	maxInf := config.Cluster.InstanceMetadata.Inf
-	if maxInf > 0 {
-		// Reserve resources for inferentia device plugin daemonset
-		maxCPU.Sub(_inferentiaCPUReserve)
-		maxMem.Sub(_inferentiaMemReserve)
+	// otherwise the api is placed on the cheapest node group which can satisfy the compute when its spec is built (see operator.SetNodeGroup())
+	for _, nodeGroup := range config.Cluster.AllNodeGroups() {
+		if validateNodeGroupCompute(compute, nodeGroup, maxMems[nodeGroup.Name]) == nil {
+			return nil
+		}
 	}
 
-	if compute.CPU != nil && maxCPU.Cmp(compute.CPU.Quantity) < 0 {
-		return ErrorNoAvailableNodeComputeLimit("CPU", compute.CPU.String(), maxCPU.String())
+	return ErrorNoNodeGroupCanSatisfyCompute(config.Cluster.NodeGroupNames())
+}
+
+func validateNodeGroupCompute(compute *userconfig.Compute, nodeGroup *clusterconfig.NodeGroup, maxMem kresource.Quantity) error {
+	capacity := operator.NodeGroupCapacity(nodeGroup, *config.Cluster.Region, maxMem)
+
+	if compute.CPU != nil && capacity.CPU.Cmp(compute.CPU.Quantity) < 0 {
+		return ErrorNoAvailableNodeComputeLimit("CPU", compute.CPU.String(), capacity.CPU.String())
 	}
-	if compute.Mem != nil && maxMem.Cmp(compute.Mem.Quantity) < 0 {
-		return ErrorNoAvailableNodeComputeLimit("memory", compute.Mem.String(), maxMem.String())
+	if compute.Mem != nil && capacity.Mem.Cmp(compute.Mem.Quantity) < 0 {
+		return ErrorNoAvailableNodeComputeLimit("memory", compute.Mem.String(), capacity.Mem.String())
 	}
-	if compute.GPU > maxGPU {
-		return ErrorNoAvailableNodeComputeLimit("GPU", fmt.Sprintf("%d", compute.GPU), fmt.Sprintf("%d", maxGPU))
+	if compute.GPU > capacity.GPU {
+		return ErrorNoAvailableNodeComputeLimit("GPU", fmt.Sprintf("%d", compute.GPU), fmt.Sprintf("%d", capacity.GPU))
 	}
-	if compute.Inf > maxInf {
-		return ErrorNoAvailableNodeComputeLimit("Inf", fmt.Sprintf("%d", compute.Inf), fmt.Sprintf("%d", maxInf))
+	if compute.Inf > capacity.Inf {
+		return ErrorNoAvailableNodeComputeLimit("Inf", fmt.Sprintf("%d", compute.Inf), fmt.Sprintf("%d", capacity.Inf))
 	}
 
 	return nil
@@ -255,9 +245,9 @@ func findDuplicateEndpoints(apis []userconfig.API) []userconfig.API {
 	return nil
 }
 
-func getValidationK8sResources() ([]istioclientnetworking.VirtualService, kresource.Quantity, error) {
+func getValidationK8sResources() ([]istioclientnetworking.VirtualService, map[string]kresource.Quantity, error) {
 	var virtualServices []istioclientnetworking.VirtualService
-	var maxMem kresource.Quantity
+	var maxMems map[string]kresource.Quantity
 
 	err := parallel.RunFirstErr(
 		func() error {
@@ -267,12 +257,12 @@ func getValidationK8sResources() ([]istioclientnetworking.VirtualService, kresou
 		},
 		func() error {
 			var err error
-			maxMem, err = operator.UpdateMemoryCapacityConfigMap()
+			maxMems, err = operator.UpdateMemoryCapacityConfigMap()
 			return err
 		},
 	)
 
-	return virtualServices, maxMem, err
+	return virtualServices, maxMems, err
 }
 
 // InclusiveFilterAPIsByKind includes only provided Kinds
@@ -17,6 +17,7 @@ limitations under the License.
 package schema
 
 import (
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -37,6 +38,7 @@ type InfoGCPResponse struct {
 
 type NodeInfo struct {
 	Name                 string             `json:"name"`
+	NodeGroup            string             `json:"node_group"`
 	InstanceType         string             `json:"instance_type"`
 	IsSpot               bool               `json:"is_spot"`
 	Price                float64            `json:"price"`
@@ -53,13 +55,28 @@ type DeployResult struct {
 }
 
 type APIResponse struct {
-	Spec         spec.API           `json:"spec"`
-	Status       *status.Status     `json:"status,omitempty"`
-	Metrics      *metrics.Metrics   `json:"metrics,omitempty"`
-	Endpoint     string             `json:"endpoint"`
-	DashboardURL *string            `json:"dashboard_url,omitempty"`
-	JobStatuses  []status.JobStatus `json:"job_statuses,omitempty"`
-	APIVersions  []APIVersion       `json:"api_versions,omitempty"`
+	Spec          spec.API           `json:"spec"`
+	Status        *status.Status     `json:"status,omitempty"`
+	Metrics       *metrics.Metrics   `json:"metrics,omitempty"`
+	Endpoint      string             `json:"endpoint"`
+	DashboardURL  *string            `json:"dashboard_url,omitempty"`
+	JobStatuses   []status.JobStatus `json:"job_statuses,omitempty"`
+	APIVersions   []APIVersion       `json:"api_versions,omitempty"`
+	ColorStatuses []ColorStatus      `json:"color_statuses,omitempty"` // only set during blue/green updates
+}
+
+type ColorRole string
+
+const (
+	ActiveColorRole    ColorRole = "active"
+	CandidateColorRole ColorRole = "candidate"
+	DrainingColorRole  ColorRole = "draining"
+)
+
+type ColorStatus struct {
+	Color  string        `json:"color"`
+	Role   ColorRole     `json:"role"`
+	Status status.Status `json:"status"`
 }
 
 type JobResponse struct {
@@ -68,6 +85,75 @@ type JobResponse struct {
 	Endpoint  string           `json:"endpoint"`
 }
 
+type LogSearchResponse struct {
+	Lines     []LogLine `json:"lines"`
+	NextToken string    `json:"next_token,omitempty"` // empty if there are no more results
+}
+
+type LogLine struct {
+	Timestamp int64  `json:"timestamp"` // milliseconds since the epoch
+	Replica   string `json:"replica"`
+	Message   string `json:"message"`
+}
+
+// messages sent over the websocket of an exec session are prefixed with the channel they belong to
+const (
+	ExecStdinChannel  byte = 0 // client -> operator, an empty message closes stdin
+	ExecStdoutChannel byte = 1 // operator -> client
+	ExecStderrChannel byte = 2 // operator -> client
+	ExecStatusChannel byte = 3 // operator -> client, ExecStatus (sent once the command exits)
+	ExecResizeChannel byte = 4 // client -> operator, TerminalSize
+)
+
+type ExecStatus struct {
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+type TopResponse struct {
+	Replicas []ReplicaUsage `json:"replicas"`
+	Nodes    []NodeUsage    `json:"nodes"`
+}
+
+type ReplicaUsage struct {
+	APIName   string             `json:"api_name"`
+	Name      string             `json:"name"`
+	NodeName  string             `json:"node_name"`
+	Usage     *ResourceUsage     `json:"usage"` // nil if metrics-server hasn't scraped the replica yet
+	Requested userconfig.Compute `json:"requested"`
+	Restarts  int32              `json:"restarts"`
+	OOMKilled bool               `json:"oom_killed"` // whether the most recent restart was caused by running out of memory
+}
+
+type NodeUsage struct {
+	Name         string             `json:"name"`
+	InstanceType string             `json:"instance_type"`
+	IsSpot       bool               `json:"is_spot"`
+	NumReplicas  int                `json:"num_replicas"`
+	Usage        *ResourceUsage     `json:"usage"` // nil if metrics-server hasn't scraped the node yet
+	Allocatable  userconfig.Compute `json:"allocatable"`
+}
+
+type ResourceUsage struct {
+	CPU k8s.Quantity `json:"cpu"`
+	Mem k8s.Quantity `json:"mem"`
+}
+
+type ReplicaLogsResponse struct {
+	Replicas []ReplicaLogs `json:"replicas"`
+}
+
+type ReplicaLogs struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Logs   string `json:"logs"`
+}
+
 type DeleteResponse struct {
 	Message string `json:"message"`
 }
@@ -76,13 +162,20 @@ type RefreshResponse struct {
 	Message string `json:"message"`
 }
 
-type ErrorResponse struct {
-	Kind    string `json:"kind"`
+type SecretResponse struct {
 	Message string `json:"message"`
 }
 
-type GCPLogsResponse struct {
-	QueryParams map[string]string `json:"query_params"`
+type SecretSummary struct {
+	Name      string   `json:"name"`
+	Keys      []string `json:"keys"`
+	UsedBy    []string `json:"used_by"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+type ErrorResponse struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
 }
 
 type APITFLiveReloadingSummary struct {
@@ -114,6 +207,13 @@ type GenericModelMetadata struct {
 }
 
 type APIVersion struct {
-	APIID       string `json:"api_id"`
-	LastUpdated int64  `json:"last_updated"`
+	APIID          string          `json:"api_id"`
+	LastUpdated    int64           `json:"last_updated"`
+	RolloutFailure *RolloutFailure `json:"rollout_failure,omitempty"`
+}
+
+type RolloutFailure struct {
+	Reason       string `json:"reason"`
+	FailedAt     int64  `json:"failed_at"`
+	RolledBackTo string `json:"rolled_back_to,omitempty"`
 }
@@ -59,12 +59,33 @@ func GetClusterProviderType(clusterConfigPath string) (types.ProviderType, error
 
 	errs := cr.ParseYAMLFile(&providerHolder, providerValidation, clusterConfigPath)
 	if errors.HasError(errs) {
-		return types.UnknownProviderType, errors.FirstError(errs...)
+		return types.UnknownProviderType, errors.Join(errs...)
 	}
 
 	return providerHolder.Provider, nil
 }
 
+// JSONSchema returns a JSON Schema for the cluster configuration file of the provider (aws or gcp)
+func JSONSchema(provider types.ProviderType) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	var err error
+
+	switch provider {
+	case types.AWSProviderType:
+		schema, err = cr.StructJSONSchema(&Config{}, UserValidation)
+	case types.GCPProviderType:
+		schema, err = cr.StructJSONSchema(&GCPConfig{}, UserGCPValidation)
+	default:
+		return nil, errors.ErrorUnexpected("cluster configuration is not supported for provider", provider.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schema["title"] = "cortex cluster configuration (" + provider.String() + ")"
+	return schema, nil
+}
+
 func specificProviderTypeValidator(expectedProvider types.ProviderType) func(string) (string, error) {
 	return func(providerType string) (string, error) {
 		if providerType != expectedProvider.String() {
@@ -63,6 +63,7 @@ type Config struct {
 	Tags                       map[string]string  `json:"tags" yaml:"tags"`
 	Spot                       *bool              `json:"spot" yaml:"spot"`
 	SpotConfig                 *SpotConfig        `json:"spot_config" yaml:"spot_config"`
+	NodeGroups                 []*NodeGroup       `json:"node_groups" yaml:"node_groups"`
 	ClusterName                string             `json:"cluster_name" yaml:"cluster_name"`
 	Region                     *string            `json:"region" yaml:"region"`
 	AvailabilityZones          []string           `json:"availability_zones" yaml:"availability_zones"`
@@ -120,6 +121,78 @@ type AccessConfig struct {
 	ImageManager string  `json:"image_manager" yaml:"image_manager"`
 }
 
+var _instanceVolumeSizeValidation = &cr.Int64Validation{
+	Default:              50,
+	GreaterThanOrEqualTo: pointer.Int64(20), // large enough to fit docker images and any other overhead
+	LessThanOrEqualTo:    pointer.Int64(16384),
+}
+
+var _instanceVolumeTypeValidation = &cr.StringValidation{
+	AllowedValues: VolumeTypesStrings(),
+	Default:       GP2VolumeType.String(),
+}
+
+func parseVolumeType(str string) (interface{}, error) {
+	return VolumeTypeFromString(str), nil
+}
+
+var _instanceVolumeIOPSValidation = &cr.Int64PtrValidation{
+	GreaterThanOrEqualTo: pointer.Int64(100),
+	LessThanOrEqualTo:    pointer.Int64(64000),
+	AllowExplicitNull:    true,
+}
+
+var _spotConfigValidation = &cr.StructValidation{
+	DefaultNil:        true,
+	AllowExplicitNull: true,
+	StructFieldValidations: []*cr.StructFieldValidation{
+		{
+			StructField: "InstanceDistribution",
+			StringListValidation: &cr.StringListValidation{
+				DisallowDups:      true,
+				Validator:         validateInstanceDistribution,
+				AllowExplicitNull: true,
+			},
+		},
+		{
+			StructField: "OnDemandBaseCapacity",
+			Int64PtrValidation: &cr.Int64PtrValidation{
+				GreaterThanOrEqualTo: pointer.Int64(0),
+				AllowExplicitNull:    true,
+			},
+		},
+		{
+			StructField: "OnDemandPercentageAboveBaseCapacity",
+			Int64PtrValidation: &cr.Int64PtrValidation{
+				GreaterThanOrEqualTo: pointer.Int64(0),
+				LessThanOrEqualTo:    pointer.Int64(100),
+				AllowExplicitNull:    true,
+			},
+		},
+		{
+			StructField: "MaxPrice",
+			Float64PtrValidation: &cr.Float64PtrValidation{
+				GreaterThan:       pointer.Float64(0),
+				AllowExplicitNull: true,
+			},
+		},
+		{
+			StructField: "InstancePools",
+			Int64PtrValidation: &cr.Int64PtrValidation{
+				GreaterThanOrEqualTo: pointer.Int64(1),
+				LessThanOrEqualTo:    pointer.Int64(int64(_maxInstancePools)),
+				AllowExplicitNull:    true,
+			},
+		},
+		{
+			StructField: "OnDemandBackup",
+			BoolPtrValidation: &cr.BoolPtrValidation{
+				Default: pointer.Bool(true),
+			},
+		},
+	},
+}
+
 var UserValidation = &cr.StructValidation{
 	Required: true,
 	StructFieldValidations: []*cr.StructFieldValidation{
@@ -152,22 +225,13 @@ var UserValidation = &cr.StructValidation{
 			},
 		},
 		{
-			StructField: "InstanceVolumeSize",
-			Int64Validation: &cr.Int64Validation{
-				Default:              50,
-				GreaterThanOrEqualTo: pointer.Int64(20), // large enough to fit docker images and any other overhead
-				LessThanOrEqualTo:    pointer.Int64(16384),
-			},
+			StructField:     "InstanceVolumeSize",
+			Int64Validation: _instanceVolumeSizeValidation,
 		},
 		{
-			StructField: "InstanceVolumeType",
-			StringValidation: &cr.StringValidation{
-				AllowedValues: VolumeTypesStrings(),
-				Default:       GP2VolumeType.String(),
-			},
-			Parser: func(str string) (interface{}, error) {
-				return VolumeTypeFromString(str), nil
-			},
+			StructField:      "InstanceVolumeType",
+			StringValidation: _instanceVolumeTypeValidation,
+			Parser:           parseVolumeType,
 		},
 		{
 			StructField: "Tags",
@@ -200,12 +264,8 @@ var UserValidation = &cr.StructValidation{
 			},
 		},
 		{
-			StructField: "InstanceVolumeIOPS",
-			Int64PtrValidation: &cr.Int64PtrValidation{
-				GreaterThanOrEqualTo: pointer.Int64(100),
-				LessThanOrEqualTo:    pointer.Int64(64000),
-				AllowExplicitNull:    true,
-			},
+			StructField:        "InstanceVolumeIOPS",
+			Int64PtrValidation: _instanceVolumeIOPSValidation,
 		},
 		{
 			StructField: "Spot",
@@ -214,58 +274,10 @@ var UserValidation = &cr.StructValidation{
 			},
 		},
 		{
-			StructField: "SpotConfig",
-			StructValidation: &cr.StructValidation{
-				DefaultNil:        true,
-				AllowExplicitNull: true,
-				StructFieldValidations: []*cr.StructFieldValidation{
-					{
-						StructField: "InstanceDistribution",
-						StringListValidation: &cr.StringListValidation{
-							DisallowDups:      true,
-							Validator:         validateInstanceDistribution,
-							AllowExplicitNull: true,
-						},
-					},
-					{
-						StructField: "OnDemandBaseCapacity",
-						Int64PtrValidation: &cr.Int64PtrValidation{
-							GreaterThanOrEqualTo: pointer.Int64(0),
-							AllowExplicitNull:    true,
-						},
-					},
-					{
-						StructField: "OnDemandPercentageAboveBaseCapacity",
-						Int64PtrValidation: &cr.Int64PtrValidation{
-							GreaterThanOrEqualTo: pointer.Int64(0),
-							LessThanOrEqualTo:    pointer.Int64(100),
-							AllowExplicitNull:    true,
-						},
-					},
-					{
-						StructField: "MaxPrice",
-						Float64PtrValidation: &cr.Float64PtrValidation{
-							GreaterThan:       pointer.Float64(0),
-							AllowExplicitNull: true,
-						},
-					},
-					{
-						StructField: "InstancePools",
-						Int64PtrValidation: &cr.Int64PtrValidation{
-							GreaterThanOrEqualTo: pointer.Int64(1),
-							LessThanOrEqualTo:    pointer.Int64(int64(_maxInstancePools)),
-							AllowExplicitNull:    true,
-						},
-					},
-					{
-						StructField: "OnDemandBackup",
-						BoolPtrValidation: &cr.BoolPtrValidation{
-							Default: pointer.Bool(true),
-						},
-					},
-				},
-			},
+			StructField:      "SpotConfig",
+			StructValidation: _spotConfigValidation,
 		},
+		_nodeGroupsValidation,
 		{
 			StructField: "ClusterName",
 			StringValidation: &cr.StringValidation{
@@ -578,20 +590,11 @@ func (cc *Config) Validate(awsClient *aws.Client) error {
 		}
 	}
 
-	// Throw error if IOPS defined for other storage than io1
-	if cc.InstanceVolumeType != IO1VolumeType && cc.InstanceVolumeIOPS != nil {
-		return ErrorIOPSNotSupported(cc.InstanceVolumeType)
-	}
-
-	if cc.InstanceVolumeType == IO1VolumeType && cc.InstanceVolumeIOPS != nil {
-		if *cc.InstanceVolumeIOPS > cc.InstanceVolumeSize*50 {
-			return ErrorIOPSTooLarge(*cc.InstanceVolumeIOPS, cc.InstanceVolumeSize)
-		}
-	}
-
-	if aws.EBSMetadatas[*cc.Region][cc.InstanceVolumeType.String()].IOPSConfigurable && cc.InstanceVolumeIOPS == nil {
-		cc.InstanceVolumeIOPS = pointer.Int64(libmath.MinInt64(cc.InstanceVolumeSize*50, 3000))
+	instanceVolumeIOPS, err := validateInstanceVolume(*cc.Region, cc.InstanceVolumeType, cc.InstanceVolumeSize, cc.InstanceVolumeIOPS)
+	if err != nil {
+		return err
 	}
+	cc.InstanceVolumeIOPS = instanceVolumeIOPS
 
 	if err := awsClient.VerifyInstanceQuota(primaryInstanceType, cc.MaxPossibleOnDemandInstances(), cc.MaxPossibleSpotInstances()); err != nil {
 		// Skip AWS errors, since some regions (e.g. eu-north-1) do not support this API
@@ -619,37 +622,75 @@ func (cc *Config) Validate(awsClient *aws.Client) error {
 	if cc.Spot != nil && *cc.Spot {
 		cc.FillEmptySpotFields(awsClient)
 
-		primaryInstance := aws.InstanceMetadatas[*cc.Region][primaryInstanceType]
+		if err := validateSpotConfig(awsClient, *cc.Region, primaryInstanceType, *cc.MaxInstances, cc.SpotConfig); err != nil {
+			return err
+		}
+	} else {
+		if cc.SpotConfig != nil {
+			return ErrorConfiguredWhenSpotIsNotEnabled(SpotConfigKey)
+		}
+	}
 
-		for _, instanceType := range cc.SpotConfig.InstanceDistribution {
-			if instanceType == primaryInstanceType {
-				continue
-			}
-			if _, ok := aws.InstanceMetadatas[*cc.Region][instanceType]; !ok {
-				return errors.Wrap(ErrorInstanceTypeNotSupportedInRegion(instanceType, *cc.Region), SpotConfigKey, InstanceDistributionKey)
-			}
+	if err := validateNodeGroups(cc.NodeGroups, *cc.Region); err != nil {
+		return err
+	}
 
-			instanceMetadata := aws.InstanceMetadatas[*cc.Region][instanceType]
-			err := CheckSpotInstanceCompatibility(primaryInstance, instanceMetadata)
-			if err != nil {
-				return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
-			}
+	for _, nodeGroup := range cc.NodeGroups {
+		if err := nodeGroup.validateWithAWS(awsClient, *cc.Region); err != nil {
+			return errors.Wrap(err, NodeGroupsKey, nodeGroup.Name)
+		}
+	}
 
-			spotInstancePrice, awsErr := awsClient.SpotInstancePrice(instanceMetadata.Region, instanceMetadata.Type)
-			if awsErr == nil {
-				if err := CheckSpotInstancePriceCompatibility(primaryInstance, instanceMetadata, cc.SpotConfig.MaxPrice, spotInstancePrice); err != nil {
-					return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
-				}
-			}
+	return nil
+}
+
+// Returns the IOPS to use for the volume (which is defaulted for volume types that support configuring it)
+func validateInstanceVolume(region string, volumeType VolumeType, volumeSize int64, volumeIOPS *int64) (*int64, error) {
+	// Throw error if IOPS defined for other storage than io1
+	if volumeType != IO1VolumeType && volumeIOPS != nil {
+		return nil, ErrorIOPSNotSupported(volumeType)
+	}
+
+	if volumeType == IO1VolumeType && volumeIOPS != nil {
+		if *volumeIOPS > volumeSize*50 {
+			return nil, ErrorIOPSTooLarge(*volumeIOPS, volumeSize)
 		}
+	}
+
+	if aws.EBSMetadatas[region][volumeType.String()].IOPSConfigurable && volumeIOPS == nil {
+		volumeIOPS = pointer.Int64(libmath.MinInt64(volumeSize*50, 3000))
+	}
 
-		if cc.SpotConfig.OnDemandBaseCapacity != nil && *cc.SpotConfig.OnDemandBaseCapacity > *cc.MaxInstances {
-			return ErrorOnDemandBaseCapacityGreaterThanMax(*cc.SpotConfig.OnDemandBaseCapacity, *cc.MaxInstances)
+	return volumeIOPS, nil
+}
+
+func validateSpotConfig(awsClient *aws.Client, region string, primaryInstanceType string, maxInstances int64, spotConfig *SpotConfig) error {
+	primaryInstance := aws.InstanceMetadatas[region][primaryInstanceType]
+
+	for _, instanceType := range spotConfig.InstanceDistribution {
+		if instanceType == primaryInstanceType {
+			continue
 		}
-	} else {
-		if cc.SpotConfig != nil {
-			return ErrorConfiguredWhenSpotIsNotEnabled(SpotConfigKey)
+		if _, ok := aws.InstanceMetadatas[region][instanceType]; !ok {
+			return errors.Wrap(ErrorInstanceTypeNotSupportedInRegion(instanceType, region), SpotConfigKey, InstanceDistributionKey)
+		}
+
+		instanceMetadata := aws.InstanceMetadatas[region][instanceType]
+		err := CheckSpotInstanceCompatibility(primaryInstance, instanceMetadata)
+		if err != nil {
+			return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
 		}
+
+		spotInstancePrice, awsErr := awsClient.SpotInstancePrice(instanceMetadata.Region, instanceMetadata.Type)
+		if awsErr == nil {
+			if err := CheckSpotInstancePriceCompatibility(primaryInstance, instanceMetadata, spotConfig.MaxPrice, spotInstancePrice); err != nil {
+				return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
+			}
+		}
+	}
+
+	if spotConfig.OnDemandBaseCapacity != nil && *spotConfig.OnDemandBaseCapacity > maxInstances {
+		return ErrorOnDemandBaseCapacityGreaterThanMax(*spotConfig.OnDemandBaseCapacity, maxInstances)
 	}
 
 	return nil
@@ -1022,7 +1063,7 @@ func SetDefaults(cc *Config) error {
 	var emptyMap interface{} = map[interface{}]interface{}{}
 	errs := cr.Struct(cc, emptyMap, Validation)
 	if errors.HasError(errs) {
-		return errors.FirstError(errs...)
+		return errors.Join(errs...)
 	}
 	return nil
 }
@@ -1043,7 +1084,7 @@ func DefaultAccessConfig() (*AccessConfig, error) {
 	var emptyMap interface{} = map[interface{}]interface{}{}
 	errs := cr.Struct(accessConfig, emptyMap, AccessValidation)
 	if errors.HasError(errs) {
-		return nil, errors.FirstError(errs...)
+		return nil, errors.Join(errs...)
 	}
 	return accessConfig, nil
 }
@@ -1053,13 +1094,7 @@ func (cc *Config) MaxPossibleOnDemandInstances() int64 {
 		return 0 // unexpected
 	}
 
-	if cc.Spot == nil || *cc.Spot == false || cc.SpotConfig == nil || cc.SpotConfig.OnDemandBackup == nil || *cc.SpotConfig.OnDemandBackup == true {
-		return *cc.MaxInstances
-	}
-
-	onDemandBaseCap, onDemandPctAboveBaseCap := cc.SpotConfigOnDemandValues()
-
-	return onDemandBaseCap + int64(math.Ceil(float64(onDemandPctAboveBaseCap)/100*float64(*cc.MaxInstances-onDemandBaseCap)))
+	return maxPossibleOnDemandInstances(*cc.MaxInstances, cc.Spot != nil && *cc.Spot, cc.SpotConfig)
 }
 
 func (cc *Config) MaxPossibleSpotInstances() int64 {
@@ -1067,30 +1102,48 @@ func (cc *Config) MaxPossibleSpotInstances() int64 {
 		return 0 // unexpected
 	}
 
-	if cc.Spot == nil || *cc.Spot == false {
+	return maxPossibleSpotInstances(*cc.MaxInstances, cc.Spot != nil && *cc.Spot, cc.SpotConfig)
+}
+
+func (cc *Config) SpotConfigOnDemandValues() (int64, int64) {
+	return spotConfigOnDemandValues(cc.SpotConfig)
+}
+
+func maxPossibleOnDemandInstances(maxInstances int64, spot bool, spotConfig *SpotConfig) int64 {
+	if !spot || spotConfig == nil || spotConfig.OnDemandBackup == nil || *spotConfig.OnDemandBackup == true {
+		return maxInstances
+	}
+
+	onDemandBaseCap, onDemandPctAboveBaseCap := spotConfigOnDemandValues(spotConfig)
+
+	return onDemandBaseCap + int64(math.Ceil(float64(onDemandPctAboveBaseCap)/100*float64(maxInstances-onDemandBaseCap)))
+}
+
+func maxPossibleSpotInstances(maxInstances int64, spot bool, spotConfig *SpotConfig) int64 {
+	if !spot {
 		return 0
 	}
 
-	if cc.SpotConfig == nil {
-		return *cc.MaxInstances
+	if spotConfig == nil {
+		return maxInstances
 	}
 
-	onDemandBaseCap, onDemandPctAboveBaseCap := cc.SpotConfigOnDemandValues()
+	onDemandBaseCap, onDemandPctAboveBaseCap := spotConfigOnDemandValues(spotConfig)
 
-	return *cc.MaxInstances - onDemandBaseCap - int64(math.Floor(float64(onDemandPctAboveBaseCap)/100*float64(*cc.MaxInstances-onDemandBaseCap)))
+	return maxInstances - onDemandBaseCap - int64(math.Floor(float64(onDemandPctAboveBaseCap)/100*float64(maxInstances-onDemandBaseCap)))
 }
 
-func (cc *Config) SpotConfigOnDemandValues() (int64, int64) {
+func spotConfigOnDemandValues(spotConfig *SpotConfig) (int64, int64) {
 	// default OnDemandBaseCapacity is 0
 	var onDemandBaseCapacity int64 = 0
-	if cc.SpotConfig.OnDemandBaseCapacity != nil {
-		onDemandBaseCapacity = *cc.SpotConfig.OnDemandBaseCapacity
+	if spotConfig.OnDemandBaseCapacity != nil {
+		onDemandBaseCapacity = *spotConfig.OnDemandBaseCapacity
 	}
 
 	// default OnDemandPercentageAboveBaseCapacity is 0
 	var onDemandPercentageAboveBaseCapacity int64 = 0
-	if cc.SpotConfig.OnDemandPercentageAboveBaseCapacity != nil {
-		onDemandPercentageAboveBaseCapacity = *cc.SpotConfig.OnDemandPercentageAboveBaseCapacity
+	if spotConfig.OnDemandPercentageAboveBaseCapacity != nil {
+		onDemandPercentageAboveBaseCapacity = *spotConfig.OnDemandPercentageAboveBaseCapacity
 	}
 
 	return onDemandBaseCapacity, onDemandPercentageAboveBaseCapacity
@@ -1137,6 +1190,9 @@ func (cc *Config) UserTable() table.KeyValuePairs {
 		items.Add(InstancePoolsUserKey, *cc.SpotConfig.InstancePools)
 		items.Add(OnDemandBackupUserKey, s.YesNo(*cc.SpotConfig.OnDemandBackup))
 	}
+	for _, nodeGroup := range cc.NodeGroups {
+		items.AddAll(nodeGroup.UserTable())
+	}
 	items.Add(SubnetVisibilityUserKey, cc.SubnetVisibility)
 	items.Add(NATGatewayUserKey, cc.NATGateway)
 	items.Add(APILoadBalancerSchemeUserKey, cc.APILoadBalancerScheme)
@@ -1291,6 +1347,20 @@ func (cc *Config) TelemetryEvent() map[string]interface{} {
 			event["spot_config.on_demand_backup"] = *cc.SpotConfig.OnDemandBackup
 		}
 	}
+	if len(cc.NodeGroups) > 0 {
+		event["node_groups._is_defined"] = true
+		event["node_groups._len"] = len(cc.NodeGroups)
+		var instanceTypes []string
+		var numSpot int
+		for _, nodeGroup := range cc.NodeGroups {
+			instanceTypes = append(instanceTypes, nodeGroup.InstanceType)
+			if nodeGroup.Spot {
+				numSpot++
+			}
+		}
+		event["node_groups.instance_types"] = instanceTypes
+		event["node_groups.spot._len"] = numSpot
+	}
 
 	return event
 }
@@ -422,7 +422,7 @@ func SetGCPDefaults(cc *GCPConfig) error {
 	var emptyMap interface{} = map[interface{}]interface{}{}
 	errs := cr.Struct(cc, emptyMap, GCPValidation)
 	if errors.HasError(errs) {
-		return errors.FirstError(errs...)
+		return errors.Join(errs...)
 	}
 	return nil
 }
@@ -443,7 +443,7 @@ func DefaultGCPAccessConfig() (*GCPAccessConfig, error) {
 	var emptyMap interface{} = map[interface{}]interface{}{}
 	errs := cr.Struct(accessConfig, emptyMap, GCPAccessValidation)
 	if errors.HasError(errs) {
-		return nil, errors.FirstError(errs...)
+		return nil, errors.Join(errs...)
 	}
 	return accessConfig, nil
 }
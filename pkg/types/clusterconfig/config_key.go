@@ -34,6 +34,8 @@ const (
 	MaxPriceKey                            = "max_price"
 	InstancePoolsKey                       = "instance_pools"
 	OnDemandBackupKey                      = "on_demand_backup"
+	NodeGroupsKey                          = "node_groups"
+	NameKey                                = "name"
 	ClusterNameKey                         = "cluster_name"
 	RegionKey                              = "region"
 	ZoneKey                                = "zone"
@@ -88,6 +90,7 @@ const (
 	MaxPriceUserKey                            = "spot max price ($ per hour)"
 	InstancePoolsUserKey                       = "spot instance pools"
 	OnDemandBackupUserKey                      = "on demand backup"
+	NodeGroupsUserKey                          = "node groups"
 	SubnetVisibilityUserKey                    = "subnet visibility"
 	NATGatewayUserKey                          = "nat gateway"
 	APILoadBalancerSchemeUserKey               = "api load balancer scheme"
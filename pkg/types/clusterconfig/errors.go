@@ -45,6 +45,9 @@ const (
 	ErrConfiguredWhenSpotIsNotEnabled             = "clusterconfig.configured_when_spot_is_not_enabled"
 	ErrOnDemandBaseCapacityGreaterThanMax         = "clusterconfig.on_demand_base_capacity_greater_than_max"
 	ErrConfigCannotBeChangedOnUpdate              = "clusterconfig.config_cannot_be_changed_on_update"
+	ErrReservedNodeGroupName                      = "clusterconfig.reserved_node_group_name"
+	ErrDuplicateNodeGroupName                     = "clusterconfig.duplicate_node_group_name"
+	ErrNodeGroupsCannotBeChangedOnUpdate          = "clusterconfig.node_groups_cannot_be_changed_on_update"
 	ErrInvalidAvailabilityZone                    = "clusterconfig.invalid_availability_zone"
 	ErrUnsupportedAvailabilityZone                = "clusterconfig.unsupported_availability_zone"
 	ErrNotEnoughValidDefaultAvailibilityZones     = "clusterconfig.not_enough_valid_default_availability_zones"
@@ -170,6 +173,33 @@ func ErrorConfigCannotBeChangedOnUpdate(configKey string, prevVal interface{}) e
 	})
 }
 
+func ErrorReservedNodeGroupName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrReservedNodeGroupName,
+		Message: fmt.Sprintf("%s is a reserved node group name (it refers to the node group defined by the top-level %s, %s, and %s fields); please choose a different name", s.UserStr(name), InstanceTypeKey, MinInstancesKey, MaxInstancesKey),
+	})
+}
+
+func ErrorDuplicateNodeGroupName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDuplicateNodeGroupName,
+		Message: fmt.Sprintf("multiple node groups are named %s; node group names must be unique", s.UserStr(name)),
+	})
+}
+
+func ErrorNodeGroupsCannotBeChangedOnUpdate(prevNames []string) error {
+	message := fmt.Sprintf("adding, removing, or renaming %s in a running cluster is not supported", NodeGroupsKey)
+	if len(prevNames) == 0 {
+		message += fmt.Sprintf("; please remove %s from your cluster configuration file", NodeGroupsKey)
+	} else {
+		message += fmt.Sprintf("; please restore the previously configured %s (%s)", NodeGroupsKey, s.StrsAnd(prevNames))
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNodeGroupsCannotBeChangedOnUpdate,
+		Message: message,
+	})
+}
+
 func ErrorInvalidAvailabilityZone(userZone string, allZones strset.Set, region string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrInvalidAvailabilityZone,
@@ -0,0 +1,293 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+)
+
+const (
+	// The node group which is configured by the top-level instance fields of the cluster configuration
+	PrimaryNodeGroupName = "primary"
+
+	// The kubernetes label which is set on every worker node to identify its node group
+	NodeGroupLabelKey = "node-group"
+)
+
+type NodeGroup struct {
+	Name               string      `json:"name" yaml:"name"`
+	InstanceType       string      `json:"instance_type" yaml:"instance_type"`
+	MinInstances       int64       `json:"min_instances" yaml:"min_instances"`
+	MaxInstances       int64       `json:"max_instances" yaml:"max_instances"`
+	InstanceVolumeSize int64       `json:"instance_volume_size" yaml:"instance_volume_size"`
+	InstanceVolumeType VolumeType  `json:"instance_volume_type" yaml:"instance_volume_type"`
+	InstanceVolumeIOPS *int64      `json:"instance_volume_iops" yaml:"instance_volume_iops"`
+	Spot               bool        `json:"spot" yaml:"spot"`
+	SpotConfig         *SpotConfig `json:"spot_config" yaml:"spot_config"`
+}
+
+var _nodeGroupsValidation = &cr.StructFieldValidation{
+	StructField: "NodeGroups",
+	StructListValidation: &cr.StructListValidation{
+		AllowExplicitNull: true,
+		StructValidation: &cr.StructValidation{
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Name",
+					StringValidation: &cr.StringValidation{
+						Required:  true,
+						DNS1035:   true,
+						MaxLength: 30, // the node group name is used in the names of the EKS node groups, which are limited to 63 characters
+						Validator: validateNodeGroupName,
+					},
+				},
+				{
+					StructField: "InstanceType",
+					StringValidation: &cr.StringValidation{
+						Required:  true,
+						Validator: validateInstanceType,
+					},
+				},
+				{
+					StructField: "MinInstances",
+					Int64Validation: &cr.Int64Validation{
+						Default:              1,
+						GreaterThanOrEqualTo: pointer.Int64(0),
+					},
+				},
+				{
+					StructField: "MaxInstances",
+					Int64Validation: &cr.Int64Validation{
+						Default:     5,
+						GreaterThan: pointer.Int64(0),
+					},
+				},
+				{
+					StructField:     "InstanceVolumeSize",
+					Int64Validation: _instanceVolumeSizeValidation,
+				},
+				{
+					StructField:      "InstanceVolumeType",
+					StringValidation: _instanceVolumeTypeValidation,
+					Parser:           parseVolumeType,
+				},
+				{
+					StructField:        "InstanceVolumeIOPS",
+					Int64PtrValidation: _instanceVolumeIOPSValidation,
+				},
+				{
+					StructField:    "Spot",
+					BoolValidation: &cr.BoolValidation{},
+				},
+				{
+					StructField:      "SpotConfig",
+					StructValidation: _spotConfigValidation,
+				},
+			},
+		},
+	},
+}
+
+func validateNodeGroupName(name string) (string, error) {
+	if name == PrimaryNodeGroupName {
+		return "", ErrorReservedNodeGroupName(name)
+	}
+	return name, nil
+}
+
+// Validates the node group fields which can be checked without calling AWS
+func (ng *NodeGroup) validate(region string) error {
+	if ng.MinInstances > ng.MaxInstances {
+		return ErrorMinInstancesGreaterThanMax(ng.MinInstances, ng.MaxInstances)
+	}
+
+	if _, ok := aws.InstanceMetadatas[region][ng.InstanceType]; !ok {
+		return errors.Wrap(ErrorInstanceTypeNotSupportedInRegion(ng.InstanceType, region), InstanceTypeKey)
+	}
+
+	instanceVolumeIOPS, err := validateInstanceVolume(region, ng.InstanceVolumeType, ng.InstanceVolumeSize, ng.InstanceVolumeIOPS)
+	if err != nil {
+		return err
+	}
+	ng.InstanceVolumeIOPS = instanceVolumeIOPS
+
+	return nil
+}
+
+// Validates the node group's instance quota and spot configuration (which require calls to AWS)
+func (ng *NodeGroup) validateWithAWS(awsClient *aws.Client, region string) error {
+	if err := awsClient.VerifyInstanceQuota(ng.InstanceType, ng.MaxPossibleOnDemandInstances(), ng.MaxPossibleSpotInstances()); err != nil {
+		// Skip AWS errors, since some regions (e.g. eu-north-1) do not support this API
+		if _, ok := errors.CauseOrSelf(err).(awserr.Error); !ok {
+			return errors.Wrap(err, InstanceTypeKey)
+		}
+	}
+
+	if ng.Spot {
+		if ng.SpotConfig == nil {
+			ng.SpotConfig = &SpotConfig{}
+		}
+		if err := AutoGenerateSpotConfig(awsClient, ng.SpotConfig, region, ng.InstanceType); err != nil {
+			return err
+		}
+
+		if err := validateSpotConfig(awsClient, region, ng.InstanceType, ng.MaxInstances, ng.SpotConfig); err != nil {
+			return err
+		}
+	} else {
+		if ng.SpotConfig != nil {
+			return ErrorConfiguredWhenSpotIsNotEnabled(SpotConfigKey)
+		}
+	}
+
+	return nil
+}
+
+// Validates the node group fields which can be checked without calling AWS, and checks that no two node groups share a name
+func validateNodeGroups(nodeGroups []*NodeGroup, region string) error {
+	nodeGroupNames := strset.New()
+	for _, nodeGroup := range nodeGroups {
+		if nodeGroupNames.Has(nodeGroup.Name) {
+			return errors.Wrap(ErrorDuplicateNodeGroupName(nodeGroup.Name), NodeGroupsKey)
+		}
+		nodeGroupNames.Add(nodeGroup.Name)
+
+		if err := nodeGroup.validate(region); err != nil {
+			return errors.Wrap(err, NodeGroupsKey, nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// Returns the node group which is defined by the top-level instance fields of the cluster configuration
+func (cc *Config) PrimaryNodeGroup() *NodeGroup {
+	nodeGroup := &NodeGroup{
+		Name:               PrimaryNodeGroupName,
+		InstanceVolumeSize: cc.InstanceVolumeSize,
+		InstanceVolumeType: cc.InstanceVolumeType,
+		InstanceVolumeIOPS: cc.InstanceVolumeIOPS,
+		Spot:               cc.Spot != nil && *cc.Spot,
+		SpotConfig:         cc.SpotConfig,
+	}
+	if cc.InstanceType != nil {
+		nodeGroup.InstanceType = *cc.InstanceType
+	}
+	if cc.MinInstances != nil {
+		nodeGroup.MinInstances = *cc.MinInstances
+	}
+	if cc.MaxInstances != nil {
+		nodeGroup.MaxInstances = *cc.MaxInstances
+	}
+	return nodeGroup
+}
+
+// Returns the primary node group followed by the additional node groups
+func (cc *Config) AllNodeGroups() []*NodeGroup {
+	return append([]*NodeGroup{cc.PrimaryNodeGroup()}, cc.NodeGroups...)
+}
+
+func (cc *Config) GetNodeGroup(name string) (*NodeGroup, bool) {
+	for _, nodeGroup := range cc.AllNodeGroups() {
+		if nodeGroup.Name == name {
+			return nodeGroup, true
+		}
+	}
+	return nil, false
+}
+
+func (cc *Config) NodeGroupNames() []string {
+	var names []string
+	for _, nodeGroup := range cc.AllNodeGroups() {
+		names = append(names, nodeGroup.Name)
+	}
+	return names
+}
+
+func (ng *NodeGroup) MaxPossibleOnDemandInstances() int64 {
+	return maxPossibleOnDemandInstances(ng.MaxInstances, ng.Spot, ng.SpotConfig)
+}
+
+func (ng *NodeGroup) MaxPossibleSpotInstances() int64 {
+	return maxPossibleSpotInstances(ng.MaxInstances, ng.Spot, ng.SpotConfig)
+}
+
+// The hourly price of the ebs volume attached to each instance in the node group
+func (ng *NodeGroup) EBSPrice(region string) float64 {
+	ebsMetadata := aws.EBSMetadatas[region][ng.InstanceVolumeType.String()]
+	price := ebsMetadata.PriceGB * float64(ng.InstanceVolumeSize) / 30 / 24
+	if ng.InstanceVolumeType == IO1VolumeType && ng.InstanceVolumeIOPS != nil {
+		price += ebsMetadata.PriceIOPS * float64(*ng.InstanceVolumeIOPS) / 30 / 24
+	}
+	return price
+}
+
+// Each key is annotated with the node group name, so that the table can be displayed alongside the primary node group's fields
+func (ng *NodeGroup) UserTable() table.KeyValuePairs {
+	var items table.KeyValuePairs
+
+	userKey := func(key string) string {
+		return fmt.Sprintf("%s (%s node group)", key, ng.Name)
+	}
+
+	items.Add(userKey(InstanceTypeUserKey), ng.InstanceType)
+	items.Add(userKey(MinInstancesUserKey), ng.MinInstances)
+	items.Add(userKey(MaxInstancesUserKey), ng.MaxInstances)
+	items.Add(userKey(InstanceVolumeSizeUserKey), ng.InstanceVolumeSize)
+	items.Add(userKey(InstanceVolumeTypeUserKey), ng.InstanceVolumeType)
+	items.Add(userKey(InstanceVolumeIOPSUserKey), ng.InstanceVolumeIOPS)
+	items.Add(userKey(SpotUserKey), s.YesNo(ng.Spot))
+
+	if ng.Spot && ng.SpotConfig != nil {
+		items.Add(userKey(InstanceDistributionUserKey), ng.SpotConfig.InstanceDistribution)
+		items.Add(userKey(OnDemandBaseCapacityUserKey), *ng.SpotConfig.OnDemandBaseCapacity)
+		items.Add(userKey(OnDemandPercentageAboveBaseCapacityUserKey), *ng.SpotConfig.OnDemandPercentageAboveBaseCapacity)
+		items.Add(userKey(MaxPriceUserKey), *ng.SpotConfig.MaxPrice)
+		items.Add(userKey(InstancePoolsUserKey), *ng.SpotConfig.InstancePools)
+		items.Add(userKey(OnDemandBackupUserKey), s.YesNo(*ng.SpotConfig.OnDemandBackup))
+	}
+
+	return items
+}
+
+// A one-line summary of the node group, e.g. "gpu: 0 - 5 g4dn.xlarge instances (spot)"
+func (ng *NodeGroup) SummaryStr() string {
+	instanceStr := "instances"
+	if ng.MinInstances == 1 && ng.MaxInstances == 1 {
+		instanceStr = "instance"
+	}
+
+	countStr := fmt.Sprintf("%d - %d", ng.MinInstances, ng.MaxInstances)
+	if ng.MinInstances == ng.MaxInstances {
+		countStr = s.Int64(ng.MinInstances)
+	}
+
+	summary := fmt.Sprintf("%s: %s %s %s", ng.Name, countStr, ng.InstanceType, instanceStr)
+	if ng.Spot {
+		summary += " (spot)"
+	}
+	return summary
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"testing"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/stretchr/testify/require"
+)
+
+func parseNodeGroups(t *testing.T, yamlStr string) ([]*NodeGroup, []error) {
+	t.Helper()
+
+	configData, err := cr.ReadYAMLBytes([]byte(yamlStr))
+	require.NoError(t, err)
+
+	config := Config{}
+	errs := cr.Struct(&config, configData, &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{_nodeGroupsValidation},
+	})
+	return config.NodeGroups, errs
+}
+
+func TestNodeGroupsValidation(t *testing.T) {
+	nodeGroups, errs := parseNodeGroups(t, `
+node_groups:
+  - name: gpu
+    instance_type: g4dn.xlarge
+`)
+	require.Empty(t, errs)
+	require.Len(t, nodeGroups, 1)
+	require.Equal(t, "gpu", nodeGroups[0].Name)
+	require.Equal(t, int64(1), nodeGroups[0].MinInstances)
+	require.Equal(t, int64(5), nodeGroups[0].MaxInstances)
+	require.Equal(t, int64(50), nodeGroups[0].InstanceVolumeSize)
+	require.Equal(t, GP2VolumeType, nodeGroups[0].InstanceVolumeType)
+	require.False(t, nodeGroups[0].Spot)
+
+	for _, tc := range []struct {
+		name    string
+		yamlStr string
+		errKind string
+	}{
+		{
+			name: "reserved name",
+			yamlStr: `
+node_groups:
+  - name: primary
+    instance_type: m5.large
+`,
+			errKind: ErrReservedNodeGroupName,
+		},
+		{
+			name: "invalid name",
+			yamlStr: `
+node_groups:
+  - name: GPU_Group
+    instance_type: m5.large
+`,
+			errKind: urls.ErrDNS1035,
+		},
+		{
+			name: "unknown instance type",
+			yamlStr: `
+node_groups:
+  - name: gpu
+    instance_type: m5.superlarge
+`,
+			errKind: ErrInvalidInstanceType,
+		},
+		{
+			name: "missing instance type",
+			yamlStr: `
+node_groups:
+  - name: gpu
+`,
+			errKind: cr.ErrMustBeDefined,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := parseNodeGroups(t, tc.yamlStr)
+			require.NotEmpty(t, errs)
+			require.Equal(t, tc.errKind, errors.GetKind(errs[0]))
+		})
+	}
+}
+
+func TestValidateNodeGroups(t *testing.T) {
+	nodeGroup := func(name string, instanceType string, minInstances int64, maxInstances int64) *NodeGroup {
+		return &NodeGroup{
+			Name:               name,
+			InstanceType:       instanceType,
+			MinInstances:       minInstances,
+			MaxInstances:       maxInstances,
+			InstanceVolumeSize: 50,
+			InstanceVolumeType: GP2VolumeType,
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		nodeGroups []*NodeGroup
+		region     string
+		errKind    string // empty if no error is expected
+	}{
+		{
+			name:       "valid",
+			nodeGroups: []*NodeGroup{nodeGroup("cpu", "m5.large", 0, 5), nodeGroup("gpu", "g4dn.xlarge", 1, 1)},
+			region:     "us-west-2",
+		},
+		{
+			name:       "duplicate names",
+			nodeGroups: []*NodeGroup{nodeGroup("gpu", "m5.large", 0, 5), nodeGroup("gpu", "g4dn.xlarge", 0, 5)},
+			region:     "us-west-2",
+			errKind:    ErrDuplicateNodeGroupName,
+		},
+		{
+			name:       "min instances greater than max instances",
+			nodeGroups: []*NodeGroup{nodeGroup("cpu", "m5.large", 6, 5)},
+			region:     "us-west-2",
+			errKind:    ErrMinInstancesGreaterThanMax,
+		},
+		{
+			name:       "instance type not supported in region",
+			nodeGroups: []*NodeGroup{nodeGroup("cpu", "m4.4xlarge", 0, 5)},
+			region:     "eu-north-1",
+			errKind:    ErrInstanceTypeNotSupportedInRegion,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNodeGroups(tc.nodeGroups, tc.region)
+			if tc.errKind == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Equal(t, tc.errKind, errors.GetKind(err))
+			}
+		})
+	}
+}
+
+func TestValidateNodeGroupsDefaultsIOPS(t *testing.T) {
+	nodeGroups := []*NodeGroup{
+		{
+			Name:               "gpu",
+			InstanceType:       "g4dn.xlarge",
+			MinInstances:       0,
+			MaxInstances:       5,
+			InstanceVolumeSize: 100,
+			InstanceVolumeType: IO1VolumeType,
+		},
+	}
+
+	require.NoError(t, validateNodeGroups(nodeGroups, "us-west-2"))
+	require.Equal(t, pointer.Int64(3000), nodeGroups[0].InstanceVolumeIOPS)
+
+	nodeGroups[0].InstanceVolumeIOPS = pointer.Int64(10000)
+	err := validateNodeGroups(nodeGroups, "us-west-2")
+	require.Error(t, err)
+	require.Equal(t, ErrIOPSTooLarge, errors.GetKind(err))
+}
+
+func TestNodeGroupEBSPrice(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		nodeGroup NodeGroup
+		expected  float64
+	}{
+		{
+			name:      "gp2",
+			nodeGroup: NodeGroup{InstanceVolumeSize: 72, InstanceVolumeType: GP2VolumeType},
+			expected:  0.1 * 72 / 30 / 24,
+		},
+		{
+			name:      "io1 includes iops",
+			nodeGroup: NodeGroup{InstanceVolumeSize: 72, InstanceVolumeType: IO1VolumeType, InstanceVolumeIOPS: pointer.Int64(720)},
+			expected:  (0.125*72 + 0.065*720) / 30 / 24,
+		},
+		{
+			name:      "sc1",
+			nodeGroup: NodeGroup{InstanceVolumeSize: 720, InstanceVolumeType: SC1VolumeType},
+			expected:  0.015 * 720 / 30 / 24,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDelta(t, tc.expected, tc.nodeGroup.EBSPrice("us-west-2"), 1e-9)
+		})
+	}
+}
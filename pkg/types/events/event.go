@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+type Event struct {
+	Type        Type            `json:"type"`
+	APIName     string          `json:"api_name"`
+	APIKind     userconfig.Kind `json:"api_kind"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Message     string          `json:"message"`
+	Replica     string          `json:"replica,omitempty"`      // name of the pod, for replica events
+	OldReplicas *int32          `json:"old_replicas,omitempty"` // for autoscaling events
+	NewReplicas *int32          `json:"new_replicas,omitempty"` // for autoscaling events
+	JobID       string          `json:"job_id,omitempty"`       // for job status change events
+	JobStatus   *status.JobCode `json:"job_status,omitempty"`   // for job status change events
+}
+
+func (event Event) String() string {
+	return fmt.Sprintf("%s %s %s: %s", event.Timestamp.Format(time.RFC3339), event.APIName, event.Type.String(), event.Message)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+type Type int
+
+const (
+	UnknownType Type = iota
+	APIDeployed
+	APIDeleted
+	UpdateStarted
+	UpdateFinished
+	ReplicaReady
+	ReplicaOOM
+	ReplicaFailed
+	Autoscaling
+	JobStatusChange
+	UpdateFailed
+	RolledBack
+)
+
+var _types = []string{
+	"unknown",
+	"api_deployed",
+	"api_deleted",
+	"update_started",
+	"update_finished",
+	"replica_ready",
+	"replica_oom",
+	"replica_failed",
+	"autoscaling",
+	"job_status_change",
+	"update_failed",
+	"rolled_back",
+}
+
+var _ = [1]int{}[int(RolledBack)-(len(_types)-1)] // Ensure list length matches
+
+func TypeFromString(s string) Type {
+	for i := 0; i < len(_types); i++ {
+		if s == _types[i] {
+			return Type(i)
+		}
+	}
+	return UnknownType
+}
+
+func TypeStrings() []string {
+	return _types[1:]
+}
+
+func (t Type) String() string {
+	if int(t) < 0 || int(t) >= len(_types) {
+		return _types[UnknownType]
+	}
+	return _types[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t Type) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *Type) UnmarshalText(text []byte) error {
+	*t = TypeFromString(string(text))
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *Type) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t Type) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -154,6 +154,18 @@ func Key(apiName string, apiID string, clusterName string) string {
 	)
 }
 
+// RolloutFailedKey marks an api id whose rolling update failed
+func RolloutFailedKey(apiName string, apiID string, clusterName string) string {
+	return filepath.Join(
+		clusterName,
+		"apis",
+		apiName,
+		"api",
+		apiID,
+		"rollout_failed.json",
+	)
+}
+
 // The path to the directory which contains one subdirectory for each API ID (for its API spec)
 func KeysPrefix(apiName string, clusterName string) string {
 	return filepath.Join(
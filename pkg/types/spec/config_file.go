@@ -0,0 +1,469 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cast"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// apiConfigFile holds the contents of an API configuration file, which is either a list of APIs,
+// or a map with the list of APIs (under "apis") and the values which are applied to every API (under "defaults")
+type apiConfigFile struct {
+	defaults      map[string]interface{}
+	apis          []map[string]interface{}
+	isList        bool
+	parentIndexes []int          // the index of the API which each API extends (or -1), set by resolveAPIs()
+	source        *cr.YAMLSource // nil if the position of the keys in the user's files is unknown
+}
+
+// prepended to API configuration files which are generated from the user's files (e.g. after merging an overlay file)
+const _generatedConfigHeader = "# generated by cortex\n"
+
+// appended to API configuration files which are generated from the user's files, followed by the position in the user's files of each key
+// (as JSON), so that errors can refer to the lines of the user's files (the generated file is what is sent to the operator)
+const _generatedConfigSourceLinesPrefix = "# source lines: "
+
+func readAPIConfigFile(configBytes []byte, configFileName string) (*apiConfigFile, error) {
+	configData, err := cr.ReadYAMLBytes(configBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	source := readConfigSource(configBytes)
+
+	if configDataSlice, ok := cast.InterfaceToStrInterfaceMapSlice(configData); ok {
+		return &apiConfigFile{apis: configDataSlice, isList: true, source: source}, nil
+	}
+
+	configDataMap, ok := cast.InterfaceToStrInterfaceMap(configData)
+	if !ok {
+		return nil, errors.Wrap(ErrorMalformedConfig(), configFileName)
+	}
+
+	configFile := apiConfigFile{source: source}
+	for _, key := range maps.InterfaceMapSortedKeys(configDataMap) {
+		val := configDataMap[key]
+		switch key {
+		case userconfig.DefaultsKey:
+			defaults, ok := cast.InterfaceToStrInterfaceMap(val)
+			if !ok {
+				return nil, errors.Wrap(cr.ErrorInvalidPrimitiveType(val, cr.PrimTypeMap), configFileName, key)
+			}
+			for _, disallowedKey := range []string{userconfig.NameKey, userconfig.ExtendsKey} {
+				if _, ok := defaults[disallowedKey]; ok {
+					return nil, errors.Wrap(cr.ErrorUnsupportedKey(disallowedKey), configFileName, key)
+				}
+			}
+			configFile.defaults = defaults
+		case userconfig.APIsKey:
+			apis, ok := cast.InterfaceToStrInterfaceMapSlice(val)
+			if !ok {
+				return nil, errors.Wrap(ErrorMalformedConfig(), configFileName, key)
+			}
+			configFile.apis = apis
+		default:
+			return nil, errors.Wrap(cr.ErrorUnsupportedKey(key, userconfig.DefaultsKey, userconfig.APIsKey), configFileName)
+		}
+	}
+
+	return &configFile, nil
+}
+
+// readConfigSource returns nil if the file was generated without recording the position of its keys in the user's files
+func readConfigSource(configBytes []byte) *cr.YAMLSource {
+	if !bytes.HasPrefix(configBytes, []byte(_generatedConfigHeader)) {
+		return cr.NewYAMLSource(configBytes)
+	}
+
+	i := bytes.LastIndex(configBytes, []byte("\n"+_generatedConfigSourceLinesPrefix))
+	if i == -1 {
+		return nil
+	}
+
+	var sourceLines map[int]cr.SourceLine
+	if err := json.Unmarshal(bytes.TrimSpace(configBytes[i+1+len(_generatedConfigSourceLinesPrefix):]), &sourceLines); err != nil {
+		return nil
+	}
+
+	return cr.NewGeneratedYAMLSource(configBytes, sourceLines)
+}
+
+// withSourceLines appends the position in the user's files of each key in the generated file (see readConfigSource)
+func withSourceLines(generatedBytes []byte, keyLine func(keyPath []string) (cr.SourceLine, bool)) ([]byte, error) {
+	sourceLines := cr.SourceLines(generatedBytes, keyLine)
+	if len(sourceLines) == 0 {
+		return generatedBytes, nil
+	}
+
+	sourceLinesBytes, err := json.Marshal(sourceLines)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedBytes = append(generatedBytes, _generatedConfigSourceLinesPrefix...)
+	generatedBytes = append(generatedBytes, sourceLinesBytes...)
+	return append(generatedBytes, '\n'), nil
+}
+
+func (configFile *apiConfigFile) yamlBytes() ([]byte, error) {
+	var yamlBytes []byte
+	var err error
+	if configFile.defaults == nil {
+		yamlBytes, err = yaml.Marshal(configFile.apis)
+	} else {
+		yamlBytes, err = yaml.Marshal(map[string]interface{}{
+			userconfig.DefaultsKey: configFile.defaults,
+			userconfig.APIsKey:     configFile.apis,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(_generatedConfigHeader), yamlBytes...), nil
+}
+
+// addLineNumbers adds the line number of the offending key to the errors from validating the API at index i;
+// keys which aren't set on the API itself are searched for in the APIs which it extends, and then in the defaults
+func (configFile *apiConfigFile) addLineNumbers(errs []error, i int) []error {
+	var fallbackKeyPaths [][]string
+	for parentIndex := configFile.parentIndex(i); parentIndex != -1; parentIndex = configFile.parentIndex(parentIndex) {
+		if len(fallbackKeyPaths) > len(configFile.apis) {
+			break // cycle (this is reported by resolveAPIs())
+		}
+		fallbackKeyPaths = append(fallbackKeyPaths, configFile.apiKeyPath(parentIndex))
+	}
+	if configFile.defaults != nil {
+		fallbackKeyPaths = append(fallbackKeyPaths, []string{userconfig.DefaultsKey})
+	}
+
+	return configFile.source.AddLineNumbers(errs, configFile.apiKeyPath(i), fallbackKeyPaths...)
+}
+
+func (configFile *apiConfigFile) apiKeyPath(i int) []string {
+	if configFile.isList {
+		return []string{s.Index(i)}
+	}
+	return []string{userconfig.APIsKey, s.Index(i)}
+}
+
+func (configFile *apiConfigFile) parentIndex(i int) int {
+	if i >= len(configFile.parentIndexes) {
+		return -1
+	}
+	return configFile.parentIndexes[i]
+}
+
+func identifyAPIConfig(configFileName string, data map[string]interface{}, index int) string {
+	name, _ := data[userconfig.NameKey].(string)
+	kindString, _ := data[userconfig.KindKey].(string)
+	return userconfig.IdentifyAPI(configFileName, name, userconfig.KindFromString(kindString), index)
+}
+
+// resolveAPIs returns the configuration of each API after applying inheritance (via "extends") and the defaults;
+// defaults are only applied for keys which are supported by the API's kind (keysForKind)
+func (configFile *apiConfigFile) resolveAPIs(configFileName string, keysForKind func(userconfig.Kind) []string) ([]map[string]interface{}, error) {
+	apiIndexes := map[string]int{}
+	for i, data := range configFile.apis {
+		if name, ok := data[userconfig.NameKey].(string); ok {
+			if _, ok := apiIndexes[name]; !ok {
+				apiIndexes[name] = i
+			}
+		}
+	}
+
+	parentIndexes := make([]int, len(configFile.apis))
+	for i, data := range configFile.apis {
+		parentIndexes[i] = -1
+
+		parentVal, ok := data[userconfig.ExtendsKey]
+		if !ok {
+			continue
+		}
+		parentName, ok := parentVal.(string)
+		if !ok {
+			return nil, errors.Wrap(cr.ErrorInvalidPrimitiveType(parentVal, cr.PrimTypeString), identifyAPIConfig(configFileName, data, i), userconfig.ExtendsKey)
+		}
+		parentIndex, ok := apiIndexes[parentName]
+		if !ok {
+			return nil, errors.Wrap(ErrorExtendedAPINotFound(parentName), identifyAPIConfig(configFileName, data, i), userconfig.ExtendsKey)
+		}
+		parentIndexes[i] = parentIndex
+	}
+	configFile.parentIndexes = parentIndexes
+
+	inherited := make([]map[string]interface{}, len(configFile.apis))
+	var inherit func(i int, chain []int) (map[string]interface{}, error)
+	inherit = func(i int, chain []int) (map[string]interface{}, error) {
+		if inherited[i] != nil {
+			return inherited[i], nil
+		}
+
+		data := configFile.apis[i]
+		if slices.HasInt(chain, i) {
+			names := make([]string, 0, len(chain)+1)
+			for _, chainIndex := range append(chain, i) {
+				name, _ := configFile.apis[chainIndex][userconfig.NameKey].(string)
+				names = append(names, name)
+			}
+			return nil, ErrorExtendsCycle(names)
+		}
+
+		if parentIndexes[i] == -1 {
+			inherited[i] = data
+			return data, nil
+		}
+
+		parent, err := inherit(parentIndexes[i], append(chain, i))
+		if err != nil {
+			return nil, err
+		}
+
+		inherited[i] = maps.DeepMergeStrInterfaceMaps(withoutKeys(parent, userconfig.NameKey), withoutKeys(data, userconfig.ExtendsKey))
+		return inherited[i], nil
+	}
+
+	resolved := make([]map[string]interface{}, len(configFile.apis))
+	for i := range configFile.apis {
+		data, err := inherit(i, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, identifyAPIConfig(configFileName, configFile.apis[i], i), userconfig.ExtendsKey)
+		}
+
+		if configFile.defaults == nil {
+			resolved[i] = data
+			continue
+		}
+
+		kindString, _ := data[userconfig.KindKey].(string)
+		if kindString == "" {
+			kindString, _ = configFile.defaults[userconfig.KindKey].(string)
+		}
+		supportedKeys := strset.New(keysForKind(userconfig.KindFromString(kindString))...)
+
+		defaults := map[string]interface{}{}
+		for key, val := range configFile.defaults {
+			if supportedKeys.Has(key) {
+				defaults[key] = val
+			}
+		}
+
+		resolved[i] = maps.DeepMergeStrInterfaceMaps(defaults, data)
+	}
+
+	return resolved, nil
+}
+
+func withoutKeys(data map[string]interface{}, keys ...string) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(data))
+	for key, val := range data {
+		filtered[key] = val
+	}
+	for _, key := range keys {
+		delete(filtered, key)
+	}
+	return filtered
+}
+
+// MergeAPIConfigOverlay merges an overlay configuration file (e.g. cortex.prod.yaml) on top of an API configuration file;
+// the defaults are merged, APIs in the overlay are merged into the APIs with the same name, and other APIs in the overlay are appended
+func MergeAPIConfigOverlay(configBytes []byte, configFileName string, overlayBytes []byte, overlayFileName string) ([]byte, error) {
+	configFile, err := readAPIConfigFile(configBytes, configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayFile, err := readAPIConfigFile(overlayBytes, overlayFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlayFile.defaults != nil {
+		configFile.defaults = maps.DeepMergeStrInterfaceMaps(configFile.defaults, overlayFile.defaults)
+	}
+
+	// the index of the API in each file which each merged API was read from (or -1)
+	configIndexes := make([]int, len(configFile.apis))
+	overlayIndexes := make([]int, len(configFile.apis))
+	for i := range configFile.apis {
+		configIndexes[i] = i
+		overlayIndexes[i] = -1
+	}
+
+	for i, overlayData := range overlayFile.apis {
+		name, ok := overlayData[userconfig.NameKey].(string)
+		if !ok || name == "" {
+			return nil, errors.Wrap(cr.ErrorMustBeDefined(), identifyAPIConfig(overlayFileName, overlayData, i), userconfig.NameKey)
+		}
+
+		merged := false
+		for j, data := range configFile.apis {
+			if dataName, _ := data[userconfig.NameKey].(string); dataName == name {
+				configFile.apis[j] = maps.DeepMergeStrInterfaceMaps(data, overlayData)
+				overlayIndexes[j] = i
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			configFile.apis = append(configFile.apis, overlayData)
+			configIndexes = append(configIndexes, -1)
+			overlayIndexes = append(overlayIndexes, i)
+		}
+	}
+
+	mergedBytes, err := configFile.yamlBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	// keys which are set in the overlay file take precedence over the same keys in the config file
+	mergedBytes, err = withSourceLines(mergedBytes, func(keyPath []string) (cr.SourceLine, bool) {
+		var configKeyPath, overlayKeyPath []string
+
+		if len(keyPath) > 0 && keyPath[0] == userconfig.DefaultsKey {
+			configKeyPath, overlayKeyPath = keyPath, keyPath
+		} else {
+			apiKeyPath := keyPath
+			if configFile.defaults != nil {
+				// the merged file is a map (see yamlBytes())
+				if len(apiKeyPath) < 2 || apiKeyPath[0] != userconfig.APIsKey {
+					return cr.SourceLine{}, false
+				}
+				apiKeyPath = apiKeyPath[1:]
+			}
+			if len(apiKeyPath) == 0 {
+				return cr.SourceLine{}, false
+			}
+
+			j, err := strconv.Atoi(strings.TrimPrefix(apiKeyPath[0], "index "))
+			if err != nil || j < 0 || j >= len(configIndexes) {
+				return cr.SourceLine{}, false
+			}
+			if configIndexes[j] != -1 {
+				configKeyPath = append(configFile.apiKeyPath(configIndexes[j]), apiKeyPath[1:]...)
+			}
+			if overlayIndexes[j] != -1 {
+				overlayKeyPath = append(overlayFile.apiKeyPath(overlayIndexes[j]), apiKeyPath[1:]...)
+			}
+		}
+
+		if overlayKeyPath != nil {
+			if sourceLine, ok := overlayFile.source.KeyLine(overlayKeyPath...); ok {
+				if sourceLine.File == "" {
+					sourceLine.File = overlayFileName
+				}
+				return sourceLine, true
+			}
+		}
+		if configKeyPath != nil {
+			return configFile.source.KeyLine(configKeyPath...)
+		}
+		return cr.SourceLine{}, false
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	return mergedBytes, nil
+}
+
+// InterpolateAPIConfigs resolves ${VAR} and ${VAR:-default} references in the API configuration file, and returns the resulting file
+func InterpolateAPIConfigs(configBytes []byte, configFileName string, lookup cr.VariableLookupFn) ([]byte, error) {
+	if !strings.Contains(string(configBytes), "${") {
+		return configBytes, nil
+	}
+
+	configFile, err := readAPIConfigFile(configBytes, configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file is parsed as a node tree so that quoted values can be distinguished from unquoted ones
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(configBytes, &document); err != nil {
+		return nil, errors.Wrap(cr.ErrorInvalidYAML(err), configFileName)
+	}
+	if len(document.Content) == 0 {
+		return configBytes, nil
+	}
+
+	var apiNodes []*yamlv3.Node
+	if configFile.isList {
+		apiNodes = document.Content[0].Content
+	} else {
+		rootNode := document.Content[0]
+		for i := 0; i+1 < len(rootNode.Content); i += 2 {
+			switch rootNode.Content[i].Value {
+			case userconfig.DefaultsKey:
+				if err := cr.InterpolateYAMLNode(rootNode.Content[i+1], lookup); err != nil {
+					return nil, errors.Wrap(err, configFileName, userconfig.DefaultsKey)
+				}
+			case userconfig.APIsKey:
+				apiNodes = rootNode.Content[i+1].Content
+			}
+		}
+	}
+
+	for i, apiNode := range apiNodes {
+		if err := cr.InterpolateYAMLNode(apiNode, lookup); err != nil {
+			var name, kindString string
+			if i < len(configFile.apis) {
+				name, _ = configFile.apis[i][userconfig.NameKey].(string)
+				kindString, _ = configFile.apis[i][userconfig.KindKey].(string)
+			}
+			if strings.Contains(name, "${") {
+				name = ""
+			}
+			return nil, errors.Wrap(err, userconfig.IdentifyAPI(configFileName, name, userconfig.KindFromString(kindString), i))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(_generatedConfigHeader)
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	// interpolation doesn't change the structure of the file, so each key has the same path in the original file
+	interpolatedBytes, err := withSourceLines(buf.Bytes(), func(keyPath []string) (cr.SourceLine, bool) {
+		return configFile.source.KeyLine(keyPath...)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	return interpolatedBytes, nil
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cast"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func testLookupFn(vars map[string]string) cr.VariableLookupFn {
+	return func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	}
+}
+
+func TestInterpolateAPIConfigs(t *testing.T) {
+	configBytes := []byte(`
+- name: ${NAME}
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+    config:
+      id: ${ID}
+    env:
+      MODEL_VERSION: ${MODEL_VERSION}
+      USE_GPU: ${USE_GPU}
+      PORT: "${PORT}"
+      DEBUG: 'false'
+  autoscaling:
+    min_replicas: ${MIN_REPLICAS}
+    target_replica_concurrency: ${CONCURRENCY}
+`)
+
+	lookup := testLookupFn(map[string]string{
+		"NAME":          "my-api",
+		"ID":            "0123",
+		"MODEL_VERSION": "1.10",
+		"USE_GPU":       "yes",
+		"PORT":          "8080",
+		"MIN_REPLICAS":  "2",
+		"CONCURRENCY":   "0.5",
+	})
+
+	interpolatedBytes, err := InterpolateAPIConfigs(configBytes, "cortex.yaml", lookup)
+	require.NoError(t, err)
+
+	apis, err := ExtractAPIConfigs(interpolatedBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, apis, 1)
+
+	api := apis[0]
+	require.Equal(t, "my-api", api.Name)
+	require.Equal(t, "0123", api.Predictor.Config["id"])
+	require.Equal(t, map[string]string{"MODEL_VERSION": "1.10", "USE_GPU": "yes", "PORT": "8080", "DEBUG": "false"}, api.Predictor.Env)
+	require.Equal(t, int32(2), api.Autoscaling.MinReplicas)
+	require.Equal(t, 0.5, *api.Autoscaling.TargetReplicaConcurrency)
+
+	// values which don't represent the field's type exactly are not converted
+	lookup = testLookupFn(map[string]string{
+		"NAME":          "my-api",
+		"ID":            "0123",
+		"MODEL_VERSION": "1.10",
+		"USE_GPU":       "yes",
+		"PORT":          "8080",
+		"MIN_REPLICAS":  "02",
+		"CONCURRENCY":   "0.5",
+	})
+
+	interpolatedBytes, err = InterpolateAPIConfigs(configBytes, "cortex.yaml", lookup)
+	require.NoError(t, err)
+
+	_, err = ExtractAPIConfigs(interpolatedBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "min_replicas")
+	require.Contains(t, err.Error(), "(line 15)") // the line in the original file
+
+	// quoted references are not converted
+	lookup = testLookupFn(map[string]string{"MIN_REPLICAS": "2"})
+	interpolatedBytes, err = InterpolateAPIConfigs([]byte(`
+defaults:
+  autoscaling:
+    min_replicas: "${MIN_REPLICAS}"
+apis:
+  - name: my-api
+    kind: RealtimeAPI
+    predictor:
+      type: python
+      path: predictor.py
+`), "cortex.yaml", lookup)
+	require.NoError(t, err)
+
+	_, err = ExtractAPIConfigs(interpolatedBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.Equal(t, cr.ErrInvalidPrimitiveType, errors.GetKind(err))
+	require.Contains(t, err.Error(), "min_replicas")
+	require.Contains(t, err.Error(), "(line 4)")
+}
+
+func TestQuotedLiteralsAreNotConverted(t *testing.T) {
+	configBytes := []byte(`
+- name: my-api
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+  autoscaling:
+    min_replicas: "5"
+`)
+
+	_, err := ExtractAPIConfigs(configBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.Equal(t, cr.ErrInvalidPrimitiveType, errors.GetKind(err))
+	require.Contains(t, err.Error(), "min_replicas")
+
+	// files without variable references are returned as is
+	interpolatedBytes, err := InterpolateAPIConfigs(configBytes, "cortex.yaml", testLookupFn(nil))
+	require.NoError(t, err)
+	require.Equal(t, configBytes, interpolatedBytes)
+}
+
+func testKeysForKind(kind userconfig.Kind) []string {
+	switch kind {
+	case userconfig.RealtimeAPIKind:
+		return []string{"name", "kind", "predictor", "compute", "autoscaling", "networking"}
+	case userconfig.BatchAPIKind:
+		return []string{"name", "kind", "predictor", "compute", "networking"}
+	}
+	return []string{"name", "kind"}
+}
+
+// yaml decodes nested maps as interface->interface maps, so nested maps are normalized before comparing
+func strInterfaceMaps(t *testing.T, in []map[string]interface{}) []map[string]interface{} {
+	t.Helper()
+	out := make([]map[string]interface{}, 0, len(in))
+	for _, m := range in {
+		casted, ok := cast.JSONMarshallable(m)
+		require.True(t, ok)
+		out = append(out, casted.(map[string]interface{}))
+	}
+	return out
+}
+
+func TestResolveAPIs(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		config    string
+		expected  []map[string]interface{}
+		errorKind string
+	}{
+		{
+			name: "list without defaults",
+			config: `
+- name: a
+  kind: RealtimeAPI
+`,
+			expected: []map[string]interface{}{
+				{"name": "a", "kind": "RealtimeAPI"},
+			},
+		},
+		{
+			name: "defaults",
+			config: `
+defaults:
+  kind: RealtimeAPI
+  compute:
+    cpu: 1
+    mem: 1G
+apis:
+  - name: a
+  - name: b
+    compute:
+      cpu: 2
+`,
+			expected: []map[string]interface{}{
+				{"name": "a", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(1), "mem": "1G"}},
+				{"name": "b", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(2), "mem": "1G"}},
+			},
+		},
+		{
+			name: "defaults which are not supported by the api's kind are dropped",
+			config: `
+defaults:
+  compute:
+    cpu: 1
+  autoscaling:
+    min_replicas: 2
+apis:
+  - name: a
+    kind: RealtimeAPI
+  - name: b
+    kind: BatchAPI
+`,
+			expected: []map[string]interface{}{
+				{"name": "a", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(1)}, "autoscaling": map[string]interface{}{"min_replicas": int64(2)}},
+				{"name": "b", "kind": "BatchAPI", "compute": map[string]interface{}{"cpu": int64(1)}},
+			},
+		},
+		{
+			name: "single-level extends",
+			config: `
+- name: a
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+    config:
+      threshold: 0.5
+      labels: [p, q]
+- name: b
+  extends: a
+  predictor:
+    config:
+      labels: [z]
+`,
+			expected: []map[string]interface{}{
+				{"name": "a", "kind": "RealtimeAPI", "predictor": map[string]interface{}{"type": "python", "path": "predictor.py", "config": map[string]interface{}{"threshold": 0.5, "labels": []interface{}{"p", "q"}}}},
+				{"name": "b", "kind": "RealtimeAPI", "predictor": map[string]interface{}{"type": "python", "path": "predictor.py", "config": map[string]interface{}{"threshold": 0.5, "labels": []interface{}{"z"}}}},
+			},
+		},
+		{
+			name: "multi-level extends with defaults",
+			config: `
+defaults:
+  kind: RealtimeAPI
+  compute:
+    mem: 1G
+apis:
+  - name: c
+    extends: b
+    compute:
+      gpu: 1
+  - name: b
+    extends: a
+    compute:
+      cpu: 2
+  - name: a
+    compute:
+      cpu: 1
+`,
+			expected: []map[string]interface{}{
+				{"name": "c", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(2), "gpu": int64(1), "mem": "1G"}},
+				{"name": "b", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(2), "mem": "1G"}},
+				{"name": "a", "kind": "RealtimeAPI", "compute": map[string]interface{}{"cpu": int64(1), "mem": "1G"}},
+			},
+		},
+		{
+			name: "self-extend cycle",
+			config: `
+- name: a
+  kind: RealtimeAPI
+  extends: a
+`,
+			errorKind: ErrExtendsCycle,
+		},
+		{
+			name: "two-api cycle",
+			config: `
+- name: a
+  kind: RealtimeAPI
+  extends: b
+- name: b
+  kind: RealtimeAPI
+  extends: a
+`,
+			errorKind: ErrExtendsCycle,
+		},
+		{
+			name: "unknown parent",
+			config: `
+- name: a
+  kind: RealtimeAPI
+  extends: missing
+`,
+			errorKind: ErrExtendedAPINotFound,
+		},
+		{
+			name: "parent name which is not a string",
+			config: `
+- name: a
+  kind: RealtimeAPI
+  extends: [b]
+`,
+			errorKind: cr.ErrInvalidPrimitiveType,
+		},
+	} {
+		configFile, err := readAPIConfigFile([]byte(tc.config), "cortex.yaml")
+		require.NoError(t, err, tc.name)
+
+		resolved, err := configFile.resolveAPIs("cortex.yaml", testKeysForKind)
+		if tc.errorKind != "" {
+			require.Equal(t, tc.errorKind, errors.GetKind(err), tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, strInterfaceMaps(t, resolved), tc.name)
+	}
+}
+
+func TestResolveAPIsCycleMessage(t *testing.T) {
+	configFile, err := readAPIConfigFile([]byte(`
+- name: a
+  kind: RealtimeAPI
+  extends: b
+- name: b
+  kind: RealtimeAPI
+  extends: a
+`), "cortex.yaml")
+	require.NoError(t, err)
+
+	_, err = configFile.resolveAPIs("cortex.yaml", testKeysForKind)
+	require.Contains(t, errors.Message(err), "a → b → a")
+}
+
+func TestMergeAPIConfigOverlay(t *testing.T) {
+	configBytes := []byte(`
+defaults:
+  kind: RealtimeAPI
+  compute:
+    cpu: 1
+apis:
+  - name: a
+    predictor:
+      type: python
+      path: predictor.py
+      config:
+        bucket: dev-bucket
+        labels: [p, q]
+  - name: b
+    predictor:
+      type: python
+      path: predictor.py
+`)
+
+	overlayBytes := []byte(`
+defaults:
+  compute:
+    mem: 2G
+apis:
+  - name: a
+    predictor:
+      config:
+        bucket: prod-bucket
+        labels: [z]
+  - name: c
+    predictor:
+      type: python
+      path: other.py
+`)
+
+	mergedBytes, err := MergeAPIConfigOverlay(configBytes, "cortex.yaml", overlayBytes, "cortex.prod.yaml")
+	require.NoError(t, err)
+
+	mergedFile, err := readAPIConfigFile(mergedBytes, "cortex.yaml")
+	require.NoError(t, err)
+
+	// keys refer to their position in the file which they were read from
+	for _, testCase := range []struct {
+		keyPath    []string
+		sourceLine cr.SourceLine
+	}{
+		{[]string{"defaults", "compute", "cpu"}, cr.SourceLine{Line: 5}},
+		{[]string{"defaults", "compute", "mem"}, cr.SourceLine{File: "cortex.prod.yaml", Line: 4}},
+		{[]string{"apis", "index 0", "predictor", "path"}, cr.SourceLine{Line: 10}},
+		{[]string{"apis", "index 0", "predictor", "config", "bucket"}, cr.SourceLine{File: "cortex.prod.yaml", Line: 9}},
+		{[]string{"apis", "index 1", "predictor", "path"}, cr.SourceLine{Line: 17}},
+		{[]string{"apis", "index 2", "predictor", "path"}, cr.SourceLine{File: "cortex.prod.yaml", Line: 14}},
+	} {
+		sourceLine, ok := mergedFile.source.KeyLine(testCase.keyPath...)
+		require.True(t, ok, testCase.keyPath)
+		require.Equal(t, testCase.sourceLine, sourceLine, testCase.keyPath)
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"kind":    "RealtimeAPI",
+		"compute": map[string]interface{}{"cpu": int64(1), "mem": "2G"},
+	}, strInterfaceMaps(t, []map[string]interface{}{mergedFile.defaults})[0])
+
+	require.Equal(t, []map[string]interface{}{
+		{"name": "a", "predictor": map[string]interface{}{"type": "python", "path": "predictor.py", "config": map[string]interface{}{"bucket": "prod-bucket", "labels": []interface{}{"z"}}}},
+		{"name": "b", "predictor": map[string]interface{}{"type": "python", "path": "predictor.py"}},
+		{"name": "c", "predictor": map[string]interface{}{"type": "python", "path": "other.py"}},
+	}, strInterfaceMaps(t, mergedFile.apis))
+
+	// errors in interpolated overlay files refer to the overlay file's lines
+	interpolatedOverlayBytes, err := InterpolateAPIConfigs([]byte(`
+# overrides for production
+
+- name: a
+  predictor:
+    config:
+      bucket: ${BUCKET}
+    pth: other.py
+`), "cortex.prod.yaml", testLookupFn(map[string]string{"BUCKET": "prod-bucket"}))
+	require.NoError(t, err)
+
+	mergedBytes, err = MergeAPIConfigOverlay(configBytes, "cortex.yaml", interpolatedOverlayBytes, "cortex.prod.yaml")
+	require.NoError(t, err)
+
+	_, err = ExtractAPIConfigs(mergedBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.Equal(t, cr.ErrUnsupportedKey, errors.GetKind(err))
+	require.Contains(t, err.Error(), `key "pth" is not supported (did you mean "path"?) (cortex.prod.yaml, line 8)`)
+
+	// apis in the overlay must be named
+	_, err = MergeAPIConfigOverlay(configBytes, "cortex.yaml", []byte("- predictor:\n    type: python\n"), "cortex.prod.yaml")
+	require.Equal(t, cr.ErrMustBeDefined, errors.GetKind(err))
+}
@@ -19,6 +19,7 @@ package spec
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -90,6 +91,16 @@ const (
 	ErrIncorrectTrafficSplitterWeight       = "spec.incorrect_traffic_splitter_weight"
 	ErrTrafficSplitterAPIsNotUnique         = "spec.traffic_splitter_apis_not_unique"
 	ErrUnexpectedDockerSecretData           = "spec.unexpected_docker_secret_data"
+	ErrSecretsNotSupportedByProvider        = "spec.secrets_not_supported_by_provider"
+	ErrNodeGroupsNotSupportedByProvider     = "spec.node_groups_not_supported_by_provider"
+	ErrSecretNotFound                       = "spec.secret_not_found"
+	ErrSecretKeyNotFound                    = "spec.secret_key_not_found"
+	ErrFieldRequiresBlueGreenUpdateStrategy = "spec.field_requires_blue_green_update_strategy"
+	ErrFieldRequiresRollingUpdateStrategy   = "spec.field_requires_rolling_update_strategy"
+	ErrInvalidSmokeTestPayload              = "spec.invalid_smoke_test_payload"
+	ErrExtendedAPINotFound                  = "spec.extended_api_not_found"
+	ErrExtendsCycle                         = "spec.extends_cycle"
+	ErrAPIGatewayDisabled                   = "spec.api_gateway_disabled"
 )
 
 var _modelCurrentStructure = `
@@ -100,7 +111,7 @@ var _modelCurrentStructure = `
 func ErrorMalformedConfig() error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrMalformedConfig,
-		Message: fmt.Sprintf("cortex YAML configuration files must contain a list of maps (see https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration for Realtime API documentation and see https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration for Batch API documentation)", consts.CortexVersionMinor, consts.CortexVersionMinor),
+		Message: fmt.Sprintf("cortex YAML configuration files must contain a list of maps, or a map with a list of maps under \"apis\" (and optionally a map under \"defaults\") (see https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration for Realtime API documentation and see https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration for Batch API documentation)", consts.CortexVersionMinor, consts.CortexVersionMinor),
 	})
 }
 
@@ -221,6 +232,27 @@ func ErrorSurgeAndUnavailableBothZero() error {
 	})
 }
 
+func ErrorFieldRequiresBlueGreenUpdateStrategy(fieldKey string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldRequiresBlueGreenUpdateStrategy,
+		Message: fmt.Sprintf("%s can only be specified when %s is set to %s", fieldKey, userconfig.UpdateStrategyTypeKey, userconfig.BlueGreenUpdateStrategyType.String()),
+	})
+}
+
+func ErrorFieldRequiresRollingUpdateStrategy(fieldKey string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldRequiresRollingUpdateStrategy,
+		Message: fmt.Sprintf("%s can only be specified when %s is set to %s (a failed %s update never receives traffic, so there is nothing to roll back)", fieldKey, userconfig.UpdateStrategyTypeKey, userconfig.RollingUpdateStrategyType.String(), userconfig.BlueGreenUpdateStrategyType.String()),
+	})
+}
+
+func ErrorInvalidSmokeTestPayload() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSmokeTestPayload,
+		Message: "the smoke test payload must be valid json",
+	})
+}
+
 func ErrorModelCachingNotSupportedWhenMultiprocessingEnabled(desiredProcesses int32) error {
 	const maxNumProcesses int32 = 1
 	return errors.WithStack(&errors.Error{
@@ -600,6 +632,34 @@ func ErrorTrafficSplitterAPIsNotUnique(names []string) error {
 	})
 }
 
+func ErrorSecretsNotSupportedByProvider(provider types.ProviderType) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretsNotSupportedByProvider,
+		Message: fmt.Sprintf("secret references are not supported on %s provider; please set the value of the environment variable directly", provider.String()),
+	})
+}
+
+func ErrorNodeGroupsNotSupportedByProvider(provider types.ProviderType) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNodeGroupsNotSupportedByProvider,
+		Message: fmt.Sprintf("node groups are not supported on %s provider; please remove %s from your api configuration", provider.String(), userconfig.NodeGroupKey),
+	})
+}
+
+func ErrorSecretNotFound(secretName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretNotFound,
+		Message: fmt.Sprintf("secret %s does not exist; you can create it with `cortex secret set %s KEY=VALUE`", secretName, secretName),
+	})
+}
+
+func ErrorSecretKeyNotFound(secretName string, key string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretKeyNotFound,
+		Message: fmt.Sprintf("secret %s does not contain key %s; you can add it with `cortex secret set %s %s=VALUE`", secretName, key, secretName, key),
+	})
+}
+
 var _pwRegex = regexp.MustCompile(`"password":"[^"]+"`)
 var _authRegex = regexp.MustCompile(`"auth":"[^"]+"`)
 
@@ -618,3 +678,24 @@ func ErrorUnexpectedDockerSecretData(reason string, secretData map[string][]byte
 		Message: fmt.Sprintf("docker registry secret named \"%s\" was found, but contains unexpected data (%s); got: %s", _dockerPullSecretName, reason, s.UserStr(secretDataStrMap)),
 	})
 }
+
+func ErrorExtendedAPINotFound(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrExtendedAPINotFound,
+		Message: fmt.Sprintf("api %s is not defined in the same configuration file", s.UserStr(apiName)),
+	})
+}
+
+func ErrorExtendsCycle(apiNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrExtendsCycle,
+		Message: fmt.Sprintf("apis cannot extend each other in a cycle (%s)", strings.Join(apiNames, " → ")),
+	})
+}
+
+func ErrorAPIGatewayDisabled(apiGatewayType userconfig.APIGatewayType) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIGatewayDisabled,
+		Message: fmt.Sprintf("%s is not permitted because api gateway is disabled cluster-wide (valid values are %s)", s.UserStr(apiGatewayType), s.UserStrsAnd(userconfig.APIGatewayTypeStrings())),
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// APIConfigJSONSchema returns a JSON Schema for API configuration files. APIs which use "extends" or
+// which are in a file with "defaults" are described by partial schemas (i.e. without required fields),
+// since their missing fields may be provided by the extended API or the defaults. Integer, number, and boolean fields also
+// accept a single config variable reference (e.g. "${MIN_REPLICAS}").
+func APIConfigJSONSchema(provider types.ProviderType) (map[string]interface{}, error) {
+	definitions := map[string]interface{}{}
+	apiRefs := []interface{}{}
+	partialAPISchemas := []interface{}{}
+	defaultsProperties := map[string]interface{}{}
+
+	for _, kindStr := range userconfig.KindStrings() {
+		kind := userconfig.KindFromString(kindStr)
+		if kind == userconfig.BatchAPIKind && provider == types.GCPProviderType {
+			continue
+		}
+
+		schema, err := cr.StructJSONSchema(&userconfig.API{}, apiValidation(provider, userconfig.Resource{Kind: kind}, nil, nil))
+		if err != nil {
+			return nil, err
+		}
+		delete(schema, "$schema")
+
+		properties := schema["properties"].(map[string]interface{})
+		properties[userconfig.KindKey] = map[string]interface{}{"const": kindStr}
+		properties[userconfig.ExtendsKey] = map[string]interface{}{"type": "string"}
+
+		definitions[kindStr] = schema
+		apiRefs = append(apiRefs, map[string]interface{}{"$ref": "#/definitions/" + kindStr})
+
+		partialSchema := withoutRequiredFields(schema).(map[string]interface{})
+		partialAPISchemas = append(partialAPISchemas, partialSchema)
+
+		for key, propertySchema := range partialSchema["properties"].(map[string]interface{}) {
+			if key == userconfig.NameKey || key == userconfig.ExtendsKey || key == userconfig.KindKey {
+				continue
+			}
+			if _, ok := defaultsProperties[key]; !ok {
+				defaultsProperties[key] = propertySchema
+			}
+		}
+	}
+
+	defaultsProperties[userconfig.KindKey] = map[string]interface{}{"enum": userconfig.KindStrings()}
+
+	definitions["api"] = map[string]interface{}{
+		"if":   map[string]interface{}{"type": "object", "required": []string{userconfig.ExtendsKey}},
+		"then": map[string]interface{}{"$ref": "#/definitions/partialAPI"},
+		"else": map[string]interface{}{"oneOf": apiRefs},
+	}
+	definitions["partialAPI"] = map[string]interface{}{"anyOf": partialAPISchemas}
+
+	schema := map[string]interface{}{
+		"$schema":     cr.JSONSchemaDraft,
+		"title":       "cortex api configuration",
+		"definitions": definitions,
+		"oneOf": []interface{}{
+			map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": "#/definitions/api"},
+			},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					userconfig.DefaultsKey: map[string]interface{}{
+						"type":                 "object",
+						"properties":           defaultsProperties,
+						"additionalProperties": false,
+					},
+					userconfig.APIsKey: map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"$ref": "#/definitions/partialAPI"},
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+
+	// typed values can be provided by config variables (e.g. `min_replicas: ${MIN_REPLICAS}`)
+	return cr.JSONSchemaWithReferences(schema).(map[string]interface{}), nil
+}
+
+// returns a copy of the schema without any "required" keywords
+func withoutRequiredFields(schema interface{}) interface{} {
+	switch typed := schema.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(typed))
+		for key, val := range typed {
+			if _, isKeyList := val.([]string); key == "required" && isKeyList {
+				continue
+			}
+			copied[key] = withoutRequiredFields(val)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for i, val := range typed {
+			copied[i] = withoutRequiredFields(val)
+		}
+		return copied
+	}
+	return schema
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIConfigJSONSchema(t *testing.T) {
+	for _, provider := range []types.ProviderType{types.LocalProviderType, types.AWSProviderType, types.GCPProviderType} {
+		schema, err := APIConfigJSONSchema(provider)
+		require.NoError(t, err, provider.String())
+
+		definitions := schema["definitions"].(map[string]interface{})
+		for _, kindStr := range userconfig.KindStrings() {
+			kind := userconfig.KindFromString(kindStr)
+
+			kindSchema, ok := definitions[kindStr].(map[string]interface{})
+			if kind == userconfig.BatchAPIKind && provider == types.GCPProviderType {
+				require.False(t, ok)
+				continue
+			}
+			require.True(t, ok, kindStr)
+
+			expectedKeys := append(cr.StructKeys(&userconfig.API{}, apiValidation(provider, userconfig.Resource{Kind: kind}, nil, nil)), userconfig.ExtendsKey)
+			require.ElementsMatch(t, expectedKeys, maps.InterfaceMapKeys(kindSchema["properties"].(map[string]interface{})), kindStr)
+			require.Equal(t, map[string]interface{}{"const": kindStr}, kindSchema["properties"].(map[string]interface{})[userconfig.KindKey])
+		}
+	}
+}
+
+func TestAPIConfigJSONSchemaAllowsReferences(t *testing.T) {
+	schema, err := APIConfigJSONSchema(types.AWSProviderType)
+	require.NoError(t, err)
+
+	realtimeSchema := schema["definitions"].(map[string]interface{})[userconfig.RealtimeAPIKind.String()].(map[string]interface{})
+	autoscalingSchema := realtimeSchema["properties"].(map[string]interface{})[userconfig.AutoscalingKey].(map[string]interface{})
+	minReplicasSchema := autoscalingSchema["properties"].(map[string]interface{})[userconfig.MinReplicasKey].(map[string]interface{})
+
+	anyOf, ok := minReplicasSchema["anyOf"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, anyOf, 2)
+	require.Equal(t, "integer", anyOf[0].(map[string]interface{})["type"])
+	require.Equal(t, map[string]interface{}{"type": "string", "pattern": cr.SingleReferencePattern}, anyOf[1])
+}
@@ -97,7 +97,7 @@ func getErrorForPredictorType(api userconfig.API, modelPrefix string, modelPaths
 	return nil
 }
 
-func validateDirModels(modelPath string, api userconfig.API, projectDir string, awsClient *aws.Client, gcpClient *gcp.Client, extraValidators []modelValidator) ([]CuratedModelResource, error) {
+func validateDirModels(modelPath string, api userconfig.API, projectDir string, awsClient *aws.Client, gcpClient *gcp.Client, offline bool, extraValidators []modelValidator) ([]CuratedModelResource, error) {
 	var bucket string
 	var dirPrefix string
 	var modelDirPaths []string
@@ -109,6 +109,11 @@ func validateDirModels(modelPath string, api userconfig.API, projectDir string,
 	gcsPath := strings.HasPrefix(modelPath, "gs://")
 	localPath := !s3Path && !gcsPath
 
+	// the models in the directory can't be determined without listing the bucket
+	if offline && !localPath {
+		return nil, nil
+	}
+
 	if s3Path {
 		awsClientForBucket, err := aws.NewFromClientS3Path(modelPath, awsClient)
 		if err != nil {
@@ -228,7 +233,7 @@ func validateDirModels(modelPath string, api userconfig.API, projectDir string,
 	return modelResources, nil
 }
 
-func validateModels(models []userconfig.ModelResource, api userconfig.API, projectDir string, awsClient *aws.Client, gcpClient *gcp.Client, extraValidators []modelValidator) ([]CuratedModelResource, error) {
+func validateModels(models []userconfig.ModelResource, api userconfig.API, projectDir string, awsClient *aws.Client, gcpClient *gcp.Client, offline bool, extraValidators []modelValidator) ([]CuratedModelResource, error) {
 	var bucket string
 	var modelPrefix string
 	var modelPaths []string
@@ -242,6 +247,19 @@ func validateModels(models []userconfig.ModelResource, api userconfig.API, proje
 		gcsPath := strings.HasPrefix(model.ModelPath, "gs://")
 		localPath := !s3Path && !gcsPath
 
+		if offline && !localPath {
+			modelResources[i] = CuratedModelResource{
+				ModelResource: &userconfig.ModelResource{
+					Name:         model.Name,
+					ModelPath:    modelPath,
+					SignatureKey: model.SignatureKey,
+				},
+				S3Path:  s3Path,
+				GCSPath: gcsPath,
+			}
+			continue
+		}
+
 		if s3Path {
 			awsClientForBucket, err := aws.NewFromClientS3Path(model.ModelPath, awsClient)
 			if err != nil {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/gcp"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/regex"
@@ -49,6 +51,8 @@ import (
 
 var AutoscalingTickInterval = 10 * time.Second
 
+var _minProgressDeadline = 1 * time.Minute
+
 const _dockerPullSecretName = "registry-credentials"
 
 func apiValidation(
@@ -295,7 +299,7 @@ func networkingValidation(
 			},
 		},
 	}
-	if kind == userconfig.RealtimeAPIKind {
+	if kind == userconfig.RealtimeAPIKind || kind == userconfig.TrafficSplitterKind {
 		structFieldValidation = append(structFieldValidation, &cr.StructFieldValidation{
 			StructField: "LocalPort",
 			IntPtrValidation: &cr.IntPtrValidation{
@@ -356,6 +360,13 @@ func computeValidation(provider types.ProviderType) *cr.StructFieldValidation {
 						GreaterThanOrEqualTo: pointer.Int64(0),
 					},
 				},
+				{
+					StructField: "NodeGroup",
+					StringPtrValidation: &cr.StringPtrValidation{
+						AllowExplicitNull: true,
+						DNS1035:           true,
+					},
+				},
 			},
 		},
 	}
@@ -479,6 +490,16 @@ func updateStrategyValidation(provider types.ProviderType) *cr.StructFieldValida
 			DefaultNil:        defaultNil,
 			AllowExplicitNull: allowExplicitNull,
 			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Type",
+					StringValidation: &cr.StringValidation{
+						AllowedValues: userconfig.UpdateStrategyTypeStrings(),
+						Default:       userconfig.RollingUpdateStrategyType.String(),
+					},
+					Parser: func(str string) (interface{}, error) {
+						return userconfig.UpdateStrategyTypeFromString(str), nil
+					},
+				},
 				{
 					StructField: "MaxSurge",
 					StringValidation: &cr.StringValidation{
@@ -495,6 +516,34 @@ func updateStrategyValidation(provider types.ProviderType) *cr.StructFieldValida
 						Validator: surgeOrUnavailableValidator,
 					},
 				},
+				{
+					StructField: "ProgressDeadline",
+					StringValidation: &cr.StringValidation{
+						Default: "10m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: &_minProgressDeadline,
+					}),
+				},
+				{
+					StructField: "AutoRollback",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField:         "SmokeTestPayload",
+					StringPtrValidation: &cr.StringPtrValidation{},
+				},
+				{
+					StructField: "TeardownGracePeriod",
+					StringValidation: &cr.StringValidation{
+						Default: "5m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(0),
+					}),
+				},
 			},
 		},
 	}
@@ -621,56 +670,46 @@ func ExtractAPIConfigs(
 
 	var err error
 
-	configData, err := cr.ReadYAMLBytes(configBytes)
+	configFile, err := readAPIConfigFile(configBytes, configFileName)
 	if err != nil {
-		return nil, errors.Wrap(err, configFileName)
+		return nil, err
 	}
 
-	configDataSlice, ok := cast.InterfaceToStrInterfaceMapSlice(configData)
-	if !ok {
-		return nil, errors.Wrap(ErrorMalformedConfig(), configFileName)
+	configDataSlice, err := configFile.resolveAPIs(configFileName, func(kind userconfig.Kind) []string {
+		return cr.StructKeys(&userconfig.API{}, apiValidation(provider, userconfig.Resource{Kind: kind}, awsClusterConfig, gcpClusterConfig))
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	var allErrs []error
+	var invalidKinds []userconfig.Kind
+
 	apis := make([]userconfig.API, len(configDataSlice))
 	for i, data := range configDataSlice {
 		api := userconfig.API{}
 		var resourceStruct userconfig.Resource
 		errs := cr.Struct(&resourceStruct, data, &resourceStructValidation)
 		if errors.HasError(errs) {
+			errs = configFile.addLineNumbers(errs, i)
 			name, _ := data[userconfig.NameKey].(string)
 			kindString, _ := data[userconfig.KindKey].(string)
-			kind := userconfig.KindFromString(kindString)
-			err = errors.Wrap(errors.FirstError(errs...), userconfig.IdentifyAPI(configFileName, name, kind, i))
-			switch provider {
-			case types.LocalProviderType:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Realtime APIs can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration", consts.CortexVersionMinor))
-			case types.AWSProviderType:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found here:\n  → Realtime API: https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration\n  → Batch API: https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration\n  → Traffic Splitter: https://docs.cortex.dev/v/%s/deployments/realtime-api/traffic-splitter", consts.CortexVersionMinor, consts.CortexVersionMinor, consts.CortexVersionMinor))
-			case types.GCPProviderType:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Realtime APIs can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration", consts.CortexVersionMinor))
-			}
+			allErrs = append(allErrs, errors.Wrap(errors.Join(errs...), userconfig.IdentifyAPI(configFileName, name, userconfig.KindFromString(kindString), i)))
+			invalidKinds = append(invalidKinds, userconfig.UnknownKind)
+			continue
 		}
 
-		if resourceStruct.Kind == userconfig.BatchAPIKind || resourceStruct.Kind == userconfig.TrafficSplitterKind {
-			if provider == types.LocalProviderType || provider == types.GCPProviderType {
-				return nil, errors.Wrap(ErrorKindIsNotSupportedByProvider(resourceStruct.Kind, provider), userconfig.IdentifyAPI(configFileName, resourceStruct.Name, resourceStruct.Kind, i))
-			}
+		if resourceStruct.Kind == userconfig.BatchAPIKind && provider == types.GCPProviderType {
+			allErrs = append(allErrs, errors.Wrap(ErrorKindIsNotSupportedByProvider(resourceStruct.Kind, provider), userconfig.IdentifyAPI(configFileName, resourceStruct.Name, resourceStruct.Kind, i)))
+			continue
 		}
 
 		errs = cr.Struct(&api, data, apiValidation(provider, resourceStruct, awsClusterConfig, gcpClusterConfig))
 		if errors.HasError(errs) {
-			name, _ := data[userconfig.NameKey].(string)
-			kindString, _ := data[userconfig.KindKey].(string)
-			kind := userconfig.KindFromString(kindString)
-			err = errors.Wrap(errors.FirstError(errs...), userconfig.IdentifyAPI(configFileName, name, kind, i))
-			switch kind {
-			case userconfig.RealtimeAPIKind:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Realtime API can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration", consts.CortexVersionMinor))
-			case userconfig.BatchAPIKind:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Batch API can be found at https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration", consts.CortexVersionMinor))
-			case userconfig.TrafficSplitterKind:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Traffic Splitter can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/traffic-splitter", consts.CortexVersionMinor))
-			}
+			errs = configFile.addLineNumbers(errs, i)
+			allErrs = append(allErrs, errors.Wrap(errors.Join(errs...), userconfig.IdentifyAPI(configFileName, resourceStruct.Name, resourceStruct.Kind, i)))
+			invalidKinds = append(invalidKinds, resourceStruct.Kind)
+			continue
 		}
 		api.Index = i
 		api.FileName = configFileName
@@ -689,9 +728,41 @@ func ExtractAPIConfigs(
 		apis[i] = api
 	}
 
+	if len(allErrs) > 0 {
+		return nil, errors.Append(errors.Join(allErrs...), apiConfigSchemaMessage(provider, invalidKinds))
+	}
+
 	return apis, nil
 }
 
+// apiConfigSchemaMessage links to the api configuration docs of the kind of the invalid apis (or of all kinds if they differ or are unknown)
+func apiConfigSchemaMessage(provider types.ProviderType, invalidKinds []userconfig.Kind) string {
+	if len(invalidKinds) == 0 {
+		return ""
+	}
+
+	kind := invalidKinds[0]
+	for _, invalidKind := range invalidKinds {
+		if invalidKind != kind {
+			kind = userconfig.UnknownKind
+		}
+	}
+
+	switch kind {
+	case userconfig.RealtimeAPIKind:
+		return fmt.Sprintf("\n\napi configuration schema for Realtime API can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration", consts.CortexVersionMinor)
+	case userconfig.BatchAPIKind:
+		return fmt.Sprintf("\n\napi configuration schema for Batch API can be found at https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration", consts.CortexVersionMinor)
+	case userconfig.TrafficSplitterKind:
+		return fmt.Sprintf("\n\napi configuration schema for Traffic Splitter can be found at https://docs.cortex.dev/v/%s/deployments/realtime-api/traffic-splitter", consts.CortexVersionMinor)
+	}
+
+	if provider == types.GCPProviderType {
+		return fmt.Sprintf("\n\napi configuration schema can be found here:\n  → Realtime API: https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration\n  → Traffic Splitter: https://docs.cortex.dev/v/%s/deployments/realtime-api/traffic-splitter", consts.CortexVersionMinor, consts.CortexVersionMinor)
+	}
+	return fmt.Sprintf("\n\napi configuration schema can be found here:\n  → Realtime API: https://docs.cortex.dev/v/%s/deployments/realtime-api/api-configuration\n  → Batch API: https://docs.cortex.dev/v/%s/deployments/batch-api/api-configuration\n  → Traffic Splitter: https://docs.cortex.dev/v/%s/deployments/realtime-api/traffic-splitter", consts.CortexVersionMinor, consts.CortexVersionMinor, consts.CortexVersionMinor)
+}
+
 func ValidateAPI(
 	api *userconfig.API,
 	models *[]CuratedModelResource,
@@ -701,6 +772,20 @@ func ValidateAPI(
 	gcpClient *gcp.Client,
 	k8sClient *k8s.Client, // will be nil for local provider
 ) error {
+	return validateAPI(api, models, projectFiles, provider, awsClient, gcpClient, k8sClient, false)
+}
+
+// validateAPI skips the checks which require network access (model paths in buckets, docker images, and secrets) when offline is true
+func validateAPI(
+	api *userconfig.API,
+	models *[]CuratedModelResource,
+	projectFiles ProjectFiles,
+	provider types.ProviderType,
+	awsClient *aws.Client,
+	gcpClient *gcp.Client,
+	k8sClient *k8s.Client, // will be nil for local provider
+	offline bool,
+) error {
 
 	// if models is nil, we need to set it to an empty slice to avoid nil pointer exceptions
 	if models == nil {
@@ -711,27 +796,21 @@ func ValidateAPI(
 		api.Networking.Endpoint = pointer.String("/" + api.Name)
 	}
 
-	if err := validatePredictor(api, models, projectFiles, provider, awsClient, gcpClient, k8sClient); err != nil {
-		return errors.Wrap(err, userconfig.PredictorKey)
-	}
+	var errs []error
+
+	errs, _ = errors.AddError(errs, validatePredictor(api, models, projectFiles, provider, awsClient, gcpClient, k8sClient, offline), userconfig.PredictorKey)
 
 	if api.Autoscaling != nil { // should only be nil for local provider
-		if err := validateAutoscaling(api); err != nil {
-			return errors.Wrap(err, userconfig.AutoscalingKey)
-		}
+		errs, _ = errors.AddError(errs, validateAutoscaling(api), userconfig.AutoscalingKey)
 	}
 
-	if err := validateCompute(api, provider); err != nil {
-		return errors.Wrap(err, userconfig.ComputeKey)
-	}
+	errs, _ = errors.AddError(errs, validateCompute(api, provider), userconfig.ComputeKey)
 
 	if api.UpdateStrategy != nil { // should only be nil for local provider
-		if err := validateUpdateStrategy(api.UpdateStrategy); err != nil {
-			return errors.Wrap(err, userconfig.UpdateStrategyKey)
-		}
+		errs, _ = errors.AddError(errs, validateUpdateStrategy(api.UpdateStrategy), userconfig.UpdateStrategyKey)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func ValidateTrafficSplitter(
@@ -740,7 +819,7 @@ func ValidateTrafficSplitter(
 	awsClient *aws.Client,
 ) error {
 
-	if api.Networking.Endpoint == nil {
+	if provider != types.LocalProviderType && api.Networking.Endpoint == nil {
 		api.Networking.Endpoint = pointer.String("/" + api.Name)
 	}
 	if err := verifyTotalWeight(api.APIs); err != nil {
@@ -753,6 +832,14 @@ func ValidateTrafficSplitter(
 	return nil
 }
 
+func ValidateAPIGateway(api *userconfig.API, awsClusterConfig *clusterconfig.Config) error {
+	if api.Networking.APIGateway != userconfig.NoneAPIGatewayType && awsClusterConfig.APIGatewaySetting == clusterconfig.NoneAPIGatewaySetting {
+		return errors.Wrap(ErrorAPIGatewayDisabled(api.Networking.APIGateway), userconfig.NetworkingKey, userconfig.APIGatewayKey)
+	}
+
+	return nil
+}
+
 func validatePredictor(
 	api *userconfig.API,
 	models *[]CuratedModelResource,
@@ -761,6 +848,7 @@ func validatePredictor(
 	awsClient *aws.Client,
 	gcpClient *gcp.Client,
 	k8sClient *k8s.Client, // will be nil for local provider
+	offline bool,
 ) error {
 	predictor := api.Predictor
 
@@ -780,18 +868,18 @@ func validatePredictor(
 
 	switch predictor.Type {
 	case userconfig.PythonPredictorType:
-		if err := validatePythonPredictor(api, models, provider, projectFiles, awsClient, gcpClient); err != nil {
+		if err := validatePythonPredictor(api, models, provider, projectFiles, awsClient, gcpClient, offline); err != nil {
 			return err
 		}
 	case userconfig.TensorFlowPredictorType:
-		if err := validateTensorFlowPredictor(api, models, provider, projectFiles, awsClient, gcpClient); err != nil {
+		if err := validateTensorFlowPredictor(api, models, provider, projectFiles, awsClient, gcpClient, offline); err != nil {
 			return err
 		}
-		if err := validateDockerImagePath(predictor.TensorFlowServingImage, provider, awsClient, k8sClient); err != nil {
+		if err := validateDockerImagePath(predictor.TensorFlowServingImage, provider, awsClient, k8sClient, offline); err != nil {
 			return errors.Wrap(err, userconfig.TensorFlowServingImageKey)
 		}
 	case userconfig.ONNXPredictorType:
-		if err := validateONNXPredictor(api, models, provider, projectFiles, awsClient, gcpClient); err != nil {
+		if err := validateONNXPredictor(api, models, provider, projectFiles, awsClient, gcpClient, offline); err != nil {
 			return err
 		}
 	}
@@ -806,7 +894,7 @@ func validatePredictor(
 		}
 	}
 
-	if err := validateDockerImagePath(predictor.Image, provider, awsClient, k8sClient); err != nil {
+	if err := validateDockerImagePath(predictor.Image, provider, awsClient, k8sClient, offline); err != nil {
 		return errors.Wrap(err, userconfig.ImageKey)
 	}
 
@@ -816,6 +904,10 @@ func validatePredictor(
 		}
 	}
 
+	if err := validateSecretRefs(predictor, provider, k8sClient); err != nil {
+		return errors.Wrap(err, userconfig.EnvKey)
+	}
+
 	if !projectFiles.HasFile(predictor.Path) {
 		return errors.Wrap(files.ErrorFileDoesNotExist(predictor.Path), userconfig.PathKey)
 	}
@@ -829,6 +921,42 @@ func validatePredictor(
 	return nil
 }
 
+func validateSecretRefs(predictor *userconfig.Predictor, provider types.ProviderType, k8sClient *k8s.Client) error {
+	envKeys := maps.StrMapKeys(predictor.Env)
+	sort.Strings(envKeys)
+
+	for _, key := range envKeys {
+		ref, ok, err := userconfig.ParseSecretRef(predictor.Env[key])
+		if !ok {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+
+		if provider == types.LocalProviderType {
+			return errors.Wrap(ErrorSecretsNotSupportedByProvider(provider), key)
+		}
+
+		if k8sClient == nil {
+			continue
+		}
+
+		secret, err := k8sClient.GetSecret(ref.Name)
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		if secret == nil || secret.Labels[userconfig.SecretLabelKey] != "true" {
+			return errors.Wrap(ErrorSecretNotFound(ref.Name), key)
+		}
+		if _, ok := secret.Data[ref.Key]; !ok {
+			return errors.Wrap(ErrorSecretKeyNotFound(ref.Name, ref.Key), key)
+		}
+	}
+
+	return nil
+}
+
 func validateMultiModelsFields(api *userconfig.API) error {
 	predictor := api.Predictor
 
@@ -864,7 +992,7 @@ func validateMultiModelsFields(api *userconfig.API) error {
 	return nil
 }
 
-func validatePythonPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client) error {
+func validatePythonPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client, offline bool) error {
 	predictor := api.Predictor
 
 	if predictor.SignatureKey != nil {
@@ -928,9 +1056,9 @@ func validatePythonPredictor(api *userconfig.API, models *[]CuratedModelResource
 
 	var err error
 	if hasMultiModels && predictor.Models.Dir != nil {
-		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, nil)
+		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, nil)
 	} else {
-		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, nil)
+		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, nil)
 	}
 	if err != nil {
 		return modelWrapError(err)
@@ -951,7 +1079,7 @@ func validatePythonPredictor(api *userconfig.API, models *[]CuratedModelResource
 	return nil
 }
 
-func validateTensorFlowPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client) error {
+func validateTensorFlowPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client, offline bool) error {
 	predictor := api.Predictor
 
 	if predictor.ServerSideBatching != nil {
@@ -1017,9 +1145,9 @@ func validateTensorFlowPredictor(api *userconfig.API, models *[]CuratedModelReso
 
 	var err error
 	if hasMultiModels && predictor.Models.Dir != nil {
-		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, validators)
+		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, validators)
 	} else {
-		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, validators)
+		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, validators)
 	}
 	if err != nil {
 		return modelWrapError(err)
@@ -1040,7 +1168,7 @@ func validateTensorFlowPredictor(api *userconfig.API, models *[]CuratedModelReso
 	return nil
 }
 
-func validateONNXPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client) error {
+func validateONNXPredictor(api *userconfig.API, models *[]CuratedModelResource, provider types.ProviderType, projectFiles ProjectFiles, awsClient *aws.Client, gcpClient *gcp.Client, offline bool) error {
 	predictor := api.Predictor
 
 	if predictor.SignatureKey != nil {
@@ -1110,9 +1238,9 @@ func validateONNXPredictor(api *userconfig.API, models *[]CuratedModelResource,
 
 	var err error
 	if hasMultiModels && predictor.Models.Dir != nil {
-		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, validators)
+		*models, err = validateDirModels(*predictor.Models.Dir, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, validators)
 	} else {
-		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, validators)
+		*models, err = validateModels(modelResources, *api, projectFiles.ProjectDir(), awsClient, gcpClient, offline, validators)
 	}
 	if err != nil {
 		return modelWrapError(err)
@@ -1240,10 +1368,29 @@ func validateCompute(api *userconfig.API, provider types.ProviderType) error {
 		return ErrorInvalidNumberOfInfs(compute.Inf)
 	}
 
+	if compute.NodeGroup != nil && provider != types.AWSProviderType {
+		return errors.Wrap(ErrorNodeGroupsNotSupportedByProvider(provider), userconfig.NodeGroupKey)
+	}
+
 	return nil
 }
 
 func validateUpdateStrategy(updateStrategy *userconfig.UpdateStrategy) error {
+	if updateStrategy.Type != userconfig.BlueGreenUpdateStrategyType && updateStrategy.SmokeTestPayload != nil {
+		return ErrorFieldRequiresBlueGreenUpdateStrategy(userconfig.SmokeTestPayloadKey)
+	}
+
+	if updateStrategy.Type == userconfig.BlueGreenUpdateStrategyType && updateStrategy.AutoRollback {
+		return ErrorFieldRequiresRollingUpdateStrategy(userconfig.AutoRollbackKey)
+	}
+
+	if updateStrategy.SmokeTestPayload != nil {
+		var payload interface{}
+		if err := libjson.Unmarshal([]byte(*updateStrategy.SmokeTestPayload), &payload); err != nil {
+			return errors.Wrap(ErrorInvalidSmokeTestPayload(), userconfig.SmokeTestPayloadKey)
+		}
+	}
+
 	if (updateStrategy.MaxSurge == "0" || updateStrategy.MaxSurge == "0%") && (updateStrategy.MaxUnavailable == "0" || updateStrategy.MaxUnavailable == "0%") {
 		return ErrorSurgeAndUnavailableBothZero()
 	}
@@ -1256,6 +1403,7 @@ func validateDockerImagePath(
 	provider types.ProviderType,
 	awsClient *aws.Client,
 	k8sClient *k8s.Client, // will be nil for local provider)
+	offline bool,
 ) error {
 	if consts.DefaultImagePathsSet.Has(image) {
 		return nil
@@ -1264,6 +1412,10 @@ func validateDockerImagePath(
 		return err
 	}
 
+	if offline {
+		return nil
+	}
+
 	// skip the docker auth check on GCP
 	if provider == types.GCPProviderType {
 		return nil
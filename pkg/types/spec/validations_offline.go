@@ -0,0 +1,166 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ValidateAPIsOffline runs the validations which don't require network access, and returns the errors of all invalid apis.
+// Model paths in buckets, docker images, secrets, and apis referenced by traffic splitters which aren't defined in the same
+// file are not checked; a description of each of these skipped checks is returned.
+func ValidateAPIsOffline(
+	apis []userconfig.API,
+	projectFiles ProjectFiles,
+	provider types.ProviderType,
+	awsClusterConfig *clusterconfig.Config, // optional
+) ([]string, error) {
+
+	if len(apis) == 0 {
+		return nil, ErrorNoAPIs()
+	}
+
+	var skippedChecks []string
+	var errs []error
+
+	for i := range apis {
+		api := &apis[i]
+
+		// validation appends a trailing slash to model paths, so they are collected beforehand
+		var modelPaths []string
+		if api.Predictor != nil {
+			modelPaths = remoteModelPaths(api.Predictor)
+		}
+
+		if api.Kind == userconfig.TrafficSplitterKind {
+			if err := ValidateTrafficSplitter(api, provider, nil); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
+			}
+		} else {
+			if err := validateAPI(api, nil, projectFiles, provider, nil, nil, nil, true); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
+			}
+		}
+
+		if awsClusterConfig != nil {
+			if err := ValidateAPIGateway(api, awsClusterConfig); err != nil {
+				errs = append(errs, errors.Wrap(err, api.Identify()))
+				continue
+			}
+		}
+
+		skippedChecks = append(skippedChecks, offlineSkippedChecks(api, apis, provider, modelPaths)...)
+	}
+
+	if dups := FindDuplicateNames(apis); len(dups) > 0 {
+		errs = append(errs, ErrorDuplicateName(dups))
+	}
+
+	return slices.UniqueStrings(skippedChecks), errors.Join(errs...)
+}
+
+func offlineSkippedChecks(api *userconfig.API, apis []userconfig.API, provider types.ProviderType, modelPaths []string) []string {
+	var skippedChecks []string
+
+	if api.Kind == userconfig.TrafficSplitterKind {
+		for _, trafficSplit := range api.APIs {
+			if !isRealtimeAPIDefined(trafficSplit.Name, apis) {
+				skippedChecks = append(skippedChecks, fmt.Sprintf("%s (used by %s) is not defined in %s, so it was not checked for being deployed", s.UserStr(trafficSplit.Name), s.UserStr(api.Name), api.FileName))
+			}
+		}
+		return skippedChecks
+	}
+
+	for _, modelPath := range modelPaths {
+		skippedChecks = append(skippedChecks, fmt.Sprintf("the contents of %s were not checked", modelPath))
+	}
+
+	// docker images are not checked on gcp, even when deploying
+	if provider != types.GCPProviderType {
+		images := []string{api.Predictor.Image}
+		if api.Predictor.Type == userconfig.TensorFlowPredictorType {
+			images = append(images, api.Predictor.TensorFlowServingImage)
+		}
+		for _, image := range images {
+			if !consts.DefaultImagePathsSet.Has(image) {
+				skippedChecks = append(skippedChecks, fmt.Sprintf("docker image %s was not checked for existence or access", image))
+			}
+		}
+	}
+
+	if provider != types.LocalProviderType {
+		secretRefs := api.Predictor.SecretRefs()
+		envNames := make([]string, 0, len(secretRefs))
+		for envName := range secretRefs {
+			envNames = append(envNames, envName)
+		}
+		sort.Strings(envNames)
+		for _, envName := range envNames {
+			skippedChecks = append(skippedChecks, fmt.Sprintf("secret %s (key %s) was not checked for existence", s.UserStr(secretRefs[envName].Name), s.UserStr(secretRefs[envName].Key)))
+		}
+	}
+
+	return skippedChecks
+}
+
+func remoteModelPaths(predictor *userconfig.Predictor) []string {
+	var modelPaths []string
+
+	if predictor.ModelPath != nil {
+		modelPaths = append(modelPaths, *predictor.ModelPath)
+	}
+	if predictor.Models != nil {
+		if predictor.Models.Dir != nil {
+			modelPaths = append(modelPaths, *predictor.Models.Dir)
+		}
+		for _, model := range predictor.Models.Paths {
+			if model != nil {
+				modelPaths = append(modelPaths, model.ModelPath)
+			}
+		}
+	}
+
+	var remotePaths []string
+	for _, modelPath := range modelPaths {
+		if strings.HasPrefix(modelPath, "s3://") || strings.HasPrefix(modelPath, "gs://") {
+			remotePaths = append(remotePaths, modelPath)
+		}
+	}
+
+	return remotePaths
+}
+
+func isRealtimeAPIDefined(apiName string, apis []userconfig.API) bool {
+	for _, api := range apis {
+		if api.Name == apiName && api.Kind == userconfig.RealtimeAPIKind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testProjectFiles struct {
+	files map[string][]byte
+}
+
+func (p testProjectFiles) AllPaths() []string {
+	paths := make([]string, 0, len(p.files))
+	for path := range p.files {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (p testProjectFiles) GetFile(path string) ([]byte, error) {
+	return p.files[path], nil
+}
+
+func (p testProjectFiles) HasFile(path string) bool {
+	_, ok := p.files[path]
+	return ok
+}
+
+func (p testProjectFiles) HasDir(path string) bool {
+	return false
+}
+
+func (p testProjectFiles) ProjectDir() string {
+	return "/project"
+}
+
+func TestValidateAPIsOffline(t *testing.T) {
+	configBytes := []byte(`
+- name: onnx-api
+  kind: RealtimeAPI
+  predictor:
+    type: onnx
+    path: predictor.py
+    model_path: s3://my-bucket/model.onnx
+    image: my-registry/onnx-predictor:custom
+- name: python-api
+  kind: RealtimeAPI
+  predictor:
+    type: python
+    path: predictor.py
+    models:
+      paths:
+        - name: a
+          model_path: s3://my-bucket/a
+        - name: b
+          model_path: s3://my-bucket/b/
+`)
+
+	projectFiles := testProjectFiles{files: map[string][]byte{"predictor.py": []byte("")}}
+
+	apis, err := ExtractAPIConfigs(configBytes, types.AWSProviderType, "cortex.yaml", nil, nil)
+	require.NoError(t, err)
+
+	skippedChecks, err := ValidateAPIsOffline(apis, projectFiles, types.AWSProviderType, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"the contents of s3://my-bucket/model.onnx were not checked",
+		"docker image my-registry/onnx-predictor:custom was not checked for existence or access",
+		"the contents of s3://my-bucket/a were not checked",
+		"the contents of s3://my-bucket/b/ were not checked",
+	}, skippedChecks)
+}
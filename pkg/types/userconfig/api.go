@@ -100,6 +100,8 @@ type Compute struct {
 	Mem *k8s.Quantity `json:"mem" yaml:"mem"`
 	GPU int64         `json:"gpu" yaml:"gpu"`
 	Inf int64         `json:"inf" yaml:"inf"`
+
+	NodeGroup *string `json:"node_group" yaml:"node_group"`
 }
 
 type Autoscaling struct {
@@ -118,8 +120,13 @@ type Autoscaling struct {
 }
 
 type UpdateStrategy struct {
-	MaxSurge       string `json:"max_surge" yaml:"max_surge"`
-	MaxUnavailable string `json:"max_unavailable" yaml:"max_unavailable"`
+	Type                UpdateStrategyType `json:"type" yaml:"type"`
+	MaxSurge            string             `json:"max_surge" yaml:"max_surge"`
+	MaxUnavailable      string             `json:"max_unavailable" yaml:"max_unavailable"`
+	ProgressDeadline    time.Duration      `json:"progress_deadline" yaml:"progress_deadline"`
+	AutoRollback        bool               `json:"auto_rollback" yaml:"auto_rollback"`
+	SmokeTestPayload    *string            `json:"smoke_test_payload" yaml:"smoke_test_payload"`
+	TeardownGracePeriod time.Duration      `json:"teardown_grace_period" yaml:"teardown_grace_period"`
 }
 
 func (api *API) Identify() string {
@@ -220,6 +227,11 @@ func (api *API) ToK8sAnnotations() map[string]string {
 		annotations[DownscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.DownscaleTolerance)
 		annotations[UpscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.UpscaleTolerance)
 	}
+
+	if api.UpdateStrategy != nil {
+		annotations[ProgressDeadlineAnnotationKey] = api.UpdateStrategy.ProgressDeadline.String()
+		annotations[AutoRollbackAnnotationKey] = s.Bool(api.UpdateStrategy.AutoRollback)
+	}
 	return annotations
 }
 
@@ -485,6 +497,9 @@ func (compute *Compute) Normalized() string {
 	} else {
 		sb.WriteString(fmt.Sprintf("%s: %d\n", MemKey, compute.Mem.Value()))
 	}
+	if compute.NodeGroup != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", NodeGroupKey, *compute.NodeGroup))
+	}
 	return sb.String()
 }
 
@@ -506,6 +521,9 @@ func (compute *Compute) UserStr() string {
 	} else {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", MemKey, compute.Mem.UserString))
 	}
+	if compute.NodeGroup != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", NodeGroupKey, *compute.NodeGroup))
+	}
 	return sb.String()
 }
 
@@ -534,6 +552,10 @@ func (compute Compute) Equals(c2 *Compute) bool {
 		return false
 	}
 
+	if s.Obj(compute.NodeGroup) != s.Obj(c2.NodeGroup) {
+		return false
+	}
+
 	return true
 }
 
@@ -556,8 +578,18 @@ func (autoscaling *Autoscaling) UserStr() string {
 
 func (updateStrategy *UpdateStrategy) UserStr() string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxSurgeKey, updateStrategy.MaxSurge))
-	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxUnavailableKey, updateStrategy.MaxUnavailable))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", UpdateStrategyTypeKey, updateStrategy.Type.String()))
+	if updateStrategy.Type == BlueGreenUpdateStrategyType {
+		if updateStrategy.SmokeTestPayload != nil {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", SmokeTestPayloadKey, s.UserStr(*updateStrategy.SmokeTestPayload)))
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TeardownGracePeriodKey, updateStrategy.TeardownGracePeriod.String()))
+	} else {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxSurgeKey, updateStrategy.MaxSurge))
+		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxUnavailableKey, updateStrategy.MaxUnavailable))
+		sb.WriteString(fmt.Sprintf("%s: %s\n", AutoRollbackKey, s.Bool(updateStrategy.AutoRollback)))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", ProgressDeadlineKey, updateStrategy.ProgressDeadline.String()))
 	return sb.String()
 }
 
@@ -615,6 +647,9 @@ func (api *API) TelemetryEvent(provider types.ProviderType) map[string]interface
 		}
 		event["compute.gpu"] = api.Compute.GPU
 		event["compute.inf"] = api.Compute.Inf
+		if api.Compute.NodeGroup != nil {
+			event["compute.node_group._is_defined"] = true
+		}
 	}
 
 	if api.Predictor != nil {
@@ -685,8 +720,13 @@ func (api *API) TelemetryEvent(provider types.ProviderType) map[string]interface
 
 	if api.UpdateStrategy != nil {
 		event["update_strategy._is_defined"] = true
+		event["update_strategy.type"] = api.UpdateStrategy.Type.String()
 		event["update_strategy.max_surge"] = api.UpdateStrategy.MaxSurge
 		event["update_strategy.max_unavailable"] = api.UpdateStrategy.MaxUnavailable
+		event["update_strategy.progress_deadline"] = api.UpdateStrategy.ProgressDeadline.Seconds()
+		event["update_strategy.auto_rollback"] = api.UpdateStrategy.AutoRollback
+		event["update_strategy.smoke_test_payload._is_defined"] = api.UpdateStrategy.SmokeTestPayload != nil
+		event["update_strategy.teardown_grace_period"] = api.UpdateStrategy.TeardownGracePeriod.Seconds()
 	}
 
 	if api.Autoscaling != nil {
@@ -27,7 +27,11 @@ const (
 	AutoscalingKey    = "autoscaling"
 	UpdateStrategyKey = "update_strategy"
 
-	// TrafficSplitter
+	// API configuration file
+	DefaultsKey = "defaults"
+	ExtendsKey  = "extends"
+
+	// TrafficSplitter (also used for the list of APIs in the API configuration file)
 	APIsKey   = "apis"
 	WeightKey = "weight"
 
@@ -69,10 +73,11 @@ const (
 	LocalPortKey  = "local_port"
 
 	// Compute
-	CPUKey = "cpu"
-	MemKey = "mem"
-	GPUKey = "gpu"
-	InfKey = "inf"
+	CPUKey       = "cpu"
+	MemKey       = "mem"
+	GPUKey       = "gpu"
+	InfKey       = "inf"
+	NodeGroupKey = "node_group"
 
 	// Autoscaling
 	MinReplicasKey                  = "min_replicas"
@@ -89,8 +94,13 @@ const (
 	UpscaleToleranceKey             = "upscale_tolerance"
 
 	// UpdateStrategy
-	MaxSurgeKey       = "max_surge"
-	MaxUnavailableKey = "max_unavailable"
+	UpdateStrategyTypeKey  = "type"
+	MaxSurgeKey            = "max_surge"
+	MaxUnavailableKey      = "max_unavailable"
+	ProgressDeadlineKey    = "progress_deadline"
+	AutoRollbackKey        = "auto_rollback"
+	SmokeTestPayloadKey    = "smoke_test_payload"
+	TeardownGracePeriodKey = "teardown_grace_period"
 
 	// K8s annotation
 	EndpointAnnotationKey                     = "networking.cortex.dev/endpoint"
@@ -108,4 +118,6 @@ const (
 	MaxUpscaleFactorAnnotationKey             = "autoscaling.cortex.dev/max-upscale-factor"
 	DownscaleToleranceAnnotationKey           = "autoscaling.cortex.dev/downscale-tolerance"
 	UpscaleToleranceAnnotationKey             = "autoscaling.cortex.dev/upscale-tolerance"
+	ProgressDeadlineAnnotationKey             = "update-strategy.cortex.dev/progress-deadline"
+	AutoRollbackAnnotationKey                 = "update-strategy.cortex.dev/auto-rollback"
 )
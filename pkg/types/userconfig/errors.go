@@ -17,11 +17,14 @@ limitations under the License.
 package userconfig
 
 import (
+	"fmt"
+
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 )
 
 const (
 	ErrUnknownAPIGatewayType = "userconfig.unknown_api_gateway_type"
+	ErrInvalidSecretRef      = "userconfig.invalid_secret_ref"
 )
 
 func ErrorUnknownAPIGatewayType() error {
@@ -30,3 +33,10 @@ func ErrorUnknownAPIGatewayType() error {
 		Message: "unknown api gateway type",
 	})
 }
+
+func ErrorInvalidSecretRef(value string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSecretRef,
+		Message: fmt.Sprintf("invalid secret reference %s; secret references must be of the form %sSECRET_NAME/KEY", value, SecretRefPrefix),
+	})
+}
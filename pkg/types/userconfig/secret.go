@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+import (
+	"strings"
+)
+
+const (
+	SecretRefPrefix = "secret://"
+
+	// label applied to k8s secrets which are managed via `cortex secret`
+	SecretLabelKey = "cortexSecret"
+)
+
+type SecretRef struct {
+	Name string
+	Key  string
+}
+
+// IsSecretRef returns true if the env var value references a secret (secret://name/key)
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefPrefix)
+}
+
+// ParseSecretRef parses values of the form secret://name/key; ok is false if the value is not a secret reference
+func ParseSecretRef(value string) (*SecretRef, bool, error) {
+	if !IsSecretRef(value) {
+		return nil, false, nil
+	}
+
+	split := strings.Split(strings.TrimPrefix(value, SecretRefPrefix), "/")
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return nil, true, ErrorInvalidSecretRef(value)
+	}
+
+	return &SecretRef{
+		Name: split[0],
+		Key:  split[1],
+	}, true, nil
+}
+
+func (ref SecretRef) String() string {
+	return SecretRefPrefix + ref.Name + "/" + ref.Key
+}
+
+// SecretRefs returns the secrets referenced by the predictor's env vars, keyed by env var name
+func (predictor *Predictor) SecretRefs() map[string]SecretRef {
+	refs := map[string]SecretRef{}
+	for name, val := range predictor.Env {
+		ref, ok, err := ParseSecretRef(val)
+		if ok && err == nil {
+			refs[name] = *ref
+		}
+	}
+	return refs
+}
@@ -138,6 +138,15 @@ if [ "$CORTEX_KIND" = "RealtimeAPI" ]; then
 # prepare batch otherwise
 else
     create_s6_service "batch" "cd /mnt/project && $source_env_file_cmd && exec env PYTHONUNBUFFERED=TRUE env PYTHONPATH=$PYTHONPATH:$CORTEX_PYTHON_PATH /opt/conda/envs/env/bin/python /src/cortex/serve/start/batch.py"
+
+    # in the local environment, the container exits once the worker is done, and the worker's exit code is recorded for `cortex get API_NAME JOB_ID`
+    if [ "$CORTEX_PROVIDER" == "local" ]; then
+        dest_script="/etc/services.d/batch/finish"
+        echo "#!/usr/bin/with-contenv bash" > $dest_script
+        echo "echo \$1 > /mnt/workspace/exit_code.txt" >> $dest_script
+        echo "s6-svscanctl -t /var/run/s6/services" >> $dest_script
+        chmod +x $dest_script
+    fi
 fi
 
 # create the python initialization service
@@ -34,6 +34,7 @@ from cortex.lib.storage import S3, LocalStorage
 from cortex.lib.exceptions import UserRuntimeException
 
 API_LIVENESS_UPDATE_PERIOD = 5  # seconds
+LOCAL_WORKER_HEARTBEAT_TIMEOUT = 30  # seconds (must match the timeout in cli/local/docker_spec.go)
 MAXIMUM_MESSAGE_VISIBILITY = 60 * 60 * 12  # 12 hours is the maximum message visibility
 
 local_cache = {
@@ -82,6 +83,10 @@ def build_predict_args(payload, batch_id):
 
 
 def get_job_spec(storage, cache_dir, job_spec_path):
+    if local_cache["provider"] == "local":
+        with open(job_spec_path) as f:
+            return json.load(f)
+
     local_spec_path = os.path.join(cache_dir, "job_spec.json")
     _, key = S3.deconstruct_s3_path(job_spec_path)
     storage.download_file(key, local_spec_path)
@@ -208,6 +213,122 @@ def sqs_loop():
             sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
 
 
+def start_local_heartbeat(worker_dir):
+    """
+    periodically touches the worker's heartbeat file, so that the other workers of the job can tell whether it is still alive
+    """
+
+    heartbeat_path = os.path.join(worker_dir, "heartbeat.txt")
+    pathlib.Path(heartbeat_path).touch()
+
+    def beat():
+        while True:
+            time.sleep(API_LIVENESS_UPDATE_PERIOD)
+            pathlib.Path(heartbeat_path).touch()
+
+    threading.Thread(target=beat, daemon=True).start()
+
+
+def is_local_worker_alive(worker_dir):
+    if os.path.isfile(os.path.join(worker_dir, "exit_code.txt")):
+        return False
+
+    try:
+        last_heartbeat = os.path.getmtime(os.path.join(worker_dir, "heartbeat.txt"))
+    except FileNotFoundError:
+        return False
+
+    return time.time() - last_heartbeat < LOCAL_WORKER_HEARTBEAT_TIMEOUT
+
+
+def local_queue_loop():
+    """
+    processes the batches of a job submitted in the local environment; the job's directory is shared by all of its workers,
+    and each batch is claimed by moving it from the batches directory to the in_progress directory (prefixed with the worker's index)
+    """
+
+    job_dir = os.path.dirname(os.environ["CORTEX_JOB_SPEC"])
+    worker_index = os.environ["CORTEX_JOB_WORKER_INDEX"]
+    batches_dir = os.path.join(job_dir, "batches")
+    in_progress_dir = os.path.join(job_dir, "in_progress")
+    results_dir = os.path.join(job_dir, "results")
+    workers_dir = os.path.join(job_dir, "workers")
+    predictor_impl = local_cache["predictor_impl"]
+
+    start_local_heartbeat(os.path.join(workers_dir, worker_index))
+
+    def write_result(batch_file_name, succeeded, time_per_batch):
+        result_path = os.path.join(results_dir, batch_file_name)
+        with open(result_path + ".tmp", "w") as f:
+            json.dump({"succeeded": succeeded, "time_per_batch": time_per_batch}, f)
+        os.rename(result_path + ".tmp", result_path)
+
+    while True:
+        batch_file_names = sorted(os.listdir(batches_dir))
+        if len(batch_file_names) == 0:
+            break
+
+        batch_file_name = batch_file_names[0]
+        in_progress_path = os.path.join(in_progress_dir, f"{worker_index}_{batch_file_name}")
+        try:
+            os.rename(os.path.join(batches_dir, batch_file_name), in_progress_path)
+        except FileNotFoundError:
+            continue  # the batch was claimed by another worker
+
+        batch_id = os.path.splitext(batch_file_name)[0]
+        logger().info(f"processing batch {batch_id}")
+
+        start_time = time.time()
+        succeeded = False
+        try:
+            with open(in_progress_path) as f:
+                payload = json.load(f)
+            predictor_impl.predict(**build_predict_args(payload, batch_id))
+            succeeded = True
+        except Exception:
+            logger().exception("failed to process batch")
+
+        write_result(batch_file_name, succeeded, time.time() - start_time)
+        os.remove(in_progress_path)
+
+    # only one of the workers waits for the batches claimed by the other workers to be processed (and runs on_job_complete)
+    try:
+        os.rename(
+            os.path.join(job_dir, "job_complete"), os.path.join(job_dir, "job_complete_in_progress")
+        )
+    except FileNotFoundError:
+        logger().info("no batches left in queue, exiting...")
+        return
+
+    while True:
+        in_progress_file_names = os.listdir(in_progress_dir)
+        if len(in_progress_file_names) == 0:
+            break
+
+        # batches which were claimed by workers that have exited (e.g. due to running out of memory) will never be processed
+        for in_progress_file_name in in_progress_file_names:
+            claimed_by, batch_file_name = in_progress_file_name.split("_", 1)
+            if is_local_worker_alive(os.path.join(workers_dir, claimed_by)):
+                continue
+            batch_id = os.path.splitext(batch_file_name)[0]
+            logger().error(
+                f"batch {batch_id} failed because worker {claimed_by} exited while processing it"
+            )
+            write_result(batch_file_name, False, 0)
+            try:
+                os.remove(os.path.join(in_progress_dir, in_progress_file_name))
+            except FileNotFoundError:
+                pass
+
+        time.sleep(1)
+
+    if getattr(predictor_impl, "on_job_complete", None):
+        logger().info("executing on_job_complete")
+        predictor_impl.on_job_complete()
+
+    logger().info("no batches left in queue, job has been completed")
+
+
 def start():
     while not pathlib.Path("/mnt/workspace/init_script_run.txt").is_file():
         time.sleep(0.2)
@@ -237,6 +358,8 @@ def start():
             json.dump(used_ports, f)
             f.truncate()
 
+    local_cache["provider"] = provider
+
     api = get_api(provider, api_spec_path, model_dir, cache_dir, region)
     storage, api_spec = get_spec(provider, api_spec_path, cache_dir, region)
     job_spec = get_job_spec(storage, cache_dir, job_spec_path)
@@ -248,10 +371,16 @@ def start():
     predictor_impl = api.predictor.initialize_impl(project_dir, client, job_spec)
 
     local_cache["api_spec"] = api
-    local_cache["provider"] = provider
     local_cache["job_spec"] = job_spec
     local_cache["predictor_impl"] = predictor_impl
     local_cache["predict_fn_args"] = inspect.getfullargspec(predictor_impl.predict).args
+
+    if provider == "local":
+        open("/mnt/workspace/api_readiness.txt", "a").close()
+        logger().info("polling for batches...")
+        local_queue_loop()
+        return
+
     local_cache["sqs_client"] = boto3.client("sqs", region_name=region)
 
     open("/mnt/workspace/api_readiness.txt", "a").close()